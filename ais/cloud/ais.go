@@ -449,37 +449,47 @@ func (m *AisCloudProvider) GetObjReader(ctx context.Context, lom *cluster.LOM) (
 }
 
 func (m *AisCloudProvider) PutObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
-	remoteBck := lom.Bck().Bck
+	fh, ok := r.(*cmn.FileHandle) // `PutObject` closes file handle.
+	cmn.Assert(ok)                // HTTP redirect requires Open().
+	err, errCode = m.PutObjBck(lom.Bck().Bck, lom.ObjName, fh, lom.Size(), lom.Cksum())
+	return lom.Version(), err, errCode
+}
+
+func (m *AisCloudProvider) DeleteObj(ctx context.Context, lom *cluster.LOM) (err error, errCode int) {
+	return m.DeleteObjBck(lom.Bck().Bck, lom.ObjName)
+}
 
+// PutObjBck and DeleteObjBck are the PutObj and DeleteObj counterparts that
+// do not require the remote bucket to be present in the (local) BMD -
+// used to replicate objects (see ais/tgtrepl.go).
+func (m *AisCloudProvider) PutObjBck(remoteBck cmn.Bck, objName string, r cmn.ReadOpenCloser, size int64,
+	cksum *cmn.Cksum) (err error, errCode int) {
 	aisCluster, err := m.remoteCluster(remoteBck.Ns.UUID)
 	if err != nil {
-		return "", err, errCode
+		r.Close()
+		return err, errCode
 	}
-	fh, ok := r.(*cmn.FileHandle) // `PutObject` closes file handle.
-	cmn.Assert(ok)                // HTTP redirect requires Open().
 	err = m.try(remoteBck, func(bck cmn.Bck) error {
 		args := api.PutObjectArgs{
 			BaseParams: aisCluster.bp,
 			Bck:        bck,
-			Object:     lom.ObjName,
-			Cksum:      lom.Cksum(),
-			Reader:     fh,
-			Size:       uint64(lom.Size()),
+			Object:     objName,
+			Cksum:      cksum,
+			Reader:     r,
+			Size:       uint64(size),
 		}
 		return api.PutObject(args)
 	})
-	err, errCode = extractErrCode(err)
-	return lom.Version(), err, errCode
+	return extractErrCode(err)
 }
 
-func (m *AisCloudProvider) DeleteObj(ctx context.Context, lom *cluster.LOM) (err error, errCode int) {
-	remoteBck := lom.Bck().Bck
+func (m *AisCloudProvider) DeleteObjBck(remoteBck cmn.Bck, objName string) (err error, errCode int) {
 	aisCluster, err := m.remoteCluster(remoteBck.Ns.UUID)
 	if err != nil {
 		return err, errCode
 	}
 	err = m.try(remoteBck, func(bck cmn.Bck) error {
-		return api.DeleteObject(aisCluster.bp, bck, lom.ObjName)
+		return api.DeleteObject(aisCluster.bp, bck, objName)
 	})
 	return extractErrCode(err)
 }
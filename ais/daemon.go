@@ -316,6 +316,10 @@ func initTarget() cmn.Runner {
 
 	housekeep, initialInterval := cluster.LomCacheHousekeep(t.gmm, t)
 	hk.Reg("lom-cache", housekeep, initialInterval)
+	hk.Reg("versions.prune", t.pruneVersions, versionsHousekeepT)
+	hk.Reg("lifecycle", t.lifecycleHk, lifecycleHousekeepT)
+	hk.Reg("scrub", t.scrubHk, scrubHousekeepT)
+	hk.Reg("quota", t.quotaHk, quotaHousekeepT)
 	if err := ts.InitCapacity(); err != nil { // goes after fs.Init
 		cmn.ExitLogf("%s", err)
 	}
@@ -7,7 +7,6 @@ package ais
 import (
 	"net/url"
 	"os"
-	"sync"
 	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
@@ -299,7 +298,7 @@ func (p *proxyrunner) _startupBMDFinal(ctx *bmdModifier, clone *bucketMD) {
 	// CAS returns `true` if it has swapped, so it a one-liner for:
 	// doRebalance=rebalance.Load(); rebalance.Store(false)
 	doRebalance := p.owner.rmd.rebalance.CAS(true, false)
-	var wg *sync.WaitGroup
+	pairs := []revsPair{{ctx.smap, msg}, {clone, msg}}
 	if doRebalance && cmn.GCO.Get().Rebalance.Enabled {
 		glog.Infof("rebalance did not finish, restarting...")
 		msg.Action = cmn.ActRebalance
@@ -308,10 +307,12 @@ func (p *proxyrunner) _startupBMDFinal(ctx *bmdModifier, clone *bucketMD) {
 			pre:  func(ctx *rmdModifier, clone *rebMD) { clone.inc() },
 		}
 		rmd := p.owner.rmd.modify(ctx)
-		wg = p.metasyncer.sync(revsPair{ctx.smap, msg}, revsPair{clone, msg}, revsPair{rmd, msg})
-	} else {
-		wg = p.metasyncer.sync(revsPair{ctx.smap, msg}, revsPair{clone, msg})
+		pairs = append(pairs, revsPair{rmd, msg})
+	}
+	if sched := p.sched.get(); sched.version() > 0 {
+		pairs = append(pairs, revsPair{sched, msg})
 	}
+	wg := p.metasyncer.sync(pairs...)
 	glog.Infof("%s: metasync %s, %s", p.si, ctx.smap.StringEx(), clone.StringEx())
 	wg.Wait()
 }
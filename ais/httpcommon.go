@@ -28,6 +28,7 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/3rdparty/golang/mux"
+	"github.com/NVIDIA/aistore/ais/s3compat"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/debug"
@@ -1135,6 +1136,17 @@ func (h *httprunner) invalmsghdlr(w http.ResponseWriter, r *http.Request, msg st
 	h.statsT.AddErrorHTTP(r.Method, 1)
 }
 
+// invalS3hdlr responds with S3 error (code and status) if `err` is one, or
+// else the same way as invalmsghdlr.
+func (h *httprunner) invalS3hdlr(w http.ResponseWriter, r *http.Request, err error, errCode ...int) {
+	if s3Err, ok := s3compat.AsError(err); ok {
+		s3compat.WriteErr(w, r, s3Err)
+		h.statsT.AddErrorHTTP(r.Method, 1)
+		return
+	}
+	h.invalmsghdlr(w, r, err.Error(), errCode...)
+}
+
 func (h *httprunner) invalmsghdlrsilent(w http.ResponseWriter, r *http.Request, msg string, errCode ...int) {
 	cmn.InvalidHandlerDetailedNoLog(w, r, msg, errCode...)
 }
@@ -1580,6 +1592,9 @@ func (h *httprunner) bucketPropsToHdr(bck *cluster.Bck, hdr http.Header) {
 		} else if fieldName == cmn.HeaderBucketCreated {
 			created := time.Unix(0, field.Value().(int64))
 			hdr.Set(cmn.HeaderBucketCreated, created.Format(time.RFC3339))
+		} else if rules, ok := field.Value().([]cmn.LifecycleRule); ok {
+			hdr.Set(fieldName, string(cmn.MustMarshal(rules)))
+			return nil, false
 		}
 
 		hdr.Set(fieldName, fmt.Sprintf("%v", field.Value()))
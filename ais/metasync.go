@@ -21,9 +21,11 @@ import (
 )
 
 const (
-	revsSmapTag = "Smap"
-	revsRMDTag  = "RMD"
-	revsBMDTag  = "BMD"
+	revsSmapTag   = "Smap"
+	revsRMDTag    = "RMD"
+	revsBMDTag    = "BMD"
+	revsSchedTag  = "schedule"
+	revsS3KeysTag = "s3keys"
 
 	revsTokenTag  = "token"
 	revsActionTag = "-action" // to make a pair (revs, action)
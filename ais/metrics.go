@@ -0,0 +1,45 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"net/http"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/xaction/xreg"
+)
+
+// GET /metrics - Prometheus scraping endpoint (see stats/prometheus.go)
+func (h *httprunner) writeMetrics(w http.ResponseWriter, r *http.Request, xacts []cluster.XactStats) {
+	if r.Method != http.MethodGet {
+		cmn.InvalidHandlerWithMsg(w, r, "invalid method for /"+cmn.Metrics+" path", http.StatusMethodNotAllowed)
+		return
+	}
+	if exporter := cmn.GCO.Get().Stats.Exporter; exporter != cmn.StatsExporterPrometheus {
+		h.invalmsghdlrstatusf(w, r, http.StatusNotFound,
+			"%s: metrics are exported to %s (see config stats.exporter)", h.si, exporter)
+		return
+	}
+	w.Header().Set(cmn.HeaderContentType, stats.PromContentType)
+	if err := h.statsT.WritePrometheus(w, h.si, xacts); err != nil {
+		glog.Errorf("%s: failed to write metrics, err: %v", h.si, err)
+	}
+}
+
+func (p *proxyrunner) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	p.writeMetrics(w, r, nil)
+}
+
+func (t *targetrunner) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	onlyRunning := true
+	xacts, err := xreg.GetStats(xreg.XactFilter{OnlyRunning: &onlyRunning})
+	if err != nil {
+		glog.Errorf("%s: failed to get xaction stats, err: %v", t.si, err)
+	}
+	t.writeMetrics(w, r, xacts)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn/mono"
 	"github.com/NVIDIA/aistore/dsort"
 	"github.com/NVIDIA/aistore/etl"
+	"github.com/NVIDIA/aistore/hk"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/nl"
 	"github.com/NVIDIA/aistore/stats"
@@ -74,6 +75,8 @@ type (
 		notifs     notifs
 		ic         ic
 		qm         queryMem
+		sched      *schedOwner
+		s3keys     *s3KeyOwner
 		gmm        *memsys.MMSA // system pagesize-based memory manager and slab allocator
 	}
 )
@@ -99,6 +102,10 @@ func (p *proxyrunner) Run() error {
 	p.owner.bmd = newBMDOwnerPrx(config)
 
 	p.owner.bmd.init() // initialize owner and load BMD
+	p.sched = newSchedOwner()
+	p.sched.load()
+	p.s3keys = newS3KeyOwner()
+	p.s3keys.load()
 
 	cluster.Init()
 
@@ -116,6 +123,7 @@ func (p *proxyrunner) Run() error {
 	p.notifs.init(p)
 	p.ic.init(p)
 	p.qm.init()
+	hk.Reg("schedule", p.schedHk, schedHousekeepT)
 
 	//
 	// REST API: register proxy handlers and start listening
@@ -143,6 +151,7 @@ func (p *proxyrunner) Run() error {
 		{r: cmn.Notifs, h: p.notifs.handler, net: []string{cmn.NetworkIntraControl}},
 
 		{r: "/" + cmn.S3, h: p.s3Handler, net: []string{cmn.NetworkPublic}},
+		{r: "/" + cmn.Metrics, h: p.metricsHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl}},
 
 		{r: "/", h: p.httpCloudHandler, net: []string{cmn.NetworkPublic}},
 	}
@@ -643,6 +652,30 @@ ExtractRMD:
 		p.authn.updateRevokedList(revokedTokens)
 	}
 
+	newSched, _, err := p.extractSched(payload)
+	if err != nil {
+		errs = append(errs, err)
+	} else if newSched != nil {
+		if glog.FastV(4, glog.SmoduleAIS) {
+			glog.Infof("new %s from %s", newSched, caller)
+		}
+		if err = p.sched.receive(newSched); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	newS3Keys, err := p.extractS3Keys(payload)
+	if err != nil {
+		errs = append(errs, err)
+	} else if newS3Keys != nil {
+		if glog.FastV(4, glog.SmoduleAIS) {
+			glog.Infof("new %s from %s", newS3Keys, caller)
+		}
+		if err = p.s3keys.receive(newS3Keys); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	if len(errs) > 0 {
 		p.invalmsghdlrf(w, r, "%v", errs)
 		return
@@ -803,7 +836,7 @@ func (p *proxyrunner) hpostBucket(w http.ResponseWriter, r *http.Request, msg *c
 			return
 		}
 		w.Write([]byte(xactID))
-	case cmn.ActCopyBucket, cmn.ActETLBucket:
+	case cmn.ActCopyBucket, cmn.ActSyncBucket, cmn.ActETLBucket:
 		if err := p.checkPermissions(r.Header, &bck.Bck, cmn.AccessGET); err != nil {
 			p.invalmsghdlr(w, r, err.Error(), http.StatusUnauthorized)
 			return
@@ -838,6 +871,16 @@ func (p *proxyrunner) hpostBucket(w http.ResponseWriter, r *http.Request, msg *c
 			internalMsg.BckTo = cpyBckMsg.BckTo
 			internalMsg.DryRun = cpyBckMsg.DryRun
 			internalMsg.Prefix = cpyBckMsg.Prefix
+		case cmn.ActSyncBucket:
+			syncBckMsg := &cmn.SyncBckMsg{}
+			if err = cmn.MorphMarshal(msg.Value, syncBckMsg); err != nil {
+				p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+				return
+			}
+			internalMsg.BckTo = syncBckMsg.BckTo
+			internalMsg.DryRun = syncBckMsg.DryRun
+			internalMsg.Prefix = syncBckMsg.Prefix
+			internalMsg.Delete = syncBckMsg.Delete
 		}
 
 		bckFrom, msgBckTo := bck, cluster.NewBckEmbed(internalMsg.BckTo)
@@ -2429,6 +2472,12 @@ func (p *proxyrunner) _becomeFinal(ctx *smapModifier, clone *smapX) {
 		msg   = p.newAisMsgStr(cmn.ActNewPrimary, clone, bmd)
 		pairs = []revsPair{{clone, msg}, {bmd, msg}, {rmd, msg}}
 	)
+	if sched := p.sched.get(); sched.version() > 0 {
+		pairs = append(pairs, revsPair{sched, msg})
+	}
+	if keys := p.s3keys.get(); keys.version() > 0 {
+		pairs = append(pairs, revsPair{keys, msg})
+	}
 
 	glog.Infof("%s: distributing (%s, %s, %s) with newly elected primary (self)", p.si, clone, bmd, rmd)
 	_ = p.metasyncer.sync(pairs...)
@@ -2526,6 +2575,8 @@ func (p *proxyrunner) tokenHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodDelete:
 		p.httpTokenDelete(w, r)
+	case http.MethodPut:
+		p.httpTokenPut(w, r)
 	default:
 		cmn.InvalidHandlerWithMsg(w, r, "invalid method for /token path")
 	}
@@ -2599,6 +2650,12 @@ func (p *proxyrunner) httpcluget(w http.ResponseWriter, r *http.Request) {
 		p.ic.writeStatus(w, r)
 	case cmn.GetWhatMountpaths:
 		p.queryClusterMountpaths(w, r, what)
+	case cmn.GetWhatScrubReport:
+		if reports := p._queryTargets(w, r); reports != nil {
+			p.writeJSON(w, r, reports, what)
+		}
+	case cmn.GetWhatSchedule:
+		p.writeJSON(w, r, &p.sched.get().Schedule, what)
 	case cmn.GetWhatRemoteAIS:
 		config := cmn.GCO.Get()
 		smap := p.owner.smap.get()
@@ -3019,6 +3076,14 @@ func (p *proxyrunner) _updFinal(ctx *smapModifier, clone *smapX) {
 		// the latest one - newly joined can become primary in a second.
 		rmd := p.owner.rmd.get()
 		pairs = append(pairs, revsPair{rmd, aisMsg})
+		// Same for the schedule - to take over the scheduling.
+		if sched := p.sched.get(); sched.version() > 0 {
+			pairs = append(pairs, revsPair{sched, aisMsg})
+		}
+		// And the S3 keys - to authenticate S3 requests.
+		if keys := p.s3keys.get(); keys.version() > 0 {
+			pairs = append(pairs, revsPair{keys, aisMsg})
+		}
 	}
 
 	if len(tokens.Tokens) > 0 {
@@ -3369,6 +3434,8 @@ func (p *proxyrunner) cluputJSON(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 		}
+	case cmn.ActAddSchedule, cmn.ActRemoveSchedule:
+		p.updSchedule(w, r, msg)
 	case cmn.ActShutdown:
 		glog.Infoln("Proxy-controlled cluster shutdown...")
 		p.callAll(http.MethodPut, cmn.JoinWords(cmn.Version, cmn.Daemon), cmn.MustMarshal(msg))
@@ -6,10 +6,13 @@ package ais
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais/s3compat"
 	"github.com/NVIDIA/aistore/cmn"
 )
 
@@ -68,3 +71,30 @@ func (p *proxyrunner) checkPermissions(hdr http.Header, bck *cmn.Bck, perms cmn.
 	uid := p.owner.smap.Get().UUID
 	return token.CheckPermissions(uid, bck, perms)
 }
+
+// Verifies AWS Signature V4 of a request to S3 compatibility API and checks
+// the permissions of the user the access key was issued for.
+// The access key is looked up in the list of S3 keys that AuthN pushes to
+// the cluster (see prxs3keys.go).
+func (p *proxyrunner) checkS3Permissions(r *http.Request, bck *cmn.Bck, perms cmn.AccessAttrs) error {
+	cfg := cmn.GCO.Get()
+	if !cfg.Auth.Enabled {
+		return nil
+	}
+	sig, err := s3compat.ParseSigV4(r)
+	if err != nil {
+		return err
+	}
+	key := p.s3keys.lookup(sig.AccessKey)
+	if key == nil {
+		return s3compat.NewInvalidAccessKey(fmt.Errorf("S3 access key %q not found", sig.AccessKey))
+	}
+	if err := sig.Verify(r, key.SecretKey, time.Now()); err != nil {
+		return err
+	}
+	uid := p.owner.smap.Get().UUID
+	if err := key.CheckPermissions(uid, bck, perms); err != nil {
+		return s3compat.NewAccessDenied(err)
+	}
+	return nil
+}
@@ -115,7 +115,7 @@ func (p *proxyrunner) broadcastDownloadAdminRequest(method, path string, msg *do
 		}
 		body := cmn.MustMarshal(stResp)
 		return body, http.StatusOK, nil
-	case http.MethodDelete:
+	case http.MethodDelete, http.MethodPut:
 		res := validResponses[0]
 		return res.bytes, res.status, res.err
 	default:
@@ -154,20 +154,21 @@ func (p *proxyrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	switch r.Method {
-	case http.MethodGet, http.MethodDelete:
+	case http.MethodGet, http.MethodDelete, http.MethodPut:
 		p.httpDownloadAdmin(w, r)
 	case http.MethodPost:
 		p.httpDownloadPost(w, r)
 	default:
-		s := fmt.Sprintf("invalid method %s for /download path; expected one of %s, %s, %s",
-			r.Method, http.MethodGet, http.MethodDelete, http.MethodPost)
+		s := fmt.Sprintf("invalid method %s for /download path; expected one of %s, %s, %s, %s",
+			r.Method, http.MethodGet, http.MethodDelete, http.MethodPut, http.MethodPost)
 		cmn.InvalidHandlerWithMsg(w, r, s)
 	}
 }
 
-// httpDownloadAdmin is meant for aborting, removing and getting status updates for downloads.
+// httpDownloadAdmin is meant for aborting, removing, pausing, resuming and getting status updates for downloads.
 // GET /v1/download?id=...
 // DELETE /v1/download/{abort, remove}?id=...
+// PUT /v1/download/{pause, resume}?id=...
 func (p *proxyrunner) httpDownloadAdmin(w http.ResponseWriter, r *http.Request) {
 	payload := &downloader.DlAdminBody{}
 	if !p.ClusterStarted() {
@@ -177,7 +178,7 @@ func (p *proxyrunner) httpDownloadAdmin(w http.ResponseWriter, r *http.Request)
 	if err := cmn.ReadJSON(w, r, &payload); err != nil {
 		return
 	}
-	if err := payload.Validate(r.Method == http.MethodDelete); err != nil {
+	if err := payload.Validate(r.Method != http.MethodGet); err != nil {
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
@@ -196,6 +197,20 @@ func (p *proxyrunner) httpDownloadAdmin(w http.ResponseWriter, r *http.Request)
 			return
 		}
 	}
+	if r.Method == http.MethodPut {
+		items, err := cmn.MatchRESTItems(r.URL.Path, 1, false, cmn.Version, cmn.Download)
+		if err != nil {
+			cmn.InvalidHandlerWithMsg(w, r, err.Error())
+			return
+		}
+
+		if items[0] != cmn.Pause && items[0] != cmn.Resume {
+			s := fmt.Sprintf("Invalid action for PUT request: %s (expected either %s or %s).",
+				items[0], cmn.Pause, cmn.Resume)
+			cmn.InvalidHandlerWithMsg(w, r, s)
+			return
+		}
+	}
 
 	if glog.FastV(4, glog.SmoduleAIS) {
 		glog.Infof("httpDownloadAdmin payload %v", payload)
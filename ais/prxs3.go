@@ -5,7 +5,6 @@
 package ais
 
 import (
-	"encoding/xml"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -29,6 +28,10 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		return
 	}
+	if r.Body, err = s3compat.NewPayloadReader(r); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
 	// TODO: Fix the hack, https://github.com/tensorflow/tensorflow/issues/41798
 	cmn.ReparseQuery(r)
 
@@ -46,7 +49,7 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		if len(apiItems) == 0 {
 			// nothing  - list all the buckets
-			p.bckNamesToS3(w)
+			p.bckNamesToS3(w, r)
 			return
 		}
 		if len(apiItems) == 1 {
@@ -56,6 +59,14 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 				p.getBckVersioningS3(w, r, apiItems[0])
 				return
 			}
+			if _, versions := q[s3compat.URLParamVersions]; versions {
+				p.listVersionsS3(w, r, apiItems[0])
+				return
+			}
+			if _, lifecycle := q[s3compat.URLParamLifecycle]; lifecycle {
+				p.getBckLifecycleS3(w, r, apiItems[0])
+				return
+			}
 			// only bucket name - list objects in the bucket
 			p.bckListS3(w, r, apiItems[0])
 			return
@@ -74,13 +85,22 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 				p.putBckVersioningS3(w, r, apiItems[0])
 				return
 			}
+			if _, lifecycle := q[s3compat.URLParamLifecycle]; lifecycle {
+				p.putBckLifecycleS3(w, r, apiItems[0])
+				return
+			}
 			p.putBckS3(w, r, apiItems[0])
 			return
 		}
 		p.putObjS3(w, r, apiItems)
 	case http.MethodPost:
-		if len(apiItems) != 1 {
-			p.invalmsghdlr(w, r, "bucket name expected")
+		if len(apiItems) == 0 {
+			p.invalmsghdlr(w, r, "object or bucket name required")
+			return
+		}
+		if len(apiItems) > 1 {
+			// multipart upload
+			p.postObjS3(w, r, apiItems)
 			return
 		}
 		q := r.URL.Query()
@@ -101,6 +121,10 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 				p.delMultipleObjs(w, r, apiItems[0])
 				return
 			}
+			if _, lifecycle := q[s3compat.URLParamLifecycle]; lifecycle {
+				p.delBckLifecycleS3(w, r, apiItems[0])
+				return
+			}
 			p.delBckS3(w, r, apiItems[0])
 			return
 		}
@@ -111,7 +135,11 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 }
 
 // GET s3/
-func (p *proxyrunner) bckNamesToS3(w http.ResponseWriter) {
+func (p *proxyrunner) bckNamesToS3(w http.ResponseWriter, r *http.Request) {
+	if err := p.checkS3Permissions(r, nil, cmn.AccessBckLIST); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
 	bmd := p.owner.bmd.get()
 	query := cmn.QueryBcks{Provider: cmn.ProviderAIS}
 	cp := &query.Provider
@@ -136,6 +164,10 @@ func (p *proxyrunner) putBckS3(w http.ResponseWriter, r *http.Request, bucket st
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if err := p.checkS3Permissions(r, &bck.Bck, cmn.AccessBckCREATE); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
 	msg := cmn.ActionMsg{Action: cmn.ActCreateLB}
 	if p.forwardCP(w, r, &msg, bucket) {
 		return
@@ -162,6 +194,10 @@ func (p *proxyrunner) delBckS3(w http.ResponseWriter, r *http.Request, bucket st
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
+	if err := p.checkS3Permissions(r, &bck.Bck, cmn.AccessBckDELETE); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
 	if p.forwardCP(w, r, &msg, bucket) {
 		return
 	}
@@ -188,10 +224,13 @@ func (p *proxyrunner) delMultipleObjs(w http.ResponseWriter, r *http.Request, bu
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
-	decoder := xml.NewDecoder(r.Body)
+	if err := p.checkS3Permissions(r, &bck.Bck, cmn.AccessObjDELETE); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
 	objList := &s3compat.Delete{}
-	if err := decoder.Decode(objList); err != nil {
-		p.invalmsghdlr(w, r, err.Error())
+	if err := s3compat.DecodeXML(r.Body, objList); err != nil {
+		p.invalS3hdlr(w, r, err)
 		return
 	}
 	if len(objList.Object) == 0 {
@@ -227,6 +266,10 @@ func (p *proxyrunner) headBckS3(w http.ResponseWriter, r *http.Request, bucket s
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
+	if err := p.checkS3Permissions(r, &bck.Bck, cmn.AccessBckHEAD); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
 	// From AWS docs:
 	// This operation is useful to determine if a bucket exists and you have
 	// permission to access it. The operation returns a 200 OK if the bucket
@@ -246,9 +289,16 @@ func (p *proxyrunner) bckListS3(w http.ResponseWriter, r *http.Request, bucket s
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
-	smsg := cmn.SelectMsg{TimeFormat: time.RFC3339}
+	if err := p.checkS3Permissions(r, &bck.Bck, cmn.AccessObjLIST); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
+	var (
+		q    = r.URL.Query()
+		smsg = cmn.SelectMsg{TimeFormat: time.RFC3339}
+	)
 	smsg.AddProps(cmn.GetPropsSize, cmn.GetPropsChecksum, cmn.GetPropsAtime, cmn.GetPropsVersion)
-	s3compat.FillMsgFromS3Query(r.URL.Query(), &smsg)
+	s3compat.FillMsgFromS3Query(q, &smsg)
 
 	objList, err := p.listObjectsAIS(bck, smsg)
 	if err != nil {
@@ -256,8 +306,38 @@ func (p *proxyrunner) bckListS3(w http.ResponseWriter, r *http.Request, bucket s
 		return
 	}
 
-	resp := s3compat.NewListObjectResult()
-	resp.ContinuationToken = smsg.ContinuationToken
+	resp := s3compat.NewListObjectResult(q)
+	resp.FillFromAisBckList(objList)
+	b := resp.MustMarshal()
+	w.Header().Set(cmn.HeaderContentType, cmn.ContentXML)
+	w.Write(b)
+}
+
+// GET s3/bckName?versions
+func (p *proxyrunner) listVersionsS3(w http.ResponseWriter, r *http.Request, bucket string) {
+	bck := cluster.NewBck(bucket, cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(p.owner.bmd, nil); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if err := p.checkS3Permissions(r, &bck.Bck, cmn.AccessObjLIST); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
+	var (
+		q    = r.URL.Query()
+		smsg = cmn.SelectMsg{TimeFormat: time.RFC3339}
+	)
+	smsg.AddProps(cmn.GetPropsSize, cmn.GetPropsChecksum, cmn.GetPropsAtime, cmn.GetPropsVersion)
+	s3compat.FillMsgFromS3VersionsQuery(q, &smsg)
+
+	objList, err := p.listObjectsAIS(bck, smsg)
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+
+	resp := s3compat.NewListVersionsResult(q)
 	resp.FillFromAisBckList(objList)
 	b := resp.MustMarshal()
 	w.Header().Set(cmn.HeaderContentType, cmn.ContentXML)
@@ -283,6 +363,10 @@ func (p *proxyrunner) copyObjS3(w http.ResponseWriter, r *http.Request, items []
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
+	if err := p.checkS3Permissions(r, &bckSrc.Bck, cmn.AccessGET); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
 	bckDst := cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
 	if err := bckDst.Init(p.owner.bmd, nil); err != nil {
 		p.invalmsghdlr(w, r, err.Error())
@@ -301,6 +385,10 @@ func (p *proxyrunner) copyObjS3(w http.ResponseWriter, r *http.Request, items []
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
+	if err = p.checkS3Permissions(r, &bckDst.Bck, cmn.AccessPUT); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
 	objName := strings.Trim(parts[1], "/")
 	si, err = cluster.HrwTarget(bckSrc.MakeUname(objName), &smap.Smap)
 	if err != nil {
@@ -344,6 +432,10 @@ func (p *proxyrunner) directPutObjS3(w http.ResponseWriter, r *http.Request, ite
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
+	if err = p.checkS3Permissions(r, &bck.Bck, cmn.AccessPUT); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
 	objName := path.Join(items[1:]...)
 	si, err = cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
 	if err != nil {
@@ -366,6 +458,36 @@ func (p *proxyrunner) putObjS3(w http.ResponseWriter, r *http.Request, items []s
 	p.copyObjS3(w, r, items)
 }
 
+// POST s3/bckName/objName?uploads|uploadId=<id> - start or complete multipart upload
+func (p *proxyrunner) postObjS3(w http.ResponseWriter, r *http.Request, items []string) {
+	started := time.Now()
+	bck := cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(p.owner.bmd, nil); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if err := bck.Allow(cmn.AccessPUT); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err := p.checkS3Permissions(r, &bck.Bck, cmn.AccessPUT); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
+	objName := path.Join(items[1:]...)
+	smap := p.owner.smap.get()
+	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("AISS3: %s %s/%s => %s", r.Method, bck, objName, si)
+	}
+	redirectURL := p.redirectURL(r, si, started, cmn.NetworkIntraData)
+	s3Redirect(w, redirectURL, bck.Name)
+}
+
 // GET s3/<bucket-name/<object-name>[?uuid=<etl-uuid>]
 func (p *proxyrunner) getObjS3(w http.ResponseWriter, r *http.Request, items []string) {
 	started := time.Now()
@@ -387,6 +509,10 @@ func (p *proxyrunner) getObjS3(w http.ResponseWriter, r *http.Request, items []s
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
+	if err = p.checkS3Permissions(r, &bck.Bck, cmn.AccessGET); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
 	objName := path.Join(items[1:]...)
 
 	si, err = cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
@@ -417,6 +543,10 @@ func (p *proxyrunner) headObjS3(w http.ResponseWriter, r *http.Request, items []
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
+	if err := p.checkS3Permissions(r, &bck.Bck, cmn.AccessObjHEAD); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
 	smap := p.owner.smap.get()
 	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
 	if err != nil {
@@ -451,6 +581,10 @@ func (p *proxyrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []s
 		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
 		return
 	}
+	if err = p.checkS3Permissions(r, &bck.Bck, cmn.AccessObjDELETE); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
 	objName := path.Join(items[1:]...)
 	si, err = cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
 	if err != nil {
@@ -471,6 +605,10 @@ func (p *proxyrunner) getBckVersioningS3(w http.ResponseWriter, r *http.Request,
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if err := p.checkS3Permissions(r, &bck.Bck, cmn.AccessBckHEAD); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
 	resp := s3compat.NewVersioningConfiguration(bck.Props.Versioning.Enabled)
 	b := resp.MustMarshal()
 	w.Header().Set(cmn.HeaderContentType, cmn.ContentXML)
@@ -493,18 +631,104 @@ func (p *proxyrunner) putBckVersioningS3(w http.ResponseWriter, r *http.Request,
 		p.invalmsghdlr(w, r, "bucket does not exists", http.StatusNotFound)
 		return
 	}
-	decoder := xml.NewDecoder(r.Body)
+	if err := p.checkS3Permissions(r, &bck.Bck, cmn.AccessPATCH); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
 	defer cmn.Close(r.Body)
 	vconf := &s3compat.VersioningConfiguration{}
-	if err := decoder.Decode(vconf); err != nil {
-		p.invalmsghdlr(w, r, err.Error())
+	if err := s3compat.DecodeXML(r.Body, vconf); err != nil {
+		p.invalS3hdlr(w, r, err)
 		return
 	}
 	enabled := vconf.Enabled()
 	propsToUpdate := cmn.BucketPropsToUpdate{
 		Versioning: &cmn.VersionConfToUpdate{Enabled: &enabled},
 	}
+	if !enabled {
+		// suspending versioning stops retaining prior versions as well
+		var (
+			maxHistory  int
+			historyTime string
+		)
+		propsToUpdate.Versioning.MaxHistory = &maxHistory
+		propsToUpdate.Versioning.HistoryTimeStr = &historyTime
+	}
 	if _, err := p.setBucketProps(w, r, msg, bck, propsToUpdate); err != nil {
 		p.invalmsghdlr(w, r, err.Error())
 	}
 }
+
+// GET s3/bk-name?lifecycle
+func (p *proxyrunner) getBckLifecycleS3(w http.ResponseWriter, r *http.Request, bucket string) {
+	bck := cluster.NewBck(bucket, cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(p.owner.bmd, nil); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if err := p.checkS3Permissions(r, &bck.Bck, cmn.AccessBckHEAD); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
+	if len(bck.Props.Lifecycle.Rules) == 0 {
+		p.invalmsghdlr(w, r, s3compat.ErrNoLifecycle.Error(), http.StatusNotFound)
+		return
+	}
+	resp := s3compat.NewLifecycleConfiguration(&bck.Props.Lifecycle)
+	w.Header().Set(cmn.HeaderContentType, cmn.ContentXML)
+	w.Write(resp.MustMarshal())
+}
+
+// PUT s3/bk-name?lifecycle
+func (p *proxyrunner) putBckLifecycleS3(w http.ResponseWriter, r *http.Request, bucket string) {
+	// forward as is (before reading the body): the primary needs the original
+	// body and headers to verify the signature
+	if p.forwardCP(w, r, nil, "s3 put lifecycle "+bucket) {
+		return
+	}
+	lc := &s3compat.LifecycleConfiguration{}
+	if err := s3compat.DecodeXML(r.Body, lc); err != nil {
+		cmn.Close(r.Body)
+		p.invalS3hdlr(w, r, err)
+		return
+	}
+	cmn.Close(r.Body)
+	rules, err := lc.AISRules()
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	p.setBckLifecycleS3(w, r, bucket, rules)
+}
+
+// DELETE s3/bk-name?lifecycle
+func (p *proxyrunner) delBckLifecycleS3(w http.ResponseWriter, r *http.Request, bucket string) {
+	if p.forwardCP(w, r, nil, "s3 delete lifecycle "+bucket) {
+		return
+	}
+	if p.setBckLifecycleS3(w, r, bucket, nil) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (p *proxyrunner) setBckLifecycleS3(w http.ResponseWriter, r *http.Request, bucket string,
+	rules []cmn.LifecycleRule) (ok bool) {
+	msg := &cmn.ActionMsg{Action: cmn.ActSetBprops}
+	bck := cluster.NewBck(bucket, cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(p.owner.bmd, nil); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if err := p.checkS3Permissions(r, &bck.Bck, cmn.AccessPATCH); err != nil {
+		p.invalS3hdlr(w, r, err)
+		return
+	}
+	propsToUpdate := cmn.BucketPropsToUpdate{
+		Lifecycle: &cmn.LifecycleConfToUpdate{Rules: &rules},
+	}
+	if _, err := p.setBucketProps(w, r, msg, bck, propsToUpdate); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	return true
+}
@@ -0,0 +1,174 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/jsp"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// S3 keys: access/secret key pairs issued by AuthN along with the permissions
+// of their users. AuthN pushes the entire list to a proxy of each registered
+// cluster whenever it changes (see `httpTokenPut`); the primary then distributes
+// it to all proxies via metasync. Every proxy persists its replica, so that
+// S3 requests can be authenticated after restart without AuthN.
+
+const s3KeysFname = ".ais.s3keys" // S3 keys persistent file basename
+
+type (
+	// s3KeysMD is revs (see metasync) which is distributed by primary proxy
+	// to all nodes (targets ignore it)
+	s3KeysMD struct {
+		cmn.AuthS3KeyList
+		Version int64 `json:"version,string"`
+	}
+	s3KeyOwner struct {
+		sync.Mutex
+		keys atomic.Pointer
+	}
+)
+
+// interface guard
+var _ revs = &s3KeysMD{}
+
+func (k *s3KeysMD) tag() string     { return revsS3KeysTag }
+func (k *s3KeysMD) version() int64  { return k.Version }
+func (k *s3KeysMD) marshal() []byte { return cmn.MustMarshal(k) }
+
+func (k *s3KeysMD) String() string {
+	if k == nil {
+		return "S3 keys <nil>"
+	}
+	return fmt.Sprintf("S3 keys v%d(%d)", k.Version, len(k.Keys))
+}
+
+//////////////////
+// s3KeyOwner   //
+//////////////////
+
+func newS3KeyOwner() *s3KeyOwner {
+	ko := &s3KeyOwner{}
+	ko.put(&s3KeysMD{AuthS3KeyList: cmn.AuthS3KeyList{Keys: make(map[string]*cmn.AuthS3Key)}})
+	return ko
+}
+
+func (ko *s3KeyOwner) persist(keys *s3KeysMD) {
+	pathName := filepath.Join(cmn.GCO.Get().Confdir, s3KeysFname)
+	if err := jsp.Save(pathName, keys, jsp.CCSign()); err != nil {
+		glog.Errorf("error writing S3 keys to %s: %v", pathName, err)
+	}
+}
+
+func (ko *s3KeyOwner) load() {
+	keys := &s3KeysMD{}
+	_, err := jsp.Load(filepath.Join(cmn.GCO.Get().Confdir, s3KeysFname), keys, jsp.CCSign())
+	if err == nil {
+		if keys.Keys == nil {
+			keys.Keys = make(map[string]*cmn.AuthS3Key)
+		}
+		ko.put(keys)
+		return
+	}
+	if !os.IsNotExist(err) {
+		glog.Errorf("failed to load S3 keys: %v", err)
+	}
+}
+
+func (ko *s3KeyOwner) put(keys *s3KeysMD) { ko.keys.Store(unsafe.Pointer(keys)) }
+func (ko *s3KeyOwner) get() *s3KeysMD     { return (*s3KeysMD)(ko.keys.Load()) }
+
+func (ko *s3KeyOwner) lookup(accessKey string) *cmn.AuthS3Key { return ko.get().Keys[accessKey] }
+
+// primary: replaces the keys with the list received from AuthN unless the list
+// is older than the current one (AuthN sends it asynchronously)
+func (ko *s3KeyOwner) modify(list *cmn.AuthS3KeyList) (*s3KeysMD, bool) {
+	ko.Lock()
+	defer ko.Unlock()
+	keys := ko.get()
+	if !list.Created.After(keys.Created) {
+		return keys, false
+	}
+	if list.Keys == nil {
+		list.Keys = make(map[string]*cmn.AuthS3Key)
+	}
+	clone := &s3KeysMD{AuthS3KeyList: *list, Version: keys.Version + 1}
+	ko.persist(clone)
+	ko.put(clone)
+	return clone, true
+}
+
+// non-primary proxies
+func (ko *s3KeyOwner) receive(newKeys *s3KeysMD) (err error) {
+	ko.Lock()
+	defer ko.Unlock()
+	keys := ko.get()
+	if newKeys.version() <= keys.version() {
+		if newKeys.version() < keys.version() {
+			err = fmt.Errorf("attempt to downgrade %s to %s", keys, newKeys)
+		}
+		return
+	}
+	if newKeys.Keys == nil {
+		newKeys.Keys = make(map[string]*cmn.AuthS3Key)
+	}
+	ko.persist(newKeys)
+	ko.put(newKeys)
+	return
+}
+
+func (p *proxyrunner) extractS3Keys(payload msPayload) (newKeys *s3KeysMD, err error) {
+	keysValue, ok := payload[revsS3KeysTag]
+	if !ok {
+		return
+	}
+	newKeys = &s3KeysMD{}
+	if err1 := jsoniter.Unmarshal(keysValue, newKeys); err1 != nil {
+		err = fmt.Errorf("%s: failed to unmarshal new S3 keys, err: %v", p.si, err1)
+		newKeys = nil
+	}
+	return
+}
+
+// PUT /v1/tokens/s3keys (AuthN)
+func (p *proxyrunner) httpTokenPut(w http.ResponseWriter, r *http.Request) {
+	apiItems, err := p.checkRESTItems(w, r, 1, false, cmn.Version, cmn.Tokens)
+	if err != nil {
+		return
+	}
+	if apiItems[0] != cmn.S3Keys {
+		p.invalmsghdlrf(w, r, "invalid path %q", r.URL.Path)
+		return
+	}
+	if p.forwardCP(w, r, nil, "update S3 keys") {
+		return
+	}
+	// unlike revoked tokens, the keys grant access - only AuthN can send them
+	token, err := p.validateToken(r.Header)
+	if err != nil || !token.IsAdmin {
+		p.invalmsghdlr(w, r, cmn.ErrNoPermissions.Error(), http.StatusUnauthorized)
+		return
+	}
+	list := &cmn.AuthS3KeyList{}
+	if err := cmn.ReadJSON(w, r, list); err != nil {
+		return
+	}
+	keys, updated := p.s3keys.modify(list)
+	if !updated {
+		return
+	}
+	glog.Infof("%s: updated %s", p.si, keys)
+	msg := p.newAisMsgStr(cmn.ActUpdateS3Keys, nil, nil)
+	_ = p.metasyncer.sync(revsPair{keys, msg})
+}
@@ -0,0 +1,372 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/jsp"
+	"github.com/NVIDIA/aistore/xaction"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Cluster-level scheduler: recurring jobs (see cmn.ScheduleJob) are kept in the
+// schedule metadata that the primary distributes to all proxies via metasync -
+// the same way it distributes BMD and RMD. Every proxy persists its replica so
+// that any of them can take over the scheduling upon primary failover.
+//
+// Only the primary runs the jobs. Once a minute it checks which jobs are due
+// and performs their actions by calling its own public API, exactly as an
+// external client would. The time of the last run and the history of the most
+// recent runs are stored in the (replicated) metadata as well; a run that was
+// missed (e.g., while the cluster was down or the primary was failing over)
+// gets executed once as soon as the new primary takes over.
+
+const (
+	schedFname      = ".ais.schedule" // schedule metadata persistent file basename
+	schedHousekeepT = time.Minute
+	schedMaxHistory = 256 // max number of runs kept in the history (cluster-wide)
+)
+
+type (
+	// schedMD is revs (see metasync) which is distributed by primary proxy to
+	// all nodes (targets ignore it) whenever a job is added, removed, or run.
+	schedMD struct {
+		cmn.Schedule
+	}
+	schedOwner struct {
+		sync.Mutex
+		sched   atomic.Pointer
+		running atomic.Bool // primary: jobs are being run
+	}
+	schedModifier struct {
+		pre   func(ctx *schedModifier, clone *schedMD) error
+		final func(ctx *schedModifier, clone *schedMD)
+
+		msg  *cmn.ActionMsg
+		job  *cmn.ScheduleJob
+		runs []cmn.ScheduleRun
+	}
+)
+
+// interface guard
+var _ revs = &schedMD{}
+
+func (s *schedMD) tag() string     { return revsSchedTag }
+func (s *schedMD) version() int64  { return s.Version }
+func (s *schedMD) marshal() []byte { return cmn.MustMarshal(s) }
+func (s *schedMD) clone() *schedMD {
+	dst := &schedMD{}
+	cmn.CopyStruct(dst, s)
+	dst.Jobs = make(map[string]*cmn.ScheduleJob, len(s.Jobs))
+	for name, job := range s.Jobs {
+		jobCopy := *job
+		dst.Jobs[name] = &jobCopy
+	}
+	dst.History = append([]cmn.ScheduleRun{}, s.History...)
+	return dst
+}
+
+func (s *schedMD) String() string {
+	if s == nil {
+		return "Schedule <nil>"
+	}
+	return fmt.Sprintf("Schedule v%d(%d)", s.Version, len(s.Jobs))
+}
+
+// returns jobs that must be run at `now` sorted by name
+func (s *schedMD) due(now time.Time) (jobs []*cmn.ScheduleJob) {
+	for _, job := range s.Jobs {
+		spec, err := cmn.ParseCron(job.Schedule)
+		if err != nil {
+			glog.Errorf("job %q: %v", job.Name, err) // validated when added - must never happen
+			continue
+		}
+		from := job.LastRun
+		if from.IsZero() {
+			from = job.Created
+		}
+		if next := spec.Next(from); !next.IsZero() && !next.After(now) {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return
+}
+
+//////////////////
+// schedOwner   //
+//////////////////
+
+func newSchedOwner() *schedOwner {
+	so := &schedOwner{}
+	so.put(&schedMD{Schedule: cmn.Schedule{Jobs: make(map[string]*cmn.ScheduleJob)}})
+	return so
+}
+
+func (so *schedOwner) persist(sched *schedMD) {
+	schedPathName := filepath.Join(cmn.GCO.Get().Confdir, schedFname)
+	if err := jsp.Save(schedPathName, sched, jsp.CCSign()); err != nil {
+		glog.Errorf("error writing schedule to %s: %v", schedPathName, err)
+	}
+}
+
+func (so *schedOwner) load() {
+	sched := &schedMD{}
+	_, err := jsp.Load(filepath.Join(cmn.GCO.Get().Confdir, schedFname), sched, jsp.CCSign())
+	if err == nil {
+		if sched.Jobs == nil {
+			sched.Jobs = make(map[string]*cmn.ScheduleJob)
+		}
+		so.put(sched)
+		return
+	}
+	if !os.IsNotExist(err) {
+		glog.Errorf("failed to load schedule: %v", err)
+	}
+}
+
+func (so *schedOwner) put(sched *schedMD) { so.sched.Store(unsafe.Pointer(sched)) }
+func (so *schedOwner) get() *schedMD      { return (*schedMD)(so.sched.Load()) }
+
+func (so *schedOwner) modify(ctx *schedModifier) (*schedMD, error) {
+	so.Lock()
+	clone := so.get().clone()
+	if err := ctx.pre(ctx, clone); err != nil {
+		so.Unlock()
+		return nil, err
+	}
+	clone.Version++
+	so.persist(clone)
+	so.put(clone)
+	so.Unlock()
+	if ctx.final != nil {
+		ctx.final(ctx, clone)
+	}
+	return clone, nil
+}
+
+// non-primary proxies
+func (so *schedOwner) receive(newSched *schedMD) (err error) {
+	so.Lock()
+	defer so.Unlock()
+	sched := so.get()
+	if newSched.version() <= sched.version() {
+		if newSched.version() < sched.version() {
+			err = fmt.Errorf("attempt to downgrade %s to %s", sched, newSched)
+		}
+		return
+	}
+	if newSched.Jobs == nil {
+		newSched.Jobs = make(map[string]*cmn.ScheduleJob)
+	}
+	so.persist(newSched)
+	so.put(newSched)
+	return
+}
+
+func (p *proxyrunner) extractSched(payload msPayload) (newSched *schedMD, msg *aisMsg, err error) {
+	if _, ok := payload[revsSchedTag]; !ok {
+		return
+	}
+	newSched, msg = &schedMD{}, &aisMsg{}
+	schedValue := payload[revsSchedTag]
+	if err1 := jsoniter.Unmarshal(schedValue, newSched); err1 != nil {
+		err = fmt.Errorf("%s: failed to unmarshal new schedule, value (%+v, %T), err: %v",
+			p.si, schedValue, schedValue, err1)
+		return
+	}
+	if msgValue, ok := payload[revsSchedTag+revsActionTag]; ok {
+		if err1 := jsoniter.Unmarshal(msgValue, msg); err1 != nil {
+			err = fmt.Errorf("%s: failed to unmarshal action message, value (%s), err: %v",
+				p.si, msgValue, err1)
+			return
+		}
+	}
+	return
+}
+
+func (p *proxyrunner) _syncSchedFinal(ctx *schedModifier, clone *schedMD) {
+	_ = p.metasyncer.sync(revsPair{clone, p.newAisMsg(ctx.msg, nil, nil)})
+}
+
+//////////////////////
+// managing jobs    //
+//////////////////////
+
+// PUT {action: cmn.ActAddSchedule | cmn.ActRemoveSchedule} /v1/cluster (primary)
+func (p *proxyrunner) updSchedule(w http.ResponseWriter, r *http.Request, msg *cmn.ActionMsg) {
+	ctx := &schedModifier{final: p._syncSchedFinal, msg: msg}
+	if msg.Action == cmn.ActAddSchedule {
+		job := &cmn.ScheduleJob{}
+		if err := cmn.MorphMarshal(msg.Value, job); err != nil {
+			p.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		if msg.Name != "" {
+			job.Name = msg.Name
+		}
+		if err := validateJob(job); err != nil {
+			p.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		job.Created, job.LastRun = time.Now(), time.Time{}
+		ctx.job, ctx.pre = job, p._addJobPre
+	} else {
+		ctx.job, ctx.pre = &cmn.ScheduleJob{Name: msg.Name}, p._rmJobPre
+	}
+	if _, err := p.sched.modify(ctx); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+	}
+}
+
+func (p *proxyrunner) _addJobPre(ctx *schedModifier, clone *schedMD) error {
+	clone.Jobs[ctx.job.Name] = ctx.job
+	return nil
+}
+
+func (p *proxyrunner) _rmJobPre(ctx *schedModifier, clone *schedMD) error {
+	if _, ok := clone.Jobs[ctx.job.Name]; !ok {
+		return fmt.Errorf("job %q does not exist", ctx.job.Name)
+	}
+	delete(clone.Jobs, ctx.job.Name)
+	return nil
+}
+
+func validateJob(job *cmn.ScheduleJob) error {
+	if job.Name == "" || strings.ContainsAny(job.Name, "/ \t") {
+		return fmt.Errorf("invalid job name %q", job.Name)
+	}
+	if _, err := cmn.ParseCron(job.Schedule); err != nil {
+		return fmt.Errorf("job %q: %v", job.Name, err)
+	}
+	if job.Bck.Name != "" {
+		if err := cmn.ValidateBckName(job.Bck.Name); err != nil {
+			return fmt.Errorf("job %q: %v", job.Name, err)
+		}
+	}
+	switch job.Action {
+	case cmn.ActCopyBucket, cmn.ActSyncBucket:
+		copyMsg := &cmn.CopyBckMsg{}
+		if err := cmn.MorphMarshal(job.Value, copyMsg); err != nil || copyMsg.BckTo.Name == "" {
+			return fmt.Errorf("job %q: %s requires destination bucket", job.Name, job.Action)
+		}
+	case cmn.ActPrefetch, cmn.ActECEncode:
+		if job.Value == nil {
+			return fmt.Errorf("job %q: %s requires value (see the corresponding API)", job.Name, job.Action)
+		}
+	default:
+		dtor, ok := xaction.XactsDtor[job.Action]
+		if !ok || !dtor.Startable {
+			return fmt.Errorf("job %q: cannot schedule %q", job.Name, job.Action)
+		}
+		if dtor.Type != xaction.XactTypeBck {
+			return nil
+		}
+	}
+	if job.Bck.Name == "" {
+		return fmt.Errorf("job %q: %s requires bucket", job.Name, job.Action)
+	}
+	return nil
+}
+
+//////////////////////
+// running jobs     //
+//////////////////////
+
+func (p *proxyrunner) schedHk() time.Duration {
+	now := time.Now()
+	if smap := p.owner.smap.get(); smap.isPrimary(p.si) && p.ClusterStarted() {
+		if jobs := p.sched.get().due(now); len(jobs) > 0 && p.sched.running.CAS(false, true) {
+			go p.runJobs(jobs)
+		}
+	}
+	// wake up at the beginning of the next minute
+	return now.Truncate(time.Minute).Add(time.Minute).Sub(now)
+}
+
+func (p *proxyrunner) runJobs(jobs []*cmn.ScheduleJob) {
+	defer p.sched.running.Store(false)
+	runs := make([]cmn.ScheduleRun, 0, len(jobs))
+	for _, job := range jobs {
+		run := cmn.ScheduleRun{Job: job.Name, Action: job.Action, Time: time.Now()}
+		xactID, err := p.runJob(job)
+		if err != nil {
+			run.Err = err.Error()
+			glog.Errorf("%s: job %q (%s) failed: %v", p.si, job.Name, job.Action, err)
+		} else {
+			run.XactID = xactID
+			glog.Infof("%s: job %q (%s) started, xaction %q", p.si, job.Name, job.Action, xactID)
+		}
+		runs = append(runs, run)
+	}
+	ctx := &schedModifier{
+		pre:   _runJobsPre,
+		final: p._syncSchedFinal,
+		msg:   &cmn.ActionMsg{Action: cmn.ActXactStart},
+		runs:  runs,
+	}
+	_, _ = p.sched.modify(ctx)
+}
+
+func _runJobsPre(ctx *schedModifier, clone *schedMD) error {
+	for _, run := range ctx.runs {
+		if job, ok := clone.Jobs[run.Job]; ok { // may have been removed in the meantime
+			job.LastRun = run.Time
+		}
+	}
+	clone.History = append(clone.History, ctx.runs...)
+	if l := len(clone.History); l > schedMaxHistory {
+		clone.History = clone.History[l-schedMaxHistory:]
+	}
+	return nil
+}
+
+// performs job's action via the public API of this (primary) proxy
+func (p *proxyrunner) runJob(job *cmn.ScheduleJob) (xactID string, err error) {
+	var (
+		method, path string
+		msg          cmn.ActionMsg
+		query        url.Values
+	)
+	if !job.Bck.IsEmpty() {
+		query = cmn.AddBckToQuery(nil, job.Bck)
+	}
+	switch job.Action {
+	case cmn.ActCopyBucket, cmn.ActSyncBucket, cmn.ActPrefetch, cmn.ActECEncode:
+		method, path = http.MethodPost, cmn.JoinWords(cmn.Version, cmn.Buckets, job.Bck.Name)
+		msg = cmn.ActionMsg{Action: job.Action, Value: job.Value}
+	default:
+		method, path = http.MethodPut, cmn.JoinWords(cmn.Version, cmn.Cluster)
+		msg = cmn.ActionMsg{Action: cmn.ActXactStart, Value: xaction.XactReqMsg{Kind: job.Action, Bck: job.Bck}}
+	}
+	res := p.call(callArgs{
+		si: p.si,
+		req: cmn.ReqArgs{
+			Method: method,
+			Base:   p.si.URL(cmn.NetworkPublic),
+			Path:   path,
+			Query:  query,
+			Body:   cmn.MustMarshal(msg),
+		},
+		timeout: cmn.GCO.Get().Timeout.MaxHostBusy,
+	})
+	if res.err != nil {
+		return "", res.err
+	}
+	return string(res.bytes), nil
+}
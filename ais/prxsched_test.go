@@ -0,0 +1,99 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schedule", func() {
+	var (
+		confdir string
+		created = time.Date(2020, time.September, 16, 10, 17, 0, 0, time.Local)
+	)
+
+	BeforeEach(func() {
+		var err error
+		confdir, err = ioutil.TempDir("", "schedule-test")
+		Expect(err).NotTo(HaveOccurred())
+		config := cmn.GCO.BeginUpdate()
+		config.Confdir = confdir
+		cmn.GCO.CommitUpdate(config)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(confdir)
+	})
+
+	It("should select due jobs", func() {
+		sched := newSchedOwner().get()
+		sched.Jobs["hourly"] = &cmn.ScheduleJob{Name: "hourly", Schedule: "@hourly", Created: created}
+		sched.Jobs["nightly"] = &cmn.ScheduleJob{Name: "nightly", Schedule: "0 3 * * *", Created: created}
+		sched.Jobs["ran"] = &cmn.ScheduleJob{
+			Name: "ran", Schedule: "@hourly", Created: created, LastRun: created.Add(time.Hour),
+		}
+
+		Expect(sched.due(created.Add(time.Minute))).To(BeEmpty())
+
+		due := sched.due(created.Add(time.Hour))
+		Expect(due).To(HaveLen(1))
+		Expect(due[0].Name).To(Equal("hourly"))
+
+		// missed runs are executed once
+		due = sched.due(created.Add(48 * time.Hour))
+		Expect(due).To(HaveLen(3))
+		Expect([]string{due[0].Name, due[1].Name, due[2].Name}).To(Equal([]string{"hourly", "nightly", "ran"}))
+	})
+
+	It("should persist, load, and refuse to downgrade", func() {
+		owner := newSchedOwner()
+		ctx := &schedModifier{
+			pre: func(ctx *schedModifier, clone *schedMD) error {
+				clone.Jobs[ctx.job.Name] = ctx.job
+				return nil
+			},
+			job: &cmn.ScheduleJob{Name: "lru", Schedule: "@daily", Action: cmn.ActLRU, Created: created},
+		}
+		sched, err := owner.modify(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sched.version()).To(BeEquivalentTo(1))
+
+		loaded := newSchedOwner()
+		loaded.load()
+		Expect(loaded.get().version()).To(BeEquivalentTo(1))
+		Expect(loaded.get().Jobs).To(HaveKey("lru"))
+
+		newer := sched.clone()
+		newer.Version++
+		delete(newer.Jobs, "lru")
+		Expect(loaded.receive(newer)).NotTo(HaveOccurred())
+		Expect(loaded.get().Jobs).To(BeEmpty())
+		Expect(loaded.receive(sched)).To(HaveOccurred())
+		Expect(loaded.get().version()).To(BeEquivalentTo(2))
+	})
+
+	It("should validate jobs", func() {
+		bck := cmn.Bck{Name: "bck", Provider: cmn.ProviderAIS}
+		Expect(validateJob(&cmn.ScheduleJob{Name: "a", Schedule: "@daily", Action: cmn.ActLRU})).To(Succeed())
+		Expect(validateJob(&cmn.ScheduleJob{
+			Name: "a", Schedule: "@daily", Action: cmn.ActCopyBucket, Bck: bck,
+			Value: &cmn.CopyBckMsg{BckTo: cmn.Bck{Name: "dst"}},
+		})).To(Succeed())
+
+		Expect(validateJob(&cmn.ScheduleJob{Name: "a b", Schedule: "@daily", Action: cmn.ActLRU})).NotTo(Succeed())
+		Expect(validateJob(&cmn.ScheduleJob{Name: "a", Schedule: "@sometimes", Action: cmn.ActLRU})).NotTo(Succeed())
+		Expect(validateJob(&cmn.ScheduleJob{Name: "a", Schedule: "@daily", Action: cmn.ActElection})).NotTo(Succeed())
+		Expect(validateJob(&cmn.ScheduleJob{Name: "a", Schedule: "@daily", Action: cmn.ActCopyBucket, Bck: bck})).
+			NotTo(Succeed())
+		Expect(validateJob(&cmn.ScheduleJob{Name: "a", Schedule: "@daily", Action: cmn.ActPrefetch, Bck: bck})).
+			NotTo(Succeed())
+	})
+})
@@ -827,6 +827,20 @@ func (p *proxyrunner) makeNprops(bck *cluster.Bck, propsToUpdate cmn.BucketProps
 		return
 	}
 
+	if nprops.SSE.Enabled && cfg.KeyProvider.Type == "" {
+		err = fmt.Errorf("%s: cannot enable server-side encryption for %s: key provider is not configured",
+			p.si, bck)
+		return
+	}
+
+	if nprops.Replicate.Enabled {
+		if _, ok := cfg.Cloud.ProviderConf(cmn.ProviderAIS); !ok {
+			err = fmt.Errorf("%s: cannot enable replication for %s: no remote ais clusters attached",
+				p.si, bck)
+			return
+		}
+	}
+
 	targetCnt := p.owner.smap.Get().CountTargets()
 	err = nprops.Validate(targetCnt)
 	return
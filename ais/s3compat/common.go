@@ -5,6 +5,9 @@
 package s3compat
 
 import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
 	"strings"
 
 	"github.com/NVIDIA/aistore/cmn"
@@ -20,13 +23,38 @@ const (
 	versioningEnabled   = "Enabled"
 	versioningDisabled  = "Suspended"
 
+	// list objects
+	URLParamListType          = "list-type"
+	URLParamMaxKeys           = "max-keys"
+	URLParamPrefix            = "prefix"
+	URLParamDelimiter         = "delimiter"
+	URLParamMarker            = "marker"
+	URLParamContinuationToken = "continuation-token"
+	URLParamStartAfter        = "start-after"
+	listTypeV2                = "2"
+	defaultMaxKeys            = 1000
+
+	// object versions
+	URLParamVersions  = "versions"
+	URLParamVersionID = "versionId"
+	URLParamKeyMarker = "key-marker"
+
+	// bucket lifecycle
+	URLParamLifecycle = "lifecycle"
+
+	// multipart upload
+	URLParamMptUploads  = "uploads"
+	URLParamMptUploadID = "uploadId"
+	URLParamMptPartNum  = "partNumber"
+
 	s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01"
 	// TODO: can it be omitted? // storageClass = "STANDARD"
 
 	// Headers
-	headerETag    = "ETag"
-	headerVersion = "x-amz-version-id"
-	HeaderObjSrc  = "x-amz-copy-source"
+	HeaderETag       = "ETag"
+	headerVersion    = "x-amz-version-id"
+	HeaderObjSrc     = "x-amz-copy-source"
+	HeaderContentMD5 = "Content-MD5"
 
 	headerAtime = "Last-Modified"
 )
@@ -53,3 +81,13 @@ func MakeRedirectBody(newPath, bucket string) string {
 		"<Bucket>" + bucket + "</Bucket></Error>"
 	return body
 }
+
+// DecodeXML reads the request body in its entirety (so that the payload is
+// verified against its signed hash) and decodes it into `v`.
+func DecodeXML(body io.Reader, v interface{}) error {
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(b, v)
+}
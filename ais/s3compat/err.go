@@ -0,0 +1,65 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// S3 error codes, see https://docs.aws.amazon.com/AmazonS3/latest/API/ErrorResponses.html
+const (
+	errCodeAccessDenied      = "AccessDenied"
+	errCodeInvalidAccessKey  = "InvalidAccessKeyId"
+	errCodeSignatureMismatch = "SignatureDoesNotMatch"
+	errCodeTimeSkewed        = "RequestTimeTooSkewed"
+	errCodeSHA256Mismatch    = "XAmzContentSHA256Mismatch"
+	errCodeInvalidArgument   = "InvalidArgument"
+	errCodeNotImplemented    = "NotImplemented"
+)
+
+type (
+	// Error is S3 error response (the error code is what S3 clients act upon).
+	Error struct {
+		XMLName  xml.Name `xml:"Error"`
+		Code     string   `xml:"Code"`
+		Message  string   `xml:"Message"`
+		Resource string   `xml:"Resource,omitempty"`
+		Status   int      `xml:"-"`
+	}
+)
+
+func (e *Error) Error() string { return e.Code + ": " + e.Message }
+
+func NewAccessDenied(err error) *Error {
+	return &Error{Code: errCodeAccessDenied, Message: err.Error(), Status: http.StatusForbidden}
+}
+
+func NewInvalidAccessKey(err error) *Error {
+	return &Error{Code: errCodeInvalidAccessKey, Message: err.Error(), Status: http.StatusForbidden}
+}
+
+// AsError returns S3 error if `err` is (or wraps) one.
+func AsError(err error) (*Error, bool) {
+	s3Err := &Error{}
+	if errors.As(err, &s3Err) {
+		return s3Err, true
+	}
+	return nil, false
+}
+
+// WriteErr writes S3 error response.
+func WriteErr(w http.ResponseWriter, r *http.Request, e *Error) {
+	resp := *e
+	resp.Resource = r.URL.Path
+	b, err := xml.Marshal(&resp)
+	cmn.AssertNoErr(err)
+	w.Header().Set(cmn.HeaderContentType, cmn.ContentXML)
+	w.WriteHeader(e.Status)
+	w.Write([]byte(xml.Header + string(b)))
+}
@@ -0,0 +1,139 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Bucket lifecycle configuration: only the subset of S3 rules that maps onto
+// AIS bucket lifecycle (see cmn.LifecycleRule) is supported, namely:
+// expiration in days and aborting incomplete multipart uploads (in AIS, the
+// latter also applies to in-progress appends).
+
+const (
+	lifecycleEnabled  = "Enabled"
+	lifecycleDisabled = "Disabled"
+)
+
+type (
+	LifecycleConfiguration struct {
+		XMLName xml.Name         `xml:"LifecycleConfiguration"`
+		Ns      string           `xml:"xmlns,attr,omitempty"`
+		Rules   []*LifecycleRule `xml:"Rule"`
+	}
+	LifecycleRule struct {
+		ID     string           `xml:"ID"`
+		Prefix *string          `xml:"Prefix,omitempty"` // legacy (prior to Filter)
+		Filter *LifecycleFilter `xml:"Filter,omitempty"`
+		Status string           `xml:"Status"`
+
+		Expiration  *LifecycleExpiration  `xml:"Expiration,omitempty"`
+		AbortUpload *LifecycleAbortUpload `xml:"AbortIncompleteMultipartUpload,omitempty"`
+
+		// not supported
+		Transitions                 []*struct{} `xml:"Transition,omitempty"`
+		NoncurrentVersionExpiration *struct{}   `xml:"NoncurrentVersionExpiration,omitempty"`
+		NoncurrentVersionTransition []*struct{} `xml:"NoncurrentVersionTransition,omitempty"`
+	}
+	LifecycleFilter struct {
+		Prefix string    `xml:"Prefix"`
+		Tag    *struct{} `xml:"Tag,omitempty"` // not supported
+		And    *struct{} `xml:"And,omitempty"` // ditto
+	}
+	LifecycleExpiration struct {
+		Days         int     `xml:"Days,omitempty"`
+		Date         string  `xml:"Date,omitempty"`                      // not supported
+		DeleteMarker *string `xml:"ExpiredObjectDeleteMarker,omitempty"` // ditto
+	}
+	LifecycleAbortUpload struct {
+		DaysAfterInitiation int `xml:"DaysAfterInitiation"`
+	}
+)
+
+var ErrNoLifecycle = errors.New("the lifecycle configuration does not exist")
+
+func NewLifecycleConfiguration(conf *cmn.LifecycleConf) *LifecycleConfiguration {
+	lc := &LifecycleConfiguration{Ns: s3Namespace, Rules: make([]*LifecycleRule, 0, len(conf.Rules))}
+	for i := range conf.Rules {
+		var (
+			rule = &conf.Rules[i]
+			lr   = &LifecycleRule{ID: rule.ID, Filter: &LifecycleFilter{Prefix: rule.Prefix}}
+		)
+		lr.Status = lifecycleDisabled
+		if rule.Enabled {
+			lr.Status = lifecycleEnabled
+		}
+		if rule.ExpireDays > 0 {
+			lr.Expiration = &LifecycleExpiration{Days: rule.ExpireDays}
+		}
+		if rule.AbortAppendDays > 0 {
+			lr.AbortUpload = &LifecycleAbortUpload{DaysAfterInitiation: rule.AbortAppendDays}
+		}
+		lc.Rules = append(lc.Rules, lr)
+	}
+	return lc
+}
+
+func (lc *LifecycleConfiguration) MustMarshal() []byte {
+	b, err := xml.Marshal(lc)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
+
+// AISRules converts S3 lifecycle configuration to AIS lifecycle rules.
+func (lc *LifecycleConfiguration) AISRules() ([]cmn.LifecycleRule, error) {
+	rules := make([]cmn.LifecycleRule, 0, len(lc.Rules))
+	for _, lr := range lc.Rules {
+		rule := cmn.LifecycleRule{ID: lr.ID}
+		switch lr.Status {
+		case lifecycleEnabled:
+			rule.Enabled = true
+		case lifecycleDisabled:
+		default:
+			return nil, fmt.Errorf("lifecycle rule %q: invalid status %q", lr.ID, lr.Status)
+		}
+		if len(lr.Transitions) > 0 || len(lr.NoncurrentVersionTransition) > 0 ||
+			lr.NoncurrentVersionExpiration != nil {
+			return nil, fmt.Errorf("lifecycle rule %q: only Expiration and AbortIncompleteMultipartUpload "+
+				"actions are supported", lr.ID)
+		}
+		if lr.Filter != nil {
+			if lr.Filter.Tag != nil || lr.Filter.And != nil {
+				return nil, fmt.Errorf("lifecycle rule %q: only prefix filter is supported", lr.ID)
+			}
+			rule.Prefix = lr.Filter.Prefix
+		} else if lr.Prefix != nil {
+			rule.Prefix = *lr.Prefix
+		}
+		if lr.Expiration != nil {
+			if lr.Expiration.Date != "" || lr.Expiration.DeleteMarker != nil {
+				return nil, fmt.Errorf("lifecycle rule %q: only expiration in Days is supported", lr.ID)
+			}
+			if lr.Expiration.Days <= 0 {
+				return nil, fmt.Errorf("lifecycle rule %q: invalid expiration days %d", lr.ID, lr.Expiration.Days)
+			}
+			rule.ExpireDays = lr.Expiration.Days
+		}
+		if lr.AbortUpload != nil {
+			if lr.AbortUpload.DaysAfterInitiation <= 0 {
+				return nil, fmt.Errorf("lifecycle rule %q: invalid days after initiation %d",
+					lr.ID, lr.AbortUpload.DaysAfterInitiation)
+			}
+			rule.AbortAppendDays = lr.AbortUpload.DaysAfterInitiation
+		}
+		// a rule without (supported) actions would be accepted and never executed
+		if rule.ExpireDays == 0 && rule.AbortAppendDays == 0 {
+			return nil, fmt.Errorf("lifecycle rule %q: Expiration or AbortIncompleteMultipartUpload action "+
+				"is required", lr.ID)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,73 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestLifecycleConfiguration(t *testing.T) {
+	const body = `<LifecycleConfiguration>
+  <Rule>
+    <ID>logs</ID>
+    <Filter><Prefix>logs/</Prefix></Filter>
+    <Status>Enabled</Status>
+    <Expiration><Days>30</Days></Expiration>
+  </Rule>
+  <Rule>
+    <ID>uploads</ID>
+    <Prefix></Prefix>
+    <Status>Disabled</Status>
+    <AbortIncompleteMultipartUpload><DaysAfterInitiation>7</DaysAfterInitiation></AbortIncompleteMultipartUpload>
+  </Rule>
+</LifecycleConfiguration>`
+
+	lc := &LifecycleConfiguration{}
+	tassert.CheckFatal(t, xml.Unmarshal([]byte(body), lc))
+	rules, err := lc.AISRules()
+	tassert.CheckFatal(t, err)
+	expected := []cmn.LifecycleRule{
+		{ID: "logs", Prefix: "logs/", Enabled: true, ExpireDays: 30},
+		{ID: "uploads", AbortAppendDays: 7},
+	}
+	tassert.Fatalf(t, len(rules) == len(expected), "expected %d rules, got %d", len(expected), len(rules))
+	for i := range expected {
+		tassert.Errorf(t, rules[i] == expected[i], "expected %v, got %v", expected[i], rules[i])
+	}
+
+	// round trip
+	lc = &LifecycleConfiguration{}
+	tassert.CheckFatal(t, xml.Unmarshal(NewLifecycleConfiguration(&cmn.LifecycleConf{Rules: rules}).MustMarshal(), lc))
+	again, err := lc.AISRules()
+	tassert.CheckFatal(t, err)
+	for i := range expected {
+		tassert.Errorf(t, again[i] == expected[i], "expected %v, got %v", expected[i], again[i])
+	}
+}
+
+func TestLifecycleConfigurationUnsupported(t *testing.T) {
+	for _, rule := range []string{
+		`<Rule><ID>a</ID><Status>Enabled</Status><Transition><Days>1</Days></Transition></Rule>`,
+		`<Rule><ID>b</ID><Status>Enabled</Status><Expiration><Date>2020-01-01T00:00:00Z</Date></Expiration></Rule>`,
+		`<Rule><ID>c</ID><Status>Enabled</Status><Filter><Tag><Key>k</Key></Tag></Filter></Rule>`,
+		`<Rule><ID>d</ID><Status>Maybe</Status></Rule>`,
+		`<Rule><ID>e</ID><Status>Enabled</Status><Expiration><Days>1</Days></Expiration>` +
+			`<Transition><Days>1</Days><StorageClass>GLACIER</StorageClass></Transition></Rule>`,
+		`<Rule><ID>f</ID><Status>Enabled</Status><Expiration><Days>1</Days></Expiration>` +
+			`<NoncurrentVersionTransition><NoncurrentDays>1</NoncurrentDays></NoncurrentVersionTransition></Rule>`,
+		`<Rule><ID>g</ID><Status>Enabled</Status><Expiration><ExpiredObjectDeleteMarker>true</ExpiredObjectDeleteMarker></Expiration></Rule>`,
+		`<Rule><ID>h</ID><Status>Enabled</Status><Filter><Prefix>p</Prefix></Filter></Rule>`,
+		`<Rule><ID>i</ID><Status>Enabled</Status><Expiration><Days>-1</Days></Expiration></Rule>`,
+	} {
+		lc := &LifecycleConfiguration{}
+		tassert.CheckFatal(t, xml.Unmarshal([]byte("<LifecycleConfiguration>"+rule+"</LifecycleConfiguration>"), lc))
+		_, err := lc.AISRules()
+		tassert.Errorf(t, err != nil, "expected error for %s", rule)
+	}
+}
@@ -0,0 +1,349 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Multipart upload is driven by the target that owns (HRW-wise) the resulting
+// object: every part is received into a separate workfile, and the workfiles
+// are concatenated into the final object when the upload completes.
+//
+// The uploads (along with their parts) are kept in the target's database, so
+// that an upload survives the target restart.
+
+const mptCollection = "s3mpt"
+
+type (
+	// Response to create multipart upload request
+	InitiateMptUploadResult struct {
+		XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+		Ns       string   `xml:"xmlns,attr"`
+		Bucket   string   `xml:"Bucket"`
+		Key      string   `xml:"Key"`
+		UploadID string   `xml:"UploadId"`
+	}
+
+	// Complete multipart upload request: the list of parts to assemble
+	CompleteMptUpload struct {
+		XMLName xml.Name    `xml:"CompleteMultipartUpload"`
+		Parts   []*PartInfo `xml:"Part"`
+	}
+	PartInfo struct {
+		ETag         string `xml:"ETag"`
+		PartNumber   int64  `xml:"PartNumber"`
+		Size         int64  `xml:"Size,omitempty"`
+		LastModified string `xml:"LastModified,omitempty"`
+	}
+
+	// Response to complete multipart upload request
+	CompleteMptUploadResult struct {
+		XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		Ns      string   `xml:"xmlns,attr"`
+		Bucket  string   `xml:"Bucket"`
+		Key     string   `xml:"Key"`
+		ETag    string   `xml:"ETag"`
+	}
+
+	// Response to list parts request
+	ListPartsResult struct {
+		XMLName  xml.Name    `xml:"ListPartsResult"`
+		Ns       string      `xml:"xmlns,attr"`
+		Bucket   string      `xml:"Bucket"`
+		Key      string      `xml:"Key"`
+		UploadID string      `xml:"UploadId"`
+		Parts    []*PartInfo `xml:"Part"`
+	}
+
+	// Uploaded part of a multipart upload
+	MptPart struct {
+		MD5   string    `json:"md5"`           // MD5 of the part content (its ETag)
+		FQN   string    `json:"fqn"`           // FQN of the workfile with the part content
+		Size  int64     `json:"size"`          // part size
+		Num   int64     `json:"num"`           // part number
+		Mtime time.Time `json:"mtime"`         // when the part was received
+		SSE   string    `json:"sse,omitempty"` // ID of the key the part is encrypted with (empty if not encrypted)
+	}
+
+	mptUpload struct {
+		BckName string     `json:"bucket"`
+		ObjName string     `json:"object"`
+		Started time.Time  `json:"started"`
+		Parts   []*MptPart `json:"-"` // sorted by part number (persisted separately, see partKey)
+	}
+
+	uploads struct {
+		sync.RWMutex
+		m  map[string]*mptUpload // upload ID => upload
+		db dbdriver.Driver
+	}
+)
+
+var ups = &uploads{m: make(map[string]*mptUpload)}
+
+// InitUploads loads the uploads from the database; from now on, every change
+// of an upload is persisted in the database.
+func InitUploads(db dbdriver.Driver) error {
+	all, err := db.GetAll(mptCollection, "")
+	if err != nil && !dbdriver.IsErrNotFound(err) {
+		return err
+	}
+	var (
+		m     = make(map[string]*mptUpload, len(all))
+		parts = make(map[string][]*MptPart, len(all))
+	)
+	for key, val := range all {
+		if i := strings.IndexByte(key, '/'); i > 0 {
+			id := key[:i]
+			part := &MptPart{}
+			if err := jsoniter.UnmarshalFromString(val, part); err != nil {
+				glog.Errorf("failed to load upload part %q: %v", key, err)
+				continue
+			}
+			parts[id] = append(parts[id], part)
+			continue
+		}
+		upload := &mptUpload{}
+		if err := jsoniter.UnmarshalFromString(val, upload); err != nil {
+			glog.Errorf("failed to load upload %q: %v", key, err)
+			continue
+		}
+		m[key] = upload
+	}
+	for id, upload := range m {
+		upload.Parts = parts[id]
+		sort.Slice(upload.Parts, func(i, j int) bool { return upload.Parts[i].Num < upload.Parts[j].Num })
+	}
+	ups.Lock()
+	ups.m, ups.db = m, db
+	ups.Unlock()
+	return nil
+}
+
+// the parts of an upload are stored under the upload ID: <upload ID>/<part number>
+func partKey(id string, num int64) string { return id + "/" + strconv.FormatInt(num, 10) }
+
+// InitUpload registers a new multipart upload.
+func InitUpload(id, bckName, objName string) error {
+	upload := &mptUpload{
+		BckName: bckName,
+		ObjName: objName,
+		Started: time.Now(),
+		Parts:   make([]*MptPart, 0, 10),
+	}
+	ups.Lock()
+	defer ups.Unlock()
+	if ups.db != nil {
+		if err := ups.db.Set(mptCollection, id, upload); err != nil {
+			return err
+		}
+	}
+	ups.m[id] = upload
+	return nil
+}
+
+// AddPart adds a part to the upload. If the part with the same number has
+// already been uploaded, the new part replaces it and the old one is returned,
+// so that the caller could cleanup its workfile.
+func AddPart(id string, npart *MptPart) (prev *MptPart, err error) {
+	ups.Lock()
+	defer ups.Unlock()
+	upload, ok := ups.m[id]
+	if !ok {
+		return nil, errNoSuchUpload(id)
+	}
+	if ups.db != nil {
+		if err = ups.db.Set(mptCollection, partKey(id, npart.Num), npart); err != nil {
+			return nil, err
+		}
+	}
+	idx := sort.Search(len(upload.Parts), func(i int) bool { return upload.Parts[i].Num >= npart.Num })
+	if idx < len(upload.Parts) && upload.Parts[idx].Num == npart.Num {
+		prev = upload.Parts[idx]
+		upload.Parts[idx] = npart
+		return
+	}
+	upload.Parts = append(upload.Parts, nil)
+	copy(upload.Parts[idx+1:], upload.Parts[idx:])
+	upload.Parts[idx] = npart
+	return
+}
+
+// UploadObj returns the bucket and object names of the upload.
+func UploadObj(id string) (bckName, objName string, err error) {
+	ups.RLock()
+	defer ups.RUnlock()
+	upload, ok := ups.m[id]
+	if !ok {
+		return "", "", errNoSuchUpload(id)
+	}
+	return upload.BckName, upload.ObjName, nil
+}
+
+// CheckParts validates the list of parts from a complete multipart upload
+// request and returns the corresponding uploaded parts in the same order.
+func CheckParts(id string, parts []*PartInfo) ([]*MptPart, error) {
+	ups.RLock()
+	defer ups.RUnlock()
+	upload, ok := ups.m[id]
+	if !ok {
+		return nil, errNoSuchUpload(id)
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("upload %q: the list of parts is empty", id)
+	}
+	res := make([]*MptPart, 0, len(parts))
+	for i, part := range parts {
+		if i > 0 && part.PartNumber <= parts[i-1].PartNumber {
+			return nil, fmt.Errorf("upload %q: parts must be in ascending order", id)
+		}
+		idx := sort.Search(len(upload.Parts), func(j int) bool { return upload.Parts[j].Num >= part.PartNumber })
+		if idx == len(upload.Parts) || upload.Parts[idx].Num != part.PartNumber {
+			return nil, fmt.Errorf("upload %q: part %d %s", id, part.PartNumber, cmn.DoesNotExist)
+		}
+		mpart := upload.Parts[idx]
+		if etag := unquote(part.ETag); etag != "" && etag != mpart.MD5 {
+			return nil, fmt.Errorf("upload %q: part %d ETag mismatch (%q vs %q)", id, part.PartNumber, etag, mpart.MD5)
+		}
+		res = append(res, mpart)
+	}
+	return res, nil
+}
+
+// ListParts returns the parts uploaded so far.
+func ListParts(id string) ([]*PartInfo, error) {
+	ups.RLock()
+	defer ups.RUnlock()
+	upload, ok := ups.m[id]
+	if !ok {
+		return nil, errNoSuchUpload(id)
+	}
+	parts := make([]*PartInfo, 0, len(upload.Parts))
+	for _, part := range upload.Parts {
+		parts = append(parts, &PartInfo{
+			ETag:         QuoteETag(part.MD5),
+			PartNumber:   part.Num,
+			Size:         part.Size,
+			LastModified: part.Mtime.UTC().Format(time.RFC3339),
+		})
+	}
+	return parts, nil
+}
+
+// FinishUpload removes the upload and returns all its parts, so that the
+// caller could cleanup the workfiles.
+func FinishUpload(id string) ([]*MptPart, error) {
+	ups.Lock()
+	defer ups.Unlock()
+	upload, ok := ups.m[id]
+	if !ok {
+		return nil, errNoSuchUpload(id)
+	}
+	delete(ups.m, id)
+	if ups.db != nil {
+		keys := []string{id}
+		for _, part := range upload.Parts {
+			keys = append(keys, partKey(id, part.Num))
+		}
+		for _, key := range keys {
+			if err := ups.db.Delete(mptCollection, key); err != nil && !dbdriver.IsErrNotFound(err) {
+				glog.Errorf("failed to remove upload %q (%s) from DB: %v", id, key, err)
+			}
+		}
+	}
+	return upload.Parts, nil
+}
+
+// PartInUse returns true if the workfile is a part of an upload in progress.
+func PartInUse(fqn string) bool {
+	ups.RLock()
+	defer ups.RUnlock()
+	for _, upload := range ups.m {
+		for _, part := range upload.Parts {
+			if part.FQN == fqn {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// StaleUploads returns IDs of the uploads into the bucket for which `stale`
+// returns true (see also bucket lifecycle rules).
+func StaleUploads(bckName string, stale func(objName string, started time.Time) bool) (ids []string) {
+	ups.RLock()
+	for id, upload := range ups.m {
+		if upload.BckName == bckName && stale(upload.ObjName, upload.Started) {
+			ids = append(ids, id)
+		}
+	}
+	ups.RUnlock()
+	return
+}
+
+// MptETag computes the ETag of a multipart object in the same way as AWS does:
+// MD5 of the concatenated binary MD5s of all parts followed by "-<number of parts>".
+func MptETag(parts []*MptPart) (string, error) {
+	h := md5.New()
+	for _, part := range parts {
+		b, err := hex.DecodeString(part.MD5)
+		if err != nil {
+			return "", fmt.Errorf("part %d: invalid MD5 %q: %v", part.Num, part.MD5, err)
+		}
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil)) + "-" + strconv.Itoa(len(parts)), nil
+}
+
+func errNoSuchUpload(id string) error { return fmt.Errorf("upload %q %s", id, cmn.DoesNotExist) }
+
+func unquote(s string) string { return strings.Trim(s, "\"") }
+
+// QuoteETag returns the ETag in the form S3 clients expect (enclosed in double quotes).
+func QuoteETag(etag string) string { return "\"" + etag + "\"" }
+
+func NewInitiateMptUploadResult(bckName, objName, id string) *InitiateMptUploadResult {
+	return &InitiateMptUploadResult{Ns: s3Namespace, Bucket: bckName, Key: objName, UploadID: id}
+}
+
+func (r *InitiateMptUploadResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
+
+func NewCompleteMptUploadResult(bckName, objName, etag string) *CompleteMptUploadResult {
+	return &CompleteMptUploadResult{Ns: s3Namespace, Bucket: bckName, Key: objName, ETag: QuoteETag(etag)}
+}
+
+func (r *CompleteMptUploadResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
+
+func NewListPartsResult(bckName, objName, id string, parts []*PartInfo) *ListPartsResult {
+	return &ListPartsResult{Ns: s3Namespace, Bucket: bckName, Key: objName, UploadID: id, Parts: parts}
+}
+
+func (r *ListPartsResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
@@ -0,0 +1,99 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"testing"
+
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func partMD5(data string) string {
+	sum := md5.Sum([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestMptUpload(t *testing.T) {
+	const id = "test-upload"
+	tassert.CheckFatal(t, InitUpload(id, "bck", "obj"))
+	defer FinishUpload(id)
+
+	// upload parts out of order, and then re-upload one of them
+	for _, num := range []int64{3, 1, 2} {
+		_, err := AddPart(id, &MptPart{MD5: partMD5("old"), FQN: "old", Num: num})
+		tassert.CheckFatal(t, err)
+	}
+	prev, err := AddPart(id, &MptPart{MD5: partMD5("new"), FQN: "new", Num: 2})
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, prev != nil && prev.FQN == "old", "expected replaced part to be returned, got %+v", prev)
+
+	listed, err := ListParts(id)
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(listed) == 3, "expected 3 parts, got %d", len(listed))
+	for i, part := range listed {
+		tassert.Errorf(t, part.PartNumber == int64(i+1), "expected part %d, got %d", i+1, part.PartNumber)
+		tassert.Errorf(t, part.ETag[0] == '"', "expected quoted ETag, got %s", part.ETag)
+	}
+
+	_, err = CheckParts(id, []*PartInfo{{PartNumber: 2}, {PartNumber: 1}})
+	tassert.Errorf(t, err != nil, "expected error for parts in descending order")
+	_, err = CheckParts(id, []*PartInfo{{PartNumber: 4}})
+	tassert.Errorf(t, err != nil, "expected error for non-existing part")
+	_, err = CheckParts(id, []*PartInfo{{PartNumber: 2, ETag: "\"" + partMD5("old") + "\""}})
+	tassert.Errorf(t, err != nil, "expected error for ETag mismatch")
+
+	parts, err := CheckParts(id, []*PartInfo{{PartNumber: 1}, {PartNumber: 2, ETag: "\"" + partMD5("new") + "\""}})
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(parts) == 2 && parts[1].FQN == "new", "unexpected parts: %+v", parts)
+}
+
+// uploads (and their parts) survive restart
+func TestMptUploadPersist(t *testing.T) {
+	const id = "test-upload-persist"
+	db := dbdriver.NewDBMock()
+	tassert.CheckFatal(t, InitUploads(db))
+	defer func() { ups.m, ups.db = make(map[string]*mptUpload), nil }()
+
+	tassert.CheckFatal(t, InitUpload(id, "bck", "obj"))
+	for _, num := range []int64{2, 1} {
+		_, err := AddPart(id, &MptPart{MD5: partMD5("old"), FQN: "old", Num: num, SSE: "key"})
+		tassert.CheckFatal(t, err)
+	}
+	_, err := AddPart(id, &MptPart{MD5: partMD5("new"), FQN: "new", Size: 3, Num: 2})
+	tassert.CheckFatal(t, err)
+
+	// "restart"
+	tassert.CheckFatal(t, InitUploads(db))
+	bckName, objName, err := UploadObj(id)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bckName == "bck" && objName == "obj", "unexpected upload object %s/%s", bckName, objName)
+	parts, err := CheckParts(id, []*PartInfo{{PartNumber: 1}, {PartNumber: 2}})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, parts[0].FQN == "old" && parts[0].SSE == "key", "unexpected part %+v", parts[0])
+	tassert.Errorf(t, parts[1].FQN == "new" && parts[1].Size == 3 && parts[1].SSE == "", "unexpected part %+v", parts[1])
+	tassert.Errorf(t, PartInUse("new") && !PartInUse("none"), "unexpected parts in use")
+
+	_, err = FinishUpload(id)
+	tassert.CheckFatal(t, err)
+	keys, _ := db.List(mptCollection, "")
+	tassert.Errorf(t, len(keys) == 0, "expected the upload to be removed from DB, got %v", keys)
+}
+
+func TestMptETag(t *testing.T) {
+	parts := []*MptPart{{MD5: partMD5("part1"), Num: 1}, {MD5: partMD5("part2"), Num: 2}}
+	etag, err := MptETag(parts)
+	tassert.CheckFatal(t, err)
+
+	b1, _ := hex.DecodeString(parts[0].MD5)
+	b2, _ := hex.DecodeString(parts[1].MD5)
+	expected := md5.Sum(append(b1, b2...))
+	tassert.Errorf(t, etag == hex.EncodeToString(expected[:])+"-2", "unexpected ETag %q", etag)
+
+	_, err = MptETag([]*MptPart{{MD5: "not-hex", Num: 1}})
+	tassert.Errorf(t, err != nil, "expected error for invalid MD5")
+}
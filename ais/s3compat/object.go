@@ -11,22 +11,29 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 )
 
 type (
-	// List objects response
+	// List objects response (both V1 and V2)
 	ListObjectResult struct {
-		Ns                    string     `xml:"xmlns,attr"`
-		Prefix                string     `xml:"Prefix"`
-		KeyCount              int        `xml:"KeyCount"` // number of objects in the response
-		MaxKeys               int        `xml:"MaxKeys"`
-		IsTruncated           bool       `xml:"IsTruncated"`           // true if there are more pages to read
-		ContinuationToken     string     `xml:"ContinuationToken"`     // original ContinuationToken
-		NextContinuationToken string     `xml:"NextContinuationToken"` // NextContinuationToken to read the next page
-		Contents              []*ObjInfo `xml:"Contents"`              // list of objects
+		Ns                    string          `xml:"xmlns,attr"`
+		Prefix                string          `xml:"Prefix"`
+		Delimiter             string          `xml:"Delimiter,omitempty"`
+		KeyCount              int             `xml:"KeyCount,omitempty"` // V2: number of objects and prefixes in the response
+		MaxKeys               int             `xml:"MaxKeys"`
+		IsTruncated           bool            `xml:"IsTruncated"`                     // true if there are more pages to read
+		Marker                string          `xml:"Marker,omitempty"`                // V1: original Marker
+		NextMarker            string          `xml:"NextMarker,omitempty"`            // V1: Marker to read the next page
+		StartAfter            string          `xml:"StartAfter,omitempty"`            // V2: original StartAfter
+		ContinuationToken     string          `xml:"ContinuationToken,omitempty"`     // V2: original ContinuationToken
+		NextContinuationToken string          `xml:"NextContinuationToken,omitempty"` // V2: NextContinuationToken to read the next page
+		Contents              []*ObjInfo      `xml:"Contents"`                        // list of objects
+		CommonPrefixes        []*CommonPrefix `xml:"CommonPrefixes"`                  // list of virtual directories
+		v2                    bool
 	}
 	ObjInfo struct {
 		Key          string `xml:"Key"`
@@ -35,6 +42,9 @@ type (
 		Size         int64  `xml:"Size"`
 		Class        string `xml:"StorageClass"`
 	}
+	CommonPrefix struct {
+		Prefix string `xml:"Prefix"`
+	}
 
 	// Response for object copy request
 	CopyObjectResult struct {
@@ -44,30 +54,50 @@ type (
 )
 
 func FillMsgFromS3Query(query url.Values, msg *cmn.SelectMsg) {
-	mxStr := query.Get("max-keys")
+	msg.PageSize = defaultMaxKeys
+	mxStr := query.Get(URLParamMaxKeys)
 	if pageSize, err := strconv.Atoi(mxStr); err == nil && pageSize > 0 {
 		msg.PageSize = uint(pageSize)
 	}
-	if prefix := query.Get("prefix"); prefix != "" {
+	if prefix := query.Get(URLParamPrefix); prefix != "" {
 		msg.Prefix = prefix
 	}
+	if query.Get(URLParamListType) != listTypeV2 {
+		// V1: marker is the key to start listing after, on every call
+		msg.ContinuationToken = query.Get(URLParamMarker)
+		return
+	}
 	var token string
-	if token = query.Get("continuation-token"); token != "" {
+	if token = query.Get(URLParamContinuationToken); token != "" {
 		msg.ContinuationToken = token
 	}
 	// start-after makes sense only on first call. For the next call,
 	// when continuation-token is set, start-after is ignored
-	if after := query.Get("start-after"); after != "" && token == "" {
+	if after := query.Get(URLParamStartAfter); after != "" && token == "" {
 		msg.StartAfter = after
 	}
 }
 
-func NewListObjectResult() *ListObjectResult {
-	return &ListObjectResult{
-		Ns:       s3Namespace,
-		MaxKeys:  1000,
-		Contents: make([]*ObjInfo, 0),
+func NewListObjectResult(query url.Values) *ListObjectResult {
+	r := &ListObjectResult{
+		Ns:             s3Namespace,
+		Prefix:         query.Get(URLParamPrefix),
+		Delimiter:      query.Get(URLParamDelimiter),
+		MaxKeys:        defaultMaxKeys,
+		Contents:       make([]*ObjInfo, 0),
+		CommonPrefixes: make([]*CommonPrefix, 0),
+		v2:             query.Get(URLParamListType) == listTypeV2,
+	}
+	if maxKeys, err := strconv.Atoi(query.Get(URLParamMaxKeys)); err == nil && maxKeys > 0 {
+		r.MaxKeys = maxKeys
 	}
+	if r.v2 {
+		r.ContinuationToken = query.Get(URLParamContinuationToken)
+		r.StartAfter = query.Get(URLParamStartAfter)
+	} else {
+		r.Marker = query.Get(URLParamMarker)
+	}
+	return r
 }
 
 func (r *ListObjectResult) MustMarshal() []byte {
@@ -80,6 +110,29 @@ func (r *ListObjectResult) Add(entry *cmn.BucketEntry) {
 	r.Contents = append(r.Contents, entryToS3(entry))
 }
 
+// addPrefix adds a virtual directory unless it is the same as the last one
+// (entries are sorted, so all the objects from the same directory are adjacent)
+func (r *ListObjectResult) addPrefix(prefix string) {
+	if l := len(r.CommonPrefixes); l > 0 && r.CommonPrefixes[l-1].Prefix == prefix {
+		return
+	}
+	r.CommonPrefixes = append(r.CommonPrefixes, &CommonPrefix{Prefix: prefix})
+}
+
+// commonPrefix returns the virtual directory that contains the object, if any:
+// the object name up to and including the first delimiter after the prefix.
+func (r *ListObjectResult) commonPrefix(objName string) (string, bool) {
+	if r.Delimiter == "" {
+		return "", false
+	}
+	rest := strings.TrimPrefix(objName, r.Prefix)
+	idx := strings.Index(rest, r.Delimiter)
+	if idx < 0 {
+		return "", false
+	}
+	return objName[:len(objName)-len(rest)+idx+len(r.Delimiter)], true
+}
+
 func entryToS3(entry *cmn.BucketEntry) *ObjInfo {
 	return &ObjInfo{
 		Key:          entry.Name,
@@ -90,12 +143,33 @@ func entryToS3(entry *cmn.BucketEntry) *ObjInfo {
 }
 
 func (r *ListObjectResult) FillFromAisBckList(bckList *cmn.BucketList) {
-	r.KeyCount = len(bckList.Entries)
-	r.IsTruncated = bckList.ContinuationToken != ""
-	r.ContinuationToken = bckList.ContinuationToken
+	var lastPrefix string
 	for _, e := range bckList.Entries {
+		if prefix, ok := r.commonPrefix(e.Name); ok {
+			r.addPrefix(prefix)
+			lastPrefix = prefix
+			continue
+		}
+		lastPrefix = ""
 		r.Add(e)
 	}
+	r.KeyCount = len(r.Contents) + len(r.CommonPrefixes)
+	r.IsTruncated = bckList.ContinuationToken != ""
+	if !r.IsTruncated {
+		return
+	}
+	token := bckList.ContinuationToken
+	if lastPrefix != "" {
+		// The page ends inside a virtual directory that has been already
+		// reported - skip the rest of its objects on the next call.
+		// NOTE: AIS continuation token is the name of the last listed object.
+		token = lastPrefix + string(utf8.MaxRune)
+	}
+	if r.v2 {
+		r.NextContinuationToken = token
+	} else {
+		r.NextMarker = token
+	}
 }
 
 func FormatTime(t time.Time) string {
@@ -106,8 +180,10 @@ func FormatTime(t time.Time) string {
 func SetHeaderFromLOM(header http.Header, lom *cluster.LOM, size int64) {
 	if v, exists := lom.GetCustomMD(cluster.SourceObjMD); exists && v == cluster.SourceAmazonObjMD {
 		if v, exists := lom.GetCustomMD(cluster.MD5ObjMD); exists {
-			header.Set(headerETag, v)
+			header.Set(HeaderETag, v)
 		}
+	} else if v, exists := lom.GetCustomMD(cluster.ETagObjMD); exists {
+		header.Set(HeaderETag, QuoteETag(v))
 	}
 	header.Set(headerAtime, FormatTime(lom.Atime()))
 	header.Set(cmn.HeaderContentLength, strconv.FormatInt(size, 10))
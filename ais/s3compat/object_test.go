@@ -0,0 +1,77 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func bckList(token string, names ...string) *cmn.BucketList {
+	list := &cmn.BucketList{ContinuationToken: token}
+	for _, name := range names {
+		list.Entries = append(list.Entries, &cmn.BucketEntry{Name: name})
+	}
+	return list
+}
+
+func TestListObjectsQuery(t *testing.T) {
+	var (
+		msg   cmn.SelectMsg
+		query = url.Values{}
+	)
+	query.Set(URLParamListType, listTypeV2)
+	query.Set(URLParamMaxKeys, "10")
+	query.Set(URLParamStartAfter, "a/b")
+	FillMsgFromS3Query(query, &msg)
+	tassert.Errorf(t, msg.PageSize == 10, "expected page size 10, got %d", msg.PageSize)
+	tassert.Errorf(t, msg.StartAfter == "a/b", "expected start-after %q, got %q", "a/b", msg.StartAfter)
+
+	// start-after is ignored when continuation token is set
+	msg = cmn.SelectMsg{}
+	query.Set(URLParamContinuationToken, "a/c")
+	FillMsgFromS3Query(query, &msg)
+	tassert.Errorf(t, msg.StartAfter == "", "expected empty start-after, got %q", msg.StartAfter)
+	tassert.Errorf(t, msg.ContinuationToken == "a/c", "expected token %q, got %q", "a/c", msg.ContinuationToken)
+
+	// V1 listing
+	msg = cmn.SelectMsg{}
+	query = url.Values{}
+	query.Set(URLParamMarker, "a/d")
+	FillMsgFromS3Query(query, &msg)
+	tassert.Errorf(t, msg.PageSize == defaultMaxKeys, "expected default page size, got %d", msg.PageSize)
+	tassert.Errorf(t, msg.ContinuationToken == "a/d", "expected token %q, got %q", "a/d", msg.ContinuationToken)
+}
+
+func TestListObjectsDelimiter(t *testing.T) {
+	query := url.Values{}
+	query.Set(URLParamListType, listTypeV2)
+	query.Set(URLParamPrefix, "data/")
+	query.Set(URLParamDelimiter, "/")
+
+	resp := NewListObjectResult(query)
+	resp.FillFromAisBckList(bckList("", "data/a.txt", "data/x/1", "data/x/2", "data/y/1", "data/z.txt"))
+	tassert.Fatalf(t, len(resp.Contents) == 2, "expected 2 objects, got %d", len(resp.Contents))
+	tassert.Fatalf(t, len(resp.CommonPrefixes) == 2, "expected 2 prefixes, got %d", len(resp.CommonPrefixes))
+	tassert.Errorf(t, resp.CommonPrefixes[0].Prefix == "data/x/", "unexpected prefix %q", resp.CommonPrefixes[0].Prefix)
+	tassert.Errorf(t, resp.CommonPrefixes[1].Prefix == "data/y/", "unexpected prefix %q", resp.CommonPrefixes[1].Prefix)
+	tassert.Errorf(t, resp.KeyCount == 4, "expected key count 4, got %d", resp.KeyCount)
+	tassert.Errorf(t, !resp.IsTruncated, "expected the listing to be complete")
+
+	// the page ends inside a virtual directory: next page must skip the rest of it
+	resp = NewListObjectResult(query)
+	resp.FillFromAisBckList(bckList("data/x/2", "data/a.txt", "data/x/1", "data/x/2"))
+	tassert.Fatalf(t, resp.IsTruncated, "expected the listing to be truncated")
+	token := resp.NextContinuationToken
+	tassert.Errorf(t, token > "data/x/zzz" && token < "data/y", "unexpected continuation token %q", token)
+
+	// the page ends with an object: the token is passed as is
+	resp = NewListObjectResult(query)
+	resp.FillFromAisBckList(bckList("data/b.txt", "data/a.txt", "data/b.txt"))
+	tassert.Errorf(t, resp.NextContinuationToken == "data/b.txt", "unexpected token %q", resp.NextContinuationToken)
+}
@@ -0,0 +1,334 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// AWS Signature Version 4, see:
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
+//
+// Both forms are supported: signature in the `Authorization` header and
+// presigned URL (signature in the query). The request payload is not read by
+// the proxy (the request is redirected to a target), so the signature covers
+// the payload hash as given in the request; the payload itself is verified
+// against the hash by whoever reads it (see NewPayloadReader).
+
+const (
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+	sigV4Request   = "aws4_request"
+	sigV4DateFmt   = "20060102T150405Z"
+
+	headerAmzDate          = "X-Amz-Date"
+	headerAmzContentSHA256 = "X-Amz-Content-Sha256"
+
+	// presigned URL query parameters
+	queryAmzAlgorithm     = "X-Amz-Algorithm"
+	queryAmzCredential    = "X-Amz-Credential"
+	queryAmzDate          = "X-Amz-Date"
+	queryAmzExpires       = "X-Amz-Expires"
+	queryAmzSignedHeaders = "X-Amz-SignedHeaders"
+	queryAmzSignature     = "X-Amz-Signature"
+
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	streamingPrefix = "STREAMING-" // aws-chunked payload (e.g., STREAMING-AWS4-HMAC-SHA256-PAYLOAD)
+
+	// max allowed difference between the request time and the local time
+	sigV4MaxSkew = 15 * time.Minute
+	// max validity period of a presigned URL (7 days)
+	sigV4MaxExpires = 7 * 24 * time.Hour
+)
+
+type (
+	// Parsed AWS Signature V4 of a request
+	SigV4 struct {
+		AccessKey     string
+		Date          string // credential scope date: YYYYMMDD
+		Region        string
+		Service       string
+		SignedHeaders []string
+		Signature     string
+		Timestamp     time.Time
+		Expires       time.Duration // presigned URL only
+		presigned     bool
+	}
+)
+
+var (
+	ErrNoSignature      = &Error{Code: errCodeAccessDenied, Message: "request is not signed", Status: http.StatusForbidden}
+	ErrSignatureInvalid = &Error{Code: errCodeSignatureMismatch, Message: "signature does not match", Status: http.StatusForbidden}
+	ErrPayloadInvalid   = &Error{Code: errCodeSHA256Mismatch, Message: "payload does not match " + headerAmzContentSHA256,
+		Status: http.StatusBadRequest}
+)
+
+// ParseSigV4 extracts the signature and its parameters from the request.
+func ParseSigV4(r *http.Request) (sig *SigV4, err error) {
+	if auth := r.Header.Get(cmn.HeaderAuthorization); strings.HasPrefix(auth, sigV4Algorithm+" ") {
+		sig, err = parseSigV4Header(r, strings.TrimPrefix(auth, sigV4Algorithm+" "))
+	} else if q := r.URL.Query(); q.Get(queryAmzAlgorithm) != "" {
+		sig, err = parseSigV4Query(q)
+	} else {
+		return nil, ErrNoSignature
+	}
+	if err != nil {
+		return nil, NewAccessDenied(err)
+	}
+	return
+}
+
+// Authorization: AWS4-HMAC-SHA256 Credential=<key>/<date>/<region>/s3/aws4_request,
+// SignedHeaders=host;x-amz-date, Signature=<hex>
+func parseSigV4Header(r *http.Request, auth string) (sig *SigV4, err error) {
+	sig = &SigV4{}
+	for _, field := range strings.Split(auth, ",") {
+		kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid authorization field %q", field)
+		}
+		switch kv[0] {
+		case "Credential":
+			err = sig.parseCredential(kv[1])
+		case "SignedHeaders":
+			sig.SignedHeaders = strings.Split(kv[1], ";")
+		case "Signature":
+			sig.Signature = kv[1]
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	amzDate := r.Header.Get(headerAmzDate)
+	if sig.Timestamp, err = time.Parse(sigV4DateFmt, amzDate); err != nil {
+		return nil, fmt.Errorf("invalid %s %q", headerAmzDate, amzDate)
+	}
+	// the payload hash must be signed, so that the payload can be verified
+	if !cmn.StringInSlice(strings.ToLower(headerAmzContentSHA256), sig.SignedHeaders) {
+		return nil, fmt.Errorf("%s must be signed", headerAmzContentSHA256)
+	}
+	return sig, sig.validate()
+}
+
+func parseSigV4Query(q url.Values) (sig *SigV4, err error) {
+	sig = &SigV4{presigned: true}
+	if algo := q.Get(queryAmzAlgorithm); algo != sigV4Algorithm {
+		return nil, fmt.Errorf("unsupported signature algorithm %q", algo)
+	}
+	if err = sig.parseCredential(q.Get(queryAmzCredential)); err != nil {
+		return nil, err
+	}
+	sig.SignedHeaders = strings.Split(q.Get(queryAmzSignedHeaders), ";")
+	sig.Signature = q.Get(queryAmzSignature)
+	if sig.Timestamp, err = time.Parse(sigV4DateFmt, q.Get(queryAmzDate)); err != nil {
+		return nil, fmt.Errorf("invalid %s %q", queryAmzDate, q.Get(queryAmzDate))
+	}
+	expires, err := strconv.ParseInt(q.Get(queryAmzExpires), 10, 64)
+	if err != nil || expires <= 0 {
+		return nil, fmt.Errorf("invalid %s %q", queryAmzExpires, q.Get(queryAmzExpires))
+	}
+	sig.Expires = time.Duration(expires) * time.Second
+	if sig.Expires > sigV4MaxExpires {
+		return nil, fmt.Errorf("%s must be less than %v", queryAmzExpires, sigV4MaxExpires)
+	}
+	return sig, sig.validate()
+}
+
+// <access-key>/<date>/<region>/<service>/aws4_request
+// NOTE: the access key may contain anything but slash, so it is parsed from the right.
+func (sig *SigV4) parseCredential(cred string) error {
+	parts := strings.Split(cred, "/")
+	l := len(parts)
+	if l < 5 || parts[l-1] != sigV4Request {
+		return fmt.Errorf("invalid credential %q", cred)
+	}
+	sig.AccessKey = strings.Join(parts[:l-4], "/")
+	sig.Date, sig.Region, sig.Service = parts[l-4], parts[l-3], parts[l-2]
+	return nil
+}
+
+func (sig *SigV4) validate() error {
+	if sig.AccessKey == "" || sig.Signature == "" || len(sig.SignedHeaders) == 0 {
+		return errors.New("incomplete signature")
+	}
+	if sig.Timestamp.Format("20060102") != sig.Date {
+		return fmt.Errorf("credential date %q does not match request time %s", sig.Date, sig.Timestamp)
+	}
+	return nil
+}
+
+// Verify checks the signature of the request against the secret key that
+// belongs to `sig.AccessKey`.
+func (sig *SigV4) Verify(r *http.Request, secretKey string, now time.Time) error {
+	if sig.presigned {
+		if now.Before(sig.Timestamp.Add(-sigV4MaxSkew)) || now.After(sig.Timestamp.Add(sig.Expires)) {
+			return NewAccessDenied(errors.New("presigned URL has expired"))
+		}
+	} else if d := now.Sub(sig.Timestamp); d > sigV4MaxSkew || d < -sigV4MaxSkew {
+		return &Error{Code: errCodeTimeSkewed, Message: fmt.Sprintf("request time %s is too skewed", sig.Timestamp),
+			Status: http.StatusForbidden}
+	}
+	signature := sig.sign(secretKey, sig.stringToSign(r))
+	if !hmac.Equal([]byte(signature), []byte(sig.Signature)) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
+
+func (sig *SigV4) scope() string {
+	return strings.Join([]string{sig.Date, sig.Region, sig.Service, sigV4Request}, "/")
+}
+
+func (sig *SigV4) stringToSign(r *http.Request) string {
+	h := sha256.Sum256([]byte(sig.canonicalRequest(r)))
+	return strings.Join([]string{
+		sigV4Algorithm,
+		sig.Timestamp.Format(sigV4DateFmt),
+		sig.scope(),
+		hex.EncodeToString(h[:]),
+	}, "\n")
+}
+
+func (sig *SigV4) sign(secretKey, stringToSign string) string {
+	key := hmacSHA256([]byte("AWS4"+secretKey), sig.Date)
+	key = hmacSHA256(key, sig.Region)
+	key = hmacSHA256(key, sig.Service)
+	key = hmacSHA256(key, sigV4Request)
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func (sig *SigV4) canonicalRequest(r *http.Request) string {
+	payloadHash := r.Header.Get(headerAmzContentSHA256)
+	if sig.presigned || payloadHash == "" {
+		payloadHash = unsignedPayload
+	}
+	return strings.Join([]string{
+		r.Method,
+		uriEncode(r.URL.Path, false),
+		sig.canonicalQuery(r.URL.Query()),
+		sig.canonicalHeaders(r),
+		strings.Join(sig.SignedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+func (sig *SigV4) canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		if sig.presigned && k == queryAmzSignature {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values := append([]string{}, q[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// NOTE: canonical headers end with newline, so the canonical request has an
+// empty line between the headers and the list of signed headers.
+func (sig *SigV4) canonicalHeaders(r *http.Request) string {
+	var sb strings.Builder
+	for _, name := range sig.SignedHeaders {
+		var value string
+		if name == "host" {
+			value = r.Host
+		} else {
+			value = strings.Join(r.Header.Values(name), ",")
+		}
+		sb.WriteString(name)
+		sb.WriteByte(':')
+		sb.WriteString(strings.Join(strings.Fields(value), " "))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// AWS URI encoding: everything except unreserved characters is percent-encoded;
+// slash is kept as is in the path
+func uriEncode(s string, encodeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			sb.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(hexDigits[c>>4])
+			sb.WriteByte(hexDigits[c&0xf])
+		}
+	}
+	return sb.String()
+}
+
+//////////////////
+// payload hash //
+//////////////////
+
+type payloadReader struct {
+	r        io.ReadCloser
+	h        hash.Hash
+	expected string
+}
+
+// NewPayloadReader returns the reader of the request body that verifies the
+// body against its (signed) SHA256 upon reaching the end; the reader returns
+// ErrPayloadInvalid in case of mismatch. Unsigned payload is read as is.
+func NewPayloadReader(r *http.Request) (io.ReadCloser, error) {
+	expected := r.Header.Get(headerAmzContentSHA256)
+	switch {
+	case expected == "" || expected == unsignedPayload:
+		return r.Body, nil
+	case strings.HasPrefix(expected, streamingPrefix):
+		return nil, &Error{Code: errCodeNotImplemented, Message: "aws-chunked payload is not supported",
+			Status: http.StatusNotImplemented}
+	case len(expected) != 2*sha256.Size:
+		return nil, &Error{Code: errCodeInvalidArgument, Message: fmt.Sprintf("invalid %s %q", headerAmzContentSHA256, expected),
+			Status: http.StatusBadRequest}
+	}
+	return &payloadReader{r: r.Body, h: sha256.New(), expected: strings.ToLower(expected)}, nil
+}
+
+func (pr *payloadReader) Read(p []byte) (n int, err error) {
+	n, err = pr.r.Read(p)
+	pr.h.Write(p[:n])
+	if err == io.EOF && hex.EncodeToString(pr.h.Sum(nil)) != pr.expected {
+		err = ErrPayloadInvalid
+	}
+	return
+}
+
+func (pr *payloadReader) Close() error { return pr.r.Close() }
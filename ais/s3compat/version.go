@@ -0,0 +1,115 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strconv"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// S3 object versions are ais object versions: prior versions are available
+// as long as the bucket retains them (see cmn.VersionConf.MaxHistory and
+// cmn.VersionConf.HistoryTimeStr). A deleted object that has prior versions
+// is listed with a delete marker as its latest version.
+
+type (
+	// List object versions response
+	// NOTE: all versions of an object are always returned on the same page,
+	// so `version-id-marker` is not used.
+	ListVersionsResult struct {
+		Ns            string            `xml:"xmlns,attr"`
+		Prefix        string            `xml:"Prefix"`
+		KeyMarker     string            `xml:"KeyMarker"`
+		NextKeyMarker string            `xml:"NextKeyMarker,omitempty"`
+		MaxKeys       int               `xml:"MaxKeys"`
+		IsTruncated   bool              `xml:"IsTruncated"`
+		Versions      []*ObjVersionInfo `xml:"Version"`
+		DeleteMarkers []*DeleteMarker   `xml:"DeleteMarker"`
+	}
+	ObjVersionInfo struct {
+		Key          string `xml:"Key"`
+		VersionID    string `xml:"VersionId"`
+		IsLatest     bool   `xml:"IsLatest"`
+		LastModified string `xml:"LastModified"`
+		ETag         string `xml:"ETag"`
+		Size         int64  `xml:"Size"`
+		Class        string `xml:"StorageClass"`
+	}
+	DeleteMarker struct {
+		Key          string `xml:"Key"`
+		VersionID    string `xml:"VersionId"`
+		IsLatest     bool   `xml:"IsLatest"`
+		LastModified string `xml:"LastModified"`
+	}
+)
+
+// ais does not version deletions: delete markers have no ID of their own
+const deleteMarkerVersion = "null"
+
+func FillMsgFromS3VersionsQuery(query url.Values, msg *cmn.SelectMsg) {
+	FillMsgFromS3Query(query, msg)
+	msg.ContinuationToken = query.Get(URLParamKeyMarker)
+	msg.Flags |= cmn.SelectVersions
+}
+
+func NewListVersionsResult(query url.Values) *ListVersionsResult {
+	r := &ListVersionsResult{
+		Ns:        s3Namespace,
+		Prefix:    query.Get(URLParamPrefix),
+		KeyMarker: query.Get(URLParamKeyMarker),
+		MaxKeys:   defaultMaxKeys,
+		Versions:  make([]*ObjVersionInfo, 0),
+	}
+	if maxKeys, err := strconv.Atoi(query.Get(URLParamMaxKeys)); err == nil && maxKeys > 0 {
+		r.MaxKeys = maxKeys
+	}
+	return r
+}
+
+func (r *ListVersionsResult) FillFromAisBckList(bckList *cmn.BucketList) {
+	r.IsTruncated = bckList.ContinuationToken != ""
+	r.NextKeyMarker = bckList.ContinuationToken
+	for _, entry := range bckList.Entries {
+		if entry.IsStatusDeleted() {
+			r.addDeleteMarker(entry)
+		} else {
+			r.add(entry, true)
+		}
+		for _, v := range entry.Versions {
+			r.add(v, false)
+		}
+	}
+}
+
+func (r *ListVersionsResult) add(entry *cmn.BucketEntry, latest bool) {
+	info := entryToS3(entry)
+	r.Versions = append(r.Versions, &ObjVersionInfo{
+		Key:          info.Key,
+		VersionID:    entry.Version,
+		IsLatest:     latest,
+		LastModified: info.LastModified,
+		ETag:         info.ETag,
+		Size:         info.Size,
+		Class:        info.Class,
+	})
+}
+
+// the object was deleted when its most recent prior version was archived
+func (r *ListVersionsResult) addDeleteMarker(entry *cmn.BucketEntry) {
+	marker := &DeleteMarker{Key: entry.Name, VersionID: deleteMarkerVersion, IsLatest: true}
+	if len(entry.Versions) > 0 {
+		marker.LastModified = entry.Versions[0].Atime
+	}
+	r.DeleteMarkers = append(r.DeleteMarkers, marker)
+}
+
+func (r *ListVersionsResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
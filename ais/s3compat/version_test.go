@@ -0,0 +1,51 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestListVersions(t *testing.T) {
+	var (
+		msg   cmn.SelectMsg
+		query = url.Values{}
+	)
+	query.Set(URLParamVersions, "")
+	query.Set(URLParamKeyMarker, "a/b")
+	query.Set(URLParamMaxKeys, "2")
+	FillMsgFromS3VersionsQuery(query, &msg)
+	tassert.Errorf(t, msg.IsFlagSet(cmn.SelectVersions), "expected versions to be requested")
+	tassert.Errorf(t, msg.ContinuationToken == "a/b", "expected token %q, got %q", "a/b", msg.ContinuationToken)
+	tassert.Errorf(t, msg.PageSize == 2, "expected page size 2, got %d", msg.PageSize)
+
+	list := bckList("a/e", "a/c", "a/d", "a/e")
+	list.Entries[0].Version = "3"
+	list.Entries[0].Versions = []*cmn.BucketEntry{{Name: "a/c", Version: "2"}, {Name: "a/c", Version: "1"}}
+	list.Entries[1].Version = "1"
+	list.Entries[2].Flags = cmn.ObjStatusDeleted
+	list.Entries[2].Versions = []*cmn.BucketEntry{{Name: "a/e", Version: "4", Atime: "T"}}
+
+	resp := NewListVersionsResult(query)
+	resp.FillFromAisBckList(list)
+	tassert.Fatalf(t, len(resp.Versions) == 5, "expected 5 versions, got %d", len(resp.Versions))
+	for i, expected := range []struct {
+		key, version string
+		latest       bool
+	}{{"a/c", "3", true}, {"a/c", "2", false}, {"a/c", "1", false}, {"a/d", "1", true}, {"a/e", "4", false}} {
+		v := resp.Versions[i]
+		tassert.Errorf(t, v.Key == expected.key && v.VersionID == expected.version && v.IsLatest == expected.latest,
+			"unexpected version [%d]: %+v", i, v)
+	}
+	tassert.Fatalf(t, len(resp.DeleteMarkers) == 1, "expected 1 delete marker, got %d", len(resp.DeleteMarkers))
+	marker := resp.DeleteMarkers[0]
+	tassert.Errorf(t, marker.Key == "a/e" && marker.IsLatest && marker.LastModified == "T",
+		"unexpected delete marker: %+v", marker)
+	tassert.Errorf(t, resp.IsTruncated && resp.NextKeyMarker == "a/e", "unexpected next key marker %q", resp.NextKeyMarker)
+}
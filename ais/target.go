@@ -21,6 +21,7 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/ais/cloud"
+	"github.com/NVIDIA/aistore/ais/s3compat"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/mono"
@@ -33,6 +34,7 @@ import (
 	"github.com/NVIDIA/aistore/mirror"
 	"github.com/NVIDIA/aistore/nl"
 	"github.com/NVIDIA/aistore/reb"
+	"github.com/NVIDIA/aistore/sse"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/xaction"
@@ -83,6 +85,8 @@ type (
 		rebManager   *reb.Manager
 		dbDriver     dbdriver.Driver
 		transactions transactions
+		quota        quotaTracker
+		repl         replicator
 		gfn          struct {
 			local  localGFN
 			global globalGFN
@@ -213,6 +217,9 @@ func (t *targetrunner) Run() error {
 	if err := fs.CSM.RegisterContentType(fs.WorkfileType, &fs.WorkfileContentResolver{}); err != nil {
 		cmn.ExitLogf("%v", err)
 	}
+	if err := fs.CSM.RegisterContentType(fs.VersionType, &fs.VersionContentResolver{}); err != nil {
+		cmn.ExitLogf("%v", err)
+	}
 
 	dryRunInit()
 	t.gfn.local.tag, t.gfn.global.tag = "local GFN", "global GFN"
@@ -282,6 +289,16 @@ func (t *targetrunner) Run() error {
 	t.dbDriver = driver
 	defer cmn.Close(driver)
 
+	// S3 multipart uploads in progress
+	if err := s3compat.InitUploads(driver); err != nil {
+		glog.Errorf("Failed to load S3 multipart uploads: %v", err)
+		return err
+	}
+
+	// replication to remote clusters (and its persistent backlog)
+	t.repl.init(t)
+	defer t.repl.stop()
+
 	// transactions
 	t.transactions.init(t)
 
@@ -366,7 +383,7 @@ func (t *targetrunner) initRecvHandlers() {
 		},
 		{r: cmn.Tokens, h: t.tokenHandler, net: []string{cmn.NetworkPublic}},
 
-		{r: cmn.Download, h: t.downloadHandler, net: []string{cmn.NetworkIntraControl}},
+		{r: cmn.Download, h: t.downloadHandler, net: []string{cmn.NetworkIntraControl, cmn.NetworkIntraData}},
 		{
 			r: cmn.Sort, h: dsort.SortHandler,
 			net: []string{cmn.NetworkIntraControl, cmn.NetworkIntraData},
@@ -379,6 +396,7 @@ func (t *targetrunner) initRecvHandlers() {
 		{r: cmn.Query, h: t.queryHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl}},
 
 		{r: "/" + cmn.S3, h: t.s3Handler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraData}},
+		{r: "/" + cmn.Metrics, h: t.metricsHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl}},
 
 		{
 			r: "/", h: cmn.InvalidHandler,
@@ -819,6 +837,11 @@ func (t *targetrunner) getObject(w http.ResponseWriter, r *http.Request, query u
 		t.doETL(w, r, query.Get(cmn.URLParamUUID), bck, objName)
 		return
 	}
+	if version := query.Get(cmn.URLParamVersion); version != "" {
+		if current := t.getObjVersion(w, r, lom, version); !current {
+			return
+		}
+	}
 	goi := &getObjInfo{
 		started: started,
 		t:       t,
@@ -952,7 +975,15 @@ func (t *targetrunner) httpobjdelete(w http.ResponseWriter, r *http.Request) {
 		t.invalmsghdlr(w, r, err.Error())
 		return
 	}
-	err, errCode := t.DeleteObject(context.Background(), lom, evict)
+	var (
+		errCode int
+		current = true
+	)
+	if version := query.Get(cmn.URLParamVersion); version != "" && !evict {
+		current, err, errCode = t.delObjVersion(lom, version)
+	} else {
+		err, errCode = t.DeleteObject(context.Background(), lom, evict)
+	}
 	if err != nil {
 		if errCode == http.StatusNotFound {
 			t.invalmsghdlrsilent(w, r,
@@ -965,7 +996,9 @@ func (t *targetrunner) httpobjdelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// EC cleanup if EC is enabled
-	ec.ECM.CleanupObject(lom)
+	if current {
+		ec.ECM.CleanupObject(lom)
+	}
 }
 
 // POST /v1/objects/bucket-name/object-name
@@ -1149,7 +1182,7 @@ func (t *targetrunner) httpecget(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Returns a CT's metadata.
+// Returns a CT's metadata; optionally, validates the CT content first.
 func (t *targetrunner) sendECMetafile(w http.ResponseWriter, r *http.Request, apiItems []string) {
 	bucket, objName := apiItems[0], apiItems[1]
 	bck, err := newBckFromQuery(bucket, r.URL.Query())
@@ -1172,6 +1205,16 @@ func (t *targetrunner) sendECMetafile(w http.ResponseWriter, r *http.Request, ap
 		}
 		return
 	}
+	if cmn.IsParseBool(r.URL.Query().Get(cmn.URLParamECValidate)) {
+		if err := ec.ValidateCT(t, bck, objName, md); err != nil {
+			errCode := http.StatusConflict // damaged
+			if os.IsNotExist(err) || cmn.IsObjNotExist(err) {
+				errCode = http.StatusNotFound
+			}
+			t.invalmsghdlrsilent(w, r, err.Error(), errCode)
+			return
+		}
+	}
 	w.Write(md.Marshal())
 }
 
@@ -1200,14 +1243,26 @@ func (t *targetrunner) sendECCT(w http.ResponseWriter, r *http.Request, apiItems
 		t.invalmsghdlrsilent(w, r, err.Error(), http.StatusNotFound)
 		return
 	}
-	file, err := os.Open(sliceFQN)
+	var (
+		file io.ReadCloser
+		size = finfo.Size()
+	)
+	metaFQN := lom.ParsedFQN.MpathInfo.MakePathFQN(bck.Bck, ec.MetaType, objName)
+	if md, errMeta := ec.LoadMetadata(metaFQN); errMeta == nil && md.SSE != "" {
+		// encrypted slice (see ec.WriteSliceAndMeta) - send plaintext
+		if size, err = sse.PlainSize(size); err == nil {
+			file, err = ec.OpenSlice(sliceFQN, md.SSE, size)
+		}
+	} else {
+		file, err = os.Open(sliceFQN)
+	}
 	if err != nil {
 		t.fsErr(err, sliceFQN)
 		t.invalmsghdlr(w, r, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Length", strconv.FormatInt(finfo.Size(), 10))
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
 	_, err = io.Copy(w, file) // No need for `io.CopyBuffer` as `sendfile` syscall will be used.
 	cmn.Close(file)
 	if err != nil {
@@ -1341,7 +1396,10 @@ func (t *targetrunner) doPut(r *http.Request, lom *cluster.LOM, started time.Tim
 			return err, http.StatusBadRequest
 		}
 		poi.migrated = cluster.RecvType(n) == cluster.Migrated
+		// (rebalance and EC use streams: migration PUT is always a copy - see sendTo)
+		poi.copied = poi.migrated
 	}
+	poi.quota = !poi.migrated
 	sizeStr := header.Get("Content-Length")
 	if sizeStr != "" {
 		if size, ers := strconv.ParseInt(sizeStr, 10, 64); ers == nil {
@@ -1408,14 +1466,28 @@ func (t *targetrunner) DeleteObject(ctx context.Context, lom *cluster.LOM, evict
 		}
 	}
 	if delFromAIS {
+		var vlom *cluster.LOM
+		if conf := lom.VersionConf(); lom.Bck().IsAIS() && conf.KeepHistory() {
+			var err error
+			if vlom, err = lom.ArchiveVersion(); err != nil {
+				return err, 0
+			}
+		}
 		errRet = lom.Remove()
 		if errRet != nil {
+			lom.AbortVersion(vlom)
 			if !os.IsNotExist(errRet) {
 				if cloudErr != nil {
 					glog.Errorf("%s: failed to delete from cloud: %v", lom, cloudErr)
 				}
 				return errRet, 0
 			}
+		} else {
+			if err := lom.CommitVersion(vlom); err != nil {
+				glog.Errorf("%s: failed to archive version %s, err: %v", lom, vlom.Version(), err)
+			}
+			t.quota.update(lom.Bck(), -lom.DiskSize(), -1)
+			t.repl.enqueue(lom)
 		}
 		if evict {
 			cmn.Assert(lom.Bck().IsRemote())
@@ -1480,6 +1552,8 @@ func (t *targetrunner) renameObject(w http.ResponseWriter, r *http.Request, msg
 		lom.Lock(true)
 		if err = lom.Remove(); err != nil {
 			glog.Warningf("%s: failed to delete renamed object source %s: %v", t.si, lom, err)
+		} else {
+			t.repl.enqueue(lom)
 		}
 		lom.Unlock(true)
 	}
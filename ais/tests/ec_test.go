@@ -2257,6 +2257,72 @@ func TestECBucketEncode(t *testing.T) {
 	//
 }
 
+// Removes a slice and the main object, and checks that EC scrubber restores them
+func TestECScrub(t *testing.T) {
+	var (
+		bck = cmn.Bck{
+			Name:     testBucketName + "-scrub",
+			Provider: cmn.ProviderAIS,
+		}
+		proxyURL   = tutils.RandomProxyURL()
+		baseParams = tutils.BaseAPIParams(proxyURL)
+		objName    = "obj-scrub"
+		objPath    = ecTestDir + objName
+	)
+
+	o := ecOptions{
+		minTargets: 4,
+		dataCnt:    2,
+		parityCnt:  1,
+		silent:     testing.Short(),
+	}.init(t, proxyURL)
+
+	newLocalBckWithProps(t, baseParams, bck, defaultECBckProps(o), o)
+	defer tutils.DestroyBucket(t, proxyURL, bck)
+
+	foundParts, mainObjPath := createECFile(t, baseParams, bck, objName, o)
+	sliceToDel := ""
+	for k := range foundParts {
+		ct, err := cluster.NewCTFromFQN(k, nil)
+		tassert.CheckFatal(t, err)
+		if ct.ContentType() == ec.SliceType {
+			sliceToDel = k
+			break
+		}
+	}
+	tassert.Fatalf(t, sliceToDel != "", "no slices found for %s", objPath)
+	ct, err := cluster.NewCTFromFQN(sliceToDel, nil)
+	tassert.CheckFatal(t, err)
+	tutils.Logf("Removing slice %s\n", sliceToDel)
+	tassert.CheckFatal(t, os.Remove(sliceToDel))
+	tassert.CheckFatal(t, os.Remove(ct.Make(ec.MetaType)))
+	tutils.Logf("Removing main object %s\n", mainObjPath)
+	tassert.CheckFatal(t, os.Remove(mainObjPath))
+
+	xactArgs := api.XactReqArgs{Kind: cmn.ActECScrub, Bck: bck, Timeout: rebalanceTimeout}
+	xactID, err := api.StartXaction(baseParams, xactArgs)
+	tassert.CheckFatal(t, err)
+	xactArgs.ID = xactID
+	_, err = api.WaitForXaction(baseParams, xactArgs)
+	tassert.CheckFatal(t, err)
+
+	var (
+		objSize   = int64(ecMinBigSize * 2)
+		sliceSize = ec.SliceSize(objSize, o.dataCnt)
+		totalCnt  = len(foundParts)
+		parts     map[string]ecSliceMD
+	)
+	// re-encoding is asynchronous: slices may still be on their way
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if parts, _ = ecGetAllSlices(t, bck, objName); len(parts) == totalCnt {
+			break
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+	ecCheckSlices(t, parts, bck, objPath, objSize, sliceSize, totalCnt)
+}
+
 func init() {
 	proxyURL := tutils.GetPrimaryURL()
 	primary, err := tutils.GetPrimaryProxy(proxyURL)
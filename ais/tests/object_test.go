@@ -7,6 +7,7 @@ package integration
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -23,6 +24,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/NVIDIA/aistore/ais/s3compat"
 	"github.com/NVIDIA/aistore/api"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
@@ -1663,3 +1665,69 @@ func TestOperationsWithRanges(t *testing.T) {
 		}
 	})
 }
+
+// Multipart upload (S3 API) must respect bucket quota the same way PUT does:
+// both when a part is uploaded and when the object is assembled from parts.
+func TestS3MultipartQuota(t *testing.T) {
+	var (
+		proxyURL   = tutils.RandomProxyURL(t)
+		baseParams = tutils.BaseAPIParams(proxyURL)
+		bck        = cmn.Bck{Name: testBucketName, Provider: cmn.ProviderAIS}
+		objName    = "mpt-quota"
+		tcnt       = int64(tutils.GetClusterMap(t, proxyURL).CountTargets())
+		partSize   = int64(128 * cmn.KiB)
+	)
+	// every target gets (hard_size / #targets) of the quota
+	tutils.CreateFreshBucket(t, proxyURL, bck, cmn.BucketPropsToUpdate{
+		Quota: &cmn.QuotaConfToUpdate{HardSize: api.Int64(2 * partSize * tcnt)},
+	})
+	defer tutils.DestroyBucket(t, proxyURL, bck)
+
+	s3Req := func(method, query string, body []byte) (*http.Response, []byte) {
+		reqURL := proxyURL + cmn.JoinWords(cmn.S3, bck.Name, objName) + "?" + query
+		req, err := http.NewRequest(method, reqURL, bytes.NewReader(body))
+		tassert.CheckFatal(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		tassert.CheckFatal(t, err)
+		defer resp.Body.Close()
+		b, err := ioutil.ReadAll(resp.Body)
+		tassert.CheckFatal(t, err)
+		return resp, b
+	}
+	resp, b := s3Req(http.MethodPost, s3compat.URLParamMptUploads, nil)
+	tassert.Fatalf(t, resp.StatusCode == http.StatusOK, "failed to start upload: %d %s", resp.StatusCode, b)
+	result := &s3compat.InitiateMptUploadResult{}
+	tassert.CheckFatal(t, xml.Unmarshal(b, result))
+	uploadQuery := s3compat.URLParamMptUploadID + "=" + result.UploadID
+	defer s3Req(http.MethodDelete, uploadQuery, nil)
+
+	putPart := func(num int, size int64) *http.Response {
+		body := make([]byte, size)
+		rand.Read(body)
+		query := fmt.Sprintf("%s=%d&%s", s3compat.URLParamMptPartNum, num, uploadQuery)
+		resp, _ := s3Req(http.MethodPut, query, body)
+		return resp
+	}
+
+	// the first part fits, the second one does not
+	resp = putPart(1, partSize)
+	tassert.Fatalf(t, resp.StatusCode == http.StatusOK, "failed to upload part 1: %d", resp.StatusCode)
+	etag := resp.Header.Get(s3compat.HeaderETag)
+	resp = putPart(2, 2*partSize)
+	tassert.Errorf(t, resp.StatusCode == http.StatusInsufficientStorage,
+		"expected part 2 to exceed quota, got status %d", resp.StatusCode)
+
+	// lower the quota, so that the object assembled from the first part does not fit
+	_, err := api.SetBucketProps(baseParams, bck, cmn.BucketPropsToUpdate{
+		Quota: &cmn.QuotaConfToUpdate{HardSize: api.Int64(partSize / 2 * tcnt)},
+	})
+	tassert.CheckFatal(t, err)
+	complete := fmt.Sprintf("<CompleteMultipartUpload><Part><PartNumber>1</PartNumber><ETag>%s</ETag></Part>"+
+		"</CompleteMultipartUpload>", etag)
+	resp, b = s3Req(http.MethodPost, uploadQuery, []byte(complete))
+	tassert.Errorf(t, resp.StatusCode == http.StatusInsufficientStorage,
+		"expected completed upload to exceed quota, got status %d: %s", resp.StatusCode, b)
+
+	_, err = api.HeadObject(baseParams, bck, objName)
+	tassert.Errorf(t, err != nil, "object %s must not exist", objName)
+}
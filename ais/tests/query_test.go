@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
+	"reflect"
 	"sort"
 	"testing"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/query"
 	"github.com/NVIDIA/aistore/tutils"
+	"github.com/NVIDIA/aistore/tutils/readers"
 	"github.com/NVIDIA/aistore/tutils/tassert"
 	jsoniter "github.com/json-iterator/go"
 )
@@ -162,6 +164,96 @@ func TestQueryVersionAndAtime(t *testing.T) {
 	checkQueryDone(t, handle)
 }
 
+func TestQueryContentFilter(t *testing.T) {
+	var (
+		proxyURL   = tutils.RandomProxyURL()
+		baseParams = tutils.BaseAPIParams(proxyURL)
+		bck        = cmn.Bck{
+			Name:     "TESTQUERYBUCKET",
+			Provider: cmn.ProviderAIS,
+		}
+		objects = map[string]string{
+			"a.json":  `{"label": "cat", "meta": {"width": 640}}`,
+			"b.json":  `{"label": "dog", "meta": {"width": 640}}`,
+			"c.json":  `{"label": "cat", "meta": {"width": 320}}`,
+			"d.txt":   "the quick brown fox",
+			"e.gz":    "\x1f\x8bgzipped",
+			"f.empty": "",
+		}
+	)
+
+	tutils.CreateFreshBucket(t, proxyURL, bck)
+	defer tutils.DestroyBucket(t, proxyURL, bck)
+
+	for objName, content := range objects {
+		err := api.PutObject(api.PutObjectArgs{
+			BaseParams: baseParams,
+			Bck:        bck,
+			Object:     objName,
+			Reader:     readers.NewBytesReader([]byte(content)),
+		})
+		tassert.CheckFatal(t, err)
+	}
+
+	tests := []struct {
+		name     string
+		where    *query.FilterMsg
+		inner    *query.FilterMsg
+		expected []string
+	}{
+		{name: "regex", inner: query.ContentRegexFilterMsg(`qu?ick|"dog"`), expected: []string{"b.json", "d.txt"}},
+		{name: "json", inner: query.ContentJSONFilterMsg("label", "cat"), expected: []string{"a.json", "c.json"}},
+		{
+			name: "json-and",
+			inner: query.NewAndFilter(
+				query.ContentJSONFilterMsg("label", "cat"),
+				query.ContentJSONFilterMsg("meta.width", "640"),
+			),
+			expected: []string{"a.json"},
+		},
+		{name: "magic", inner: query.ContentMagicFilterMsg([]byte{0x1f, 0x8b}), expected: []string{"e.gz"}},
+		{
+			name:     "where-and-magic",
+			where:    query.ExtFilterMsg("txt"),
+			inner:    query.ContentRegexFilterMsg("fox$"),
+			expected: []string{"d.txt"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handle, err := api.InitQueryMsg(baseParams, &query.DefMsg{
+				From:        query.FromMsg{Bck: bck},
+				Where:       query.WhereMsg{Filter: test.where},
+				InnerSelect: query.InnerSelectMsg{Filter: test.inner},
+			})
+			tassert.CheckFatal(t, err)
+
+			entries, err := api.NextQueryResults(baseParams, handle, uint(len(objects)))
+			tassert.CheckFatal(t, err)
+			names := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				names = append(names, entry.Name)
+			}
+			sort.Strings(names)
+			tassert.Errorf(t, reflect.DeepEqual(names, test.expected), "expected %v, got %v", test.expected, names)
+
+			checkQueryDone(t, handle)
+		})
+	}
+
+	// invalid content filters must be rejected upfront
+	_, err := api.InitQueryMsg(baseParams, &query.DefMsg{
+		From:        query.FromMsg{Bck: bck},
+		InnerSelect: query.InnerSelectMsg{Filter: query.ContentRegexFilterMsg("(")},
+	})
+	tassert.Errorf(t, err != nil, "expected invalid regex to fail")
+	_, err = api.InitQueryMsg(baseParams, &query.DefMsg{
+		From:        query.FromMsg{Bck: bck},
+		InnerSelect: query.InnerSelectMsg{Filter: query.SizeLEFilterMsg(10)},
+	})
+	tassert.Errorf(t, err != nil, "expected metadata filter in inner select to fail")
+}
+
 func TestQueryWorkersTargets(t *testing.T) {
 	var (
 		proxyURL   = tutils.RandomProxyURL()
@@ -5,9 +5,11 @@
 package ais
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
@@ -28,6 +30,7 @@ import (
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/nl"
 	"github.com/NVIDIA/aistore/reb"
+	"github.com/NVIDIA/aistore/scrub"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/sys"
 	"github.com/NVIDIA/aistore/xaction"
@@ -338,6 +341,8 @@ func (t *targetrunner) httpdaeget(w http.ResponseWriter, r *http.Request) {
 	case cmn.GetWhatDiskStats:
 		diskStats := fs.GetSelectedDiskStats()
 		t.writeJSON(w, r, diskStats, httpdaeWhat)
+	case cmn.GetWhatScrubReport:
+		t.writeJSON(w, r, scrub.LastReport(), httpdaeWhat)
 	case cmn.GetWhatRemoteAIS:
 		conf, ok := cmn.GCO.Get().Cloud.ProviderConf(cmn.ProviderAIS)
 		if !ok {
@@ -1157,6 +1162,81 @@ func (t *targetrunner) LookupRemoteSingle(lom *cluster.LOM, tsi *cluster.Snode)
 	return
 }
 
+// HeadObjT2T asks the given target for the object's properties; if the object exists
+// the function fills in the LOM's size, version, and checksum and returns true.
+// Non-nil error means that the existence could not be established.
+func (t *targetrunner) HeadObjT2T(lom *cluster.LOM, tsi *cluster.Snode) (ok bool, err error) {
+	header := make(http.Header)
+	header.Add(cmn.HeaderCallerID, t.Snode().ID())
+	query := make(url.Values)
+	query.Add(cmn.URLParamSilent, "true")
+	query = cmn.AddBckToQuery(query, lom.Bck().Bck)
+	args := callArgs{
+		si: tsi,
+		req: cmn.ReqArgs{
+			Method: http.MethodHead,
+			Header: header,
+			Base:   tsi.URL(cmn.NetworkIntraControl),
+			Path:   cmn.JoinWords(cmn.Version, cmn.Objects, lom.BckName(), lom.ObjName),
+			Query:  query,
+		},
+		timeout: lom.Config().Timeout.CplaneOperation,
+	}
+	res := t.call(args)
+	if res.err != nil {
+		if res.status != http.StatusNotFound {
+			err = res.err
+		}
+		return
+	}
+	objProps := &cmn.ObjectProps{}
+	err = cmn.IterFields(objProps, func(tag string, field cmn.IterField) (error, bool) {
+		return field.SetValue(res.header.Get(tag), true /*force*/), false
+	}, cmn.IterOpts{OnlyRead: false})
+	if err != nil || !objProps.Present {
+		return
+	}
+	lom.SetSize(objProps.Size)
+	lom.SetVersion(objProps.Version)
+	if ty := objProps.Checksum.Type; ty != "" && ty != cmn.ChecksumNone {
+		lom.SetCksum(cmn.NewCksum(ty, objProps.Checksum.Value))
+	} else {
+		lom.SetCksum(nil)
+	}
+	ok = true
+	return
+}
+
+// GetObjT2T GETs the object from the given target and returns the (streaming)
+// reader of its content - the caller is expected to close it.
+func (t *targetrunner) GetObjT2T(ctx context.Context, lom *cluster.LOM, tsi *cluster.Snode) (io.ReadCloser, error) {
+	header := make(http.Header)
+	header.Add(cmn.HeaderCallerID, t.Snode().ID())
+	query := cmn.AddBckToQuery(nil, lom.Bck().Bck)
+	reqArgs := cmn.ReqArgs{
+		Method: http.MethodGet,
+		Base:   tsi.URL(cmn.NetworkIntraData),
+		Header: header,
+		Path:   cmn.JoinWords(cmn.Version, cmn.Objects, lom.BckName(), lom.ObjName),
+		Query:  query,
+	}
+	req, err := reqArgs.Req()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.httpclientGetPut.Do(req.WithContext(ctx)) // nolint:bodyclose // closed by the caller
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		b, _ := ioutil.ReadAll(resp.Body)
+		cmn.Close(resp.Body)
+		httpErr, _ := cmn.NewHTTPError(req, string(b), resp.StatusCode)
+		return nil, httpErr
+	}
+	return resp.Body, nil
+}
+
 // lookupRemoteAll sends the broadcast message to all targets to see if they
 // have the specific object.
 func (t *targetrunner) lookupRemoteAll(lom *cluster.LOM, smap *smapX) *cluster.Snode {
@@ -7,6 +7,7 @@ package ais
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"regexp"
 	"time"
@@ -95,8 +96,12 @@ func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 		}, dlJob)
 		response, respErr, statusCode = downloaderXact.Download(dlJob)
 	case http.MethodGet:
-		_, err := cmn.MatchRESTItems(r.URL.Path, 0, false, cmn.Version, cmn.Download)
+		items, err := cmn.MatchRESTItems(r.URL.Path, 0, true, cmn.Version, cmn.Download)
 		debug.AssertNoErr(err)
+		if len(items) > 0 && items[0] == cmn.Records {
+			t.sendManifestRows(w, r)
+			return
+		}
 
 		payload := &downloader.DlAdminBody{}
 		if err := cmn.ReadJSON(w, r, payload); err != nil {
@@ -149,10 +154,37 @@ func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 					items[0], cmn.Abort, cmn.Remove))
 			return
 		}
+	case http.MethodPut:
+		items, err := cmn.MatchRESTItems(r.URL.Path, 1, false, cmn.Version, cmn.Download)
+		debug.AssertNoErr(err)
+
+		payload := &downloader.DlAdminBody{}
+		if err = cmn.ReadJSON(w, r, payload); err != nil {
+			return
+		}
+		debug.AssertNoErr(payload.Validate(true))
+
+		switch items[0] {
+		case cmn.Pause:
+			if glog.FastV(4, glog.SmoduleAIS) {
+				glog.Infof("Pausing download: %v", payload)
+			}
+			response, respErr, statusCode = downloaderXact.PauseJob(payload.ID)
+		case cmn.Resume:
+			if glog.FastV(4, glog.SmoduleAIS) {
+				glog.Infof("Resuming download: %v", payload)
+			}
+			response, respErr, statusCode = downloaderXact.ResumeJob(payload.ID)
+		default:
+			cmn.AssertMsg(false,
+				fmt.Sprintf("Invalid action for PUT request: %s (expected either %s or %s).",
+					items[0], cmn.Pause, cmn.Resume))
+			return
+		}
 	default:
 		cmn.AssertMsg(false,
-			fmt.Sprintf("Invalid http method %s; expected one of %s, %s, %s",
-				r.Method, http.MethodGet, http.MethodPost, http.MethodDelete))
+			fmt.Sprintf("Invalid http method %s; expected one of %s, %s, %s, %s",
+				r.Method, http.MethodGet, http.MethodPost, http.MethodDelete, http.MethodPut))
 		return
 	}
 
@@ -168,3 +200,39 @@ func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+// GET /v1/download/records?uuid=<job ID>&tid=<target ID>
+// streams the manifest rows of the target as the manifest is being split
+// (see downloader.ManifestRows).
+func (t *targetrunner) sendManifestRows(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	rows, err := downloader.ManifestRows(query.Get(cmn.URLParamUUID), query.Get(cmn.URLParamTargetID))
+	if err != nil {
+		t.invalmsghdlrsilent(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	defer cmn.Close(rows)
+	var (
+		buf, slab = t.gmm.Alloc()
+		flusher   = w.(http.Flusher)
+	)
+	defer slab.Free(buf)
+	for {
+		n, err := rows.Read(buf)
+		if n > 0 {
+			if _, errW := w.Write(buf[:n]); errW != nil {
+				return
+			}
+			flusher.Flush()
+		}
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			// NOTE: the status has been sent - the receiver finds out by the
+			//  lack of the final row.
+			glog.Errorf("%s: failed to send manifest rows: %v", t.si, err)
+			return
+		}
+	}
+}
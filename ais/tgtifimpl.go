@@ -102,6 +102,7 @@ func (t *targetrunner) RunLRU(id string, force bool, bcks ...cmn.Bck) {
 		Buckets:             bcks,
 		GetFSUsedPercentage: ios.GetFSUsedPercentage,
 		GetFSStats:          ios.GetFSStats,
+		KeepWork:            isMptPartInUse,
 	}
 
 	xlru.AddNotif(&xaction.NotifXact{
@@ -136,10 +137,12 @@ func (t *targetrunner) PutObject(lom *cluster.LOM, params cluster.PutObjectParam
 		ctx:     context.Background(),
 		started: params.Started,
 		skipEC:  params.SkipEncode,
+		quota:   params.CheckQuota,
 	}
 	if params.RecvType == cluster.Migrated {
 		poi.cksumToCheck = params.Cksum
 		poi.migrated = true
+		poi.copied = params.Copied
 	} else if params.RecvType == cluster.ColdGet {
 		poi.cold = true
 		poi.cksumToCheck = params.Cksum
@@ -231,6 +234,8 @@ func (t *targetrunner) _recvObjDM(w http.ResponseWriter, hdr transport.ObjHdr, o
 		Cksum:        cmn.NewCksum(hdr.ObjAttrs.CksumType, hdr.ObjAttrs.CksumValue),
 		Started:      time.Now(),
 		WithFinalize: true,
+		Copied:       true,
+		CheckQuota:   true,
 	}
 	if err := t.PutObject(lom, params); err != nil {
 		glog.Error(err)
@@ -408,6 +413,10 @@ func (t *targetrunner) PromoteFile(params cluster.PromoteFileParams) (nlom *clus
 		}
 		glog.Infof("promote%s %s => %s", s, params.SrcFQN, lom)
 	}
+	if lom.Bprops().SSE.Enabled {
+		return t.promoteEncrypt(lom, params)
+	}
+	lom.SetSSEKeyID("") // (when overwriting encrypted object)
 	var (
 		cksum   *cmn.CksumHash
 		fi      os.FileInfo
@@ -462,6 +471,35 @@ func (t *targetrunner) PromoteFile(params cluster.PromoteFileParams) (nlom *clus
 	return
 }
 
+// promoting into a bucket with server-side encryption: the file cannot be
+// used as is and goes through the regular (encrypting) PUT path instead
+func (t *targetrunner) promoteEncrypt(lom *cluster.LOM, params cluster.PromoteFileParams) (*cluster.LOM, error) {
+	fh, err := cmn.NewFileHandle(params.SrcFQN)
+	if err != nil {
+		return nil, err
+	}
+	poi := &putObjInfo{
+		started:      time.Now(),
+		t:            t,
+		lom:          lom,
+		r:            fh, // closed by `writeToFile`
+		cksumToCheck: params.Cksum,
+		workFQN:      fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut),
+	}
+	if err = poi.writeToFile(); err != nil {
+		return nil, err
+	}
+	if err, _ = poi.finalize(); err != nil {
+		return nil, err
+	}
+	if !params.KeepOrig {
+		if errRm := cmn.RemoveFile(params.SrcFQN); errRm != nil {
+			glog.Errorf("%s: failed to remove promoted %q, err: %v", t.si, params.SrcFQN, errRm)
+		}
+	}
+	return lom, nil
+}
+
 //
 // implements health.fspathDispatcher interface
 //
@@ -0,0 +1,85 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/ais/s3compat"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/lifecycle"
+	"github.com/NVIDIA/aistore/nl"
+	"github.com/NVIDIA/aistore/xaction"
+	"github.com/NVIDIA/aistore/xaction/xreg"
+)
+
+// Bucket lifecycle rules (see cmn.LifecycleConf and the lifecycle package)
+// are enforced periodically, and only when at least one ais bucket has them.
+
+const lifecycleHousekeepT = 6 * time.Hour
+
+func (t *targetrunner) lifecycleHk() time.Duration {
+	var (
+		provider = cmn.ProviderAIS
+		enabled  bool
+	)
+	t.owner.bmd.get().Range(&provider, nil, func(bck *cluster.Bck) bool {
+		enabled = bck.Props.Lifecycle.Enabled()
+		return enabled
+	})
+	if enabled {
+		go t.runLifecycle("")
+	}
+	return lifecycleHousekeepT
+}
+
+func (t *targetrunner) runLifecycle(id string) {
+	regToIC := id == ""
+	if regToIC {
+		id = cmn.GenUUID()
+	}
+	xlc := xreg.RenewLifecycle(id)
+	if xlc == nil {
+		return
+	}
+	if regToIC && xlc.ID().String() == id {
+		regMsg := xactRegMsg{UUID: id, Kind: cmn.ActLifecycle, Srcs: []string{t.si.ID()}}
+		msg := t.newAisMsg(&cmn.ActionMsg{Action: cmn.ActRegGlobalXaction, Value: regMsg}, nil, nil)
+		t.bcastToIC(msg, false /*wait*/)
+	}
+	xlc.AddNotif(&xaction.NotifXact{
+		NotifBase: nl.NotifBase{When: cluster.UponTerm, Dsts: []string{equalIC}, F: t.callerNotifyFin},
+		Xact:      xlc,
+	})
+	ini := lifecycle.InitLifecycle{
+		T:            t,
+		Xaction:      xlc.(*lifecycle.Xaction),
+		AbortUploads: t.abortStaleMpts,
+	}
+	lifecycle.Run(&ini) // blocking
+	xlc.Finish()
+}
+
+// discards S3 multipart uploads that were started longer than the rule allows
+func (t *targetrunner) abortStaleMpts(bck *cluster.Bck, rule *cmn.LifecycleRule, now time.Time) {
+	if !bck.Ns.IsGlobal() { // (multipart uploads are supported only in the global namespace)
+		return
+	}
+	ids := s3compat.StaleUploads(bck.Name, func(objName string, started time.Time) bool {
+		return rule.AppendAborted(objName, started, now)
+	})
+	for _, id := range ids {
+		_, objName, err := s3compat.UploadObj(id)
+		if err != nil {
+			continue
+		}
+		lom := &cluster.LOM{T: t, ObjName: objName}
+		if err := lom.Init(bck.Bck); err != nil {
+			continue
+		}
+		t.cleanupMpt(lom, id)
+	}
+}
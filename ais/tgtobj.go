@@ -25,6 +25,7 @@ import (
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/sse"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/xaction/xreg"
 )
@@ -54,10 +55,15 @@ type (
 		// Determines if the object was already in cluster and is being PUT due to
 		// migration or replication of some sort.
 		migrated bool
+		// Determines if the migrated object is a new copy (copy bucket, rename,
+		// promote) rather than relocated by rebalance or EC.
+		copied bool
 		// Determines if the recv is cold recv: either from another cluster or cloud.
 		cold bool
 		// if true, poi won't erasure-encode an object when finalizing
 		skipEC bool
+		// enforce bucket quota
+		quota bool
 	}
 
 	getObjInfo struct {
@@ -130,6 +136,15 @@ func (poi *putObjInfo) putObject() (err error, errCode int) {
 		if err := poi.writeToFile(); err != nil {
 			return err, http.StatusInternalServerError
 		}
+		if poi.quota {
+			prevSize, prevObjs := quotaPrev(lom)
+			if err := poi.t.checkQuota(lom, lom.DiskSize()-prevSize, 1-prevObjs); err != nil {
+				if errRm := cmn.RemoveFile(poi.workFQN); errRm != nil {
+					glog.Errorf("Nested error: %s => (remove %s => err: %v)", err, poi.workFQN, errRm)
+				}
+				return err, http.StatusInsufficientStorage
+			}
+		}
 		if err, errCode := poi.finalize(); err != nil {
 			return err, errCode
 		}
@@ -208,14 +223,26 @@ func (poi *putObjInfo) tryFinalize() (err error, errCode int) {
 	lom.Lock(true)
 	defer lom.Unlock(true)
 
-	if bck.IsAIS() && lom.VersionConf().Enabled && !poi.migrated {
+	var vlom *cluster.LOM
+	if conf := lom.VersionConf(); bck.IsAIS() && conf.Enabled && !poi.migrated {
+		if conf.KeepHistory() {
+			if vlom, err = lom.ArchiveVersion(); err != nil {
+				return fmt.Errorf("%s: failed to archive version %s: %w", lom, lom.Version(), err), 0
+			}
+		}
 		if err = lom.IncVersion(); err != nil {
+			lom.AbortVersion(vlom)
 			return
 		}
 	}
+	prevSize, prevObjs := quotaPrev(lom)
 	if err := cmn.Rename(poi.workFQN, lom.FQN); err != nil {
+		lom.AbortVersion(vlom)
 		return fmt.Errorf("rename failed => %s: %w", lom, err), 0
 	}
+	if err := lom.CommitVersion(vlom); err != nil {
+		glog.Errorf("%s: failed to archive version %s, err: %v", lom, vlom.Version(), err)
+	}
 	if lom.HasCopies() {
 		if err = lom.DelAllCopies(); err != nil {
 			return
@@ -225,6 +252,10 @@ func (poi *putObjInfo) tryFinalize() (err error, errCode int) {
 		return
 	}
 	lom.ReCache()
+	poi.t.quota.update(bck, lom.DiskSize()-prevSize, 1-prevObjs)
+	if !poi.migrated || poi.copied {
+		poi.t.repl.enqueue(lom)
+	}
 	return
 }
 
@@ -274,6 +305,7 @@ func (poi *putObjInfo) writeToFile() (err error) {
 	var (
 		written int64
 		file    *os.File
+		encw    *sse.Writer
 		buf     []byte
 		slab    *memsys.Slab
 		reader  = poi.r
@@ -313,6 +345,21 @@ func (poi *putObjInfo) writeToFile() (err error) {
 			}
 		}
 	}()
+	// encryption at rest (NOTE: checksums are still computed over plaintext)
+	if sseConf := &poi.lom.Bprops().SSE; sseConf.Enabled {
+		var key []byte
+		if key, err = sse.Key(sseConf.KeyID); err != nil {
+			err = fmt.Errorf("%s: failed to encrypt, err: %w", poi.lom, err)
+			return
+		}
+		if encw, err = sse.NewWriter(file, key); err != nil {
+			return
+		}
+		writer = encw
+		poi.lom.SetSSEKeyID(sseConf.KeyID)
+	} else {
+		poi.lom.SetSSEKeyID("")
+	}
 	// checksums
 	if conf.Type == cmn.ChecksumNone {
 		goto write
@@ -356,6 +403,11 @@ write:
 	if err != nil {
 		return
 	}
+	if encw != nil {
+		if err = encw.Close(); err != nil {
+			return
+		}
+	}
 	// validate
 	if cksums.given != nil {
 		cksums.given.Finalize()
@@ -692,7 +744,7 @@ func (goi *getObjInfo) getFromNeighbor(lom *cluster.LOM, tsi *cluster.Snode) (ok
 
 func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode int) {
 	var (
-		file    *os.File
+		file    cmn.ReadAtOpenCloser
 		sgl     *memsys.SGL
 		slab    *memsys.Slab
 		buf     []byte
@@ -742,7 +794,7 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode in
 		// best-effort GET load balancing (see also mirror.findLeastUtilized())
 		fqn = goi.lom.LoadBalanceGET()
 	}
-	file, err = os.Open(fqn)
+	file, err = goi.lom.OpenFQN(fqn) // (decrypts if need be)
 	if err != nil {
 		if os.IsNotExist(err) {
 			errCode = http.StatusNotFound
@@ -811,6 +863,9 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode in
 	w := goi.w
 	if r == nil {
 		reader = file
+		if fh, ok := file.(*cmn.FileHandle); ok {
+			reader = fh.File // allows for `sendfile`
+		}
 		if goi.chunked {
 			// Explicitly hiding `ReadFrom` implemented for `http.ResponseWriter`
 			// so the `sendfile` syscall won't be used.
@@ -847,6 +902,7 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode in
 	// GFN: atime must be already set
 	if !coldGet && !goi.isGFN {
 		goi.lom.SetAtimeUnix(goi.started.UnixNano())
+		goi.lom.IncAccessCount()
 		goi.lom.ReCache() // GFN and cold GETs already did this
 	}
 
@@ -881,6 +937,11 @@ func (aoi *appendObjInfo) appendObject() (newHandle string, err error, errCode i
 	filePath := aoi.hi.filePath
 	switch aoi.op {
 	case cmn.AppendOp:
+		// (the appended content is accounted for when flushed)
+		if err = aoi.t.checkQuota(aoi.lom, aoi.size, 0); err != nil {
+			errCode = http.StatusInsufficientStorage
+			return
+		}
 		var f *os.File
 		if filePath == "" {
 			filePath = fs.CSM.GenContentParsedFQN(aoi.lom.ParsedFQN, fs.WorkfileType, fs.WorkfileAppend)
@@ -934,6 +995,17 @@ func (aoi *appendObjInfo) appendObject() (newHandle string, err error, errCode i
 			errCode = http.StatusInternalServerError
 			return
 		}
+		var (
+			prevSize, prevObjs = quotaPrev(aoi.lom)
+			size               int64
+		)
+		if finfo, err := os.Stat(filePath); err == nil {
+			size = finfo.Size()
+		}
+		if err = aoi.t.checkQuota(aoi.lom, size-prevSize, 1-prevObjs); err != nil {
+			errCode = http.StatusInsufficientStorage
+			return
+		}
 		params := cluster.PromoteFileParams{
 			SrcFQN:    filePath,
 			Bck:       aoi.lom.Bck(),
@@ -946,6 +1018,7 @@ func (aoi *appendObjInfo) appendObject() (newHandle string, err error, errCode i
 		if _, err := aoi.t.PromoteFile(params); err != nil {
 			return "", err, 0
 		}
+		aoi.t.quota.update(aoi.lom.Bck(), size-prevSize, 1-prevObjs)
 	default:
 		cmn.AssertMsg(false, aoi.op)
 	}
@@ -1081,9 +1154,20 @@ func (coi *copyObjInfo) copyObject(srcLOM *cluster.LOM, objNameTo string) (copie
 		return
 	}
 
+	var prevSize, prevObjs int64
+	if !coi.localOnly {
+		prevSize, prevObjs = quotaPrev(dst)
+		if err = coi.t.checkQuota(dst, srcLOM.Size()-prevSize, 1-prevObjs); err != nil {
+			return
+		}
+	}
 	if dst, err = srcLOM.CopyObject(dst.FQN, coi.Buf); err == nil {
 		copied = true
 		dst.ReCache()
+		if !coi.localOnly {
+			coi.t.quota.update(dst.Bck(), dst.DiskSize()-prevSize, 1-prevObjs)
+			coi.t.repl.enqueue(dst)
+		}
 		if coi.finalize {
 			coi.t.putMirror(dst)
 		}
@@ -1145,6 +1229,8 @@ func (coi *copyObjInfo) copyReader(lom *cluster.LOM, objNameTo string) (copied b
 		WorkFQN:      fs.CSM.GenContentFQN(lom.FQN, fs.WorkfileType, "cpy-dp"),
 		WithFinalize: true,
 		RecvType:     cluster.Migrated,
+		Copied:       true,
+		CheckQuota:   true,
 	}
 
 	if err := coi.t.PutObject(dst, params); err != nil {
@@ -1217,8 +1303,8 @@ func (coi *copyObjInfo) putRemote(lom *cluster.LOM, params cluster.SendToParams)
 			return true, lom.Size(), nil
 		}
 
-		var file *cmn.FileHandle // Closed by `SendTo()`
-		if file, err = cmn.NewFileHandle(lom.FQN); err != nil {
+		var file cmn.ReadAtOpenCloser // Closed by `SendTo()`
+		if file, err = lom.Open(); err != nil {
 			return false, 0, fmt.Errorf("failed to open %s, err: %v", lom.FQN, err)
 		}
 		params.Reader = file
@@ -0,0 +1,207 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Bucket quotas (see cmn.QuotaConf) are cluster-wide while objects are
+// distributed between targets (HRW). Therefore, each target enforces its
+// share of the quota (limit divided by the number of targets) against the
+// local usage of the bucket.
+//
+// Local usage is computed (lazily) from the mountpaths, updated upon each
+// PUT, APPEND and DELETE, and periodically recomputed to account for
+// everything else (rebalance, LRU, lifecycle, etc.).
+
+const quotaHousekeepT = time.Minute
+
+type (
+	quotaTracker struct {
+		mu   sync.Mutex
+		bcks map[uint64]*bckUsage // by bucket ID
+	}
+	bckUsage struct {
+		size    int64
+		objects int64
+		warned  bool // soft limit exceeded and the warning logged
+	}
+)
+
+func (q *quotaTracker) get(bck *cluster.Bck) (usage bckUsage, ok bool) {
+	q.mu.Lock()
+	if u, exists := q.bcks[bck.Props.BID]; exists {
+		usage, ok = *u, true
+	}
+	q.mu.Unlock()
+	return
+}
+
+func (q *quotaTracker) set(bck *cluster.Bck, size, objects int64) {
+	q.mu.Lock()
+	if q.bcks == nil {
+		q.bcks = make(map[uint64]*bckUsage, 4)
+	}
+	if u, exists := q.bcks[bck.Props.BID]; exists {
+		u.size, u.objects = size, objects
+	} else {
+		q.bcks[bck.Props.BID] = &bckUsage{size: size, objects: objects}
+	}
+	q.mu.Unlock()
+}
+
+// update adds the deltas to the usage of the bucket (if tracked)
+func (q *quotaTracker) update(bck *cluster.Bck, size, objects int64) {
+	q.mu.Lock()
+	if u, exists := q.bcks[bck.Props.BID]; exists {
+		u.size += size
+		u.objects += objects
+	}
+	q.mu.Unlock()
+}
+
+// warn returns true only once per crossing of a soft limit
+func (q *quotaTracker) warn(bck *cluster.Bck, exceeded bool) (warn bool) {
+	q.mu.Lock()
+	if u, exists := q.bcks[bck.Props.BID]; exists {
+		warn = exceeded && !u.warned
+		u.warned = exceeded
+	}
+	q.mu.Unlock()
+	return
+}
+
+// walks all mountpaths; in mirrored buckets the copies are accounted for
+// approximately (same as the "fast" bucket summary)
+func bckUsageFS(bck *cluster.Bck) (size, objects int64, err error) {
+	availablePaths, _ := fs.Get()
+	for _, mpathInfo := range availablePaths {
+		opts := &fs.Options{
+			Mpath: mpathInfo,
+			Bck:   bck.Bck,
+			CTs:   []string{fs.ObjectType},
+			Callback: func(fqn string, de fs.DirEntry) error {
+				if de.IsDir() {
+					return nil
+				}
+				if finfo, err := os.Stat(fqn); err == nil {
+					size += finfo.Size()
+					objects++
+				}
+				return nil
+			},
+		}
+		if err = fs.Walk(opts); err != nil && !os.IsNotExist(err) {
+			return
+		}
+	}
+	err = nil
+	if copies := bck.Props.Mirror.Copies; bck.Props.Mirror.Enabled && copies > 1 {
+		size /= copies
+		objects /= copies
+	}
+	return
+}
+
+/////////////////////////
+// targetrunner: quota //
+/////////////////////////
+
+// quotaHk recomputes local usage of all buckets that have quotas
+func (t *targetrunner) quotaHk() time.Duration {
+	bcks := make(map[uint64]*cluster.Bck, 4)
+	t.owner.bmd.get().Range(nil, nil, func(bck *cluster.Bck) bool {
+		if bck.Props.Quota.Enabled() {
+			bcks[bck.Props.BID] = bck
+		}
+		return false
+	})
+	t.quota.mu.Lock()
+	for bid := range t.quota.bcks {
+		if _, ok := bcks[bid]; !ok {
+			delete(t.quota.bcks, bid)
+		}
+	}
+	t.quota.mu.Unlock()
+	for _, bck := range bcks {
+		size, objects, err := bckUsageFS(bck)
+		if err != nil {
+			glog.Errorf("%s: failed to compute usage of %s, err: %v", t.si, bck, err)
+			continue
+		}
+		t.quota.set(bck, size, objects)
+	}
+	return quotaHousekeepT
+}
+
+// checkQuota returns cmn.ErrorQuotaExceeded if storing additional `size` bytes
+// and `objects` objects would exceed this target's share of the bucket's hard
+// quota; exceeding soft quota is logged (once).
+func (t *targetrunner) checkQuota(lom *cluster.LOM, size, objects int64) error {
+	var (
+		bck   = lom.Bck()
+		quota = &bck.Props.Quota
+	)
+	if !quota.Enabled() {
+		return nil
+	}
+	usage, ok := t.quota.get(bck)
+	if !ok {
+		s, o, err := bckUsageFS(bck)
+		if err != nil {
+			return err
+		}
+		t.quota.set(bck, s, o)
+		usage.size, usage.objects = s, o
+	}
+	var (
+		tcnt    = int64(t.owner.smap.get().CountTargets())
+		newSize = usage.size + size
+		newObjs = usage.objects + objects
+	)
+	if limit := quotaShare(quota.HardSize, tcnt); limit > 0 && newSize > limit {
+		return cmn.NewErrorQuotaExceeded(bck.Bck, "size", newSize, limit)
+	}
+	if limit := quotaShare(quota.HardObjects, tcnt); limit > 0 && newObjs > limit {
+		return cmn.NewErrorQuotaExceeded(bck.Bck, "objects", newObjs, limit)
+	}
+	var (
+		softSize = quotaShare(quota.SoftSize, tcnt)
+		softObjs = quotaShare(quota.SoftObjects, tcnt)
+		exceeded = (softSize > 0 && newSize > softSize) || (softObjs > 0 && newObjs > softObjs)
+	)
+	if t.quota.warn(bck, exceeded) {
+		glog.Warningf("%s: bucket %s exceeded soft quota: size %s, objects %d (%s)",
+			t.si, bck, cmn.B2S(newSize, 2), newObjs, quota)
+	}
+	return nil
+}
+
+// returns the current (on-disk) size of the object and 1 if it exists
+// (used to account for overwrites)
+func quotaPrev(lom *cluster.LOM) (size, objects int64) {
+	if !lom.Bprops().Quota.Enabled() {
+		return
+	}
+	if finfo, err := os.Stat(lom.FQN); err == nil {
+		size, objects = finfo.Size(), 1
+	}
+	return
+}
+
+func quotaShare(limit, tcnt int64) int64 {
+	if limit == 0 || tcnt <= 1 {
+		return limit
+	}
+	return (limit + tcnt - 1) / tcnt
+}
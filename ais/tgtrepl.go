@@ -0,0 +1,433 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais/cloud"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/stats"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Asynchronous replication of ais buckets to remote AIS clusters (see
+// cmn.ReplicateConf).
+//
+// Each target keeps track of its own (HRW) objects that were PUT or deleted
+// and have not yet been replicated. The tracking is coalesced: an object
+// that gets overwritten (or deleted) multiple times is replicated only once,
+// in its latest state. The pending set is persisted in the target's DB
+// (dbdriver) and survives restarts.
+//
+// When the link to a remote cluster fails, replication to that cluster backs
+// off (exponentially, up to replMaxBackoff) while periodically probing the
+// link. Once the link is restored (and, likewise, upon restart with a
+// non-empty backlog) the target switches to catch-up mode and drains the
+// backlog with more workers.
+//
+// Note that objects migrated by rebalance are not replicated (and are not
+// deleted from the remote cluster) by the targets they have left.
+
+const (
+	replCollection     = "replication"
+	replHousekeepT     = 10 * time.Second
+	replWorkers        = 4
+	replCatchupWorkers = 16
+	replCatchupLen     = 1000 // stay in catch-up mode while the backlog is longer than
+	replBatchSize      = 1024
+	replMinBackoff     = time.Second
+	replMaxBackoff     = time.Minute
+)
+
+type (
+	replicator struct {
+		t       *targetrunner
+		mu      sync.Mutex
+		pending map[string]*replEntry // by object uname
+		links   map[string]*replLink  // by remote cluster (alias or UUID)
+		kickCh  chan struct{}
+		stopCh  *cmn.StopCh
+		stopped chan struct{}
+		catchup bool
+		lag     int64 // last reported (stats.ReplLag)
+	}
+	// persistent part of the entry: the object and the time it was first
+	// modified (and not yet replicated) - the rest is runtime state
+	replEntry struct {
+		Bck      cmn.Bck `json:"bck"`
+		ObjName  string  `json:"obj"`
+		Since    int64   `json:"since,string"`
+		seq      int64   // incremented upon each modification
+		busy     bool    // being replicated
+		failures int     // consecutive (non-link) failures
+		retryAt  int64   // when failed - not until
+	}
+	replLink struct {
+		down    bool
+		since   time.Time
+		backoff time.Duration
+		retryAt time.Time
+	}
+	replTask struct {
+		uname string
+		e     *replEntry
+		seq   int64 // at the time the task was taken
+		bck   *cluster.Bck
+	}
+)
+
+var (
+	errReplMigrated = errors.New("migrated")
+	errReplStopped  = errors.New("stopped")
+)
+
+func (r *replicator) init(t *targetrunner) {
+	r.t = t
+	r.pending = make(map[string]*replEntry, 64)
+	r.links = make(map[string]*replLink, 2)
+	r.kickCh = make(chan struct{}, 1)
+	r.stopCh = cmn.NewStopCh()
+	r.stopped = make(chan struct{})
+
+	records, err := t.DB().GetAll(replCollection, "")
+	if err != nil && !dbdriver.IsErrNotFound(err) {
+		glog.Errorf("%s: failed to load replication backlog, err: %v", t.si, err)
+	}
+	for uname, rec := range records {
+		e := &replEntry{}
+		if err := jsoniter.Unmarshal([]byte(rec), e); err != nil {
+			glog.Errorf("%s: invalid replication record %q, err: %v", t.si, uname, err)
+			_ = t.DB().Delete(replCollection, uname)
+			continue
+		}
+		r.pending[uname] = e
+	}
+	if n := len(r.pending); n > 0 {
+		glog.Infof("%s: replication backlog: %d object(s) - catching up", t.si, n)
+		r.catchup = true
+		t.statsT.Add(stats.ReplPending, int64(n))
+	}
+	hk.Reg("replication", r.housekeep, replHousekeepT)
+	go r.run()
+}
+
+func (r *replicator) stop() {
+	r.stopCh.Close()
+	<-r.stopped
+}
+
+// enqueue is called upon finalizing (PUT, APPEND, promote, copy) and
+// deleting (including rename) of the object
+func (r *replicator) enqueue(lom *cluster.LOM) {
+	if !lom.Bprops().Replicate.Enabled || r.pending == nil {
+		return
+	}
+	uname := lom.Uname()
+	r.mu.Lock()
+	if e, ok := r.pending[uname]; ok {
+		e.seq++
+		r.mu.Unlock()
+		r.kick()
+		return
+	}
+	e := &replEntry{Bck: lom.Bck().Bck, ObjName: lom.ObjName, Since: time.Now().UnixNano()}
+	e.Bck.Props = nil
+	r.pending[uname] = e
+	// NOTE: the record is persisted (and removed - see done) under the lock
+	// to keep the DB in sync with the pending set
+	if err := r.t.DB().Set(replCollection, uname, e); err != nil {
+		glog.Errorf("%s: failed to persist %s for replication, err: %v", r.t.si, lom, err)
+	}
+	r.mu.Unlock()
+
+	r.t.statsT.Add(stats.ReplPending, 1)
+	r.kick()
+}
+
+func (r *replicator) kick() {
+	select {
+	case r.kickCh <- struct{}{}:
+	default:
+	}
+}
+
+func (r *replicator) run() {
+	ticker := time.NewTicker(replMinBackoff)
+	defer func() {
+		ticker.Stop()
+		close(r.stopped)
+	}()
+	for {
+		if tasks := r.due(); len(tasks) > 0 {
+			r.process(tasks)
+			continue
+		}
+		select {
+		case <-r.kickCh:
+		case <-ticker.C:
+		case <-r.stopCh.Listen():
+			return
+		}
+	}
+}
+
+// due returns (and marks busy) the oldest pending entries that can be
+// replicated now; entries of the buckets that were destroyed or that no
+// longer replicate are dropped
+func (r *replicator) due() (tasks []*replTask) {
+	var (
+		now     = time.Now()
+		bcks    = make(map[string]*cluster.Bck, 4)
+		probing = make(map[string]bool, 2)
+		drop    []string
+	)
+	r.mu.Lock()
+	for uname, e := range r.pending {
+		if e.busy || e.retryAt > now.UnixNano() {
+			continue
+		}
+		key := e.Bck.String()
+		bck, ok := bcks[key]
+		if !ok {
+			bck = cluster.NewBckEmbed(e.Bck)
+			if err := bck.Init(r.t.owner.bmd, r.t.si); err != nil || !bck.Props.Replicate.Enabled {
+				bck = nil
+			}
+			bcks[key] = bck
+		}
+		if bck == nil {
+			drop = append(drop, uname)
+			continue
+		}
+		tasks = append(tasks, &replTask{uname: uname, e: e, seq: e.seq, bck: bck})
+	}
+	for _, uname := range drop {
+		delete(r.pending, uname)
+		_ = r.t.DB().Delete(replCollection, uname)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].e.Since < tasks[j].e.Since })
+
+	// skip the remotes that are down, except for a single probe when it's time
+	n := 0
+	for _, task := range tasks {
+		remote := task.bck.Props.Replicate.Remote
+		if link, ok := r.links[remote]; ok && link.down {
+			if probing[remote] || now.Before(link.retryAt) {
+				continue
+			}
+			probing[remote] = true
+		}
+		task.e.busy = true
+		tasks[n] = task
+		n++
+		if n == replBatchSize {
+			break
+		}
+	}
+	tasks = tasks[:n]
+	r.mu.Unlock()
+
+	if len(drop) > 0 {
+		r.t.statsT.Add(stats.ReplPending, -int64(len(drop)))
+		glog.Infof("%s: dropped %d object(s) pending replication (buckets destroyed or no longer replicated)",
+			r.t.si, len(drop))
+	}
+	return
+}
+
+func (r *replicator) process(tasks []*replTask) {
+	var (
+		wg       = &sync.WaitGroup{}
+		workCh   = make(chan *replTask, len(tasks))
+		nworkers = replWorkers
+	)
+	r.mu.Lock()
+	if r.catchup {
+		nworkers = replCatchupWorkers
+	}
+	r.mu.Unlock()
+	for _, task := range tasks {
+		workCh <- task
+	}
+	close(workCh)
+	for i := 0; i < cmn.Min(nworkers, len(tasks)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range workCh {
+				select {
+				case <-r.stopCh.Listen():
+					r.done(task, errReplStopped, false)
+					continue
+				default:
+				}
+				err := r.do(task)
+				r.done(task, err, err != nil && isReplLinkErr(err))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// do replicates the current state of the object: PUT if the object exists,
+// DELETE otherwise.
+// The object is locked only to open it: the (open) file keeps the content
+// while being sent even if the object gets overwritten in the meantime - in
+// which case the object is pending replication again (see enqueue).
+func (r *replicator) do(task *replTask) (err error) {
+	var (
+		lom      = &cluster.LOM{T: r.t, ObjName: task.e.ObjName}
+		dst      = task.bck.Props.Replicate.DstBck(task.bck.Bck)
+		aisCloud = r.t.cloud[cmn.ProviderAIS].(*cloud.AisCloudProvider)
+		errCode  int
+	)
+	if err = lom.Init(task.bck.Bck); err != nil {
+		return
+	}
+	lom.Lock(false)
+	if err = lom.Load(false); err == nil {
+		var (
+			fh    cmn.ReadAtOpenCloser
+			size  = lom.Size()
+			cksum = lom.Cksum()
+		)
+		fh, err = lom.Open() // (closed by PutObjBck)
+		lom.Unlock(false)
+		if err != nil {
+			return
+		}
+		if err, _ = aisCloud.PutObjBck(dst, lom.ObjName, fh, size, cksum); err == nil {
+			r.t.statsT.AddMany(
+				stats.NamedVal64{Name: stats.ReplCount, Value: 1},
+				stats.NamedVal64{Name: stats.ReplSize, Value: size},
+			)
+		}
+		return
+	}
+	lom.Unlock(false)
+	if !cmn.IsObjNotExist(err) {
+		return
+	}
+	smap := r.t.owner.smap.get()
+	tsi, err := cluster.HrwTarget(lom.Uname(), &smap.Smap)
+	if err != nil {
+		return
+	}
+	if tsi.ID() != r.t.si.ID() {
+		return errReplMigrated
+	}
+	if err, errCode = aisCloud.DeleteObjBck(dst, lom.ObjName); errCode == http.StatusNotFound {
+		err = nil
+	}
+	if err == nil {
+		r.t.statsT.Add(stats.ReplDeleteCount, 1)
+	}
+	return
+}
+
+func (r *replicator) done(task *replTask, err error, linkErr bool) {
+	var (
+		e       = task.e
+		remote  = task.bck.Props.Replicate.Remote
+		removed bool
+		now     = time.Now()
+	)
+	if err == errReplMigrated {
+		err = nil
+	}
+	r.mu.Lock()
+	e.busy = false
+	if err == errReplStopped {
+		r.mu.Unlock()
+		return
+	}
+	link, ok := r.links[remote]
+	if !ok {
+		link = &replLink{}
+		r.links[remote] = link
+	}
+	switch {
+	case err == nil:
+		e.failures, e.retryAt = 0, 0
+		if e.seq == task.seq {
+			delete(r.pending, task.uname)
+			removed = true
+			if errDB := r.t.DB().Delete(replCollection, task.uname); errDB != nil && !dbdriver.IsErrNotFound(errDB) {
+				glog.Errorf("%s: failed to remove %s/%s from replication backlog, err: %v",
+					r.t.si, e.Bck, e.ObjName, errDB)
+			}
+		}
+		if link.down {
+			link.down = false
+			r.catchup = true
+			glog.Infof("%s: link to remote cluster %q restored after %v, %d object(s) pending replication - catching up",
+				r.t.si, remote, now.Sub(link.since), len(r.pending))
+		}
+	case linkErr:
+		if !link.down {
+			link.down, link.since, link.backoff = true, now, replMinBackoff
+			glog.Errorf("%s: link to remote cluster %q is down, err: %v", r.t.si, remote, err)
+		} else {
+			link.backoff = cmn.MinDuration(2*link.backoff, replMaxBackoff)
+		}
+		link.retryAt = now.Add(link.backoff)
+	default:
+		e.failures++
+		backoff := cmn.MinDuration(replMinBackoff<<uint(cmn.Min(e.failures, 8)), replMaxBackoff)
+		e.retryAt = now.Add(backoff).UnixNano()
+		if e.failures == 1 {
+			glog.Errorf("%s: failed to replicate %s/%s to %q, err: %v", r.t.si, e.Bck, e.ObjName, remote, err)
+		}
+	}
+	if r.catchup && len(r.pending) < replCatchupLen {
+		r.catchup = false
+	}
+	r.mu.Unlock()
+
+	if removed {
+		r.t.statsT.Add(stats.ReplPending, -1)
+	}
+	if err != nil && !linkErr {
+		r.t.statsT.Add(stats.ErrReplCount, 1)
+	}
+}
+
+// housekeep updates the replication lag - the age of the oldest object
+// pending replication
+func (r *replicator) housekeep() time.Duration {
+	var oldest, lag int64
+	r.mu.Lock()
+	for _, e := range r.pending {
+		if oldest == 0 || e.Since < oldest {
+			oldest = e.Since
+		}
+	}
+	r.mu.Unlock()
+	if oldest != 0 {
+		lag = time.Now().UnixNano() - oldest
+	}
+	r.t.statsT.Add(stats.ReplLag, lag-r.lag)
+	r.lag = lag
+	return replHousekeepT
+}
+
+// network errors (as opposed to the remote cluster's errors and local failures)
+func isReplLinkErr(err error) bool {
+	if cmn.IsErrConnectionRefused(err) || cmn.IsErrConnectionReset(err) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
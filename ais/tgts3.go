@@ -26,6 +26,10 @@ func (t *targetrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		return
 	}
+	if r.Body, err = s3compat.NewPayloadReader(r); err != nil {
+		t.invalS3hdlr(w, r, err)
+		return
+	}
 
 	switch r.Method {
 	case http.MethodHead:
@@ -34,6 +38,8 @@ func (t *targetrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 		t.getObjS3(w, r, apiItems)
 	case http.MethodPut:
 		t.putObjS3(w, r, apiItems)
+	case http.MethodPost:
+		t.postObjS3(w, r, apiItems)
 	case http.MethodDelete:
 		t.delObjS3(w, r, apiItems)
 	default:
@@ -138,13 +144,18 @@ func (t *targetrunner) directPutObjS3(w http.ResponseWriter, r *http.Request, it
 
 	if err, errCode := t.doPut(r, lom, started); err != nil {
 		t.fsErr(err, lom.FQN)
-		t.invalmsghdlr(w, r, err.Error(), errCode)
+		t.invalS3hdlr(w, r, err, errCode)
 		return
 	}
 }
 
 // PUT s3/bckName/objName
 func (t *targetrunner) putObjS3(w http.ResponseWriter, r *http.Request, items []string) {
+	q := r.URL.Query()
+	if q.Get(s3compat.URLParamMptUploadID) != "" {
+		t.putMptPart(w, r, items, q)
+		return
+	}
 	if r.Header.Get(s3compat.HeaderObjSrc) == "" {
 		t.directPutObjS3(w, r, items)
 		return
@@ -152,12 +163,31 @@ func (t *targetrunner) putObjS3(w http.ResponseWriter, r *http.Request, items []
 	t.copyObjS3(w, r, items)
 }
 
+// POST s3/bckName/objName?uploads|uploadId=<id>
+func (t *targetrunner) postObjS3(w http.ResponseWriter, r *http.Request, items []string) {
+	q := r.URL.Query()
+	if _, ok := q[s3compat.URLParamMptUploads]; ok {
+		t.startMpt(w, r, items)
+		return
+	}
+	if q.Get(s3compat.URLParamMptUploadID) != "" {
+		t.completeMpt(w, r, items, q)
+		return
+	}
+	t.invalmsghdlr(w, r, "invalid request")
+}
+
 // GET s3/<bucket-name/<object-name>[?uuid=<etl-uuid>]
 func (t *targetrunner) getObjS3(w http.ResponseWriter, r *http.Request, items []string) {
 	if len(items) < 2 {
 		t.invalmsghdlr(w, r, "object name is undefined")
 		return
 	}
+	q := r.URL.Query()
+	if q.Get(s3compat.URLParamMptUploadID) != "" {
+		t.listMptParts(w, r, items, q)
+		return
+	}
 	started := time.Now()
 	config := cmn.GCO.Get()
 	bck := cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
@@ -171,7 +201,7 @@ func (t *targetrunner) getObjS3(w http.ResponseWriter, r *http.Request, items []
 
 		objName = path.Join(items[1:]...)
 	)
-	uuid := r.URL.Query().Get(cmn.URLParamUUID)
+	uuid := q.Get(cmn.URLParamUUID)
 	if uuid != "" {
 		t.doETL(w, r, uuid, bck, objName)
 		return
@@ -188,6 +218,18 @@ func (t *targetrunner) getObjS3(w http.ResponseWriter, r *http.Request, items []
 		}
 		return
 	}
+	if version := q.Get(s3compat.URLParamVersionID); version != "" {
+		vlom, file, err, errCode := t.openObjVersion(lom, version)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error(), errCode)
+			return
+		}
+		if vlom != nil {
+			s3compat.SetHeaderFromLOM(w.Header(), vlom, vlom.Size())
+			t.sendObjVersion(w, vlom, file)
+			return
+		}
+	}
 	if err = lom.Load(true); err != nil {
 		t.invalmsghdlr(w, r, err.Error())
 		return
@@ -239,6 +281,18 @@ func (t *targetrunner) headObjS3(w http.ResponseWriter, r *http.Request, items [
 		}
 		return
 	}
+	if version := r.URL.Query().Get(s3compat.URLParamVersionID); version != "" {
+		vlom, file, err, errCode := t.openObjVersion(lom, version)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error(), errCode)
+			return
+		}
+		if vlom != nil {
+			cmn.Close(file)
+			s3compat.SetHeaderFromLOM(w.Header(), vlom, vlom.Size())
+			return
+		}
+	}
 
 	lom.Lock(false)
 	if err = lom.Load(true); err != nil && !cmn.IsObjNotExist(err) { // (doesnotexist -> ok, other)
@@ -263,6 +317,11 @@ func (t *targetrunner) headObjS3(w http.ResponseWriter, r *http.Request, items [
 
 // DEL s3/bckName/objName
 func (t *targetrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []string) {
+	q := r.URL.Query()
+	if q.Get(s3compat.URLParamMptUploadID) != "" {
+		t.abortMpt(w, r, items, q)
+		return
+	}
 	var (
 		config = cmn.GCO.Get()
 		bck    = cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
@@ -281,7 +340,16 @@ func (t *targetrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []
 		t.invalmsghdlr(w, r, err.Error())
 		return
 	}
-	err, errCode := t.DeleteObject(context.Background(), lom, false)
+	var (
+		err     error
+		errCode int
+		current = true
+	)
+	if version := q.Get(s3compat.URLParamVersionID); version != "" {
+		current, err, errCode = t.delObjVersion(lom, version)
+	} else {
+		err, errCode = t.DeleteObject(context.Background(), lom, false)
+	}
 	if err != nil {
 		if errCode == http.StatusNotFound {
 			t.invalmsghdlrsilent(w, r,
@@ -294,5 +362,7 @@ func (t *targetrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []
 		return
 	}
 	// EC cleanup if EC is enabled
-	ec.ECM.CleanupObject(lom)
+	if current {
+		ec.ECM.CleanupObject(lom)
+	}
 }
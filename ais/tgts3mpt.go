@@ -0,0 +1,372 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais/s3compat"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/sse"
+)
+
+//
+// S3 multipart upload: all requests of the same upload are handled by the
+// target that owns the resulting object
+//
+
+func (t *targetrunner) initMptLOM(w http.ResponseWriter, r *http.Request, items []string) (lom *cluster.LOM) {
+	if len(items) < 2 {
+		t.invalmsghdlr(w, r, "object name is undefined")
+		return nil
+	}
+	bck := cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(t.owner.bmd, nil); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return nil
+	}
+	lom = &cluster.LOM{T: t, ObjName: path.Join(items[1:]...)}
+	if err := lom.Init(bck.Bck, cmn.GCO.Get()); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return nil
+	}
+	return lom
+}
+
+// checks that the upload exists and is for the object in the request
+func (t *targetrunner) checkMptUpload(w http.ResponseWriter, r *http.Request, lom *cluster.LOM, uploadID string) bool {
+	bckName, objName, err := s3compat.UploadObj(uploadID)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return false
+	}
+	if bckName != lom.BckName() || objName != lom.ObjName {
+		t.invalmsghdlrf(w, r, "upload %q is for %s/%s, not for %s", uploadID, bckName, objName, lom)
+		return false
+	}
+	return true
+}
+
+// POST s3/bckName/objName?uploads
+func (t *targetrunner) startMpt(w http.ResponseWriter, r *http.Request, items []string) {
+	lom := t.initMptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	uploadID := cmn.GenUUID()
+	if err := s3compat.InitUpload(uploadID, lom.BckName(), lom.ObjName); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: started multipart upload %q", lom, uploadID)
+	}
+	result := s3compat.NewInitiateMptUploadResult(lom.BckName(), lom.ObjName, uploadID)
+	w.Header().Set(cmn.HeaderContentType, cmn.ContentXML)
+	w.Write(result.MustMarshal())
+}
+
+// PUT s3/bckName/objName?partNumber=<n>&uploadId=<id>
+func (t *targetrunner) putMptPart(w http.ResponseWriter, r *http.Request, items []string, q url.Values) {
+	if cs := fs.GetCapStatus(); cs.OOS {
+		t.invalmsghdlr(w, r, cs.Err.Error())
+		return
+	}
+	lom := t.initMptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	uploadID := q.Get(s3compat.URLParamMptUploadID)
+	partNum, err := strconv.ParseInt(q.Get(s3compat.URLParamMptPartNum), 10, 16)
+	if err != nil || partNum < 1 || partNum > 10000 {
+		t.invalmsghdlrf(w, r, "invalid part number %q", q.Get(s3compat.URLParamMptPartNum))
+		return
+	}
+	if !t.checkMptUpload(w, r, lom, uploadID) {
+		return
+	}
+	var (
+		expectedMD5 string
+		workFQN     = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileMptPart)
+	)
+	if contentMD5 := r.Header.Get(s3compat.HeaderContentMD5); contentMD5 != "" {
+		b, err := base64.StdEncoding.DecodeString(contentMD5)
+		if err != nil {
+			t.invalmsghdlrf(w, r, "invalid %s %q", s3compat.HeaderContentMD5, contentMD5)
+			return
+		}
+		expectedMD5 = hex.EncodeToString(b)
+	}
+	part, err := t.recvMptPart(lom, r.Body, workFQN, expectedMD5)
+	if err != nil {
+		t.fsErr(err, workFQN)
+		t.invalS3hdlr(w, r, err)
+		return
+	}
+	part.Num = partNum
+	if err := t.checkMptQuota(lom, uploadID, part); err != nil {
+		if errRm := cmn.RemoveFile(workFQN); errRm != nil {
+			glog.Errorf("Nested error: %v => (remove %s => err: %v)", err, workFQN, errRm)
+		}
+		t.invalmsghdlr(w, r, err.Error(), http.StatusInsufficientStorage)
+		return
+	}
+	prev, err := s3compat.AddPart(uploadID, part)
+	if err != nil {
+		// the upload has been completed or aborted in the meantime (or else
+		// failed to persist the part)
+		if errRm := cmn.RemoveFile(workFQN); errRm != nil {
+			glog.Errorf("Nested error: %v => (remove %s => err: %v)", err, workFQN, errRm)
+		}
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	if prev != nil {
+		if err := cmn.RemoveFile(prev.FQN); err != nil {
+			glog.Errorf("%s: failed to remove replaced part %d of upload %q: %v", lom, prev.Num, uploadID, err)
+		}
+	}
+	w.Header().Set(s3compat.HeaderETag, s3compat.QuoteETag(part.MD5))
+}
+
+// receives the part into the workfile computing its MD5 on the fly; the part
+// of an encrypted bucket is encrypted as it is written
+// checkMptQuota fails the part if the object assembled from the parts
+// uploaded so far and the new one would exceed the bucket's quota (see
+// checkQuota). Parts are not counted in the bucket usage, hence the sum.
+func (t *targetrunner) checkMptQuota(lom *cluster.LOM, uploadID string, npart *s3compat.MptPart) error {
+	if !lom.Bprops().Quota.Enabled() {
+		return nil
+	}
+	parts, err := s3compat.ListParts(uploadID)
+	if err != nil {
+		return err
+	}
+	size := npart.Size
+	for _, part := range parts {
+		if part.PartNumber != npart.Num { // replaced by the new part
+			size += part.Size
+		}
+	}
+	prevSize, prevObjs := quotaPrev(lom)
+	return t.checkQuota(lom, size-prevSize, 1-prevObjs)
+}
+
+func (t *targetrunner) recvMptPart(lom *cluster.LOM, reader io.ReadCloser, workFQN,
+	expectedMD5 string) (part *s3compat.MptPart, err error) {
+	var (
+		file      *os.File
+		encw      *sse.Writer
+		keyID     string
+		written   int64
+		writer    io.Writer
+		buf, slab = t.gmm.Alloc()
+		cksum     = cmn.NewCksumHash(cmn.ChecksumMD5)
+	)
+	defer func() {
+		slab.Free(buf)
+		cmn.Close(reader)
+	}()
+	if file, err = lom.CreateFile(workFQN); err != nil {
+		return
+	}
+	writer = file
+	if sseConf := &lom.Bprops().SSE; sseConf.Enabled {
+		var key []byte
+		if key, err = sse.Key(sseConf.KeyID); err == nil {
+			encw, err = sse.NewWriter(file, key)
+		}
+		if err != nil {
+			cmn.Close(file)
+			if errRm := cmn.RemoveFile(workFQN); errRm != nil {
+				glog.Errorf("Nested error: %v => (remove %s => err: %v)", err, workFQN, errRm)
+			}
+			return nil, fmt.Errorf("%s: failed to encrypt, err: %w", lom, err)
+		}
+		writer, keyID = encw, sseConf.KeyID
+	}
+	written, err = io.CopyBuffer(cmn.NewWriterMulti(cmn.WriterOnly{Writer: writer}, cksum.H), reader, buf)
+	if err == nil && encw != nil {
+		err = encw.Close()
+	}
+	if errClose := file.Close(); err == nil && errClose != nil {
+		err = errClose
+	}
+	if err == nil {
+		cksum.Finalize()
+		if expectedMD5 != "" && expectedMD5 != cksum.Value() {
+			err = cmn.NewBadDataCksumError(cmn.NewCksum(cmn.ChecksumMD5, expectedMD5), &cksum.Cksum, lom.String())
+		}
+	}
+	if err != nil {
+		if errRm := cmn.RemoveFile(workFQN); errRm != nil {
+			glog.Errorf("Nested error: %v => (remove %s => err: %v)", err, workFQN, errRm)
+		}
+		return nil, err
+	}
+	part = &s3compat.MptPart{
+		MD5:   cksum.Value(),
+		FQN:   workFQN,
+		Size:  written,
+		Mtime: time.Now(),
+		SSE:   keyID,
+	}
+	return
+}
+
+// opens the part for reading (decrypting, if need be)
+func openMptPart(part *s3compat.MptPart) (io.ReadCloser, error) {
+	if part.SSE == "" {
+		return os.Open(part.FQN)
+	}
+	key, err := sse.Key(part.SSE)
+	if err != nil {
+		return nil, err
+	}
+	return sse.NewFileHandle(part.FQN, key, part.Size)
+}
+
+// POST s3/bckName/objName?uploadId=<id>
+func (t *targetrunner) completeMpt(w http.ResponseWriter, r *http.Request, items []string, q url.Values) {
+	started := time.Now()
+	if cs := fs.GetCapStatus(); cs.OOS {
+		t.invalmsghdlr(w, r, cs.Err.Error())
+		return
+	}
+	lom := t.initMptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	uploadID := q.Get(s3compat.URLParamMptUploadID)
+	if !t.checkMptUpload(w, r, lom, uploadID) {
+		return
+	}
+	defer cmn.Close(r.Body)
+	partList := &s3compat.CompleteMptUpload{}
+	if err := s3compat.DecodeXML(r.Body, partList); err != nil {
+		t.invalS3hdlr(w, r, err)
+		return
+	}
+	parts, err := s3compat.CheckParts(uploadID, partList.Parts)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	etag, err := s3compat.MptETag(parts)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+
+	// assemble the object from the parts
+	var (
+		size    int64
+		readers = make([]io.Reader, 0, len(parts))
+	)
+	for _, part := range parts {
+		fh, err := openMptPart(part)
+		if err != nil {
+			t.fsErr(err, part.FQN)
+			t.invalmsghdlr(w, r, fmt.Sprintf("upload %q: failed to open part %d: %v", uploadID, part.Num, err))
+			return
+		}
+		defer cmn.Close(fh)
+		readers = append(readers, fh)
+		size += part.Size
+	}
+	if lom.VersionConf().Enabled {
+		lom.Load() // need to know the current version if versioning enabled
+	}
+	lom.SetAtimeUnix(started.UnixNano())
+	lom.SetCustomMD(cmn.SimpleKVs{cluster.ETagObjMD: etag})
+	poi := &putObjInfo{
+		started: started,
+		t:       t,
+		lom:     lom,
+		r:       ioutil.NopCloser(io.MultiReader(readers...)),
+		size:    size,
+		ctx:     context.Background(),
+		workFQN: fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut),
+		quota:   true,
+	}
+	if err, errCode := poi.putObject(); err != nil {
+		t.fsErr(err, lom.FQN)
+		t.invalmsghdlr(w, r, err.Error(), errCode)
+		return
+	}
+
+	// cleanup: all uploaded parts, including the ones that are not in the list
+	t.cleanupMpt(lom, uploadID)
+	result := s3compat.NewCompleteMptUploadResult(lom.BckName(), lom.ObjName, etag)
+	w.Header().Set(cmn.HeaderContentType, cmn.ContentXML)
+	w.Write(result.MustMarshal())
+}
+
+// DELETE s3/bckName/objName?uploadId=<id>
+func (t *targetrunner) abortMpt(w http.ResponseWriter, r *http.Request, items []string, q url.Values) {
+	lom := t.initMptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	uploadID := q.Get(s3compat.URLParamMptUploadID)
+	if !t.checkMptUpload(w, r, lom, uploadID) {
+		return
+	}
+	t.cleanupMpt(lom, uploadID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET s3/bckName/objName?uploadId=<id>
+func (t *targetrunner) listMptParts(w http.ResponseWriter, r *http.Request, items []string, q url.Values) {
+	lom := t.initMptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	uploadID := q.Get(s3compat.URLParamMptUploadID)
+	if !t.checkMptUpload(w, r, lom, uploadID) {
+		return
+	}
+	parts, err := s3compat.ListParts(uploadID)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	result := s3compat.NewListPartsResult(lom.BckName(), lom.ObjName, uploadID, parts)
+	w.Header().Set(cmn.HeaderContentType, cmn.ContentXML)
+	w.Write(result.MustMarshal())
+}
+
+// parts of the uploads in progress are kept across restarts (see lru.InitLRU.KeepWork)
+func isMptPartInUse(fqn string) bool {
+	return strings.HasPrefix(filepath.Base(fqn), fs.WorkfileMptPart+".") && s3compat.PartInUse(fqn)
+}
+
+func (t *targetrunner) cleanupMpt(lom *cluster.LOM, uploadID string) {
+	parts, err := s3compat.FinishUpload(uploadID)
+	if err != nil {
+		return
+	}
+	for _, part := range parts {
+		if err := cmn.RemoveFile(part.FQN); err != nil {
+			glog.Errorf("%s: failed to remove part %d of upload %q: %v", lom, part.Num, uploadID, err)
+		}
+	}
+}
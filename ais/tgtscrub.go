@@ -0,0 +1,59 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/nl"
+	"github.com/NVIDIA/aistore/scrub"
+	"github.com/NVIDIA/aistore/xaction"
+	"github.com/NVIDIA/aistore/xaction/xreg"
+)
+
+// Checksum scrub (see the scrub package) runs every `scrub.interval` counting
+// from the start of the previous run; the schedule is re-checked at least
+// every scrubHousekeepT to pick up configuration changes.
+
+const scrubHousekeepT = time.Hour
+
+func (t *targetrunner) scrubHk() time.Duration {
+	interval := cmn.GCO.Get().Scrub.Interval
+	if interval <= 0 {
+		return scrubHousekeepT
+	}
+	if report := scrub.LastReport(); report != nil {
+		if since := time.Since(report.Started); since < interval {
+			return cmn.MinDuration(interval-since, scrubHousekeepT)
+		}
+	}
+	go t.runScrub("")
+	return cmn.MinDuration(interval, scrubHousekeepT)
+}
+
+func (t *targetrunner) runScrub(id string) {
+	regToIC := id == ""
+	if regToIC {
+		id = cmn.GenUUID()
+	}
+	xscrub := xreg.RenewScrub(id)
+	if xscrub == nil {
+		return
+	}
+	if regToIC && xscrub.ID().String() == id {
+		regMsg := xactRegMsg{UUID: id, Kind: cmn.ActScrub, Srcs: []string{t.si.ID()}}
+		msg := t.newAisMsg(&cmn.ActionMsg{Action: cmn.ActRegGlobalXaction, Value: regMsg}, nil, nil)
+		t.bcastToIC(msg, false /*wait*/)
+	}
+	xscrub.AddNotif(&xaction.NotifXact{
+		NotifBase: nl.NotifBase{When: cluster.UponTerm, Dsts: []string{equalIC}, F: t.callerNotifyFin},
+		Xact:      xscrub,
+	})
+	ini := scrub.InitScrub{T: t, Xaction: xscrub.(*scrub.Xaction)}
+	scrub.Run(&ini) // blocking
+	xscrub.Finish()
+}
@@ -97,16 +97,16 @@ func (t *targetrunner) txnHandler(w http.ResponseWriter, r *http.Request) {
 		if err = t.renameBucket(c); err != nil {
 			t.invalmsghdlr(w, r, err.Error())
 		}
-	case cmn.ActCopyBucket, cmn.ActETLBucket:
+	case cmn.ActCopyBucket, cmn.ActSyncBucket, cmn.ActETLBucket:
 		bck2BckMsg := &cmn.Bck2BckMsg{}
 		if err = cmn.MorphMarshal(c.msg.Value, bck2BckMsg); err != nil {
 			t.invalmsghdlr(w, r, err.Error())
 		}
 
-		if msg.Action == cmn.ActCopyBucket {
-			err = t.transferBucket(c, bck2BckMsg)
-		} else {
+		if msg.Action == cmn.ActETLBucket {
 			err = t.etlBucket(c, bck2BckMsg)
+		} else {
+			err = t.transferBucket(c, bck2BckMsg)
 		}
 		if err != nil {
 			t.invalmsghdlr(w, r, err.Error())
@@ -0,0 +1,156 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Prior versions of objects in ais buckets (see cluster/lom_version.go)
+//
+// GET and DELETE of a specific version is requested with `cmn.URLParamVersion`.
+// When the requested version is the current one, the request is handled as
+// a regular GET (respectively, DELETE without archiving).
+
+const versionsHousekeepT = time.Hour
+
+// openObjVersion opens the prior version of the object for reading.
+// Returns nil LOM (and no error) if the requested version is the current one.
+func (t *targetrunner) openObjVersion(lom *cluster.LOM, version string) (vlom *cluster.LOM, file cmn.ReadAtOpenCloser,
+	err error, errCode int) {
+	if !lom.Bck().IsAIS() {
+		err = fmt.Errorf("%s: object versions can be requested only from ais buckets", lom)
+		return nil, nil, err, http.StatusBadRequest
+	}
+	lom.Lock(false)
+	defer lom.Unlock(false)
+	if err = lom.Load(); err == nil && lom.Version() == version {
+		return nil, nil, nil, 0
+	}
+	if err != nil && !cmn.IsObjNotExist(err) {
+		return nil, nil, err, http.StatusInternalServerError
+	}
+	if vlom, err = lom.LoadVersion(version); err != nil {
+		if cmn.IsObjNotExist(err) {
+			return nil, nil, err, http.StatusNotFound
+		}
+		return nil, nil, err, http.StatusInternalServerError
+	}
+	// NOTE: prior versions are immutable and it is safe to read the file
+	// outside of the lock, even if the version gets pruned in the meantime
+	if file, err = vlom.Open(); err != nil {
+		t.fsErr(err, vlom.FQN)
+		return nil, nil, err, http.StatusInternalServerError
+	}
+	return
+}
+
+// sendObjVersion writes the prior version of the object; the caller is expected
+// to set response headers.
+func (t *targetrunner) sendObjVersion(w http.ResponseWriter, vlom *cluster.LOM, file cmn.ReadAtOpenCloser) {
+	buf, slab := t.gmm.Alloc(vlom.Size())
+	if _, err := io.CopyBuffer(w, file, buf); err != nil {
+		glog.Errorf("GET %s version %s: %v", vlom, vlom.Version(), err)
+	}
+	slab.Free(buf)
+	cmn.Close(file)
+}
+
+// GET /v1/objects/bucket-name/object-name?version=...
+func (t *targetrunner) getObjVersion(w http.ResponseWriter, r *http.Request, lom *cluster.LOM,
+	version string) (current bool) {
+	vlom, file, err, errCode := t.openObjVersion(lom, version)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), errCode)
+		return
+	}
+	if vlom == nil {
+		return true
+	}
+	vlom.ToHTTPHdr(w.Header())
+	t.sendObjVersion(w, vlom, file)
+	return
+}
+
+// DELETE /v1/objects/bucket-name/object-name?version=...
+// The current version is deleted without being archived; `current` tells
+// the caller whether it was the current version that got deleted.
+func (t *targetrunner) delObjVersion(lom *cluster.LOM, version string) (current bool, err error, errCode int) {
+	if !lom.Bck().IsAIS() {
+		err = fmt.Errorf("%s: object versions can be deleted only in ais buckets", lom)
+		return false, err, http.StatusBadRequest
+	}
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err = lom.Load(false); err == nil && lom.Version() == version {
+		return true, lom.Remove(), 0
+	}
+	if err != nil && !cmn.IsObjNotExist(err) {
+		return false, err, 0
+	}
+	if err = lom.DelVersion(version); err != nil {
+		if cmn.IsObjNotExist(err) {
+			return false, err, http.StatusNotFound
+		}
+		return false, err, 0
+	}
+	return false, nil, 0
+}
+
+// housekeeping: removes prior versions that have been retained longer than
+// `versioning.history_time` of the bucket (`versioning.max_history`, on the
+// other hand, is enforced when the object gets archived)
+func (t *targetrunner) pruneVersions() time.Duration {
+	var (
+		provider  = cmn.ProviderAIS
+		mpaths, _ = fs.Get()
+	)
+	t.owner.bmd.get().Range(&provider, nil, func(bck *cluster.Bck) bool {
+		conf := bck.VersionConf()
+		if !conf.KeepHistory() || conf.HistoryTime() == 0 {
+			return false
+		}
+		for _, mpathInfo := range mpaths {
+			// all prior versions of an object are pruned at once (and under its lock)
+			var lastObj string
+			cb := func(fqn string, de fs.DirEntry) error {
+				if de.IsDir() {
+					return nil
+				}
+				parsedFQN, err := fs.ParseFQN(fqn)
+				if err != nil {
+					return nil
+				}
+				objName, _, ok := fs.ParseVersionName(parsedFQN.ObjName)
+				if !ok || objName == lastObj {
+					return nil
+				}
+				lastObj = objName
+				lom := &cluster.LOM{T: t, FQN: mpathInfo.MakePathFQN(bck.Bck, fs.ObjectType, objName)}
+				if err := lom.Init(bck.Bck); err != nil {
+					return nil
+				}
+				lom.Lock(true)
+				lom.PruneVersions()
+				lom.Unlock(true)
+				return nil
+			}
+			opts := &fs.Options{Mpath: mpathInfo, Bck: bck.Bck, CTs: []string{fs.VersionType}, Callback: cb}
+			if err := fs.Walk(opts); err != nil {
+				glog.Errorf("%s: failed to prune versions in %s, err: %v", t.si, bck, err)
+			}
+		}
+		return false
+	})
+	return versionsHousekeepT
+}
@@ -133,6 +133,16 @@ func (t *targetrunner) cmdXactStart(xactMsg *xaction.XactReqMsg, bck *cluster.Bc
 			glog.Errorf(erfmb, xactMsg.Kind, bck)
 		}
 		go t.RunLRU(xactMsg.ID, xactMsg.Force != nil && *xactMsg.Force, xactMsg.Buckets...)
+	case cmn.ActLifecycle:
+		if bck != nil {
+			glog.Errorf(erfmb, xactMsg.Kind, bck)
+		}
+		go t.runLifecycle(xactMsg.ID)
+	case cmn.ActScrub:
+		if bck != nil {
+			glog.Errorf(erfmb, xactMsg.Kind, bck)
+		}
+		go t.runScrub(xactMsg.ID)
 	case cmn.ActResilver:
 		if bck != nil {
 			glog.Errorf(erfmb, xactMsg.Kind, bck)
@@ -164,6 +174,23 @@ func (t *targetrunner) cmdXactStart(xactMsg *xaction.XactReqMsg, bck *cluster.Bc
 			Xact: xact,
 		})
 		go xact.Run()
+	case cmn.ActECScrub:
+		if bck == nil {
+			return fmt.Errorf(erfmn, xactMsg.Kind)
+		}
+		xact, err := xreg.RenewECScrub(t, bck, xactMsg.ID)
+		if err != nil {
+			return err
+		}
+		xact.AddNotif(&xaction.NotifXact{
+			NotifBase: nl.NotifBase{
+				When: cluster.UponTerm,
+				Dsts: []string{equalIC},
+				F:    t.callerNotifyFin,
+			},
+			Xact: xact,
+		})
+		go xact.Run()
 	// 3. cannot start
 	case cmn.ActPutCopies:
 		return fmt.Errorf("cannot start %q (is driven by PUTs into a mirrored bucket)", xactMsg)
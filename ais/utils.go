@@ -199,7 +199,9 @@ func reEC(bprops, nprops *cmn.BucketProps, bck *cluster.Bck) bool {
 		return true
 	}
 	return bprops.EC.DataSlices != nprops.EC.DataSlices ||
-		bprops.EC.ParitySlices != nprops.EC.ParitySlices
+		bprops.EC.ParitySlices != nprops.EC.ParitySlices ||
+		bprops.EC.Codec != nprops.EC.Codec ||
+		bprops.EC.LocalGroups != nprops.EC.LocalGroups
 }
 
 func withRetry(cond func() bool) (ok bool) {
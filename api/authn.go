@@ -23,6 +23,10 @@ type (
 		Password string `json:"password"`
 	}
 
+	oidcLoginRec struct {
+		IDToken string `json:"id_token"`
+	}
+
 	AuthCreds struct {
 		Token string `json:"token"`
 	}
@@ -78,6 +82,58 @@ func LoginUser(baseParams BaseParams, userID, pass string) (token *AuthCreds, er
 	return token, nil
 }
 
+// LoginUserOIDC exchanges an ID token issued by the OpenID Connect provider
+// configured in AuthN for an AuthN token.
+func LoginUserOIDC(baseParams BaseParams, idToken string) (token *AuthCreds, err error) {
+	baseParams.Method = http.MethodPost
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.JoinWords(cmn.Version, cmn.Tokens),
+		Body:       cmn.MustMarshal(oidcLoginRec{IDToken: idToken}),
+	}, &token)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Token == "" {
+		return nil, errors.New("login failed: empty response from AuthN server")
+	}
+	return token, nil
+}
+
+// GetS3KeysAuthN issues a new pair of S3 credentials for the user: the access
+// and secret keys to sign requests to AIS S3 compatibility API.
+func GetS3KeysAuthN(baseParams BaseParams, userID, pass string) (keys *cmn.AuthS3Keys, err error) {
+	baseParams.Method = http.MethodPost
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.JoinWords(cmn.Version, cmn.Users, userID, cmn.S3Keys),
+		Body:       cmn.MustMarshal(loginRec{Password: pass}),
+	}, &keys)
+	return
+}
+
+// ListS3KeysAuthN returns S3 access keys of the user (without secret keys).
+// Requires the token of the user or of an administrator.
+func ListS3KeysAuthN(baseParams BaseParams, userID string) (keys []*cmn.AuthS3Keys, err error) {
+	baseParams.Method = http.MethodGet
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.JoinWords(cmn.Version, cmn.Users, userID, cmn.S3Keys),
+	}, &keys)
+	return
+}
+
+// RevokeS3KeyAuthN revokes S3 credentials of the user.
+// Requires the token of the user or of an administrator.
+func RevokeS3KeyAuthN(baseParams BaseParams, userID, accessKey string) error {
+	baseParams.Method = http.MethodDelete
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.JoinWords(cmn.Version, cmn.Users, userID, cmn.S3Keys, accessKey),
+	})
+}
+
 func RegisterClusterAuthN(baseParams BaseParams, cluSpec cmn.AuthCluster) error {
 	msg := cmn.MustMarshal(cluSpec)
 	baseParams.Method = http.MethodPost
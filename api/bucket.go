@@ -193,6 +193,22 @@ func CopyBucket(baseParams BaseParams, fromBck, toBck cmn.Bck, msgs ...*cmn.Copy
 	return
 }
 
+// SyncBucket makes `toBck` identical to `fromBck`: copies new and changed
+// objects only and, if `msg.Delete` is set, deletes objects that don't exist in `fromBck`.
+// Either of the buckets can be remote (Cloud or remote AIS).
+// With `msg.DryRun` set, nothing is changed - see xaction stats for the report.
+func SyncBucket(baseParams BaseParams, fromBck, toBck cmn.Bck, msg *cmn.SyncBckMsg) (xactID string, err error) {
+	msg.BckTo = toBck
+	baseParams.Method = http.MethodPost
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.JoinWords(cmn.Version, cmn.Buckets, fromBck.Name),
+		Body:       cmn.MustMarshal(cmn.ActionMsg{Action: cmn.ActSyncBucket, Value: msg}),
+		Query:      cmn.AddBckToQuery(nil, fromBck),
+	}, &xactID)
+	return
+}
+
 // RenameBucket changes the name of a bucket from `oldBck` to `newBck`.
 func RenameBucket(baseParams BaseParams, oldBck, newBck cmn.Bck) (xactID string, err error) {
 	baseParams.Method = http.MethodPost
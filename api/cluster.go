@@ -13,6 +13,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/ios"
+	"github.com/NVIDIA/aistore/scrub"
 	"github.com/NVIDIA/aistore/stats"
 )
 
@@ -76,6 +77,51 @@ func GetTargetDiskStats(baseParams BaseParams, targetID string) (diskStats map[s
 	return
 }
 
+// GetScrubReport retrieves the reports of the last checksum scrub run on each
+// target: target ID => report (nil if the target has never been scrubbed).
+func GetScrubReport(baseParams BaseParams) (reports map[string]*scrub.Report, err error) {
+	baseParams.Method = http.MethodGet
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.JoinWords(cmn.Version, cmn.Cluster),
+		Query:      url.Values{cmn.URLParamWhat: []string{cmn.GetWhatScrubReport}},
+	}, &reports)
+	return
+}
+
+// Cluster-level scheduler API
+//
+// AddSchedule adds a new recurring job or replaces the existing job with the same name.
+func AddSchedule(baseParams BaseParams, job *cmn.ScheduleJob) error {
+	baseParams.Method = http.MethodPut
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.JoinWords(cmn.Version, cmn.Cluster),
+		Body:       cmn.MustMarshal(cmn.ActionMsg{Action: cmn.ActAddSchedule, Name: job.Name, Value: job}),
+	})
+}
+
+func RemoveSchedule(baseParams BaseParams, name string) error {
+	baseParams.Method = http.MethodPut
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.JoinWords(cmn.Version, cmn.Cluster),
+		Body:       cmn.MustMarshal(cmn.ActionMsg{Action: cmn.ActRemoveSchedule, Name: name}),
+	})
+}
+
+// GetSchedule returns all recurring jobs and the history of their recent runs.
+func GetSchedule(baseParams BaseParams) (sched *cmn.Schedule, err error) {
+	baseParams.Method = http.MethodGet
+	sched = &cmn.Schedule{}
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.JoinWords(cmn.Version, cmn.Cluster),
+		Query:      url.Values{cmn.URLParamWhat: []string{cmn.GetWhatSchedule}},
+	}, sched)
+	return
+}
+
 func GetRemoteAIS(baseParams BaseParams) (aisInfo cmn.CloudInfoAIS, err error) {
 	baseParams.Method = http.MethodGet
 	err = DoHTTPRequest(ReqParams{
@@ -82,6 +82,21 @@ func DownloadCloud(baseParams BaseParams, description string, bck cmn.Bck, prefi
 	return DownloadWithParam(baseParams, downloader.DlTypeCloud, dlBody)
 }
 
+func DownloadManifest(baseParams BaseParams, description string, bck, manifestBck cmn.Bck, manifest string, intervals ...time.Duration) (string, error) {
+	dlBody := downloader.DlManifestBody{
+		ManifestBck: manifestBck,
+		Manifest:    manifest,
+	}
+
+	if len(intervals) > 0 {
+		dlBody.ProgressInterval = intervals[0].String()
+	}
+
+	dlBody.Bck = bck
+	dlBody.Description = description
+	return DownloadWithParam(baseParams, downloader.DlTypeManifest, dlBody)
+}
+
 func DownloadStatus(baseParams BaseParams, id string, onlyActiveTasks ...bool) (downloader.DlStatusResp, error) {
 	dlBody := downloader.DlAdminBody{
 		ID: id,
@@ -126,6 +141,32 @@ func AbortDownload(baseParams BaseParams, id string) error {
 	})
 }
 
+// PauseDownload pauses the download job: the objects that are being downloaded
+// are put aside (along with the downloaded parts) until the job is resumed.
+func PauseDownload(baseParams BaseParams, id string) error {
+	dlBody := downloader.DlAdminBody{
+		ID: id,
+	}
+	baseParams.Method = http.MethodPut
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.JoinWords(cmn.Version, cmn.Download, cmn.Pause),
+		Body:       cmn.MustMarshal(dlBody),
+	})
+}
+
+func ResumeDownload(baseParams BaseParams, id string) error {
+	dlBody := downloader.DlAdminBody{
+		ID: id,
+	}
+	baseParams.Method = http.MethodPut
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.JoinWords(cmn.Version, cmn.Download, cmn.Resume),
+		Body:       cmn.MustMarshal(dlBody),
+	})
+}
+
 func RemoveDownload(baseParams BaseParams, id string) error {
 	dlBody := downloader.DlAdminBody{
 		ID: id,
@@ -23,6 +23,19 @@ func StartDSort(baseParams BaseParams, rs dsort.RequestSpec) (string, error) {
 	return id, err
 }
 
+// ResumeDSort starts a new dSort job which continues the (aborted) job with
+// the given UUID from its last checkpoint. Returns UUID of the new job.
+func ResumeDSort(baseParams BaseParams, managerUUID string) (string, error) {
+	baseParams.Method = http.MethodPost
+	var id string
+	err := DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.JoinWords(cmn.Version, cmn.Sort, cmn.Resume),
+		Query:      url.Values{cmn.URLParamUUID: []string{managerUUID}},
+	}, &id)
+	return id, err
+}
+
 func AbortDSort(baseParams BaseParams, managerUUID string) error {
 	baseParams.Method = http.MethodDelete
 	return DoHTTPRequest(ReqParams{
@@ -133,6 +133,19 @@ func DeleteObject(baseParams BaseParams, bck cmn.Bck, object string) error {
 	})
 }
 
+// DeleteObjectVersion deletes the given version of the object: either one of
+// the prior versions retained by the bucket, or the current one.
+// NOTE: to GET a specific version, use GetObjectInput.Query with cmn.URLParamVersion.
+func DeleteObjectVersion(baseParams BaseParams, bck cmn.Bck, object, version string) error {
+	baseParams.Method = http.MethodDelete
+	query := cmn.AddBckToQuery(url.Values{cmn.URLParamVersion: []string{version}}, bck)
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.JoinWords(cmn.Version, cmn.Objects, bck.Name, object),
+		Query:      query,
+	})
+}
+
 // EvictObject evicts an object specified by bucket/object.
 func EvictObject(baseParams BaseParams, bck cmn.Bck, object string) error {
 	baseParams.Method = http.MethodDelete
@@ -12,15 +12,18 @@ import (
 )
 
 func InitQuery(baseParams BaseParams, objectsTemplate string, bck cmn.Bck, filter *query.FilterMsg, workersCnts ...uint) (string, error) {
-	var (
-		outerSelectMsg = query.OuterSelectMsg{Template: objectsTemplate}
-		fromMsg        = query.FromMsg{Bck: bck}
-		qMsg           = query.DefMsg{
-			OuterSelect: outerSelectMsg,
-			From:        fromMsg,
-			Where:       query.WhereMsg{Filter: filter},
-		}
+	qMsg := &query.DefMsg{
+		OuterSelect: query.OuterSelectMsg{Template: objectsTemplate},
+		From:        query.FromMsg{Bck: bck},
+		Where:       query.WhereMsg{Filter: filter},
+	}
+	return InitQueryMsg(baseParams, qMsg, workersCnts...)
+}
 
+// InitQueryMsg initializes query defined by the message, e.g. the one that
+// includes inner select (content) filter.
+func InitQueryMsg(baseParams BaseParams, qMsg *query.DefMsg, workersCnts ...uint) (string, error) {
+	var (
 		workersCnt uint
 		handle     string
 	)
@@ -30,7 +33,7 @@ func InitQuery(baseParams BaseParams, objectsTemplate string, bck cmn.Bck, filte
 		workersCnt = workersCnts[0]
 	}
 
-	initMsg := query.InitMsg{QueryMsg: qMsg, WorkersCnt: workersCnt}
+	initMsg := query.InitMsg{QueryMsg: *qMsg, WorkersCnt: workersCnt}
 
 	err := DoHTTPRequest(ReqParams{
 		BaseParams: baseParams,
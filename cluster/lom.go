@@ -25,6 +25,7 @@ import (
 	"github.com/NVIDIA/aistore/hk"
 	"github.com/NVIDIA/aistore/ios"
 	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/sse"
 )
 
 //
@@ -41,17 +42,20 @@ const (
 )
 
 type (
-	// NOTE: sizeof(lmeta) = 88 as of 5/26
+	// NOTE: sizeof(lmeta) = 104 (88 as of 5/26 + access counts)
 	lmeta struct {
 		uname    string
 		version  string
 		size     int64
 		atime    int64
 		atimefs  int64
+		acnt     uint64 // access count (see LRU policies)
+		acntfs   uint64 // ditto, persisted
 		bckID    uint64
 		cksum    *cmn.Cksum // ReCache(ref)
 		copies   fs.MPI     // ditto
 		customMD cmn.SimpleKVs
+		sse      string // encryption key ID (empty if not encrypted)
 	}
 	LOM struct {
 		md      lmeta  // local meta
@@ -96,8 +100,12 @@ func (lom *LOM) SetCksum(cksum *cmn.Cksum)    { lom.md.cksum = cksum }
 func (lom *LOM) Atime() time.Time             { return time.Unix(0, lom.md.atime) }
 func (lom *LOM) AtimeUnix() int64             { return lom.md.atime }
 func (lom *LOM) SetAtimeUnix(tu int64)        { lom.md.atime = tu }
+func (lom *LOM) AccessCount() uint64          { return lom.md.acnt }
+func (lom *LOM) IncAccessCount()              { lom.md.acnt++ }
 func (lom *LOM) SetCustomMD(md cmn.SimpleKVs) { lom.md.customMD = md }
 func (lom *LOM) CustomMD() cmn.SimpleKVs      { return lom.md.customMD }
+func (lom *LOM) SSEKeyID() string             { return lom.md.sse }
+func (lom *LOM) SetSSEKeyID(id string)        { lom.md.sse = id }
 func (lom *LOM) GetCustomMD(key string) (string, bool) {
 	value, exists := lom.md.customMD[key]
 	return value, exists
@@ -134,6 +142,7 @@ func (lom *LOM) CopyMetadata(from *LOM) {
 	lom.md.size = from.md.size
 	lom.md.version = from.md.version
 	lom.md.atime = from.md.atime
+	lom.md.sse = from.md.sse
 }
 
 func (lom *LOM) CloneCopiesMd() int {
@@ -386,7 +395,8 @@ func (lom *LOM) CopyObject(dstFQN string, buf []byte) (dst *LOM, err error) {
 		srcCksum  = lom.Cksum()
 		cksumType = cmn.ChecksumNone
 	)
-	if srcCksum != nil {
+	// (checksums are computed over plaintext - encrypted objects are copied as is)
+	if srcCksum != nil && lom.md.sse == "" {
 		cksumType = srcCksum.Type()
 	}
 	_, dstCksum, err = cmn.CopyFile(lom.FQN, workFQN, buf, cksumType)
@@ -478,8 +488,17 @@ func (lom *LOM) _string(b string) string {
 func (lom *LOM) IncVersion() error {
 	cmn.Assert(lom.Bck().IsAIS())
 	if lom.Version() == "" {
-		lom.SetVersion(lomInitialVersion)
-		return nil
+		// the object may have been deleted while its prior versions are
+		// retained - continue numbering from the most recent one
+		if conf := lom.VersionConf(); conf.KeepHistory() {
+			if versions, _ := lom.PriorVersions(); len(versions) > 0 {
+				lom.SetVersion(versions[0].Version)
+			}
+		}
+		if lom.Version() == "" {
+			lom.SetVersion(lomInitialVersion)
+			return nil
+		}
 	}
 	ver, err := strconv.Atoi(lom.Version())
 	if err != nil {
@@ -621,7 +640,7 @@ func (lom *LOM) ComputeCksumIfMissing() (cksum *cmn.Cksum, err error) {
 
 func (lom *LOM) ComputeCksum(cksumTypes ...string) (cksum *cmn.CksumHash, err error) {
 	var (
-		file      *os.File
+		file      cmn.ReadAtOpenCloser
 		cksumType string
 	)
 	if len(cksumTypes) > 0 {
@@ -632,7 +651,7 @@ func (lom *LOM) ComputeCksum(cksumTypes ...string) (cksum *cmn.CksumHash, err er
 	if cksumType == cmn.ChecksumNone {
 		return
 	}
-	if file, err = os.Open(lom.FQN); err != nil {
+	if file, err = lom.Open(); err != nil {
 		return
 	}
 	// No need to allocate `buf` as `ioutil.Discard` has efficient `io.ReaderFrom` implementation.
@@ -644,6 +663,42 @@ func (lom *LOM) ComputeCksum(cksumTypes ...string) (cksum *cmn.CksumHash, err er
 	return
 }
 
+// Open opens the object for reading; encrypted objects are transparently
+// decrypted (see sse package).
+func (lom *LOM) Open() (cmn.ReadAtOpenCloser, error) {
+	return lom.OpenFQN(lom.FQN)
+}
+
+// OpenFQN is the same as Open but opens the given replica of the object
+// (e.g., as per lom.LoadBalanceGET).
+func (lom *LOM) OpenFQN(fqn string) (cmn.ReadAtOpenCloser, error) {
+	if lom.md.sse == "" {
+		fh, err := cmn.NewFileHandle(fqn)
+		if err != nil {
+			return nil, err
+		}
+		return fh, nil
+	}
+	key, err := sse.Key(lom.md.sse)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", lom, err)
+	}
+	fh, err := sse.NewFileHandle(fqn, key, lom.md.size)
+	if err != nil {
+		return nil, err
+	}
+	return fh, nil
+}
+
+// DiskSize returns the size of the object on the mountpath - for encrypted
+// objects it is greater than the object's size.
+func (lom *LOM) DiskSize() int64 {
+	if lom.md.sse == "" {
+		return lom.md.size
+	}
+	return sse.CipherSize(lom.md.size)
+}
+
 // NOTE: Clone performs shallow copy of the LOM struct.
 func (lom *LOM) Clone(fqn string) *LOM {
 	dst := &LOM{}
@@ -833,8 +888,8 @@ beg:
 		return
 	}
 	// fstat & atime
-	if lom.md.size != finfo.Size() { // corruption or tampering
-		return fmt.Errorf("%s: errsize (%d != %d)", lom, lom.md.size, finfo.Size())
+	if size := lom.DiskSize(); size != finfo.Size() { // corruption or tampering
+		return fmt.Errorf("%s: errsize (%d != %d)", lom, size, finfo.Size())
 	}
 	atime := ios.GetATime(finfo)
 	lom.md.atime = atime.UnixNano()
@@ -922,6 +977,12 @@ func lomCacheCleanup(t Target, d time.Duration) (evictedCnt, totalCnt int) {
 					}
 					// TODO: throttle via mountpath.IsIdle()
 				}
+				if md.acnt != md.acntfs {
+					if lom, bucketExists := lomFromLmeta(md, bmd); bucketExists {
+						lom.T = t
+						lom.flushAccessCount(md.acnt)
+					}
+				}
 				cache.Delete(hkey)
 				evicted.Add(1)
 				return true
@@ -981,6 +1042,25 @@ func (lom *LOM) flushAtime(atime time.Time) {
 	}
 }
 
+// persists the access count that was incremented in memory (see LRU policies);
+// best effort - skips the object if it is locked
+func (lom *LOM) flushAccessCount(acnt uint64) {
+	if err := lom.Init(lom.bck.Bck); err != nil {
+		return
+	}
+	if !lom.TryLock(true) {
+		return
+	}
+	defer lom.Unlock(true)
+	if err := lom.LoadMetaFromFS(); err != nil {
+		return
+	}
+	lom.md.acnt = acnt
+	if err := lom.Persist(); err != nil {
+		glog.Errorf("%s: flush access count err: %v", lom, err)
+	}
+}
+
 //
 // static helpers
 //
@@ -30,8 +30,8 @@ func (r *LomReader) Reader(lom *LOM) (cmn.ReadOpenCloser, cmn.ObjHeaderMetaProvi
 
 	lom.Lock(false)
 	if lomLoadErr = lom.Load(); lomLoadErr == nil {
-		var file *cmn.FileHandle
-		if file, err = cmn.NewFileHandle(lom.FQN); err != nil {
+		var file cmn.ReadAtOpenCloser
+		if file, err = lom.Open(); err != nil {
 			lom.Unlock(false)
 			return nil, nil, nil, fmt.Errorf("failed to open %s, err: %v", lom.FQN, err)
 		}
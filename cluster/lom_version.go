@@ -0,0 +1,244 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Versioning history (see cmn.VersionConf.KeepHistory)
+//
+// When an object in an ais bucket gets overwritten or deleted, its current
+// content (along with its metadata) becomes a prior version stored in the
+// per-object `fs.VersionType` directory of the same mountpath:
+// <bucket>/%vr/<objname>%v/<version>. Thus, listing prior versions of an
+// object reads only its own directory.
+//
+// Archiving is two-phase: the current content is first hard-linked into
+// history (ArchiveVersion) and remains in place until replaced (or removed);
+// only then the prior version gets committed (CommitVersion) - or aborted
+// (AbortVersion) if the object could not be replaced.
+//
+// Prior versions are pruned when their number exceeds `MaxHistory` and/or
+// when they have been retained longer than `HistoryTime`. Rebalance and
+// resilver migrate prior versions (SaveVersion) along with objects.
+//
+// All methods below must be called under the object's exclusive lock
+// (read lock suffices for PriorVersions and LoadVersion).
+
+type PriorVersion struct {
+	Version string
+	FQN     string
+	Size    int64
+	Mtime   time.Time // time the version was archived (i.e., became prior)
+}
+
+// VersionFQN returns the FQN of the given prior version of the object.
+func (lom *LOM) VersionFQN(version string) string {
+	return fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.VersionType, version)
+}
+
+// ArchiveVersion links the current content of the object into history and
+// returns the prior version to be either committed or aborted. Objects that
+// do not exist or have no version (e.g., written prior to enabling
+// versioning) are not archived - nil is returned.
+func (lom *LOM) ArchiveVersion() (vlom *LOM, err error) {
+	// NOTE: the metadata of the current content - the LOM may already
+	// describe the new one
+	vlom = lom.Clone(lom.FQN)
+	vlom.md = lmeta{uname: lom.md.uname}
+	if err = vlom.FromFS(); err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return nil, err
+	}
+	if vlom.Version() == "" {
+		return nil, nil
+	}
+	vlom.FQN = lom.VersionFQN(vlom.Version())
+	if err = cmn.CreateDir(filepath.Dir(vlom.FQN)); err != nil {
+		return nil, err
+	}
+	// (left over by an aborted archiving)
+	if err = os.Remove(vlom.FQN); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err = os.Link(lom.FQN, vlom.FQN); err != nil {
+		return nil, err
+	}
+	return vlom, nil
+}
+
+// CommitVersion completes archiving once the current content has been
+// replaced (or removed): stores the metadata of the prior version and prunes
+// the history according to the bucket's versioning config.
+func (lom *LOM) CommitVersion(vlom *LOM) (err error) {
+	if vlom == nil {
+		return
+	}
+	// copies (if any) are not archived
+	vlom.md.copies = nil
+	if err = vlom.Persist(); err != nil {
+		return
+	}
+	now := time.Now()
+	if err = os.Chtimes(vlom.FQN, now, now); err != nil {
+		return
+	}
+	lom.PruneVersions()
+	return
+}
+
+// AbortVersion removes the prior version linked by ArchiveVersion when the
+// object could not be replaced.
+func (lom *LOM) AbortVersion(vlom *LOM) {
+	if vlom == nil {
+		return
+	}
+	if err := cmn.RemoveFile(vlom.FQN); err != nil {
+		glog.Errorf("%s: failed to remove version %s, err: %v", lom, vlom.Version(), err)
+	}
+	lom.rmVersionsDir()
+}
+
+// SaveVersion moves the given (work) file into history as the prior version
+// of the object described by the LOM's metadata (version, size, checksum,
+// encryption key). Used to migrate prior versions between mountpaths and
+// targets.
+func (lom *LOM) SaveVersion(workFQN string, archived time.Time) (err error) {
+	vlom := lom.Clone(lom.VersionFQN(lom.Version()))
+	vlom.md.copies = nil
+	if err = cmn.CreateDir(filepath.Dir(vlom.FQN)); err != nil {
+		return
+	}
+	if err = cmn.Rename(workFQN, vlom.FQN); err != nil {
+		return
+	}
+	if err = vlom.Persist(); err == nil {
+		err = os.Chtimes(vlom.FQN, archived, archived)
+	}
+	if err != nil {
+		if errRm := cmn.RemoveFile(vlom.FQN); errRm != nil {
+			glog.Errorf(fmtNestedErr, errRm)
+		}
+	}
+	return
+}
+
+// PriorVersions returns prior versions of the object, the most recent first.
+func (lom *LOM) PriorVersions() (versions []*PriorVersion, err error) {
+	dir := lom.VersionFQN("")
+	finfos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	for _, finfo := range finfos {
+		// NOTE: skipping the directories of other objects, e.g. "a%v/b%v/"
+		// and the files that are not versions
+		ver := finfo.Name()
+		if finfo.IsDir() {
+			continue
+		}
+		if _, err := strconv.ParseUint(ver, 10, 64); err != nil {
+			continue
+		}
+		versions = append(versions, &PriorVersion{
+			Version: ver,
+			FQN:     filepath.Join(dir, ver),
+			Size:    finfo.Size(),
+			Mtime:   finfo.ModTime(),
+		})
+	}
+	sort.Slice(versions, func(i, j int) bool { return VersionLess(versions[j].Version, versions[i].Version) })
+	return
+}
+
+// PruneVersions removes prior versions that must not be retained anymore:
+// all of them if the bucket does not keep history, otherwise those that
+// exceed `MaxHistory` or are older than `HistoryTime`.
+func (lom *LOM) PruneVersions() (pruned int) {
+	versions, err := lom.PriorVersions()
+	if err != nil {
+		glog.Errorf("%s: failed to list prior versions, err: %v", lom, err)
+		return
+	}
+	var (
+		conf    = lom.VersionConf()
+		keep    = conf.KeepHistory()
+		maxAge  = conf.HistoryTime()
+		now     = time.Now()
+		retired bool
+	)
+	for i, v := range versions {
+		retired = !keep ||
+			(conf.MaxHistory > 0 && i >= conf.MaxHistory) ||
+			(maxAge > 0 && now.Sub(v.Mtime) > maxAge)
+		if !retired {
+			continue
+		}
+		if err := cmn.RemoveFile(v.FQN); err != nil {
+			glog.Errorf("%s: failed to remove version %s, err: %v", lom, v.Version, err)
+			continue
+		}
+		pruned++
+	}
+	if pruned == len(versions) {
+		lom.rmVersionsDir()
+	}
+	return
+}
+
+// removes the directory of prior versions if it is empty
+func (lom *LOM) rmVersionsDir() {
+	_ = os.Remove(lom.VersionFQN(""))
+}
+
+// LoadVersion returns LOM of the given prior version. The returned LOM is not
+// cached and must be used for reading only.
+func (lom *LOM) LoadVersion(version string) (vlom *LOM, err error) {
+	vlom = lom.Clone(lom.VersionFQN(version))
+	vlom.md = lmeta{uname: lom.md.uname}
+	if err = vlom.FromFS(); err != nil {
+		if os.IsNotExist(err) {
+			err = cmn.NewNotFoundError("%s version %q", lom, version)
+		}
+		return nil, err
+	}
+	return
+}
+
+// DelVersion removes the given prior version of the object.
+func (lom *LOM) DelVersion(version string) error {
+	vfqn := lom.VersionFQN(version)
+	if err := os.Remove(vfqn); err != nil {
+		if os.IsNotExist(err) {
+			return cmn.NewNotFoundError("%s version %q", lom, version)
+		}
+		return err
+	}
+	lom.rmVersionsDir()
+	return nil
+}
+
+// VersionLess compares ais object versions (numeric strings).
+func VersionLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
@@ -0,0 +1,187 @@
+// Package cluster_test provides tests for cluster package
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster_test
+
+import (
+	"os"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LOM versions", func() {
+	const (
+		tmpDir       = "/tmp/lom_version_test"
+		versionMpath = tmpDir + "/mpath"
+
+		bucketLocal = "LOM_TEST_Versions"
+		testObjName = "version-foldr/test-obj.ext"
+		testSize    = 123
+	)
+
+	localBck := cmn.Bck{Name: bucketLocal, Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}
+
+	_ = fs.CSM.RegisterContentType(fs.ObjectType, &fs.ObjectContentResolver{})
+	_ = fs.CSM.RegisterContentType(fs.WorkfileType, &fs.WorkfileContentResolver{})
+	_ = fs.CSM.RegisterContentType(fs.VersionType, &fs.VersionContentResolver{})
+
+	var (
+		mix     = fs.MountpathInfo{Path: versionMpath}
+		bmdMock = cluster.NewBaseBownerMock(
+			cluster.NewBck(
+				bucketLocal, cmn.ProviderAIS, cmn.NsGlobal,
+				&cmn.BucketProps{
+					Cksum:      cmn.CksumConf{Type: cmn.ChecksumXXHash},
+					Versioning: cmn.VersionConf{Enabled: true, MaxHistory: 2},
+				},
+			),
+		)
+		tMock    = cluster.NewTargetMock(bmdMock)
+		localFQN = mix.MakePathFQN(localBck, fs.ObjectType, testObjName)
+	)
+
+	// overwrite the object: new content with the next version
+	overwrite := func(lom *cluster.LOM, size int) {
+		vlom, err := lom.ArchiveVersion()
+		Expect(err).NotTo(HaveOccurred())
+		createTestFile(localFQN, size)
+		lom.SetSize(int64(size))
+		lom.SetCksum(nil)
+		Expect(lom.IncVersion()).NotTo(HaveOccurred())
+		Expect(lom.Persist()).NotTo(HaveOccurred())
+		Expect(lom.CommitVersion(vlom)).NotTo(HaveOccurred())
+	}
+
+	// archive the current content and remove the object
+	remove := func(lom *cluster.LOM) {
+		vlom, err := lom.ArchiveVersion()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lom.Remove()).NotTo(HaveOccurred())
+		Expect(lom.CommitVersion(vlom)).NotTo(HaveOccurred())
+	}
+
+	priorVersions := func(lom *cluster.LOM) (versions []string) {
+		prior, err := lom.PriorVersions()
+		Expect(err).NotTo(HaveOccurred())
+		for _, v := range prior {
+			versions = append(versions, v.Version)
+		}
+		return
+	}
+
+	BeforeEach(func() {
+		_ = cmn.CreateDir(versionMpath)
+		fs.DisableFsIDCheck()
+		_, _ = fs.Add(versionMpath, "daeID")
+	})
+
+	AfterEach(func() {
+		_, _ = fs.Remove(versionMpath)
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	It("should archive, prune, load and delete prior versions", func() {
+		lom := filePut(localFQN, testSize, tMock)
+		Expect(lom.Version()).To(Equal("1"))
+
+		overwrite(lom, testSize+1)
+		Expect(lom.Version()).To(Equal("2"))
+		Expect(priorVersions(lom)).To(Equal([]string{"1"}))
+
+		overwrite(lom, testSize+2)
+		overwrite(lom, testSize+3)
+		Expect(lom.Version()).To(Equal("4"))
+		// max_history == 2
+		Expect(priorVersions(lom)).To(Equal([]string{"3", "2"}))
+
+		vlom, err := lom.LoadVersion("2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vlom.Version()).To(Equal("2"))
+		Expect(vlom.Size()).To(BeEquivalentTo(testSize + 1))
+		Expect(vlom.HasCopies()).To(BeFalse())
+
+		_, err = lom.LoadVersion("1")
+		Expect(cmn.IsObjNotExist(err)).To(BeTrue())
+
+		Expect(lom.DelVersion("3")).NotTo(HaveOccurred())
+		Expect(cmn.IsObjNotExist(lom.DelVersion("3"))).To(BeTrue())
+		Expect(priorVersions(lom)).To(Equal([]string{"2"}))
+	})
+
+	It("should continue numbering after the object is deleted", func() {
+		lom := filePut(localFQN, testSize, tMock)
+		overwrite(lom, testSize)
+		remove(lom)
+		Expect(priorVersions(lom)).To(Equal([]string{"2", "1"}))
+
+		lom = filePut(localFQN, testSize, tMock)
+		Expect(lom.Version()).To(Equal("3"))
+	})
+
+	It("should not confuse prior versions of objects with similar names", func() {
+		lom := filePut(localFQN, testSize, tMock)
+		overwrite(lom, testSize)
+
+		other := filePut(localFQN+".1", testSize, tMock)
+		remove(other)
+
+		// the object named as if it were a prior version of ours
+		nested := filePut(localFQN+fs.VersionDirSuffix+"/1", testSize, tMock)
+		remove(nested)
+
+		Expect(priorVersions(lom)).To(Equal([]string{"1"}))
+		Expect(priorVersions(other)).To(Equal([]string{"1"}))
+		Expect(priorVersions(nested)).To(Equal([]string{"1"}))
+	})
+
+	It("should keep the current object intact until the version is committed", func() {
+		lom := filePut(localFQN, testSize, tMock)
+		cksum, err := lom.ComputeCksumIfMissing()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lom.Persist()).NotTo(HaveOccurred())
+
+		vlom, err := lom.ArchiveVersion()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vlom).NotTo(BeNil())
+		Expect(localFQN).To(BeARegularFile())
+		Expect(lom.Load(false)).NotTo(HaveOccurred())
+
+		lom.AbortVersion(vlom)
+		Expect(vlom.FQN).NotTo(BeAnExistingFile())
+		Expect(priorVersions(lom)).To(BeEmpty())
+
+		overwrite(lom, testSize+1)
+		vlom, err = lom.LoadVersion("1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vlom.Size()).To(BeEquivalentTo(testSize))
+		Expect(vlom.Cksum().Equal(cksum)).To(BeTrue())
+	})
+
+	It("should save migrated versions", func() {
+		lom := filePut(localFQN, testSize, tMock)
+		overwrite(lom, testSize+1)
+
+		workFQN := mix.MakePathFQN(localBck, fs.WorkfileType, testObjName)
+		createTestFile(workFQN, testSize+2)
+		archived := time.Now().Add(-time.Hour)
+		mlom := lom.Clone(lom.FQN)
+		mlom.SetVersion("7")
+		mlom.SetSize(testSize + 2)
+		Expect(mlom.SaveVersion(workFQN, archived)).NotTo(HaveOccurred())
+		Expect(workFQN).NotTo(BeAnExistingFile())
+
+		Expect(priorVersions(lom)).To(Equal([]string{"7", "1"}))
+		vlom, err := lom.LoadVersion("7")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vlom.Size()).To(BeEquivalentTo(testSize + 2))
+		prior, err := lom.PriorVersions()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(prior[0].Mtime.Unix()).To(Equal(archived.Unix()))
+	})
+})
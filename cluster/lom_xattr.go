@@ -52,6 +52,8 @@ const (
 	lomObjSize
 	lomObjCopies
 	lomCustomMD
+	lomObjAccess // access count (see LRU policies)
+	lomObjSSE    // ID of the key the object is encrypted with (see sse package)
 )
 
 // packing format separators
@@ -76,6 +78,7 @@ const (
 	VersionObjMD = "v"
 	CRC32CObjMD  = cmn.ChecksumCRC32C
 	MD5ObjMD     = cmn.ChecksumMD5
+	ETagObjMD    = "etag" // S3 ETag of the object assembled from multipart upload
 
 	OrigURLObjMD = "orig_url"
 )
@@ -130,6 +133,8 @@ func (lom *LOM) Persist() (err error) {
 	buf, mm := lom._persist()
 	if err = fs.SetXattr(lom.FQN, XattrLOM, buf); err != nil {
 		lom.T.FSHC(err, lom.FQN)
+	} else {
+		lom.md.acntfs = lom.md.acnt
 	}
 	mm.Free(buf)
 	return
@@ -190,6 +195,7 @@ func (md *lmeta) unmarshal(buf []byte) (err error) {
 		cksumType, cksumValue             string
 		haveSize, haveVersion, haveCopies bool
 		haveCksumType, haveCksumValue     bool
+		haveAccess, haveSSE               bool
 		last                              bool
 	)
 	if len(buf) < prefLen {
@@ -276,6 +282,19 @@ func (md *lmeta) unmarshal(buf []byte) (err error) {
 			for i := 0; i < len(entries); i += 2 {
 				md.customMD[entries[i]] = entries[i+1]
 			}
+		case lomObjAccess:
+			if haveAccess {
+				return errors.New(invalid + " #9")
+			}
+			md.acnt = binary.BigEndian.Uint64([]byte(val))
+			md.acntfs = md.acnt
+			haveAccess = true
+		case lomObjSSE:
+			if haveSSE {
+				return errors.New(invalid + " #10")
+			}
+			md.sse = val
+			haveSSE = true
 		default:
 			return errors.New(invalid + " #6")
 		}
@@ -320,6 +339,15 @@ func (md *lmeta) marshal(mm *memsys.MMSA, mdSize int64) (buf []byte) {
 		buf = _marshRecord(mm, buf, lomCustomMD, "", false)
 		buf = _marshCustomMD(mm, buf, md.customMD)
 	}
+	if md.acnt > 0 {
+		binary.BigEndian.PutUint64(b8[:], md.acnt)
+		buf = mm.Append(buf, recordSepa)
+		buf = _marshRecord(mm, buf, lomObjAccess, string(b8[:]), false)
+	}
+	if md.sse != "" {
+		buf = mm.Append(buf, recordSepa)
+		buf = _marshRecord(mm, buf, lomObjSSE, md.sse, false)
+	}
 
 	// checksum, prepend, and return
 	buf[0] = mdVersion
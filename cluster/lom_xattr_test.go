@@ -110,6 +110,19 @@ var _ = Describe("LOM Xattributes", func() {
 				Expect(lom.CustomMD()).To(BeEquivalentTo(newLom.CustomMD()))
 			})
 
+			It("should save access count", func() {
+				lom := filePut(localFQN, testFileSize, tMock)
+				Expect(lom.AccessCount()).To(BeEquivalentTo(0))
+				lom.IncAccessCount()
+				lom.IncAccessCount()
+				Expect(lom.Persist()).NotTo(HaveOccurred())
+
+				lom.Uncache()
+				newLom := NewBasicLom(localFQN, tMock)
+				Expect(newLom.Load(false)).NotTo(HaveOccurred())
+				Expect(newLom.AccessCount()).To(BeEquivalentTo(2))
+			})
+
 			It("should override old values", func() {
 				lom := filePut(localFQN, testFileSize, tMock)
 				lom.SetCksum(cmn.NewCksum(cmn.ChecksumXXHash, "test_checksum"))
@@ -74,6 +74,8 @@ type (
 		Started      time.Time
 		WithFinalize bool // Determines if we should also finalize the object.
 		SkipEncode   bool // Do not run EC encode after finalizing.
+		Copied       bool // Migrated object is a new copy (e.g., copy bucket) - not relocated by rebalance.
+		CheckQuota   bool // Enforce bucket quota (see cmn.QuotaConf).
 	}
 	CopyObjectParams struct {
 		BckTo     *Bck
@@ -124,6 +126,8 @@ type Target interface {
 	GetCold(ctx context.Context, lom *LOM, prefetch bool) (error, int)
 	PromoteFile(params PromoteFileParams) (lom *LOM, err error)
 	LookupRemoteSingle(lom *LOM, si *Snode) bool
+	HeadObjT2T(lom *LOM, si *Snode) (bool, error)
+	GetObjT2T(ctx context.Context, lom *LOM, si *Snode) (io.ReadCloser, error)
 
 	// File-system related functions.
 	FSHC(err error, path string)
@@ -54,11 +54,15 @@ func (*TargetMock) Cloud(_ *Bck) CloudProvider                             { ret
 func (*TargetMock) StartTime() time.Time                                   { return time.Now() }
 func (*TargetMock) GFN(_ GFNType) GFN                                      { return nil }
 func (*TargetMock) LookupRemoteSingle(_ *LOM, _ *Snode) bool               { return false }
+func (*TargetMock) HeadObjT2T(_ *LOM, _ *Snode) (bool, error)              { return false, nil }
 func (*TargetMock) RebalanceNamespace(_ *Snode) ([]byte, int, error)       { return nil, 0, nil }
 func (*TargetMock) BMDVersionFixup(_ *http.Request, _ cmn.Bck, _ bool)     {}
 func (*TargetMock) Health(_ *Snode, _ time.Duration, _ url.Values) ([]byte, error, int) {
 	return nil, nil, 0
 }
+func (*TargetMock) GetObjT2T(_ context.Context, _ *LOM, _ *Snode) (io.ReadCloser, error) {
+	return nil, nil
+}
 func (*TargetMock) AbortAllXacts(_ ...string) {}
 func (*TargetMock) CheckCloudVersion(_ context.Context, _ *LOM) (bool, error, int) {
 	return false, nil, 0
@@ -6,6 +6,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -13,6 +14,9 @@ import (
 
 const (
 	secretKeyEnvVar = "SECRETKEY"
+
+	oidcUsernameClaim = "sub"
+	oidcGroupsClaim   = "groups"
 )
 
 type (
@@ -41,6 +45,17 @@ type (
 		Secret          string        `json:"secret"`
 		ExpirePeriodStr string        `json:"expiration_time"`
 		ExpirePeriod    time.Duration `json:"-"`
+		OIDC            oidcConfig    `json:"oidc"`
+	}
+	// External OpenID Connect identity provider. AuthN exchanges ID tokens
+	// issued by the provider for its own tokens. Disabled if Issuer is empty.
+	oidcConfig struct {
+		Issuer        string              `json:"issuer"`
+		JWKSURL       string              `json:"jwks_url"`
+		ClientID      string              `json:"client_id"`
+		UsernameClaim string              `json:"username_claim"`
+		GroupsClaim   string              `json:"groups_claim"`
+		Groups        map[string][]string `json:"groups"` // IdP group => AuthN roles
 	}
 	timeoutConfig struct {
 		DefaultStr string        `json:"default_timeout"`
@@ -62,6 +77,27 @@ func (c *config) validate() (err error) {
 	if c.Auth.ExpirePeriod, err = time.ParseDuration(c.Auth.ExpirePeriodStr); err != nil {
 		return fmt.Errorf("invalid expire time format %s, err: %v", c.Auth.ExpirePeriodStr, err)
 	}
+	return c.Auth.OIDC.validate()
+}
+
+func (c *oidcConfig) enabled() bool { return c.Issuer != "" }
+
+func (c *oidcConfig) validate() error {
+	if !c.enabled() {
+		return nil
+	}
+	if c.JWKSURL == "" {
+		return errors.New("OIDC JWKS URL is not defined")
+	}
+	if c.ClientID == "" {
+		return errors.New("OIDC client ID is not defined")
+	}
+	if c.UsernameClaim == "" {
+		c.UsernameClaim = oidcUsernameClaim
+	}
+	if c.GroupsClaim == "" {
+		c.GroupsClaim = oidcGroupsClaim
+	}
 
 	return nil
 }
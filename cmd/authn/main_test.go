@@ -194,3 +194,57 @@ func TestToken(t *testing.T) {
 
 	deleteUsers(mgr, false, t)
 }
+
+func TestS3Keys(t *testing.T) {
+	driver := dbdriver.NewDBMock()
+	mgr, err := newUserManager(driver)
+	tassert.CheckError(t, err)
+	createUsers(mgr, t)
+
+	// incorrect user creds
+	_, err = mgr.issueS3Keys(users[0], passs[1])
+	if err != errInvalidCredentials {
+		t.Errorf("Invalid error: %v", err)
+	}
+
+	keys1, err := mgr.issueS3Keys(users[0], passs[0])
+	tassert.CheckFatal(t, err)
+	keys2, err := mgr.issueS3Keys(users[0], passs[0])
+	tassert.CheckFatal(t, err)
+	_, err = mgr.issueS3Keys(users[1], passs[1])
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, keys1.AccessKey != keys2.AccessKey && keys1.SecretKey != keys2.SecretKey,
+		"Same S3 keys issued twice: %+v", keys1)
+	tassert.Errorf(t, len(keys1.AccessKey) == 20 && len(keys1.SecretKey) == 40, "Invalid S3 keys: %+v", keys1)
+
+	list, err := mgr.userS3Keys(users[0])
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(list) == 2, "Expected 2 S3 keys, got %d", len(list))
+	for _, k := range list {
+		tassert.Errorf(t, k.SecretKey == "", "Secret key %s listed", k.AccessKey)
+	}
+
+	// the list pushed to clusters: all keys with their secrets and permissions
+	all, err := mgr.s3KeyList()
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(all.Keys) == 3, "Expected 3 S3 keys, got %d", len(all.Keys))
+	key, ok := all.Keys[keys1.AccessKey]
+	tassert.Fatalf(t, ok, "S3 key %s not found", keys1.AccessKey)
+	tassert.Errorf(t, key.SecretKey == keys1.SecretKey && key.UserID == users[0] && !key.IsAdmin,
+		"Invalid S3 key: %+v", key)
+
+	// only the owner's keys can be revoked
+	err = mgr.revokeS3Key(users[1], keys1.AccessKey)
+	tassert.Errorf(t, err == errS3KeyNotFound, "Invalid error: %v", err)
+	err = mgr.revokeS3Key(users[0], keys1.AccessKey)
+	tassert.CheckFatal(t, err)
+	list, err = mgr.userS3Keys(users[0])
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(list) == 1 && list[0].AccessKey == keys2.AccessKey, "Invalid S3 keys after revoke: %v", list)
+
+	// deleting a user revokes all their keys
+	deleteUsers(mgr, false, t)
+	all, err = mgr.s3KeyList()
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(all.Keys) == 0, "Expected no S3 keys, got %d", len(all.Keys))
+}
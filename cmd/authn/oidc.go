@@ -0,0 +1,375 @@
+// Package main - authorization server for AIStore. See README.md for more info.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ *
+ */
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/dgrijalva/jwt-go"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// a message to exchange an ID token issued by an external OIDC provider
+// for an AuthN token
+// POST: <version>/<pathTokens>
+//		Body: <oidcLoginMsg>
+//	Returns: <tokenMsg>
+type oidcLoginMsg struct {
+	IDToken string `json:"id_token"`
+}
+
+const (
+	// the minimal interval between two JWKS downloads caused by ID tokens
+	// signed with unknown keys
+	jwksRefreshInterval = 30 * time.Second
+)
+
+type (
+	// Validates ID tokens issued by an external OpenID Connect provider
+	// against the provider's public keys (JWKS) and the configured issuer
+	// and client ID.
+	oidcVerifier struct {
+		conf    *oidcConfig
+		client  *http.Client
+		mtx     sync.Mutex
+		keys    map[string]interface{} // key ID => *rsa.PublicKey or *ecdsa.PublicKey
+		fetched time.Time
+	}
+	jwkSet struct {
+		Keys []jwk `json:"keys"`
+	}
+	// JSON web key (RFC 7517): only the fields required to verify signatures
+	jwk struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+	// identity extracted from a valid ID token
+	oidcIdentity struct {
+		username string
+		groups   []string
+		expires  time.Time // ID token expiration time
+	}
+)
+
+var errOIDCDisabled = errors.New("OIDC login is not configured")
+
+func newOIDCVerifier(conf *oidcConfig, client *http.Client) *oidcVerifier {
+	return &oidcVerifier{conf: conf, client: client, keys: make(map[string]interface{})}
+}
+
+// Verifies the token signature and the standard claims, and returns
+// the user's identity.
+func (v *oidcVerifier) verify(idToken string) (*oidcIdentity, error) {
+	token, err := jwt.Parse(idToken, v.keyFunc)
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, cmn.ErrInvalidToken
+	}
+	// jwt-go validates "exp" only if it is present, ID tokens must have it
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errTokenExpired
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, errors.New("invalid \"exp\" claim")
+	}
+	if iss, _ := claims["iss"].(string); iss != v.conf.Issuer {
+		return nil, fmt.Errorf("invalid issuer %q", iss)
+	}
+	if !hasAudience(claims["aud"], v.conf.ClientID) {
+		return nil, fmt.Errorf("token is not issued for client %q", v.conf.ClientID)
+	}
+
+	ident := &oidcIdentity{
+		groups:  claimStrings(claims[v.conf.GroupsClaim]),
+		expires: time.Unix(int64(exp), 0),
+	}
+	if ident.username, _ = claims[v.conf.UsernameClaim].(string); ident.username == "" {
+		return nil, fmt.Errorf("missing %q claim", v.conf.UsernameClaim)
+	}
+	return ident, nil
+}
+
+// Returns the provider's public key the token is signed with.
+// Only asymmetric algorithms are allowed: a token signed with HMAC
+// could be forged by anyone who knows the client secret.
+func (v *oidcVerifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+	default:
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, err := v.lookupKey(kid)
+	if err != nil {
+		return nil, err
+	}
+	switch key.(type) {
+	case *rsa.PublicKey:
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); ok {
+			return key, nil
+		}
+	case *ecdsa.PublicKey:
+		if _, ok := token.Method.(*jwt.SigningMethodECDSA); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("key %q does not match signing method %v", kid, token.Header["alg"])
+}
+
+// Looks up a key by its ID. Providers rotate keys, so an unknown key ID
+// triggers downloading of the key set (but not more often than
+// jwksRefreshInterval). A token without key ID is accepted only if
+// the provider publishes a single key.
+func (v *oidcVerifier) lookupKey(kid string) (interface{}, error) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+	if key := v.findKey(kid); key != nil {
+		return key, nil
+	}
+	if !v.fetched.IsZero() && time.Since(v.fetched) < jwksRefreshInterval {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	if err := v.fetchKeys(); err != nil {
+		return nil, err
+	}
+	if key := v.findKey(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+func (v *oidcVerifier) findKey(kid string) interface{} {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key
+		}
+	}
+	return v.keys[kid]
+}
+
+func (v *oidcVerifier) fetchKeys() error {
+	v.fetched = time.Now()
+	resp, err := v.client.Get(v.conf.JWKSURL)
+	if err != nil {
+		return fmt.Errorf("failed to get OIDC keys: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get OIDC keys: %s", resp.Status)
+	}
+	set := &jwkSet{}
+	if err := jsoniter.NewDecoder(resp.Body).Decode(set); err != nil {
+		return fmt.Errorf("failed to parse OIDC keys: %v", err)
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for i := range set.Keys {
+		k := &set.Keys[i]
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			glog.Errorf("Skipping OIDC key %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	v.keys = keys
+	if glog.V(4) {
+		glog.Infof("Loaded %d OIDC key(s) from %s", len(keys), v.conf.JWKSURL)
+	}
+	return nil
+}
+
+func (k *jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("invalid EC point")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid key parameter %q", s)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// "aud" is either a single string or an array of strings
+func hasAudience(aud interface{}, clientID string) bool {
+	for _, a := range claimStrings(aud) {
+		if a == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+func claimStrings(claim interface{}) []string {
+	switch val := claim.(type) {
+	case string:
+		return []string{val}
+	case []interface{}:
+		strs := make([]string, 0, len(val))
+		for _, v := range val {
+			if s, ok := v.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+		return strs
+	default:
+		return nil
+	}
+}
+
+// Generates an AuthN token for a user authenticated by the external OIDC
+// provider. The user is not registered in AuthN: the permissions are
+// taken from the roles that the user's groups are mapped to.
+func (m *userManager) issueOIDCToken(idToken string) (string, error) {
+	if m.oidc == nil {
+		return "", errOIDCDisabled
+	}
+	ident, err := m.oidc.verify(idToken)
+	if err != nil {
+		glog.Errorf("Invalid OIDC token: %v", err)
+		return "", errInvalidCredentials
+	}
+	uInfo := m.oidcUser(ident)
+	if len(uInfo.Roles) == 0 {
+		glog.Errorf("OIDC user %q: none of the groups %v is mapped to AuthN roles", ident.username, ident.groups)
+		return "", errInvalidCredentials
+	}
+
+	// the token must not outlive the ID token: the user may lose access
+	// at the provider at any moment after that
+	expires := ident.expires
+	if conf.Auth.ExpirePeriod != 0 {
+		if exp := time.Now().Add(conf.Auth.ExpirePeriod); exp.Before(expires) {
+			expires = exp
+		}
+	}
+	return m.generateToken(uInfo, expires)
+}
+
+// Builds a user from the roles that the user's groups are mapped to.
+// Roles are resolved recursively; unknown roles are skipped.
+func (m *userManager) oidcUser(ident *oidcIdentity) *cmn.AuthUser {
+	var (
+		uInfo = &cmn.AuthUser{ID: ident.username}
+		seen  = make(map[string]struct{})
+		queue []string
+	)
+	for _, group := range ident.groups {
+		queue = append(queue, m.oidc.conf.Groups[group]...)
+	}
+	for len(queue) != 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		rInfo := &cmn.AuthRole{}
+		if err := m.db.Get(rolesCollection, name, rInfo); err != nil {
+			glog.Errorf("OIDC user %q: role %q not found", ident.username, name)
+			continue
+		}
+		uInfo.Roles = append(uInfo.Roles, name)
+		uInfo.Clusters = unionClusterACLs(uInfo.Clusters, rInfo.Clusters)
+		uInfo.Buckets = unionBckACLs(uInfo.Buckets, rInfo.Buckets)
+		queue = append(queue, rInfo.Roles...)
+	}
+	return uInfo
+}
+
+// Unlike cmn.MergeClusterACLs that overrides permissions, a user gets
+// the union of permissions granted by all their roles.
+func unionClusterACLs(acls, add []*cmn.AuthCluster) []*cmn.AuthCluster {
+outer:
+	for _, n := range add {
+		for _, o := range acls {
+			if o.ID == n.ID {
+				o.Access |= n.Access
+				continue outer
+			}
+		}
+		acls = append(acls, &cmn.AuthCluster{ID: n.ID, Access: n.Access})
+	}
+	return acls
+}
+
+func unionBckACLs(acls, add []*cmn.AuthBucket) []*cmn.AuthBucket {
+outer:
+	for _, n := range add {
+		for _, o := range acls {
+			if o.Bck.Equal(n.Bck) {
+				o.Access |= n.Access
+				continue outer
+			}
+		}
+		acls = append(acls, &cmn.AuthBucket{Bck: n.Bck, Access: n.Access})
+	}
+	return acls
+}
@@ -0,0 +1,215 @@
+// Package main - authorization server for AIStore. See README.md for more info.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ *
+ */
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	oidcTestIssuer = "https://idp.example.com"
+	oidcTestClient = "aistore"
+)
+
+var idExpires = time.Now().Add(time.Hour).Truncate(time.Second)
+
+// mock OIDC provider: publishes its keys and signs ID tokens
+type mockIdP struct {
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+	srv    *httptest.Server
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	tassert.CheckFatal(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tassert.CheckFatal(t, err)
+	idp := &mockIdP{rsaKey: rsaKey, ecKey: ecKey}
+
+	enc := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	keys := jwkSet{Keys: []jwk{
+		{
+			Kty: "RSA", Kid: "rsa", Use: "sig",
+			N: enc(rsaKey.N.Bytes()), E: enc(big.NewInt(int64(rsaKey.E)).Bytes()),
+		},
+		{
+			Kty: "EC", Kid: "ec", Crv: "P-256",
+			X: enc(ecKey.X.Bytes()), Y: enc(ecKey.Y.Bytes()),
+		},
+		{Kty: "RSA", Kid: "enc", Use: "enc", N: enc(rsaKey.N.Bytes()), E: "AQAB"},
+	}}
+	idp.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(cmn.HeaderContentType, cmn.ContentJSON)
+		w.Write(cmn.MustMarshal(keys))
+	}))
+	return idp
+}
+
+func (idp *mockIdP) token(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.MapClaims) string {
+	var key crypto.PrivateKey
+	switch method.(type) {
+	case *jwt.SigningMethodRSA:
+		key = idp.rsaKey
+	case *jwt.SigningMethodECDSA:
+		key = idp.ecKey
+	default:
+		key = []byte(oidcTestClient)
+	}
+	tk := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		tk.Header["kid"] = kid
+	}
+	s, err := tk.SignedString(key)
+	tassert.CheckFatal(t, err)
+	return s
+}
+
+type testTokenClaims struct {
+	cmn.AuthToken
+}
+
+func (*testTokenClaims) Valid() error { return nil }
+
+func parseTestToken(t *testing.T, token string) *cmn.AuthToken {
+	claims := &testTokenClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(conf.Auth.Secret), nil
+	})
+	tassert.CheckFatal(t, err)
+	return &claims.AuthToken
+}
+
+func oidcClaims(sub string, groups ...string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":    oidcTestIssuer,
+		"aud":    []string{"other", oidcTestClient},
+		"sub":    sub,
+		"exp":    idExpires.Unix(),
+		"groups": groups,
+	}
+}
+
+func TestOIDCLogin(t *testing.T) {
+	idp := newMockIdP(t)
+	defer idp.srv.Close()
+
+	if conf.Auth.Secret == "" {
+		conf.Auth.Secret = "aBitLongSecretKey"
+	}
+	oconf := &oidcConfig{
+		Issuer:   oidcTestIssuer,
+		JWKSURL:  idp.srv.URL,
+		ClientID: oidcTestClient,
+		Groups: map[string][]string{
+			"ais-admins": {cmn.AuthAdminRole},
+			"team-a":     {cmn.AuthBucketOwnerRole + "-one"},
+			"team-b":     {cmn.AuthClusterOwnerRole + "-one", "no-such-role"},
+			"readers":    {cmn.AuthGuestRole + "-one"},
+		},
+	}
+	tassert.CheckFatal(t, oconf.validate())
+
+	mgr, err := newUserManager(dbdriver.NewDBMock())
+	tassert.CheckFatal(t, err)
+	_, err = mgr.issueOIDCToken(idp.token(t, jwt.SigningMethodRS256, "rsa", oidcClaims("alice", "team-a")))
+	tassert.Errorf(t, err == errOIDCDisabled, "expected OIDC to be disabled, got %v", err)
+
+	mgr.oidc = newOIDCVerifier(oconf, idp.srv.Client())
+	tassert.CheckFatal(t, mgr.addCluster(&cmn.AuthCluster{ID: "clu1", Alias: "one"}))
+
+	tests := []struct {
+		name   string
+		token  string
+		admin  bool
+		access cmn.AccessAttrs
+	}{
+		{
+			name:   "bucket owner",
+			token:  idp.token(t, jwt.SigningMethodRS256, "rsa", oidcClaims("alice", "team-a", "unknown")),
+			access: cmn.ReadWriteAccess(),
+		},
+		{
+			name:   "union of roles",
+			token:  idp.token(t, jwt.SigningMethodES256, "ec", oidcClaims("bob", "readers", "team-b")),
+			access: cmn.AllAccess(),
+		},
+		{
+			name:  "admin",
+			token: idp.token(t, jwt.SigningMethodRS256, "rsa", oidcClaims("carol", "ais-admins")),
+			admin: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			token, err := mgr.issueOIDCToken(test.token)
+			tassert.CheckFatal(t, err)
+			tk := parseTestToken(t, token)
+			tassert.Errorf(t, !tk.Expires.After(idExpires),
+				"token expires %v after the ID token (%v)", tk.Expires, idExpires)
+			tassert.Errorf(t, tk.IsAdmin == test.admin, "expected admin=%t", test.admin)
+			if test.admin {
+				return
+			}
+			tassert.Fatalf(t, len(tk.Clusters) == 1, "expected 1 cluster, got %d", len(tk.Clusters))
+			tassert.Errorf(t, tk.Clusters[0].ID == "clu1", "invalid cluster %q", tk.Clusters[0].ID)
+			tassert.Errorf(t, tk.Clusters[0].Access == test.access,
+				"expected access %v, got %v", test.access, tk.Clusters[0].Access)
+		})
+	}
+
+	// no AuthN expiration: the token expires along with the ID token
+	expirePeriod := conf.Auth.ExpirePeriod
+	conf.Auth.ExpirePeriod = 0
+	token, err := mgr.issueOIDCToken(idp.token(t, jwt.SigningMethodRS256, "rsa", oidcClaims("alice", "team-a")))
+	conf.Auth.ExpirePeriod = expirePeriod
+	tassert.CheckFatal(t, err)
+	tk := parseTestToken(t, token)
+	tassert.Errorf(t, tk.Expires.Equal(idExpires), "expected token to expire at %v, got %v", idExpires, tk.Expires)
+
+	expired := oidcClaims("alice", "team-a")
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	wrongIssuer := oidcClaims("alice", "team-a")
+	wrongIssuer["iss"] = "https://evil.example.com"
+	wrongAudience := oidcClaims("alice", "team-a")
+	wrongAudience["aud"] = "other"
+	noExpiration := oidcClaims("alice", "team-a")
+	delete(noExpiration, "exp")
+	invalid := map[string]string{
+		"expired":          idp.token(t, jwt.SigningMethodRS256, "rsa", expired),
+		"wrong issuer":     idp.token(t, jwt.SigningMethodRS256, "rsa", wrongIssuer),
+		"wrong audience":   idp.token(t, jwt.SigningMethodRS256, "rsa", wrongAudience),
+		"no expiration":    idp.token(t, jwt.SigningMethodRS256, "rsa", noExpiration),
+		"no username":      idp.token(t, jwt.SigningMethodRS256, "rsa", oidcClaims("", "team-a")),
+		"unmapped groups":  idp.token(t, jwt.SigningMethodRS256, "rsa", oidcClaims("alice", "unknown")),
+		"HMAC signed":      idp.token(t, jwt.SigningMethodHS256, "rsa", oidcClaims("alice", "team-a")),
+		"mismatched key":   idp.token(t, jwt.SigningMethodRS256, "ec", oidcClaims("alice", "team-a")),
+		"encryption key":   idp.token(t, jwt.SigningMethodRS256, "enc", oidcClaims("alice", "team-a")),
+		"unknown key":      idp.token(t, jwt.SigningMethodRS256, "other", oidcClaims("alice", "team-a")),
+		"ambiguous key":    idp.token(t, jwt.SigningMethodRS256, "", oidcClaims("alice", "team-a")),
+		"malformed token":  "not.a.token",
+		"tampered payload": idp.token(t, jwt.SigningMethodRS256, "rsa", oidcClaims("alice", "team-a")) + "x",
+	}
+	for name, token := range invalid {
+		_, err := mgr.issueOIDCToken(token)
+		tassert.Errorf(t, err == errInvalidCredentials, "%s: expected invalid credentials, got %v", name, err)
+	}
+}
@@ -20,12 +20,33 @@ import (
 func (m *userManager) broadcastRevoked(token string) {
 	tokenList := ais.TokenList{Tokens: []string{token}}
 	body := cmn.MustMarshal(tokenList)
-	m.broadcast(http.MethodDelete, cmn.Tokens, body)
+	m.broadcast(http.MethodDelete, cmn.Tokens, body, "")
+}
+
+// replace the list of S3 keys on all clusters (the list includes all keys, so
+// that revoked ones are removed). Unlike the list of revoked tokens, it grants
+// access and so must be sent with an administrator's token.
+func (m *userManager) broadcastS3Keys() {
+	if cluList, err := m.clusterList(); err != nil || len(cluList) == 0 {
+		return // nothing to sync
+	}
+	list, err := m.s3KeyList()
+	if err != nil {
+		glog.Errorf("Failed to read S3 keys: %v", err)
+		return
+	}
+	admin := &cmn.AuthUser{ID: adminID, Roles: []string{cmn.AuthAdminRole}}
+	token, err := m.generateToken(admin, time.Now().Add(proxyTimeout+proxyRetryTime))
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	m.broadcast(http.MethodPut, cmn.Tokens+"/"+cmn.S3Keys, cmn.MustMarshal(list), token)
 }
 
 // broadcast the request to all clusters. If a cluster has a few URLS,
 // it sends to the first working one. Clusters are processed in parallel.
-func (m *userManager) broadcast(method, path string, body []byte) {
+func (m *userManager) broadcast(method, path string, body []byte, token string) {
 	cluList, err := m.clusterList()
 	if err != nil {
 		glog.Errorf("Failed to read cluster list: %v", err)
@@ -38,12 +59,12 @@ func (m *userManager) broadcast(method, path string, body []byte) {
 			defer wg.Done()
 			var err error
 			for _, u := range clu.URLs {
-				if err = m.proxyRequest(method, u, path, body); err == nil {
+				if err = m.proxyRequest(method, u, path, body, token); err == nil {
 					break
 				}
 			}
 			if err != nil {
-				glog.Errorf("Failed to sync revoked tokens with %q: %v", clu.ID, err)
+				glog.Errorf("Failed to sync %s with %q: %v", path, clu.ID, err)
 			}
 		}(clu)
 	}
@@ -51,7 +72,7 @@ func (m *userManager) broadcast(method, path string, body []byte) {
 }
 
 // Generic function to send everything to a proxy
-func (m *userManager) proxyRequest(method, proxyURL, path string, injson []byte) error {
+func (m *userManager) proxyRequest(method, proxyURL, path string, injson []byte, token string) error {
 	startRequest := time.Now()
 	for {
 		url := proxyURL + cmn.JoinWords(cmn.Version, path)
@@ -65,6 +86,9 @@ func (m *userManager) proxyRequest(method, proxyURL, path string, injson []byte)
 			client = m.clientHTTPS
 		}
 		request.Header.Set(cmn.HeaderContentType, cmn.ContentJSON)
+		if token != "" {
+			request.Header.Set(cmn.HeaderAuthorization, cmn.MakeHeaderAuthnToken(token))
+		}
 		response, err := client.Do(request)
 		var respCode int
 		if response != nil {
@@ -0,0 +1,171 @@
+// Package main - authorization server for AIStore. See README.md for more info.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// S3 credentials are random access/secret key pairs that are not related to
+// user tokens: a user may have any number of them, and each pair lives until
+// it is revoked (or the user is deleted). The pairs are stored in the DB and,
+// whenever they change, pushed to all registered clusters along with the
+// permissions of their users - see broadcastS3Keys.
+
+const (
+	s3KeysCollection = "s3key"
+
+	s3AccessKeyLen = 10 // random bytes (20 hex characters, the length of AWS access key)
+	s3SecretKeyLen = 30 // random bytes (40 base64 characters, the length of AWS secret key)
+)
+
+var errS3KeyNotFound = errors.New("S3 access key not found")
+
+func genS3Keys() (accessKey, secretKey string, err error) {
+	b := make([]byte, s3AccessKeyLen+s3SecretKeyLen)
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+	accessKey = strings.ToUpper(hex.EncodeToString(b[:s3AccessKeyLen]))
+	secretKey = base64.StdEncoding.EncodeToString(b[s3AccessKeyLen:])
+	return
+}
+
+// Issues a new S3 key pair for a user if the user credentials are valid.
+func (m *userManager) issueS3Keys(userID, pwd string) (*cmn.AuthS3Keys, error) {
+	uInfo := &cmn.AuthUser{}
+	if err := m.db.Get(usersCollection, userID, uInfo); err != nil {
+		glog.Error(err)
+		return nil, errInvalidCredentials
+	}
+	if !isSamePassword(pwd, uInfo.Password) {
+		return nil, errInvalidCredentials
+	}
+	accessKey, secretKey, err := genS3Keys()
+	if err != nil {
+		return nil, err
+	}
+	keys := &cmn.AuthS3Keys{
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		UserID:    userID,
+		Created:   time.Now(),
+	}
+	if err := m.db.Set(s3KeysCollection, accessKey, keys); err != nil {
+		return nil, err
+	}
+	go m.broadcastS3Keys()
+	return keys, nil
+}
+
+func (m *userManager) allS3Keys() ([]*cmn.AuthS3Keys, error) {
+	recs, err := m.db.GetAll(s3KeysCollection, "")
+	if err != nil {
+		return nil, err
+	}
+	all := make([]*cmn.AuthS3Keys, 0, len(recs))
+	for accessKey, rec := range recs {
+		keys := &cmn.AuthS3Keys{}
+		if err := jsoniter.Unmarshal([]byte(rec), keys); err != nil {
+			glog.Errorf("Failed to parse S3 key %s: %v", accessKey, err)
+			continue
+		}
+		all = append(all, keys)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Created.Before(all[j].Created) })
+	return all, nil
+}
+
+// Returns S3 key pairs of a user (without secret keys)
+func (m *userManager) userS3Keys(userID string) ([]*cmn.AuthS3Keys, error) {
+	all, err := m.allS3Keys()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*cmn.AuthS3Keys, 0, 2)
+	for _, k := range all {
+		if k.UserID == userID {
+			k.SecretKey = ""
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+// Revokes S3 key pair of a user
+func (m *userManager) revokeS3Key(userID, accessKey string) error {
+	keys := &cmn.AuthS3Keys{}
+	if err := m.db.Get(s3KeysCollection, accessKey, keys); err != nil || keys.UserID != userID {
+		return errS3KeyNotFound
+	}
+	if err := m.db.Delete(s3KeysCollection, accessKey); err != nil {
+		return err
+	}
+	go m.broadcastS3Keys()
+	return nil
+}
+
+// Removes all S3 key pairs of a user; returns true if there were any
+func (m *userManager) delUserS3Keys(userID string) (deleted bool) {
+	all, err := m.allS3Keys()
+	if err != nil {
+		glog.Errorf("Failed to read S3 keys: %v", err)
+		return
+	}
+	for _, k := range all {
+		if k.UserID != userID {
+			continue
+		}
+		if err := m.db.Delete(s3KeysCollection, k.AccessKey); err != nil {
+			glog.Errorf("Failed to remove S3 key %s of %s: %v", k.AccessKey, userID, err)
+			continue
+		}
+		deleted = true
+	}
+	return
+}
+
+// Returns all S3 key pairs along with the permissions of their users
+// (the same permissions a token of the user would carry - see generateToken)
+func (m *userManager) s3KeyList() (*cmn.AuthS3KeyList, error) {
+	all, err := m.allS3Keys()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		list  = &cmn.AuthS3KeyList{Keys: make(map[string]*cmn.AuthS3Key, len(all)), Created: time.Now()}
+		users = make(map[string]*cmn.AuthUser, 4)
+	)
+	for _, keys := range all {
+		uInfo, ok := users[keys.UserID]
+		if !ok {
+			uInfo = &cmn.AuthUser{}
+			if err := m.db.Get(usersCollection, keys.UserID, uInfo); err != nil {
+				glog.Errorf("S3 key %s: user %q not found", keys.AccessKey, keys.UserID)
+				continue
+			}
+			m.fixClusterIDs(uInfo.Clusters)
+			users[keys.UserID] = uInfo
+		}
+		key := &cmn.AuthS3Key{AuthS3Keys: *keys}
+		if uInfo.IsAdmin() {
+			key.IsAdmin = true
+		} else {
+			key.Clusters, key.Buckets = uInfo.Clusters, uInfo.Buckets
+		}
+		list.Keys[keys.AccessKey] = key
+	}
+	return list, nil
+}
@@ -20,6 +20,7 @@ const (
 	pathUsers    = "users"
 	pathTokens   = "tokens"
 	pathClusters = "clusters"
+	pathS3Keys   = cmn.S3Keys
 )
 
 // a message to generate token
@@ -30,6 +31,17 @@ type loginMsg struct {
 	Password string `json:"password"`
 }
 
+// a message to generate S3 credentials (a new key pair)
+// POST: <version>/<pathUsers>/<username>/<pathS3Keys>
+//		Body: <loginMsg>
+//	Returns: <cmn.AuthS3Keys>
+// list S3 key pairs of a user (secret keys are not returned)
+// GET: <version>/<pathUsers>/<username>/<pathS3Keys>
+//	Returns: []<cmn.AuthS3Keys>
+// revoke S3 key pair
+// DEL: <version>/<pathUsers>/<username>/<pathS3Keys>/<access key>
+// (listing and revoking require the token of the user or administrator)
+
 // a message to test token validity and to revoke existing token
 // check: GET <version>/<pathTokens>
 //		Body: <tokenMsg>
@@ -130,6 +142,8 @@ func (a *authServ) tokenHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodDelete:
 		a.httpRevokeToken(w, r)
+	case http.MethodPost:
+		a.httpOIDCLogin(w, r)
 	default:
 		cmn.InvalidHandlerWithMsg(w, r, "Unsupported method for /token handler")
 	}
@@ -165,11 +179,47 @@ func (a *authServ) httpRevokeToken(w http.ResponseWriter, r *http.Request) {
 	a.users.revokeToken(msg.Token)
 }
 
+// Exchanges an ID token issued by the external OIDC provider for
+// an AuthN token
+func (a *authServ) httpOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	if _, err := checkRESTItems(w, r, 0, cmn.Version, pathTokens); err != nil {
+		return
+	}
+
+	msg := &oidcLoginMsg{}
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		glog.Errorf("Failed to read request body: %v\n", err)
+		return
+	}
+	if msg.IDToken == "" {
+		cmn.InvalidHandlerWithMsg(w, r, "Not authorized", http.StatusUnauthorized)
+		return
+	}
+
+	tokenString, err := a.users.issueOIDCToken(msg.IDToken)
+	if err == errOIDCDisabled {
+		cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		glog.Errorf("Failed to generate token: %v\n", err)
+		cmn.InvalidHandlerWithMsg(w, r, "Not authorized", http.StatusUnauthorized)
+		return
+	}
+
+	repl := fmt.Sprintf(`{"token": "%s"}`, tokenString)
+	a.writeBytes(w, []byte(repl), "auth")
+}
+
 func (a *authServ) httpUserDel(w http.ResponseWriter, r *http.Request) {
 	apiItems, err := checkRESTItems(w, r, 1, cmn.Version, pathUsers)
 	if err != nil {
 		return
 	}
+	if len(apiItems) == 3 && apiItems[1] == pathS3Keys {
+		a.userS3KeyRevoke(w, r, apiItems[0], apiItems[2])
+		return
+	}
 
 	if err = a.checkAuthorization(w, r); err != nil {
 		glog.Errorf("Not authorized: %v\n", err)
@@ -186,6 +236,8 @@ func (a *authServ) httpUserPost(w http.ResponseWriter, r *http.Request) {
 		return
 	} else if len(apiItems) == 0 {
 		a.userAdd(w, r)
+	} else if len(apiItems) == 2 && apiItems[1] == pathS3Keys {
+		a.userS3Keys(w, r)
 	} else {
 		a.userLogin(w, r)
 	}
@@ -243,10 +295,14 @@ func (a *authServ) userAdd(w http.ResponseWriter, r *http.Request) {
 
 // Returns list of users (without superusers)
 func (a *authServ) httpUserGet(w http.ResponseWriter, r *http.Request) {
-	_, err := checkRESTItems(w, r, 0, cmn.Version, pathUsers)
+	apiItems, err := checkRESTItems(w, r, 0, cmn.Version, pathUsers)
 	if err != nil {
 		return
 	}
+	if len(apiItems) == 2 && apiItems[1] == pathS3Keys {
+		a.userS3KeyList(w, r, apiItems[0])
+		return
+	}
 
 	var users map[string]*cmn.AuthUser
 	if users, err = a.users.userList(); err != nil {
@@ -281,6 +337,27 @@ func (a *authServ) checkAuthorization(w http.ResponseWriter, r *http.Request) er
 	return nil
 }
 
+// Checks if the request header contains the token of the user or of
+// an administrator.
+func (a *authServ) checkUserAuthorization(w http.ResponseWriter, r *http.Request, userID string) error {
+	s := strings.SplitN(r.Header.Get(cmn.HeaderAuthorization), " ", 2)
+	if len(s) != 2 {
+		cmn.InvalidHandlerWithMsg(w, r, "Not authorized", http.StatusUnauthorized)
+		return fmt.Errorf("invalid header")
+	}
+	token, err := cmn.DecryptToken(s[1], conf.Auth.Secret)
+	if err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, "Not authorized", http.StatusUnauthorized)
+		return err
+	}
+	if !token.IsAdmin && token.UserID != userID {
+		msg := "insufficient permissions"
+		cmn.InvalidHandlerWithMsg(w, r, msg, http.StatusUnauthorized)
+		return errors.New(msg)
+	}
+	return nil
+}
+
 // Generate a token for a user if provided credentials are valid.
 // If a token is already issued and it is not expired yet then the old
 // token is returned
@@ -320,6 +397,66 @@ func (a *authServ) userLogin(w http.ResponseWriter, r *http.Request) {
 	a.writeBytes(w, []byte(repl), "auth")
 }
 
+// Generate a new S3 key pair for a user if provided credentials are valid.
+func (a *authServ) userS3Keys(w http.ResponseWriter, r *http.Request) {
+	apiItems, err := checkRESTItems(w, r, 2, cmn.Version, pathUsers)
+	if err != nil {
+		return
+	}
+
+	msg := &loginMsg{}
+	if err = cmn.ReadJSON(w, r, msg); err != nil {
+		glog.Errorf("Failed to read request body: %v\n", err)
+		return
+	}
+	if msg.Password == "" {
+		cmn.InvalidHandlerWithMsg(w, r, "Not authorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID := apiItems[0]
+	keys, err := a.users.issueS3Keys(userID, msg.Password)
+	if err == errInvalidCredentials {
+		cmn.InvalidHandlerWithMsg(w, r, "Not authorized", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		glog.Errorf("Failed to generate S3 keys: %v\n", err)
+		cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	a.writeJSON(w, keys, "S3 keys")
+}
+
+// Returns S3 key pairs of a user
+func (a *authServ) userS3KeyList(w http.ResponseWriter, r *http.Request, userID string) {
+	if err := a.checkUserAuthorization(w, r, userID); err != nil {
+		glog.Errorf("Not authorized: %v\n", err)
+		return
+	}
+	keys, err := a.users.userS3Keys(userID)
+	if err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	a.writeJSON(w, keys, "S3 keys")
+}
+
+// Revokes S3 key pair of a user
+func (a *authServ) userS3KeyRevoke(w http.ResponseWriter, r *http.Request, userID, accessKey string) {
+	if err := a.checkUserAuthorization(w, r, userID); err != nil {
+		glog.Errorf("Not authorized: %v\n", err)
+		return
+	}
+	if err := a.users.revokeS3Key(userID, accessKey); err != nil {
+		status := http.StatusInternalServerError
+		if err == errS3KeyNotFound {
+			status = http.StatusNotFound
+		}
+		cmn.InvalidHandlerWithMsg(w, r, err.Error(), status)
+	}
+}
+
 // Borrowed from ais (modified cmn.InvalidHandler calls)
 func (a *authServ) writeJSON(w http.ResponseWriter, val interface{}, tag string) {
 	w.Header().Set(cmn.HeaderContentType, cmn.ContentJSON)
@@ -41,6 +41,7 @@ type (
 		clientHTTP  *http.Client
 		clientHTTPS *http.Client
 		db          dbdriver.Driver
+		oidc        *oidcVerifier // nil if OIDC login is disabled
 	}
 )
 
@@ -114,6 +115,11 @@ func newUserManager(driver dbdriver.Driver) (*userManager, error) {
 		clientHTTPS: clientHTTPS,
 		db:          driver,
 	}
+	if conf.Auth.OIDC.enabled() {
+		// unlike clientHTTPS, verifies the identity provider's certificate
+		client := cmn.NewClient(cmn.TransportArgs{Timeout: conf.Timeout.Default, UseHTTPS: true})
+		mgr.oidc = newOIDCVerifier(&conf.Auth.OIDC, client)
+	}
 	err := initializeDB(driver)
 	return mgr, err
 }
@@ -200,6 +206,9 @@ func (m *userManager) addCluster(info *cmn.AuthCluster) error {
 		return err
 	}
 	m.createRolesForCluster(info)
+	if keys, err := m.allS3Keys(); err == nil && len(keys) > 0 {
+		go m.broadcastS3Keys()
+	}
 	return nil
 }
 
@@ -220,6 +229,9 @@ func (m *userManager) delUser(userID string) error {
 		_ = m.db.Delete(tokensCollection, userID)
 		err = m.db.SetString(revokedCollection, token.Token, userID)
 	}
+	if m.delUserS3Keys(userID) {
+		go m.broadcastS3Keys()
+	}
 
 	return err
 }
@@ -262,7 +274,14 @@ func (m *userManager) updateUser(userID string, updateReq *cmn.AuthUser) error {
 	uInfo.Clusters = cmn.MergeClusterACLs(uInfo.Clusters, updateReq.Clusters)
 	uInfo.Buckets = cmn.MergeBckACLs(uInfo.Buckets, updateReq.Buckets)
 
-	return m.db.Set(usersCollection, userID, uInfo)
+	if err := m.db.Set(usersCollection, userID, uInfo); err != nil {
+		return err
+	}
+	// S3 keys carry the user's permissions
+	if keys, err := m.userS3Keys(userID); err == nil && len(keys) > 0 {
+		go m.broadcastS3Keys()
+	}
+	return nil
 }
 
 // Updates an existing role
@@ -307,7 +326,13 @@ func (m *userManager) updateCluster(cluID string, info *cmn.AuthCluster) error {
 	if len(info.URLs) != 0 {
 		clu.URLs = info.URLs
 	}
-	return m.db.Set(clustersCollection, cluID, clu)
+	if err := m.db.Set(clustersCollection, cluID, clu); err != nil {
+		return err
+	}
+	if keys, err := m.allS3Keys(); err == nil && len(keys) > 0 {
+		go m.broadcastS3Keys()
+	}
+	return nil
 }
 
 // Before putting a list of cluster permissions to a token, cluster aliases
@@ -365,22 +390,38 @@ func (m *userManager) issueToken(userID, pwd string, ttl ...time.Duration) (stri
 		expDelta = foreverTokenTime
 	}
 	expires = issued.Add(expDelta)
+	tokenString, err := m.generateToken(uInfo, expires)
+	if err != nil {
+		return "", err
+	}
+
+	// TODO: multiple tokens per user
+	tInfo = &cmn.AuthToken{
+		UserID:  userID,
+		Expires: expires,
+		Token:   tokenString,
+	}
+	err = m.db.Set(tokensCollection, userID, tInfo)
+	return tokenString, err
+}
 
-	// put all useful info into token: who owns the token, when it was issued,
-	// when it expires and credentials to log in AWS, GCP etc.
-	// If a user is a super user, it is enough to pass only isAdmin marker
+// Signs a new token for a user.
+// Put all useful info into token: who owns the token, when it was issued,
+// when it expires and credentials to log in AWS, GCP etc.
+// If a user is a super user, it is enough to pass only isAdmin marker
+func (m *userManager) generateToken(uInfo *cmn.AuthUser, expires time.Time) (string, error) {
 	var t *jwt.Token
 	if uInfo.IsAdmin() {
 		t = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 			"expires":  expires,
-			"username": userID,
+			"username": uInfo.ID,
 			"admin":    true,
 		})
 	} else {
 		m.fixClusterIDs(uInfo.Clusters)
 		t = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 			"expires":  expires,
-			"username": userID,
+			"username": uInfo.ID,
 			"buckets":  uInfo.Buckets,
 			"clusters": uInfo.Clusters,
 		})
@@ -389,15 +430,7 @@ func (m *userManager) issueToken(userID, pwd string, ttl ...time.Duration) (stri
 	if err != nil {
 		return "", fmt.Errorf("failed to generate token: %v", err)
 	}
-
-	// TODO: multiple tokens per user
-	tInfo = &cmn.AuthToken{
-		UserID:  userID,
-		Expires: expires,
-		Token:   tokenString,
-	}
-	err = m.db.Set(tokensCollection, userID, tInfo)
-	return tokenString, err
+	return tokenString, nil
 }
 
 // Delete existing token, a.k.a log out
@@ -170,6 +170,8 @@ func (aisCLI *AISCLI) setupCommands() {
 	app.Commands = append(app.Commands, waitCmds...)
 	app.Commands = append(app.Commands, objectSpecificCmds...)
 	app.Commands = append(app.Commands, etlCmds...)
+	app.Commands = append(app.Commands, scheduleCmds...)
+	app.Commands = append(app.Commands, syncCmds...)
 	sort.Sort(cli.CommandsByName(app.Commands))
 
 	setupCommandHelp(app.Commands)
@@ -195,6 +195,10 @@ func listObjects(c *cli.Context, bck cmn.Bck) error {
 		}
 	}
 
+	if flagIsSet(c, versionsFlag) {
+		msg.Flags |= cmn.SelectVersions
+		msg.AddProps(cmn.GetPropsVersion)
+	}
 	if flagIsSet(c, startAfterFlag) {
 		msg.StartAfter = parseStrFlag(c, startAfterFlag)
 	}
@@ -229,6 +233,7 @@ func listObjects(c *cli.Context, bck cmn.Bck) error {
 			} else {
 				toPrint = objList.Entries
 			}
+			toPrint = flattenVersions(toPrint)
 			err = printObjectProps(c, toPrint, objectListFilter, msg.Props, showUnmatched, !flagIsSet(c, noHeaderFlag))
 			if err != nil {
 				return err
@@ -270,7 +275,25 @@ func listObjects(c *cli.Context, bck cmn.Bck) error {
 		return err
 	}
 
-	return printObjectProps(c, objList.Entries, objectListFilter, msg.Props, showUnmatched, !flagIsSet(c, noHeaderFlag))
+	return printObjectProps(c, flattenVersions(objList.Entries), objectListFilter, msg.Props, showUnmatched,
+		!flagIsSet(c, noHeaderFlag))
+}
+
+// Prior versions of an object (if requested) are listed right after the object.
+func flattenVersions(entries []*cmn.BucketEntry) []*cmn.BucketEntry {
+	var n int
+	for _, entry := range entries {
+		n += len(entry.Versions)
+	}
+	if n == 0 {
+		return entries
+	}
+	flat := make([]*cmn.BucketEntry, 0, len(entries)+n)
+	for _, entry := range entries {
+		flat = append(flat, entry)
+		flat = append(flat, entry.Versions...)
+	}
+	return flat
 }
 
 func fetchSummaries(query cmn.QueryBcks, fast, cached bool) (summaries cmn.BucketsSummaries, err error) {
@@ -579,8 +602,12 @@ func newObjectListFilter(c *cli.Context) (*objectListFilter, error) {
 	objFilter := &objectListFilter{}
 
 	if !flagIsSet(c, allItemsFlag) {
-		// Filter out files with status different than OK
-		objFilter.addFilter(func(obj *cmn.BucketEntry) bool { return obj.IsStatusOK() })
+		// Filter out files with status different than OK (except deleted
+		// objects when listing their prior versions)
+		versions := flagIsSet(c, versionsFlag)
+		objFilter.addFilter(func(obj *cmn.BucketEntry) bool {
+			return obj.IsStatusOK() || (versions && obj.IsStatusDeleted())
+		})
 	}
 
 	if regexStr := parseStrFlag(c, regexFlag); regexStr != "" {
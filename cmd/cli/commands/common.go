@@ -39,9 +39,13 @@ const (
 	commandShow      = "show"
 	commandStart     = cmn.ActXactStart
 	commandStop      = cmn.ActXactStop
+	commandPause     = cmn.Pause
+	commandResume    = cmn.Resume
 	commandWait      = "wait"
 	commandSearch    = "search"
 	commandETL       = cmn.ETL
+	commandSchedule  = "schedule"
+	commandSync      = "sync"
 
 	// Subcommands - preferably nouns
 	subcmdDsort     = cmn.DSortNameLowercase
@@ -67,6 +71,7 @@ const (
 	subcmdLogs      = "logs"
 	subcmdStop      = "stop"
 	subcmdLRU       = cmn.ActLRU
+	subcmdScrub     = cmn.ActScrub
 
 	// Show subcommands
 	subcmdShowBucket    = subcmdBucket
@@ -81,6 +86,7 @@ const (
 	subcmdShowRemoteAIS = subcmdRemoteAIS
 	subcmdShowCluster   = subcmdCluster
 	subcmdShowMpath     = subcmdMountpath
+	subcmdShowScrub     = subcmdScrub
 
 	// Create subcommands
 	subcmdCreateBucket = subcmdBucket
@@ -99,6 +105,9 @@ const (
 	// Copy subcommands
 	subcmdCopyBucket = subcmdBucket
 
+	// Sync subcommands
+	subcmdSyncBucket = subcmdBucket
+
 	// Start subcommands
 	subcmdStartXaction  = subcmdXaction
 	subcmdStartDsort    = subcmdDsort
@@ -109,6 +118,10 @@ const (
 	subcmdStopDsort    = subcmdDsort
 	subcmdStopDownload = subcmdDownload
 
+	// Pause/Resume subcommands
+	subcmdPauseDownload  = subcmdDownload
+	subcmdResumeDownload = subcmdDownload
+
 	// Set subcommand
 	subcmdSetConfig  = subcmdConfig
 	subcmdSetProps   = subcmdProps
@@ -140,6 +153,12 @@ const (
 	subcmdAuthRole    = "role"
 	subcmdAuthCluster = "cluster"
 
+	// Schedule subcommands
+	subcmdScheduleAdd     = "add"
+	subcmdScheduleShow    = commandShow
+	subcmdScheduleRemove  = commandRemove
+	subcmdScheduleHistory = "history"
+
 	// Default values for long running operations
 	refreshRateDefault = time.Second
 	countDefault       = 1
@@ -165,6 +184,7 @@ const (
 	optionalBucketArgument = "[BUCKET_NAME]"
 	bucketsArgument        = "BUCKET_NAME [BUCKET_NAME...]"
 	bucketOldNewArgument   = bucketArgument + " NEW_NAME"
+	bucketSyncArgument     = "SRC_BUCKET_NAME DST_BUCKET_NAME"
 	bucketPropsArgument    = bucketArgument + " " + jsonSpecArgument + "|" + keyValuePairsArgument
 	bucketAndPropsArgument = "BUCKET_NAME [PROP_PREFIX]"
 
@@ -193,6 +213,11 @@ const (
 	// Xactions
 	xactionArgument = "XACTION_NAME"
 
+	// Schedule
+	scheduleAddArgument         = "JOB_NAME SCHEDULE ACTION [BUCKET_NAME [BUCKET_TO]]"
+	scheduleJobArgument         = "JOB_NAME"
+	optionalScheduleJobArgument = "[JOB_NAME]"
+
 	// List command
 	listCommandArgument = "[PROVIDER://][BUCKET_NAME]"
 
@@ -278,10 +303,19 @@ var (
 		Name:  "limit-bytes-per-hour,limit-bph,bph",
 		Usage: "number of bytes (can end with suffix (k, MB, GiB, ...)) that all targets can maximally download in hour",
 	}
+	priorityFlag = cli.IntFlag{
+		Name: "priority",
+		Usage: fmt.Sprintf("priority of the job (%d-%d) - its weight in the cluster-wide download bandwidth (default: %d)",
+			downloader.DlPriorityMin, downloader.DlPriorityMax, downloader.DlPriorityDefault),
+	}
 	objectsListFlag = cli.StringFlag{
 		Name:  "object-list,from",
 		Usage: "path to file containing JSON array of strings with object names to download",
 	}
+	manifestFlag = cli.StringFlag{
+		Name:  "manifest",
+		Usage: "manifest object (CSV or JSONL with a link, object name, size, and checksum per line) to download from, e.g. ais://manifests/images.csv",
+	}
 	syncFlag             = cli.BoolFlag{Name: "sync", Usage: "sync bucket with cloud"}
 	progressIntervalFlag = cli.StringFlag{Name: "progress-interval", Value: downloader.DownloadProgressInterval.String(), Usage: "interval(in secs) at which progress will be monitored, e.g. '10s'"}
 
@@ -311,6 +345,7 @@ var (
 	lengthFlag    = cli.StringFlag{Name: "length", Usage: "object read length, can contain prefix 'b', 'KiB', 'MB'"}
 	isCachedFlag  = cli.BoolFlag{Name: "is-cached", Usage: "check if an object is cached"}
 	cachedFlag    = cli.BoolFlag{Name: "cached", Usage: "list only cached objects"}
+	versionsFlag  = cli.BoolFlag{Name: "versions", Usage: "list prior versions of objects (ais buckets only)"}
 	checksumFlag  = cli.BoolFlag{Name: "checksum", Usage: "validate checksum"}
 	recursiveFlag = cli.BoolFlag{Name: "recursive,r", Usage: "recursive operation"}
 	overwriteFlag = cli.BoolTFlag{Name: "overwrite,o", Usage: "overwrite destination if exists"}
@@ -328,6 +363,7 @@ var (
 	// AuthN
 	tokenFileFlag = cli.StringFlag{Name: "file,f", Value: "", Usage: "save token to file"}
 	passwordFlag  = cli.StringFlag{Name: "password,p", Value: "", Usage: "user password"}
+	idTokenFlag   = cli.StringFlag{Name: "id-token", Value: "", Usage: "log in with ID token issued by OpenID Connect provider"}
 
 	// Copy Bucket
 	cpBckDryRunFlag = cli.BoolFlag{
@@ -336,6 +372,13 @@ var (
 	}
 	cpBckPrefixFlag = cli.StringFlag{Name: "prefix", Usage: "prefix added to every new object's name"}
 
+	// Sync Bucket
+	syncDeleteFlag = cli.BoolFlag{Name: "delete", Usage: "delete destination objects that do not exist in the source"}
+
+	// Schedule
+	schedDataSlicesFlag   = cli.IntFlag{Name: "data-slices,data,d", Usage: "number of data slices (ec-encode)"}
+	schedParitySlicesFlag = cli.IntFlag{Name: "parity-slices,parity,p", Usage: "number of parity slices (ec-encode)"}
+
 	// ETL
 	etlExtFlag = cli.StringFlag{Name: "ext", Usage: "mapping from old to new extensions of transformed objects' names"}
 
@@ -31,7 +31,9 @@ var (
 			timeoutFlag,
 			descriptionFlag,
 			limitConnectionsFlag,
+			priorityFlag,
 			objectsListFlag,
+			manifestFlag,
 			progressIntervalFlag,
 		},
 		subcmdStartDsort: {
@@ -118,6 +120,32 @@ var (
 				},
 			},
 		},
+		{
+			Name:  commandPause,
+			Usage: "pause jobs running in the cluster",
+			Subcommands: []cli.Command{
+				{
+					Name:         subcmdPauseDownload,
+					Usage:        "pause a download job with given ID",
+					ArgsUsage:    jobIDArgument,
+					Action:       pauseDownloadHandler,
+					BashComplete: downloadIDRunningCompletions,
+				},
+			},
+		},
+		{
+			Name:  commandResume,
+			Usage: "resume paused jobs",
+			Subcommands: []cli.Command{
+				{
+					Name:         subcmdResumeDownload,
+					Usage:        "resume a paused download job with given ID",
+					ArgsUsage:    jobIDArgument,
+					Action:       resumeDownloadHandler,
+					BashComplete: downloadIDRunningCompletions,
+				},
+			},
+		},
 	}
 )
 
@@ -214,31 +242,44 @@ func startDownloadHandler(c *cli.Context) error {
 		description      = parseStrFlag(c, descriptionFlag)
 		timeout          = parseStrFlag(c, timeoutFlag)
 		objectsListPath  = parseStrFlag(c, objectsListFlag)
+		manifest         = parseStrFlag(c, manifestFlag)
 		progressInterval = parseStrFlag(c, progressIntervalFlag)
+		source           dlSource
+		dst              string
 		id               string
+		err              error
 	)
 
-	if c.NArg() == 0 {
-		return missingArgumentsError(c, "source", "destination")
-	}
-	if c.NArg() == 1 {
-		return missingArgumentsError(c, "destination")
-	}
-	if c.NArg() > 2 {
-		const q = "For range download, enclose source in quotation marks, e.g.: \"gs://imagenet/train-{00..99}.tgz\""
-		s := fmt.Sprintf("too many arguments - expected 2, got %d.\n%s", len(c.Args()), q)
-		return &usageError{
-			context:      c,
-			message:      s,
-			helpData:     c.Command,
-			helpTemplate: cli.CommandHelpTemplate,
+	if manifest != "" {
+		// The manifest is the source.
+		if c.NArg() == 0 {
+			return missingArgumentsError(c, "destination")
 		}
-	}
-
-	src, dst := c.Args().Get(0), c.Args().Get(1)
-	source, err := parseSource(src)
-	if err != nil {
-		return err
+		if c.NArg() > 1 {
+			return incorrectUsageMsg(c, "source %q cannot be used together with flag %q", c.Args().First(), manifestFlag.Name)
+		}
+		dst = c.Args().First()
+	} else {
+		if c.NArg() == 0 {
+			return missingArgumentsError(c, "source", "destination")
+		}
+		if c.NArg() == 1 {
+			return missingArgumentsError(c, "destination")
+		}
+		if c.NArg() > 2 {
+			const q = "For range download, enclose source in quotation marks, e.g.: \"gs://imagenet/train-{00..99}.tgz\""
+			s := fmt.Sprintf("too many arguments - expected 2, got %d.\n%s", len(c.Args()), q)
+			return &usageError{
+				context:      c,
+				message:      s,
+				helpData:     c.Command,
+				helpTemplate: cli.CommandHelpTemplate,
+			}
+		}
+		if source, err = parseSource(c.Args().Get(0)); err != nil {
+			return err
+		}
+		dst = c.Args().Get(1)
 	}
 	bucket, pathSuffix, err := parseDest(dst)
 	if err != nil {
@@ -267,11 +308,14 @@ func startDownloadHandler(c *cli.Context) error {
 			Connections:  parseIntFlag(c, limitConnectionsFlag),
 			BytesPerHour: int(limitBPH),
 		},
+		Priority: parseIntFlag(c, priorityFlag),
 	}
 
 	// Heuristics to determine the download type.
 	var dlType downloader.DlType
-	if objectsListPath != "" {
+	if manifest != "" {
+		dlType = downloader.DlTypeManifest
+	} else if objectsListPath != "" {
 		dlType = downloader.DlTypeMulti
 	} else if strings.Contains(source.link, "{") && strings.Contains(source.link, "}") {
 		dlType = downloader.DlTypeRange
@@ -358,6 +402,23 @@ func startDownloadHandler(c *cli.Context) error {
 			Prefix: source.cloud.prefix,
 		}
 		id, err = api.DownloadWithParam(defaultAPIParams, dlType, payload)
+	case downloader.DlTypeManifest:
+		var (
+			manifestBck cmn.Bck
+			objName     string
+		)
+		if manifestBck, objName, err = parseBckObjectURI(c, manifest); err != nil {
+			return err
+		}
+		if objName == "" {
+			return incorrectUsageMsg(c, "flag %q: missing manifest object name in %q", manifestFlag.Name, manifest)
+		}
+		payload := downloader.DlManifestBody{
+			DlBase:      basePayload,
+			ManifestBck: manifestBck,
+			Manifest:    objName,
+		}
+		id, err = api.DownloadWithParam(defaultAPIParams, dlType, payload)
 	default:
 		cmn.Assert(false)
 	}
@@ -386,6 +447,36 @@ func stopDownloadHandler(c *cli.Context) (err error) {
 	return
 }
 
+func pauseDownloadHandler(c *cli.Context) (err error) {
+	id := c.Args().First()
+
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "download job ID")
+	}
+
+	if err = api.PauseDownload(defaultAPIParams, id); err != nil {
+		return
+	}
+
+	fmt.Fprintf(c.App.Writer, "download job %q paused\n", id)
+	return
+}
+
+func resumeDownloadHandler(c *cli.Context) (err error) {
+	id := c.Args().First()
+
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "download job ID")
+	}
+
+	if err = api.ResumeDownload(defaultAPIParams, id); err != nil {
+		return
+	}
+
+	fmt.Fprintf(c.App.Writer, "download job %q resumed\n", id)
+	return
+}
+
 func startDsortHandler(c *cli.Context) (err error) {
 	var (
 		id       string
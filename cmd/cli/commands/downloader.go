@@ -380,6 +380,12 @@ func printDownloadStatus(w io.Writer, d downloader.DlStatusResp, verbose bool) {
 		}
 		fmt.Fprintln(w, progressMsg)
 	}
+	if d.ManifestLines > 0 {
+		fmt.Fprintf(w, "Manifest lines processed: %d\n", d.ManifestLines)
+	}
+	if d.Paused {
+		fmt.Fprintln(w, "Download paused (run `ais resume download` to resume)")
+	}
 	if verbose {
 		if len(d.CurrentTasks) > 0 {
 			sort.Slice(d.CurrentTasks, func(i, j int) bool {
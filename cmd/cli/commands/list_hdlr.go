@@ -28,6 +28,7 @@ var (
 		startAfterFlag,
 		cachedFlag,
 		useCacheFlag,
+		versionsFlag,
 	}
 
 	listCmds = []cli.Command{
@@ -0,0 +1,186 @@
+// Package commands provides the set of CLI commands used to communicate with the AIS cluster.
+// This file handles commands that manage recurring cluster-level jobs.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cmd/cli/templates"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/urfave/cli"
+)
+
+var scheduleCmds = []cli.Command{
+	{
+		Name:  commandSchedule,
+		Usage: "manage recurring jobs that the cluster runs on schedule",
+		Subcommands: []cli.Command{
+			{
+				Name: subcmdScheduleAdd,
+				Usage: "add (or replace) a job running the ACTION (lru, prefetch, copybck, syncbck, ecencode, " +
+					"or any startable xaction) on cron SCHEDULE, e.g. \"0 3 * * *\" or \"@every 6h\"",
+				ArgsUsage: scheduleAddArgument,
+				Flags: []cli.Flag{
+					listFlag,
+					templateFlag,
+					cpBckPrefixFlag,
+					cpBckDryRunFlag,
+					syncDeleteFlag,
+					schedDataSlicesFlag,
+					schedParitySlicesFlag,
+				},
+				Action: addScheduleHandler,
+			},
+			{
+				Name:      subcmdScheduleRemove,
+				Usage:     "remove a job",
+				ArgsUsage: scheduleJobArgument,
+				Action:    removeScheduleHandler,
+			},
+			{
+				Name:      subcmdScheduleShow,
+				Usage:     "show all jobs or the specified one",
+				ArgsUsage: optionalScheduleJobArgument,
+				Flags:     []cli.Flag{jsonFlag},
+				Action:    showScheduleHandler,
+			},
+			{
+				Name:      subcmdScheduleHistory,
+				Usage:     "show the history of recent runs of all jobs or the specified one",
+				ArgsUsage: optionalScheduleJobArgument,
+				Flags:     []cli.Flag{jsonFlag},
+				Action:    scheduleHistoryHandler,
+			},
+		},
+	},
+}
+
+func addScheduleHandler(c *cli.Context) (err error) {
+	if c.NArg() < 3 {
+		return missingArgumentsError(c, "job name", "schedule", "action")
+	}
+	job := &cmn.ScheduleJob{
+		Name:     c.Args().Get(0),
+		Schedule: c.Args().Get(1),
+		Action:   c.Args().Get(2),
+	}
+	if _, err := cmn.ParseCron(job.Schedule); err != nil {
+		return err
+	}
+	if c.NArg() > 3 {
+		if job.Bck, err = parseBckURI(c, c.Args().Get(3)); err != nil {
+			return err
+		}
+	}
+	switch job.Action {
+	case cmn.ActCopyBucket:
+		if c.NArg() < 5 {
+			return missingArgumentsError(c, "bucket name", "destination bucket name")
+		}
+		toBck, err := parseBckURI(c, c.Args().Get(4))
+		if err != nil {
+			return err
+		}
+		job.Bck.Provider, toBck.Provider = cmn.ProviderAIS, cmn.ProviderAIS
+		job.Value = &cmn.CopyBckMsg{
+			BckTo:  toBck,
+			Prefix: parseStrFlag(c, cpBckPrefixFlag),
+			DryRun: flagIsSet(c, cpBckDryRunFlag),
+		}
+	case cmn.ActSyncBucket:
+		if c.NArg() < 5 {
+			return missingArgumentsError(c, "bucket name", "destination bucket name")
+		}
+		toBck, err := parseBckURI(c, c.Args().Get(4))
+		if err != nil {
+			return err
+		}
+		if job.Bck.Provider == "" {
+			job.Bck.Provider = cmn.ProviderAIS
+		}
+		if toBck.Provider == "" {
+			toBck.Provider = cmn.ProviderAIS
+		}
+		job.Value = &cmn.SyncBckMsg{
+			CopyBckMsg: cmn.CopyBckMsg{
+				BckTo:  toBck,
+				Prefix: parseStrFlag(c, cpBckPrefixFlag),
+				DryRun: flagIsSet(c, cpBckDryRunFlag),
+			},
+			Delete: flagIsSet(c, syncDeleteFlag),
+		}
+	case cmn.ActPrefetch:
+		switch {
+		case flagIsSet(c, listFlag):
+			job.Value = cmn.ListMsg{ObjNames: makeList(parseStrFlag(c, listFlag), ",")}
+		case flagIsSet(c, templateFlag):
+			job.Value = cmn.RangeMsg{Template: parseStrFlag(c, templateFlag)}
+		default:
+			return missingArgumentsError(c, fmt.Sprintf("object list or range (flag --%s or --%s)",
+				listFlag.Name, templateFlag.Name))
+		}
+	case cmn.ActECEncode:
+		if !flagIsSet(c, schedDataSlicesFlag) || !flagIsSet(c, schedParitySlicesFlag) {
+			return missingArgumentsError(c, "number of data and parity slices")
+		}
+		data, parity := parseIntFlag(c, schedDataSlicesFlag), parseIntFlag(c, schedParitySlicesFlag)
+		// the same value as in api.ECEncodeBucket
+		job.Value = string(cmn.MustMarshal(&cmn.ECConfToUpdate{DataSlices: &data, ParitySlices: &parity}))
+	}
+	if err = api.AddSchedule(defaultAPIParams, job); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.Writer, "Job %q scheduled (%s)\n", job.Name, job.Schedule)
+	return
+}
+
+func removeScheduleHandler(c *cli.Context) (err error) {
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "job name")
+	}
+	name := c.Args().First()
+	if err = api.RemoveSchedule(defaultAPIParams, name); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.Writer, "Job %q removed\n", name)
+	return
+}
+
+func showScheduleHandler(c *cli.Context) (err error) {
+	name := c.Args().First()
+	sched, err := api.GetSchedule(defaultAPIParams)
+	if err != nil {
+		return err
+	}
+	jobs := make([]*cmn.ScheduleJob, 0, len(sched.Jobs))
+	for _, job := range sched.Jobs {
+		if name == "" || name == job.Name {
+			jobs = append(jobs, job)
+		}
+	}
+	if name != "" && len(jobs) == 0 {
+		return fmt.Errorf("job %q does not exist", name)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return templates.DisplayOutput(jobs, c.App.Writer, templates.ScheduleJobsTmpl, flagIsSet(c, jsonFlag))
+}
+
+func scheduleHistoryHandler(c *cli.Context) (err error) {
+	name := c.Args().First()
+	sched, err := api.GetSchedule(defaultAPIParams)
+	if err != nil {
+		return err
+	}
+	runs := make([]cmn.ScheduleRun, 0, len(sched.History))
+	for i := len(sched.History) - 1; i >= 0; i-- { // most recent first
+		if run := sched.History[i]; name == "" || name == run.Job {
+			runs = append(runs, run)
+		}
+	}
+	return templates.DisplayOutput(runs, c.App.Writer, templates.ScheduleHistoryTmpl, flagIsSet(c, jsonFlag))
+}
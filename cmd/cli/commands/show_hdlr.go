@@ -16,6 +16,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmd/cli/templates"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/scrub"
 	"github.com/NVIDIA/aistore/xaction"
 	"github.com/urfave/cli"
 )
@@ -36,6 +37,11 @@ type (
 		Avail    []string
 		Disabled []string
 	}
+
+	targetScrubReport struct {
+		DaemonID string
+		Report   *scrub.Report
+	}
 )
 
 var (
@@ -98,6 +104,9 @@ var (
 		subcmdShowMpath: {
 			jsonFlag,
 		},
+		subcmdShowScrub: {
+			jsonFlag,
+		},
 	}
 
 	showCmds = []cli.Command{
@@ -223,6 +232,14 @@ var (
 					Action:       showMpathHandler,
 					BashComplete: daemonCompletions(completeTargets),
 				},
+				{
+					Name:         subcmdShowScrub,
+					Usage:        "show the report of the last checksum scrub on each target",
+					ArgsUsage:    optionalTargetIDArgument,
+					Flags:        showCmdsFlags[subcmdShowScrub],
+					Action:       showScrubHandler,
+					BashComplete: daemonCompletions(completeTargets),
+				},
 			},
 		},
 	}
@@ -519,3 +536,27 @@ func showMpathHandler(c *cli.Context) (err error) {
 	useJSON := flagIsSet(c, jsonFlag)
 	return templates.DisplayOutput(mpls, c.App.Writer, templates.TargetMpathListTmpl, useJSON)
 }
+
+func showScrubHandler(c *cli.Context) (err error) {
+	daemonID := c.Args().First()
+	reports, err := api.GetScrubReport(defaultAPIParams)
+	if err != nil {
+		return err
+	}
+	if daemonID != "" {
+		if _, ok := reports[daemonID]; !ok {
+			return fmt.Errorf("target ID %q invalid - no such target", daemonID)
+		}
+	}
+	out := make([]*targetScrubReport, 0, len(reports))
+	for tid, report := range reports {
+		if daemonID == "" || daemonID == tid {
+			out = append(out, &targetScrubReport{DaemonID: tid, Report: report})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].DaemonID < out[j].DaemonID // ascending by node id/name
+	})
+	useJSON := flagIsSet(c, jsonFlag)
+	return templates.DisplayOutput(out, c.App.Writer, templates.ScrubReportTmpl, useJSON)
+}
@@ -0,0 +1,122 @@
+// Package commands provides the set of CLI commands used to communicate with the AIS cluster.
+// This file handles commands that synchronize buckets.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/mirror"
+	"github.com/urfave/cli"
+)
+
+var (
+	syncCmdsFlags = map[string][]cli.Flag{
+		subcmdSyncBucket: {
+			syncDeleteFlag,
+			cpBckDryRunFlag,
+			cpBckPrefixFlag,
+		},
+	}
+
+	syncCmds = []cli.Command{
+		{
+			Name:  commandSync,
+			Usage: "synchronize buckets",
+			Subcommands: []cli.Command{
+				{
+					Name: subcmdSyncBucket,
+					Usage: "copy new and changed objects from one bucket to another (ais or cloud) " +
+						"and, optionally, delete extraneous objects from the destination",
+					ArgsUsage:    bucketSyncArgument,
+					Flags:        syncCmdsFlags[subcmdSyncBucket],
+					Action:       syncBucketHandler,
+					BashComplete: oldAndNewBucketCompletions([]cli.BashCompleteFunc{}, false /* separator */),
+				},
+			},
+		},
+	}
+)
+
+func syncBucketHandler(c *cli.Context) (err error) {
+	if c.NArg() < 2 {
+		return missingArgumentsError(c, "source bucket name", "destination bucket name")
+	}
+	fromBck, err := parseBckURI(c, c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	toBck, err := parseBckURI(c, c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	if fromBck.Provider == "" {
+		fromBck.Provider = cmn.ProviderAIS
+	}
+	if toBck.Provider == "" {
+		toBck.Provider = cmn.ProviderAIS
+	}
+	if fromBck.Equal(toBck) {
+		return fmt.Errorf("cannot sync bucket %q onto itself", fromBck)
+	}
+
+	msg := &cmn.SyncBckMsg{
+		CopyBckMsg: cmn.CopyBckMsg{
+			Prefix: parseStrFlag(c, cpBckPrefixFlag),
+			DryRun: flagIsSet(c, cpBckDryRunFlag),
+		},
+		Delete: flagIsSet(c, syncDeleteFlag),
+	}
+	xactID, err := api.SyncBucket(defaultAPIParams, fromBck, toBck, msg)
+	if err != nil {
+		return err
+	}
+	if !msg.DryRun {
+		msgFmt := "Syncing bucket %q to %q in progress.\nTo check the status, run: ais show xaction %s\n"
+		fmt.Fprintf(c.App.Writer, msgFmt, fromBck, toBck, xactID)
+		return nil
+	}
+
+	if _, err := api.WaitForXaction(defaultAPIParams, api.XactReqArgs{ID: xactID}); err != nil {
+		return err
+	}
+	stats, err := api.GetXactionStatsByID(defaultAPIParams, xactID)
+	if err != nil {
+		return err
+	}
+	var (
+		skipped, deleted int64
+		toCopy, toDelete []string
+	)
+	for _, stat := range stats {
+		ext := &mirror.ExtSyncBckStats{}
+		if err := cmn.MorphMarshal(stat.Ext, ext); err != nil {
+			return err
+		}
+		skipped += ext.SkippedCount
+		deleted += ext.DeletedCount
+		toCopy = append(toCopy, ext.ToCopy...)
+		toDelete = append(toDelete, ext.ToDelete...)
+	}
+	sort.Strings(toCopy)
+	sort.Strings(toDelete)
+
+	fmt.Fprintln(c.App.Writer, dryRunHeader+" "+dryRunExplanation)
+	fmt.Fprintf(c.App.Writer, "%d objects (%s) would have been copied into bucket %s, %d objects are up to date\n",
+		stats.ObjCount(), cmn.B2S(stats.BytesCount(), 2), toBck, skipped)
+	for _, name := range toCopy {
+		fmt.Fprintf(c.App.Writer, "COPY: %s/%s\n", fromBck, name)
+	}
+	if msg.Delete {
+		fmt.Fprintf(c.App.Writer, "%d objects would have been deleted from bucket %s\n", deleted, toBck)
+		for _, name := range toDelete {
+			fmt.Fprintf(c.App.Writer, "DELETE: %s/%s\n", toBck, name)
+		}
+	}
+	return nil
+}
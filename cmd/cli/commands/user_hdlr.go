@@ -32,7 +32,7 @@ const (
 
 var (
 	authFlags = map[string][]cli.Flag{
-		flagsAuthUserLogin: {tokenFileFlag, passwordFlag},
+		flagsAuthUserLogin: {tokenFileFlag, passwordFlag, idTokenFlag},
 		subcmdAuthUser:     {passwordFlag},
 		flagsAuthRoleAdd:   {descriptionFlag},
 	}
@@ -233,9 +233,14 @@ func loginUserHandler(c *cli.Context) (err error) {
 	if authnHTTPClient == nil {
 		return fmt.Errorf("AuthN URL is not set") // nolint:golint // name of the service
 	}
-	name := cliAuthnUserName(c)
-	password := cliAuthnUserPassword(c)
-	token, err := api.LoginUser(authParams, name, password)
+	var token *api.AuthCreds
+	if idToken := parseStrFlag(c, idTokenFlag); idToken != "" {
+		token, err = api.LoginUserOIDC(authParams, idToken)
+	} else {
+		name := cliAuthnUserName(c)
+		password := cliAuthnUserPassword(c)
+		token, err = api.LoginUser(authParams, name, password)
+	}
 	if err != nil {
 		return err
 	}
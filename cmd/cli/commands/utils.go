@@ -338,7 +338,7 @@ func parseSource(rawURL string) (source dlSource, err error) {
 		fullPath = path.Join(cmn.Version, cmn.Objects, fullPath)
 	case "":
 		scheme = cmn.DefaultScheme
-	case "https", "http":
+	case "https", "http", cmn.SFTPScheme, cmn.FileScheme:
 		break
 	default:
 		err = fmt.Errorf("invalid scheme: %s", scheme)
@@ -931,6 +931,10 @@ func bckPropList(props *cmn.BucketProps, verbose bool) (propList []prop, err err
 			{"ec", props.EC.String()},
 			{"lru", props.LRU.String()},
 			{"versioning", props.Versioning.String()},
+			{"lifecycle", props.Lifecycle.String()},
+			{"quota", props.Quota.String()},
+			{"sse", props.SSE.String()},
+			{"replicate", props.Replicate.String()},
 		}
 		if props.Extra.OrigURLBck != "" {
 			propList = append(propList, prop{Name: "original-url", Value: props.Extra.OrigURLBck})
@@ -964,6 +968,7 @@ func bckSummaryList(summary cmn.BucketSummary, approx bool) (propList []prop) {
 		{Name: prefix + "objects", Value: strconv.FormatUint(summary.ObjCount, 10)},
 		{Name: prefix + "size", Value: cmn.UnsignedB2S(summary.Size, 2)},
 		{Name: prefix + "usage%", Value: fmt.Sprintf("%.2f", summary.UsedPct)},
+		{Name: prefix + "quota usage", Value: summary.QuotaUsage()},
 	}
 	return
 }
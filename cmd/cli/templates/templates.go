@@ -134,6 +134,8 @@ const (
 		" Out-of-Space:\t{{$obj.OOS}}\n" +
 		" Don't Evict Time:\t{{$obj.DontEvictTimeStr}}\n" +
 		" Capacity Update Time:\t{{$obj.CapacityUpdTimeStr}}\n" +
+		" Policy:\t{{$obj.Policy}}\n" +
+		" Pinned Prefixes:\t{{$obj.PinnedPrefixes}}\n" +
 		" Enabled:\t{{$obj.Enabled}}\n"
 	DiskConfTmpl = "\n{{$obj := .Disk}}Disk Config\n" +
 		" Disk Utilization Low WM:\t{{$obj.DiskUtilLowWM}}\n" +
@@ -154,7 +156,9 @@ const (
 		" Enable For Read Range:\t{{$obj.EnableReadRange}}\n"
 	VerConfTmpl = "\n{{$obj := .Versioning}}Version Config\n" +
 		" Enabled:\t{{$obj.Enabled}}\n" +
-		" Validate Warm Get:\t{{$obj.ValidateWarmGet}}\n"
+		" Validate Warm Get:\t{{$obj.ValidateWarmGet}}\n" +
+		" Max History:\t{{$obj.MaxHistory}}\n" +
+		" History Time:\t{{$obj.HistoryTimeStr}}\n"
 	FSpathsConfTmpl = "\nFile System Paths Config\n" +
 		"{{$obj := .FSpaths.Paths}}" +
 		"{{range $key, $val := $obj}}" +
@@ -193,7 +197,12 @@ const (
 		" Name: \t{{$obj.Proxy.Name}}\t \t{{$obj.Target.Name}}\n" +
 		" Factor: \t{{$obj.Proxy.Factor}}\t \t{{$obj.Target.Factor}}\n"
 	DownloaderConfTmpl = "\n{{$obj := .Downloader}}Downloader Config\n" +
-		" Timeout: {{$obj.TimeoutStr}}\n"
+		" Timeout: {{$obj.TimeoutStr}}\n" +
+		" Bandwidth: {{$obj.BandwidthStr}}\n" +
+		" File Roots: {{$obj.FileRoots}}\n" +
+		" SFTP User: {{$obj.SFTP.User}}\n" +
+		" SFTP Key File: {{$obj.SFTP.KeyFile}}\n" +
+		" SFTP Known Hosts: {{$obj.SFTP.KnownHostsFile}}\n"
 	DSortConfTmpl = "\n{{$obj := .DSort}}Distributed Sort Config\n" +
 		" Duplicated Records:\t{{$obj.DuplicatedRecords}}\n" +
 		" Missing Shards:\t{{$obj.MissingShards}}\n" +
@@ -204,11 +213,22 @@ const (
 	CompressionTmpl = "\n{{$obj := .Compression}}Compression\n" +
 		" BlockSize:\t{{$obj.BlockMaxSize}}\n" +
 		" Checksum:\t{{$obj.Checksum}}\n"
+	StatsConfTmpl = "\n{{$obj := .Stats}}Stats\n" +
+		" Exporter:\t{{$obj.Exporter}}\n"
+	KeyProviderConfTmpl = "\n{{$obj := .KeyProvider}}Key Provider\n" +
+		" Type:\t{{$obj.Type}}\n" +
+		" Key File:\t{{$obj.KeyFile}}\n" +
+		" URL:\t{{$obj.URL}}\n"
+	ScrubConfTmpl = "\n{{$obj := .Scrub}}Scrub\n" +
+		" Interval:\t{{$obj.IntervalStr}}\n" +
+		" Rate:\t{{$obj.RateStr}}\n"
 	ECTmpl = "\n{{$obj := .EC}}EC\n" +
 		" Enabled:\t{{$obj.Enabled}}\n" +
 		" Minimum object size for EC:\t{{$obj.ObjSizeLimit}}\n" +
 		" Number of data slices:\t{{$obj.DataSlices}}\n" +
 		" Number of parity slices:\t{{$obj.ParitySlices}}\n" +
+		" Codec:\t{{$obj.Codec}}\n" +
+		" Number of local groups:\t{{$obj.LocalGroups}}\n" +
 		" Rebalance batch size:\t{{$obj.BatchSize}}\n" +
 		" Compression options:\t{{$obj.Compression}}\n"
 	GlobalConfTmpl = "Config Directory: {{.Confdir}}\nCloud Providers: {{ range $key := .Cloud.Providers}} {{$key}} {{end}}\n"
@@ -226,7 +246,7 @@ const (
 		ReplicationConfTmpl + CksumConfTmpl + VerConfTmpl + FSpathsConfTmpl +
 		TestFSPConfTmpl + NetConfTmpl + FSHCConfTmpl + AuthConfTmpl + KeepaliveConfTmpl +
 		DownloaderConfTmpl + DSortConfTmpl +
-		CompressionTmpl + ECTmpl
+		CompressionTmpl + ECTmpl + StatsConfTmpl + KeyProviderConfTmpl + ScrubConfTmpl
 
 	BucketPropsSimpleTmpl = "PROPERTY\t VALUE\n" +
 		"{{range $p := . }}" +
@@ -236,7 +256,7 @@ const (
 	DownloadListHeader = "JOB ID\t STATUS\t ERRORS\t DESCRIPTION\n"
 	DownloadListBody   = "{{$value.ID}}\t " +
 		"{{if $value.Aborted}}Aborted" +
-		"{{else}}{{if $value.JobFinished}}Finished{{else}}{{$value.PendingCnt}} pending{{end}}" +
+		"{{else}}{{if $value.JobFinished}}Finished{{else}}{{$value.PendingCnt}} pending{{if $value.Paused}} (paused){{end}}{{end}}" +
 		"{{end}}\t {{$value.ErrorCnt}}\t {{$value.Description}}\n"
 	DownloadListTmpl = DownloadListHeader + "{{ range $key, $value := . }}" + DownloadListBody + "{{end}}"
 
@@ -275,9 +295,10 @@ const (
 
 	// Buckets templates
 	BucketsSummariesFastTmpl = "NAME\t EST. OBJECTS\t EST. SIZE\t EST. USED %\n" + bucketsSummariesBody
-	BucketsSummariesTmpl     = "NAME\t OBJECTS\t SIZE \t USED %\n" + bucketsSummariesBody
+	BucketsSummariesTmpl     = "NAME\t OBJECTS\t SIZE \t USED %\t QUOTA USED\n" + bucketsSummariesBody
 	bucketsSummariesBody     = "{{range $k, $v := . }}" +
-		"{{$v.Bck}}\t {{$v.ObjCount}}\t {{FormatBytesUnsigned $v.Size 2}}\t {{FormatFloat $v.UsedPct}}%\n" +
+		"{{$v.Bck}}\t {{$v.ObjCount}}\t {{FormatBytesUnsigned $v.Size 2}}\t {{FormatFloat $v.UsedPct}}%\t " +
+		"{{$v.QuotaUsage}}\n" +
 		"{{end}}"
 
 	// For `object put` mass uploader. A caller adds to the template
@@ -327,6 +348,33 @@ const (
 		"{{end}}"
 
 	// Command `show mountpath`
+	ScrubReportTmpl = "TARGET\t STARTED\t FINISHED\t CHECKED\t SIZE\t CORRUPTED\t REPAIRED\t UNRECOVERABLE\n" +
+		"{{range $r := . }}" +
+		"{{ $r.DaemonID }}\t " +
+		"{{if $r.Report}}" +
+		"{{FormatTime $r.Report.Started}}\t {{FormatTime $r.Report.Finished}}{{if $r.Report.Aborted}} (aborted){{end}}\t " +
+		"{{$r.Report.Checked}}\t {{FormatBytesSigned $r.Report.Bytes 2}}\t " +
+		"{{$r.Report.Corrupted}}\t {{$r.Report.Repaired}}\t {{$r.Report.Unrecoverable}}\n" +
+		"{{else}}" +
+		"-\t -\t -\t -\t -\t -\t -\n" +
+		"{{end}}" +
+		"{{end}}" +
+		"{{range $r := . }}{{if $r.Report}}{{range $o := $r.Report.Objects}}" +
+		"\n{{ $r.DaemonID }}: {{$o.Bck}}/{{$o.ObjName}} ({{$o.FQN}}): {{$o.Err}}" +
+		"{{end}}{{end}}{{end}}\n"
+
+	// Scheduler
+	ScheduleJobsTmpl = "NAME\t SCHEDULE\t ACTION\t BUCKET\t LAST RUN\n" +
+		"{{range $j := . }}" +
+		"{{$j.Name}}\t {{$j.Schedule}}\t {{$j.Action}}\t {{if $j.Bck.Name}}{{$j.Bck}}{{else}}-{{end}}\t " +
+		"{{if (IsUnsetTime $j.LastRun)}}-{{else}}{{FormatTime $j.LastRun}}{{end}}\n" +
+		"{{end}}"
+	ScheduleHistoryTmpl = "TIME\t JOB\t ACTION\t XACTION\t ERROR\n" +
+		"{{range $r := . }}" +
+		"{{FormatTime $r.Time}}\t {{$r.Job}}\t {{$r.Action}}\t " +
+		"{{if $r.XactID}}{{$r.XactID}}{{else}}-{{end}}\t {{if $r.Err}}{{$r.Err}}{{else}}-{{end}}\n" +
+		"{{end}}"
+
 	TargetMpathListTmpl = "{{range $p := . }}" +
 		"{{ $p.DaemonID }}\n" +
 		"{{if and (eq (len $p.Avail) 0) (eq (len $p.Disabled) 0)}}" +
@@ -489,6 +537,9 @@ func fmtObjStatus(obj *cmn.BucketEntry) string {
 	if obj.IsStatusOK() {
 		return "ok"
 	}
+	if obj.IsStatusDeleted() {
+		return "deleted"
+	}
 	return "moved"
 }
 
@@ -516,6 +567,9 @@ var ConfigSectionTmpl = map[string]string{
 	"compression":          CompressionTmpl,
 	"ec":                   ECTmpl,
 	"replication":          ReplicationConfTmpl,
+	"stats":                StatsConfTmpl,
+	"key_provider":         KeyProviderConfTmpl,
+	"scrub":                ScrubConfTmpl,
 }
 
 func fmtObjIsCached(obj *cmn.BucketEntry) string {
@@ -8,7 +8,9 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/NVIDIA/aistore/cmn/debug"
 )
@@ -18,6 +20,7 @@ const (
 	SelectCached    = 1 << iota // list only cached (Cloud buckets only)
 	SelectMisplaced             // Include misplaced
 	SelectDeleted               // Include marked for deletion
+	SelectVersions              // Include prior versions of objects (ais buckets only)
 )
 
 // ActionMsg is a JSON-formatted control structures for the REST API
@@ -57,10 +60,11 @@ type (
 
 	BucketSummary struct {
 		Bck
-		ObjCount       uint64  `json:"count,string"`
-		Size           uint64  `json:"size,string"`
-		TotalDisksSize uint64  `json:"disks_size,string"`
-		UsedPct        float64 `json:"used_pct"`
+		ObjCount       uint64    `json:"count,string"`
+		Size           uint64    `json:"size,string"`
+		TotalDisksSize uint64    `json:"disks_size,string"`
+		UsedPct        float64   `json:"used_pct"`
+		Quota          QuotaConf `json:"quota"`
 	}
 	// BucketSummaryMsg represents options that can be set when asking for bucket summary.
 	BucketSummaryMsg struct {
@@ -94,6 +98,14 @@ type (
 		DryRun bool   `json:"dry_run"` // Don't perform any PUT
 	}
 
+	// SyncBckMsg makes the destination bucket identical to the source: copies new and
+	// changed objects (by size, version, and checksum) and, if requested, deletes
+	// destination objects that no longer exist in the source.
+	SyncBckMsg struct {
+		CopyBckMsg
+		Delete bool `json:"delete"` // Delete extraneous objects from the destination
+	}
+
 	Bck2BckMsg struct {
 		BckTo Bck `json:"bck_to"`
 
@@ -108,6 +120,37 @@ type (
 		// The same as CopyBckMsg
 		Prefix string `json:"prefix"`
 		DryRun bool   `json:"dry_run"`
+
+		Delete bool `json:"delete,omitempty"` // optional, sync only (see SyncBckMsg)
+	}
+)
+
+// cluster-level scheduler (see `ais schedule`)
+type (
+	// ScheduleJob is a recurring job: the primary proxy performs the job's action
+	// every time its schedule fires, exactly as if it was requested via the API.
+	ScheduleJob struct {
+		Name     string      `json:"name"`
+		Schedule string      `json:"schedule"`        // cron expression, see `ParseCron`
+		Action   string      `json:"action"`          // ActCopyBucket, ActSyncBucket, ActPrefetch, ActECEncode, or a startable xaction kind
+		Bck      Bck         `json:"bck"`             // bucket to run the action on (optional for global xactions)
+		Value    interface{} `json:"value,omitempty"` // action-specific: the same value as in the corresponding API call
+		Created  time.Time   `json:"created"`
+		LastRun  time.Time   `json:"last_run"`
+	}
+	ScheduleRun struct {
+		Job    string    `json:"job"`
+		Action string    `json:"action"`
+		Time   time.Time `json:"time"`
+		XactID string    `json:"xaction_id,omitempty"`
+		Err    string    `json:"error,omitempty"`
+	}
+	// Schedule is the cluster-wide replicated (metasync-ed) list of jobs
+	// and the history of their most recent runs.
+	Schedule struct {
+		Version int64                   `json:"version,string"`
+		Jobs    map[string]*ScheduleJob `json:"jobs"`
+		History []ScheduleRun           `json:"history"` // oldest first
 	}
 )
 
@@ -144,6 +187,18 @@ type (
 		// EC defines erasure coding setting for the bucket
 		EC ECConf `json:"ec"`
 
+		// Lifecycle defines rules to expire objects and to cleanup stale appends
+		Lifecycle LifecycleConf `json:"lifecycle"`
+
+		// Quota limits the capacity (bytes and number of objects) of the bucket
+		Quota QuotaConf `json:"quota"`
+
+		// SSE configures server-side encryption of the bucket's objects
+		SSE SSEConf `json:"sse"`
+
+		// Replicate configures asynchronous replication to a remote AIS cluster
+		Replicate ReplicateConf `json:"replicate"`
+
 		// Bucket access attributes - see Allow* above
 		Access AccessAttrs `json:"access,string"`
 
@@ -166,18 +221,90 @@ type (
 		Renamed string `list:"omit"`
 	}
 	BucketPropsToUpdate struct {
-		BackendBck *BckToUpdate         `json:"backend_bck"`
-		Versioning *VersionConfToUpdate `json:"versioning"`
-		Cksum      *CksumConfToUpdate   `json:"checksum"`
-		LRU        *LRUConfToUpdate     `json:"lru"`
-		Mirror     *MirrorConfToUpdate  `json:"mirror"`
-		EC         *ECConfToUpdate      `json:"ec"`
-		Access     *AccessAttrs         `json:"access,string"`
+		BackendBck *BckToUpdate           `json:"backend_bck"`
+		Versioning *VersionConfToUpdate   `json:"versioning"`
+		Cksum      *CksumConfToUpdate     `json:"checksum"`
+		LRU        *LRUConfToUpdate       `json:"lru"`
+		Mirror     *MirrorConfToUpdate    `json:"mirror"`
+		EC         *ECConfToUpdate        `json:"ec"`
+		Lifecycle  *LifecycleConfToUpdate `json:"lifecycle"`
+		Quota      *QuotaConfToUpdate     `json:"quota"`
+		SSE        *SSEConfToUpdate       `json:"sse"`
+		Replicate  *ReplicateConfToUpdate `json:"replicate"`
+		Access     *AccessAttrs           `json:"access,string"`
 	}
 	BckToUpdate struct {
 		Name     *string `json:"name"`
 		Provider *string `json:"provider"`
 	}
+
+	// Lifecycle rules are enforced by the (periodic) lifecycle xaction that
+	// runs on each target (see lifecycle package).
+	LifecycleConf struct {
+		Rules []LifecycleRule `json:"rules"`
+	}
+	LifecycleConfToUpdate struct {
+		Rules *[]LifecycleRule `json:"rules"`
+	}
+	LifecycleRule struct {
+		ID      string `json:"id"`
+		Prefix  string `json:"prefix"` // applies to objects with names starting with the prefix
+		Enabled bool   `json:"enabled"`
+		// remove objects older than (so many) days (0 - never)
+		ExpireDays int `json:"expire_days"`
+		// remove in-progress appends and S3 multipart uploads that were started
+		// (so many) days ago and haven't been completed (0 - never)
+		AbortAppendDays int `json:"abort_append_days"`
+	}
+
+	// Quotas are cluster-wide and are enforced by each target proportionally
+	// to its share of the bucket (see ais/tgtquota.go). Exceeding a soft limit
+	// results in a warning, while hard limits fail PUT, APPEND, download and
+	// copy (into the bucket). Zero means unlimited.
+	QuotaConf struct {
+		SoftSize    int64 `json:"soft_size,string"`
+		HardSize    int64 `json:"hard_size,string"`
+		SoftObjects int64 `json:"soft_objects,string"`
+		HardObjects int64 `json:"hard_objects,string"`
+	}
+	QuotaConfToUpdate struct {
+		SoftSize    *int64 `json:"soft_size,string"`
+		HardSize    *int64 `json:"hard_size,string"`
+		SoftObjects *int64 `json:"soft_objects,string"`
+		HardObjects *int64 `json:"hard_objects,string"`
+	}
+
+	// Server-side encryption at rest: objects are encrypted (AES-GCM) when
+	// written to the mountpaths, with the key identified by KeyID and
+	// provided by the cluster's key provider (see KeyProviderConf and the sse
+	// package). Each object keeps the ID of the key it was encrypted with, so
+	// that changing (rotating) the key or disabling SSE affects only objects
+	// written thereafter.
+	SSEConf struct {
+		Enabled bool   `json:"enabled"`
+		KeyID   string `json:"key_id"`
+	}
+	SSEConfToUpdate struct {
+		Enabled *bool   `json:"enabled"`
+		KeyID   *string `json:"key_id"`
+	}
+
+	// Replication forwards PUTs and deletes of the bucket's objects to a
+	// bucket of a remote AIS cluster attached via `ais attach remote`.
+	// Replication is asynchronous: each target queues (and persists) the
+	// names of updated objects and then catches up with the remote - see
+	// ais/tgtrepl.go. Remote is the alias or UUID of the remote cluster;
+	// the destination bucket (that must exist) defaults to the same name.
+	ReplicateConf struct {
+		Enabled bool   `json:"enabled"`
+		Remote  string `json:"remote"`
+		Bucket  string `json:"bucket"`
+	}
+	ReplicateConfToUpdate struct {
+		Enabled *bool   `json:"enabled"`
+		Remote  *string `json:"remote"`
+		Bucket  *string `json:"bucket"`
+	}
 )
 
 // object properties
@@ -278,6 +405,25 @@ func (bs *BucketSummary) Aggregate(bckSummary BucketSummary) {
 	bs.UsedPct = float64(bs.Size) * 100 / float64(bs.TotalDisksSize)
 }
 
+// QuotaUsage returns the percentage of the (hard) quota used by the bucket.
+func (bs *BucketSummary) QuotaUsage() string {
+	if !bs.Quota.Enabled() {
+		return "-"
+	}
+	var parts []string
+	if bs.Quota.HardSize > 0 {
+		parts = append(parts, fmt.Sprintf("size %.2f%%", float64(bs.Size)*100/float64(bs.Quota.HardSize)))
+	}
+	if bs.Quota.HardObjects > 0 {
+		parts = append(parts,
+			fmt.Sprintf("objects %.2f%%", float64(bs.ObjCount)*100/float64(bs.Quota.HardObjects)))
+	}
+	if len(parts) == 0 {
+		return "soft only"
+	}
+	return strings.Join(parts, ", ")
+}
+
 //////////////////////
 // BucketsSummaries //
 //////////////////////
@@ -322,10 +468,179 @@ func (c *VersionConf) String() string {
 	} else {
 		text += "no"
 	}
+	if c.KeepHistory() {
+		text += " | History: " + strconv.Itoa(c.MaxHistory)
+		if c.HistoryTimeStr != "" {
+			text += ", " + c.HistoryTimeStr
+		}
+	}
 
 	return text
 }
 
+// KeepHistory returns true if prior versions of objects must be retained.
+func (c *VersionConf) KeepHistory() bool {
+	return c.Enabled && (c.MaxHistory > 0 || c.HistoryTime() > 0)
+}
+
+// HistoryTime returns the parsed value of HistoryTimeStr (zero - no time limit).
+// NOTE: the value is validated when the props are set.
+func (c *VersionConf) HistoryTime() time.Duration {
+	if c.HistoryTimeStr == "" {
+		return 0
+	}
+	d, _ := time.ParseDuration(c.HistoryTimeStr)
+	return d
+}
+
+func (c *LifecycleConf) String() string {
+	var enabled int
+	for i := range c.Rules {
+		if c.Rules[i].Enabled {
+			enabled++
+		}
+	}
+	if enabled == 0 {
+		return "Disabled"
+	}
+	return fmt.Sprintf("%d rule(s)", enabled)
+}
+
+func (c *LifecycleConf) ValidateAsProps(_ *ValidationArgs) error {
+	ids := make(StringSet, len(c.Rules))
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.ID == "" {
+			return fmt.Errorf("lifecycle rule #%d: id is empty", i)
+		}
+		if ids.Contains(rule.ID) {
+			return fmt.Errorf("lifecycle rule %q: duplicate id", rule.ID)
+		}
+		ids.Add(rule.ID)
+		if rule.ExpireDays < 0 || rule.AbortAppendDays < 0 {
+			return fmt.Errorf("lifecycle rule %q: the number of days cannot be negative", rule.ID)
+		}
+		if rule.ExpireDays == 0 && rule.AbortAppendDays == 0 {
+			return fmt.Errorf("lifecycle rule %q: no action (expected expire_days and/or abort_append_days)",
+				rule.ID)
+		}
+	}
+	return nil
+}
+
+// Enabled returns true if the bucket has at least one enabled rule.
+func (c *LifecycleConf) Enabled() bool {
+	for i := range c.Rules {
+		if c.Rules[i].Enabled {
+			return true
+		}
+	}
+	return false
+}
+
+func (r LifecycleRule) String() string {
+	return fmt.Sprintf("%s(prefix %q, enabled %t, expire %dd, abort-append %dd)",
+		r.ID, r.Prefix, r.Enabled, r.ExpireDays, r.AbortAppendDays)
+}
+
+// Expired returns true if the object with the given name and modification
+// time must be removed according to the rule.
+func (r *LifecycleRule) Expired(objName string, mtime, now time.Time) bool {
+	return r.applies(objName) && r.ExpireDays > 0 && now.Sub(mtime) > days(r.ExpireDays)
+}
+
+// AppendAborted returns true if the in-progress append (or multipart upload)
+// of the object started at the given time must be removed according to the rule.
+func (r *LifecycleRule) AppendAborted(objName string, started, now time.Time) bool {
+	return r.applies(objName) && r.AbortAppendDays > 0 && now.Sub(started) > days(r.AbortAppendDays)
+}
+
+func (r *LifecycleRule) applies(objName string) bool {
+	return r.Enabled && strings.HasPrefix(objName, r.Prefix)
+}
+
+func days(n int) time.Duration { return time.Duration(n) * 24 * time.Hour }
+
+func (c *QuotaConf) String() string {
+	if !c.Enabled() {
+		return "Disabled"
+	}
+	limit := func(n int64, f func(int64) string) string {
+		if n == 0 {
+			return "-"
+		}
+		return f(n)
+	}
+	count := func(n int64) string { return strconv.FormatInt(n, 10) }
+	size := func(n int64) string { return B2S(n, 2) }
+	return fmt.Sprintf("size(soft %s, hard %s), objects(soft %s, hard %s)",
+		limit(c.SoftSize, size), limit(c.HardSize, size),
+		limit(c.SoftObjects, count), limit(c.HardObjects, count))
+}
+
+func (c *QuotaConf) ValidateAsProps(_ *ValidationArgs) error {
+	if c.SoftSize < 0 || c.HardSize < 0 || c.SoftObjects < 0 || c.HardObjects < 0 {
+		return fmt.Errorf("quota limits cannot be negative (%+v)", *c)
+	}
+	if c.HardSize > 0 && c.SoftSize > c.HardSize {
+		return fmt.Errorf("soft size quota (%d) cannot exceed hard size quota (%d)", c.SoftSize, c.HardSize)
+	}
+	if c.HardObjects > 0 && c.SoftObjects > c.HardObjects {
+		return fmt.Errorf("soft objects quota (%d) cannot exceed hard objects quota (%d)",
+			c.SoftObjects, c.HardObjects)
+	}
+	return nil
+}
+
+// Enabled returns true if at least one of the limits is set.
+func (c *QuotaConf) Enabled() bool { return *c != QuotaConf{} }
+
+func (c *SSEConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return fmt.Sprintf("key %q", c.KeyID)
+}
+
+func (c *SSEConf) ValidateAsProps(_ *ValidationArgs) error {
+	if c.Enabled && c.KeyID == "" {
+		return fmt.Errorf("sse.key_id must be specified when server-side encryption is enabled")
+	}
+	return nil
+}
+
+func (c *ReplicateConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	if c.Bucket == "" {
+		return fmt.Sprintf("remote %q", c.Remote)
+	}
+	return fmt.Sprintf("remote %q, bucket %q", c.Remote, c.Bucket)
+}
+
+func (c *ReplicateConf) ValidateAsProps(_ *ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Remote == "" {
+		return fmt.Errorf("replicate.remote must be specified when replication is enabled")
+	}
+	if c.Bucket != "" {
+		return ValidateBckName(c.Bucket)
+	}
+	return nil
+}
+
+// DstBck returns the remote (destination) bucket of the given bucket.
+func (c *ReplicateConf) DstBck(bck Bck) Bck {
+	name := c.Bucket
+	if name == "" {
+		name = bck.Name
+	}
+	return Bck{Name: name, Provider: ProviderAIS, Ns: Ns{UUID: c.Remote}}
+}
+
 func (c *CksumConf) String() string {
 	if c.Type == ChecksumNone {
 		return "Disabled"
@@ -354,8 +669,15 @@ func (c *LRUConf) String() string {
 	if !c.Enabled {
 		return "Disabled"
 	}
-	return fmt.Sprintf("Watermarks: %d%%/%d%% | Do not evict time: %s | OOS: %v%%",
+	s := fmt.Sprintf("Watermarks: %d%%/%d%% | Do not evict time: %s | OOS: %v%%",
 		c.LowWM, c.HighWM, c.DontEvictTimeStr, c.OOS)
+	if c.Policy != "" && c.Policy != LRUPolicyAtime {
+		s += " | Policy: " + c.Policy
+	}
+	if len(c.PinnedPrefixes) > 0 {
+		s += " | Pinned: " + strings.Join(c.PinnedPrefixes, ",")
+	}
+	return s
 }
 
 func (c *MirrorConf) String() string {
@@ -378,6 +700,10 @@ func (c *ECConf) String() string {
 		return "Disabled"
 	}
 	objSizeLimit := c.ObjSizeLimit
+	if c.Codec == ECCodecLRC {
+		return fmt.Sprintf("%d:%d, %s/%d (%s)", c.DataSlices, c.ParitySlices, c.Codec, c.LocalGroups,
+			B2S(objSizeLimit, 0))
+	}
 	return fmt.Sprintf("%d:%d (%s)", c.DataSlices, c.ParitySlices, B2S(objSizeLimit, 0))
 }
 
@@ -411,6 +737,7 @@ func DefaultAISBckProps() *BucketProps {
 func DefaultCloudBckProps(header http.Header) (props *BucketProps) {
 	props = DefaultAISBckProps()
 	props.Versioning.Enabled = false
+	props.Versioning.MaxHistory, props.Versioning.HistoryTimeStr = 0, ""
 	return MergeCloudBckProps(props, header)
 }
 
@@ -442,6 +769,12 @@ func MergeCloudBckProps(base *BucketProps, header http.Header) (props *BucketPro
 
 func (bp *BucketProps) Clone() *BucketProps {
 	to := *bp
+	if bp.Lifecycle.Rules != nil {
+		to.Lifecycle.Rules = append(make([]LifecycleRule, 0, len(bp.Lifecycle.Rules)), bp.Lifecycle.Rules...)
+	}
+	if bp.LRU.PinnedPrefixes != nil {
+		to.LRU.PinnedPrefixes = append(make([]string, 0, len(bp.LRU.PinnedPrefixes)), bp.LRU.PinnedPrefixes...)
+	}
 	debug.Assert(bp.Equal(&to))
 	return &to
 }
@@ -478,16 +811,30 @@ func (bp *BucketProps) Validate(targetCnt int) error {
 	}
 
 	validationArgs := &ValidationArgs{TargetCnt: targetCnt}
-	validators := []PropsValidator{&bp.Cksum, &bp.LRU, &bp.Mirror, &bp.EC}
+	validators := []PropsValidator{&bp.Cksum, &bp.LRU, &bp.Mirror, &bp.EC, &bp.Lifecycle, &bp.Quota, &bp.SSE,
+		&bp.Replicate}
 	for _, validator := range validators {
 		if err := validator.ValidateAsProps(validationArgs); err != nil {
 			return err
 		}
 	}
 
+	if err := bp.Versioning.ValidateAsProps(); err != nil {
+		return err
+	}
+
 	if bp.Mirror.Enabled && bp.EC.Enabled {
 		return fmt.Errorf("cannot enable mirroring and ec at the same time for the same bucket")
 	}
+	if len(bp.Lifecycle.Rules) > 0 && (bp.Provider != ProviderAIS || !bp.BackendBck.IsEmpty()) {
+		return fmt.Errorf("lifecycle rules are supported only for ais buckets")
+	}
+	if bp.SSE.Enabled && (bp.Provider != ProviderAIS || !bp.BackendBck.IsEmpty()) {
+		return fmt.Errorf("server-side encryption is supported only for ais buckets")
+	}
+	if bp.Replicate.Enabled && (bp.Provider != ProviderAIS || !bp.BackendBck.IsEmpty()) {
+		return fmt.Errorf("replication is supported only for ais buckets")
+	}
 	return nil
 }
 
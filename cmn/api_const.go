@@ -36,11 +36,14 @@ const (
 	ActRebalance      = "rebalance"
 	ActResilver       = "resilver"
 	ActLRU            = "lru"
+	ActLifecycle      = "lifecycle"
+	ActScrub          = "scrub" // re-hash all objects and repair the corrupted ones
 	ActSyncLB         = "synclb"
 	ActCreateLB       = "createlb"
 	ActDestroyLB      = "destroylb"
 	ActRenameLB       = "renamelb"
 	ActCopyBucket     = "copybck"
+	ActSyncBucket     = "syncbck" // copy new and changed objects only; optionally, delete extraneous ones
 	ActETLBucket      = "etlbck"
 	ActRegisterCB     = "registercb"
 	ActEvictCB        = "evictcb"
@@ -64,6 +67,7 @@ const (
 	ActUnregProxy     = "unregproxy"
 	ActNewPrimary     = "newprimary"
 	ActRevokeToken    = "revoketoken"
+	ActUpdateS3Keys   = "updates3keys"
 	ActElection       = "election"
 	ActPutCopies      = "putcopies"
 	ActMakeNCopies    = "makencopies"
@@ -72,10 +76,13 @@ const (
 	ActECPut          = "ecput"    // erasure encode objects
 	ActECRespond      = "ecresp"   // respond to other targets' EC requests
 	ActECEncode       = "ecencode" // erasure code a bucket
+	ActECScrub        = "ecscrub"  // verify and repair EC slices and replicas of a bucket
 	ActStartGFN       = "metasync-start-gfn"
 	ActRecoverBck     = "recoverbck"
 	ActAttach         = "attach"
 	ActDetach         = "detach"
+	ActAddSchedule    = "addschedule" // add (or update) recurring job
+	ActRemoveSchedule = "rmschedule"  // remove recurring job
 	// Node maintenance
 	ActStartMaintenance = "startmaitenance" // put into maintenance state
 	ActStopMaintenance  = "stopmaintenance" // cancel maintenance state
@@ -172,8 +179,9 @@ const (
 	URLParamCheckExists = "check_cached" // true: check if object exists
 	URLParamProvider    = "provider"     // cloud provider
 	URLParamNamespace   = "namespace"
-	URLParamPrefix      = "prefix" // prefix for list objects in a bucket
-	URLParamRegex       = "regex"  // dsort/downloader regex
+	URLParamPrefix      = "prefix"  // prefix for list objects in a bucket
+	URLParamRegex       = "regex"   // dsort/downloader regex
+	URLParamVersion     = "version" // object version (GET and DELETE prior versions)
 	// internal use
 	URLParamCheckExistsAny   = "cea" // true: lookup object in all mountpaths (NOTE: compare with URLParamCheckExists)
 	URLParamProxyID          = "pid" // ID of the redirecting proxy
@@ -190,6 +198,7 @@ const (
 	URLParamTaskAction       = "tac" // "start", "status", "result"
 	URLParamClusterInfo      = "cii" // true: Health to return ais.clusterInfo
 	URLParamRecvType         = "rtp" // to tell real PUT from migration PUT
+	URLParamECValidate       = "ecv" // true: validate EC slice/replica content before returning its metadata
 
 	URLParamAppendType   = "appendty"
 	URLParamAppendHandle = "handle"
@@ -238,6 +247,8 @@ const (
 	GetWhatStatus       = "status"    // JTX status by uuid
 	GetWhatICBundle     = "ic-bundle"
 	GetWhatTargetIPs    = "target_ips"
+	GetWhatScrubReport  = "scrub_report"
+	GetWhatSchedule     = "schedule"
 )
 
 // SelectMsg.TimeFormat enum
@@ -264,7 +275,7 @@ const (
 const (
 	ObjStatusOK = iota
 	ObjStatusMoved
-	ObjStatusDeleted // TODO: reserved for future when we introduce delayed delete of the object/bucket
+	ObjStatusDeleted // deleted object that has prior versions (see SelectVersions)
 )
 
 // BucketEntry Flags constants
@@ -305,6 +316,7 @@ const (
 	Users     = "users"    // AuthN
 	Clusters  = "clusters" // AuthN
 	Roles     = "roles"    // AuthN
+	S3Keys    = "s3keys"   // AuthN
 	Query     = "query"
 	IC        = "ic" // information center
 
@@ -334,6 +346,9 @@ const (
 	Records     = "records"
 	Shards      = "shards"
 	FinishedAck = "finished-ack"
+	Checkpoint  = "checkpoint"
+	Resume      = "resume"
+	Pause       = "pause"
 	List        = "list"
 	Remove      = "remove"
 	Next        = "next"
@@ -63,6 +63,27 @@ type (
 	AuthClusterList struct {
 		Clusters map[string]*AuthCluster `json:"clusters,omitempty"`
 	}
+	// S3 credentials of a user: a pair of random keys issued by AuthN
+	// (the secret key is returned only once - when the pair is issued)
+	AuthS3Keys struct {
+		AccessKey string    `json:"access_key"`
+		SecretKey string    `json:"secret_key,omitempty"`
+		UserID    string    `json:"username"`
+		Created   time.Time `json:"created"`
+	}
+	// S3 key pair along with the permissions of its user, as AuthN pushes it
+	// to AIS clusters
+	AuthS3Key struct {
+		AuthS3Keys
+		Clusters []*AuthCluster `json:"clusters"`
+		Buckets  []*AuthBucket  `json:"buckets,omitempty"`
+		IsAdmin  bool           `json:"admin"`
+	}
+	// all S3 keys issued by AuthN: access key => key
+	AuthS3KeyList struct {
+		Keys    map[string]*AuthS3Key `json:"keys"`
+		Created time.Time             `json:"created"` // when AuthN generated the list
+	}
 )
 
 var (
@@ -116,6 +137,11 @@ func (tk *AuthToken) CheckPermissions(clusterID string, bck *Bck, perms AccessAt
 	return ErrNoPermissions
 }
 
+func (k *AuthS3Key) CheckPermissions(clusterID string, bck *Bck, perms AccessAttrs) error {
+	tk := &AuthToken{UserID: k.UserID, Clusters: k.Clusters, Buckets: k.Buckets, IsAdmin: k.IsAdmin}
+	return tk.CheckPermissions(clusterID, bck, perms)
+}
+
 func (uInfo *AuthUser) IsAdmin() bool {
 	for _, r := range uInfo.Roles {
 		if r == AuthAdminRole {
@@ -33,6 +33,8 @@ const (
 	S3Scheme      = "s3"
 	AZScheme      = "az"
 	AISScheme     = "ais"
+	SFTPScheme    = "sftp" // (downloader only)
+	FileScheme    = "file" // ditto
 )
 
 type (
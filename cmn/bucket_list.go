@@ -23,6 +23,8 @@ type BucketEntry struct {
 	TargetURL string `json:"target_url,omitempty" msg:"t,omitempty"`  // URL of target which has the entry
 	Copies    int16  `json:"copies,omitempty" msg:"c,omitempty"`      // ## copies (non-replicated = 1)
 	Flags     uint16 `json:"flags,omitempty" msg:"f,omitempty"`       // object flags, like CheckExists, IsMoved etc
+	// prior versions of the object, the most recent first (ais buckets only, see SelectVersions)
+	Versions []*BucketEntry `json:"versions,omitempty" msg:"vs,omitempty"`
 }
 
 func (be *BucketEntry) CheckExists() bool {
@@ -37,6 +39,10 @@ func (be *BucketEntry) IsStatusOK() bool {
 	return be.Flags&EntryStatusMask == 0
 }
 
+func (be *BucketEntry) IsStatusDeleted() bool {
+	return be.Flags&EntryStatusMask == ObjStatusDeleted
+}
+
 func (be *BucketEntry) String() string { return "{" + be.Name + "}" }
 
 func (be *BucketEntry) CopyWithProps(propsSet StringSet) (ne *BucketEntry) {
@@ -59,6 +65,7 @@ func (be *BucketEntry) CopyWithProps(propsSet StringSet) (ne *BucketEntry) {
 	if propsSet.Contains(GetPropsCopies) {
 		ne.Copies = be.Copies
 	}
+	ne.Versions = be.Versions
 	return
 }
 
@@ -76,6 +76,37 @@ func (z *BucketEntry) DecodeMsg(dc *msgp.Reader) (err error) {
 				err = msgp.WrapError(err, "Flags")
 				return
 			}
+		case "vs":
+			var zb0002 uint32
+			zb0002, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Versions")
+				return
+			}
+			if cap(z.Versions) >= int(zb0002) {
+				z.Versions = (z.Versions)[:zb0002]
+			} else {
+				z.Versions = make([]*BucketEntry, zb0002)
+			}
+			for za0001 := range z.Versions {
+				if dc.IsNil() {
+					err = dc.ReadNil()
+					if err != nil {
+						err = msgp.WrapError(err, "Versions", za0001)
+						return
+					}
+					z.Versions[za0001] = nil
+				} else {
+					if z.Versions[za0001] == nil {
+						z.Versions[za0001] = new(BucketEntry)
+					}
+					err = z.Versions[za0001].DecodeMsg(dc)
+					if err != nil {
+						err = msgp.WrapError(err, "Versions", za0001)
+						return
+					}
+				}
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -90,8 +121,8 @@ func (z *BucketEntry) DecodeMsg(dc *msgp.Reader) (err error) {
 // EncodeMsg implements msgp.Encodable
 func (z *BucketEntry) EncodeMsg(en *msgp.Writer) (err error) {
 	// omitempty: check for empty values
-	zb0001Len := uint32(8)
-	var zb0001Mask uint8 /* 8 bits */
+	zb0001Len := uint32(9)
+	var zb0001Mask uint16 /* 9 bits */
 	if z.Size == 0 {
 		zb0001Len--
 		zb0001Mask |= 0x2
@@ -120,6 +151,10 @@ func (z *BucketEntry) EncodeMsg(en *msgp.Writer) (err error) {
 		zb0001Len--
 		zb0001Mask |= 0x80
 	}
+	if z.Versions == nil {
+		zb0001Len--
+		zb0001Mask |= 0x100
+	}
 	// variable map header, size zb0001Len
 	err = en.Append(0x80 | uint8(zb0001Len))
 	if err != nil {
@@ -222,12 +257,45 @@ func (z *BucketEntry) EncodeMsg(en *msgp.Writer) (err error) {
 			return
 		}
 	}
+	if (zb0001Mask & 0x100) == 0 { // if not empty
+		// write "vs"
+		err = en.Append(0xa2, 0x76, 0x73)
+		if err != nil {
+			return
+		}
+		err = en.WriteArrayHeader(uint32(len(z.Versions)))
+		if err != nil {
+			err = msgp.WrapError(err, "Versions")
+			return
+		}
+		for za0001 := range z.Versions {
+			if z.Versions[za0001] == nil {
+				err = en.WriteNil()
+				if err != nil {
+					return
+				}
+			} else {
+				err = z.Versions[za0001].EncodeMsg(en)
+				if err != nil {
+					err = msgp.WrapError(err, "Versions", za0001)
+					return
+				}
+			}
+		}
+	}
 	return
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *BucketEntry) Msgsize() (s int) {
-	s = 1 + 2 + msgp.StringPrefixSize + len(z.Name) + 2 + msgp.Int64Size + 3 + msgp.StringPrefixSize + len(z.Checksum) + 2 + msgp.StringPrefixSize + len(z.Atime) + 2 + msgp.StringPrefixSize + len(z.Version) + 2 + msgp.StringPrefixSize + len(z.TargetURL) + 2 + msgp.Int16Size + 2 + msgp.Uint16Size
+	s = 1 + 2 + msgp.StringPrefixSize + len(z.Name) + 2 + msgp.Int64Size + 3 + msgp.StringPrefixSize + len(z.Checksum) + 2 + msgp.StringPrefixSize + len(z.Atime) + 2 + msgp.StringPrefixSize + len(z.Version) + 2 + msgp.StringPrefixSize + len(z.TargetURL) + 2 + msgp.Int16Size + 2 + msgp.Uint16Size + 3 + msgp.ArrayHeaderSize
+	for za0001 := range z.Versions {
+		if z.Versions[za0001] == nil {
+			s += msgp.NilSize
+		} else {
+			s += z.Versions[za0001].Msgsize()
+		}
+	}
 	return
 }
 
@@ -43,6 +43,10 @@ const (
 	ExtTarTgz = ".tar.gz"
 	// ExtZip is zip files extension
 	ExtZip = ".zip"
+	// ExtTFRecord is TFRecord files extension
+	ExtTFRecord = ".tfrecord"
+	// ExtMsgpack is msgpack files extension
+	ExtMsgpack = ".msgpack"
 
 	// misc
 	SizeofI64 = int(unsafe.Sizeof(uint64(0)))
@@ -34,6 +34,10 @@ const (
 	// EC
 	MinSliceCount = 1  // minimum number of data or parity slices
 	MaxSliceCount = 32 // maximum number of data or parity slices
+
+	// EC codecs
+	ECCodecRS  = "rs"  // Reed-Solomon (default)
+	ECCodecLRC = "lrc" // Locally Repairable Codes
 )
 
 const (
@@ -51,6 +55,26 @@ const (
 	httpsProto = "https"
 )
 
+// LRU eviction policies (see LRUConf.Policy)
+const (
+	LRUPolicyAtime       = "atime"        // least recently accessed first (default)
+	LRUPolicyLFU         = "lfu"          // least frequently accessed first (access counts are kept in object metadata)
+	LRUPolicySize        = "size"         // size-weighted: largest size times time since the last access first
+	LRUPolicyCopiesFirst = "copies-first" // drop mirrored copies and evict erasure coded objects first
+)
+
+// stats exporters
+const (
+	StatsExporterStatsD     = "statsd"
+	StatsExporterPrometheus = "prometheus"
+)
+
+// key providers (see KeyProviderConf)
+const (
+	KeyProviderFile = "keyfile"
+	KeyProviderHTTP = "http"
+)
+
 // FeatureFlags
 const (
 	FeatureDirectAccess = 1 << iota
@@ -145,6 +169,9 @@ type (
 		Downloader       DownloaderConf  `json:"downloader"`
 		DSort            DSortConf       `json:"distributed_sort"`
 		Compression      CompressionConf `json:"compression"`
+		Stats            StatsConf       `json:"stats"`
+		KeyProvider      KeyProviderConf `json:"key_provider"`
+		Scrub            ScrubConf       `json:"scrub"`
 	}
 	CloudConf struct {
 		Conf map[string]interface{} `json:"conf,omitempty"` // implementation depends on cloud provider
@@ -184,6 +211,8 @@ type (
 		DataSlices   int    `json:"data_slices"`   // number of data slices
 		ParitySlices int    `json:"parity_slices"` // number of parity slices/replicas
 		BatchSize    int    `json:"batch_size"`    // Batch size for EC rebalance
+		Codec        string `json:"codec"`         // erasure code: ECCodecRS (default) or ECCodecLRC
+		LocalGroups  int    `json:"local_groups"`  // LRC: number of local groups (and local parity slices)
 		Enabled      bool   `json:"enabled"`       // EC is enabled
 	}
 	ECConfToUpdate struct {
@@ -192,6 +221,8 @@ type (
 		DataSlices   *int    `json:"data_slices"`
 		ParitySlices *int    `json:"parity_slices"`
 		Compression  *string `json:"compression"`
+		Codec        *string `json:"codec"`
+		LocalGroups  *int    `json:"local_groups"`
 	}
 	LogConf struct {
 		Dir      string `json:"dir"`       // log directory
@@ -263,14 +294,23 @@ type (
 		// CapacityUpdTime is the parsed value of CapacityUpdTimeStr
 		CapacityUpdTime time.Duration `json:"-"`
 
+		// Policy: the order in which objects get evicted (one of the LRUPolicy* enumerated above);
+		// empty value defaults to LRUPolicyAtime
+		Policy string `json:"policy"`
+
+		// PinnedPrefixes: objects with names that start with any of the prefixes are never evicted
+		PinnedPrefixes []string `json:"pinned_prefixes"`
+
 		// Enabled: LRU will only run when set to true
 		Enabled bool `json:"enabled"`
 	}
 	LRUConfToUpdate struct {
-		LowWM   *int64 `json:"lowwm"`
-		HighWM  *int64 `json:"highwm"`
-		OOS     *int64 `json:"out_of_space"`
-		Enabled *bool  `json:"enabled"`
+		LowWM          *int64    `json:"lowwm"`
+		HighWM         *int64    `json:"highwm"`
+		OOS            *int64    `json:"out_of_space"`
+		Policy         *string   `json:"policy"`
+		PinnedPrefixes *[]string `json:"pinned_prefixes"`
+		Enabled        *bool     `json:"enabled"`
 	}
 	DiskConf struct {
 		DiskUtilLowWM   int64         `json:"disk_util_low_wm"`  // no throttling below
@@ -331,10 +371,21 @@ type (
 
 		// Validate object version upon warm GET.
 		ValidateWarmGet bool `json:"validate_warm_get"`
+
+		// MaxHistory is the number of prior versions retained when an object
+		// in an ais bucket gets overwritten or deleted (0 - prior versions are
+		// not retained unless HistoryTimeStr is set).
+		MaxHistory int `json:"max_history"`
+
+		// HistoryTimeStr denotes the period of time during which prior versions
+		// are retained (empty - no time limit).
+		HistoryTimeStr string `json:"history_time"`
 	}
 	VersionConfToUpdate struct {
-		Enabled         *bool `json:"enabled"`
-		ValidateWarmGet *bool `json:"validate_warm_get"`
+		Enabled         *bool   `json:"enabled"`
+		ValidateWarmGet *bool   `json:"validate_warm_get"`
+		MaxHistory      *int    `json:"max_history"`
+		HistoryTimeStr  *string `json:"history_time"`
 	}
 
 	TestfspathConf struct {
@@ -401,8 +452,18 @@ type (
 		TimeoutFactor uint8                `json:"timeout_factor"`
 	}
 	DownloaderConf struct {
-		TimeoutStr string        `json:"timeout"`
-		Timeout    time.Duration `json:"-"`
+		TimeoutStr   string             `json:"timeout"`
+		Timeout      time.Duration      `json:"-"`
+		BandwidthStr string             `json:"bandwidth"` // cluster-wide max bytes per second shared by all jobs; "0" - no limit
+		Bandwidth    int64              `json:"-"`
+		FileRoots    []string           `json:"file_roots"` // file:// links must point under one of these directories
+		SFTP         DownloaderSFTPConf `json:"sftp"`
+	}
+	// DownloaderSFTPConf configures downloads from sftp:// links
+	DownloaderSFTPConf struct {
+		User           string `json:"user"`             // unless specified in the link
+		KeyFile        string `json:"key_file"`         // private key (in PEM format)
+		KnownHostsFile string `json:"known_hosts_file"` // host keys (in OpenSSH known_hosts format)
 	}
 	DSortConf struct {
 		DuplicatedRecords   string        `json:"duplicated_records"`
@@ -423,6 +484,28 @@ type (
 		BlockMaxSize int  `json:"block_size"` // *uncompressed* block max size
 		Checksum     bool `json:"checksum"`   // true: checksum lz4 frames
 	}
+	StatsConf struct {
+		// Exporter selects how the node's statistics are exported:
+		// pushed to StatsD (default) or pulled by Prometheus via `/metrics`.
+		// Changing the exporter requires restart.
+		Exporter string `json:"exporter"`
+	}
+	// KeyProviderConf configures where the keys used for server-side
+	// encryption of the buckets (see SSEConf) come from
+	KeyProviderConf struct {
+		Type    string `json:"type"`     // "" (none), "keyfile", or "http" - see KeyProvider* enum
+		KeyFile string `json:"key_file"` // keyfile: JSON map of key IDs to base64 encoded keys
+		URL     string `json:"url"`      // http: key service URL - the key is fetched from `<url>/<key_id>`
+		Token   string `json:"token"`    // http: (optional) bearer token
+	}
+	// ScrubConf configures the checksum scrub that re-hashes all objects
+	// to detect (and repair) silent data corruption - see scrub package
+	ScrubConf struct {
+		IntervalStr string        `json:"interval"` // time between consecutive runs; "0" - on demand only
+		Interval    time.Duration `json:"-"`
+		RateStr     string        `json:"rate"` // max bytes per second re-hashed on each mountpath; "0" - no limit
+		Rate        int64         `json:"-"`
+	}
 )
 
 var (
@@ -558,6 +641,9 @@ var (
 	_ Validator = &FSPathsConf{}
 	_ Validator = &TestfspathConf{}
 	_ Validator = &CompressionConf{}
+	_ Validator = &StatsConf{}
+	_ Validator = &KeyProviderConf{}
+	_ Validator = &ScrubConf{}
 
 	_ PropsValidator = &CksumConf{}
 	_ PropsValidator = &LRUConf{}
@@ -690,9 +776,32 @@ func (c *LRUConf) Validate(_ *Config) (err error) {
 	if c.CapacityUpdTime, err = time.ParseDuration(c.CapacityUpdTimeStr); err != nil {
 		return fmt.Errorf("invalid lru.capacity_upd_time format: %v", err)
 	}
+	switch c.Policy {
+	case "":
+		c.Policy = LRUPolicyAtime
+	case LRUPolicyAtime, LRUPolicyLFU, LRUPolicySize, LRUPolicyCopiesFirst:
+	default:
+		return fmt.Errorf("invalid lru.policy %q (expecting one of: %s, %s, %s, %s)", c.Policy,
+			LRUPolicyAtime, LRUPolicyLFU, LRUPolicySize, LRUPolicyCopiesFirst)
+	}
+	for _, prefix := range c.PinnedPrefixes {
+		if prefix == "" {
+			return errors.New("invalid lru.pinned_prefixes: empty prefix (use lru.enabled=false instead)")
+		}
+	}
 	return nil
 }
 
+// Pinned returns true if the object must never be evicted.
+func (c *LRUConf) Pinned(objName string) bool {
+	for _, prefix := range c.PinnedPrefixes {
+		if strings.HasPrefix(objName, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *LRUConf) ValidateAsProps(args *ValidationArgs) (err error) {
 	if !c.Enabled {
 		return nil
@@ -712,6 +821,18 @@ func (c *VersionConf) Validate(_ *Config) error {
 	if !c.Enabled && c.ValidateWarmGet {
 		return errors.New("versioning.validate_warm_get requires versioning to be enabled")
 	}
+	if c.MaxHistory < 0 {
+		return fmt.Errorf("invalid versioning.max_history: %d (expected non-negative value)", c.MaxHistory)
+	}
+	if c.HistoryTimeStr != "" {
+		d, err := time.ParseDuration(c.HistoryTimeStr)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid versioning.history_time format %q", c.HistoryTimeStr)
+		}
+	}
+	if !c.Enabled && (c.MaxHistory > 0 || c.HistoryTimeStr != "") {
+		return errors.New("versioning history requires versioning to be enabled")
+	}
 	return nil
 }
 
@@ -757,6 +878,21 @@ func (c *ECConf) Validate(_ *Config) error {
 	if c.BatchSize < 4 || c.BatchSize > 128 {
 		return fmt.Errorf("invalid ec.batch_size: %d (must be in the range 4..128)", c.ObjSizeLimit)
 	}
+	switch c.Codec {
+	case "", ECCodecRS:
+		if c.LocalGroups != 0 {
+			return fmt.Errorf("invalid ec.local_groups: %d (codec %q does not support local groups)",
+				c.LocalGroups, ECCodecRS)
+		}
+	case ECCodecLRC:
+		// local parity slices are a part of parity slices, at least one global parity is required
+		if c.LocalGroups < 1 || c.LocalGroups > c.DataSlices || c.LocalGroups >= c.ParitySlices {
+			return fmt.Errorf("invalid ec.local_groups: %d (expected value in range [1, %d])",
+				c.LocalGroups, Min(c.DataSlices, c.ParitySlices-1))
+		}
+	default:
+		return fmt.Errorf("invalid ec.codec: %q (expected one of: %q, %q)", c.Codec, ECCodecRS, ECCodecLRC)
+	}
 	return nil
 }
 
@@ -836,6 +972,50 @@ func (c *PeriodConf) Validate(_ *Config) (err error) {
 	return nil
 }
 
+func (c *StatsConf) Validate(_ *Config) (err error) {
+	if c.Exporter == "" {
+		c.Exporter = StatsExporterStatsD
+	}
+	if c.Exporter != StatsExporterStatsD && c.Exporter != StatsExporterPrometheus {
+		return fmt.Errorf("invalid stats.exporter %q (expecting %q or %q)",
+			c.Exporter, StatsExporterStatsD, StatsExporterPrometheus)
+	}
+	return nil
+}
+
+func (c *KeyProviderConf) Validate(_ *Config) (err error) {
+	switch c.Type {
+	case "":
+	case KeyProviderFile:
+		if c.KeyFile == "" {
+			return fmt.Errorf("key_provider.key_file must be specified for %q key provider", c.Type)
+		}
+	case KeyProviderHTTP:
+		if c.URL == "" {
+			return fmt.Errorf("key_provider.url must be specified for %q key provider", c.Type)
+		}
+	default:
+		return fmt.Errorf("invalid key_provider.type %q (expecting %q, %q, or none)",
+			c.Type, KeyProviderFile, KeyProviderHTTP)
+	}
+	return nil
+}
+
+func (c *ScrubConf) Validate(_ *Config) (err error) {
+	c.Interval, c.Rate = 0, 0
+	if c.IntervalStr != "" {
+		if c.Interval, err = time.ParseDuration(c.IntervalStr); err != nil || c.Interval < 0 {
+			return fmt.Errorf("invalid scrub.interval %q", c.IntervalStr)
+		}
+	}
+	if c.RateStr != "" {
+		if c.Rate, err = S2B(c.RateStr); err != nil || c.Rate < 0 {
+			return fmt.Errorf("invalid scrub.rate %q", c.RateStr)
+		}
+	}
+	return nil
+}
+
 func (c *KeepaliveConf) Validate(_ *Config) (err error) {
 	if c.Proxy.Interval, err = time.ParseDuration(c.Proxy.IntervalStr); err != nil {
 		return fmt.Errorf("invalid keepalivetracker.proxy.interval %s", c.Proxy.IntervalStr)
@@ -908,6 +1088,18 @@ func (c *DownloaderConf) Validate(_ *Config) (err error) {
 	if c.Timeout, err = time.ParseDuration(c.TimeoutStr); err != nil {
 		return fmt.Errorf("invalid downloader.timeout %s", c.TimeoutStr)
 	}
+	c.Bandwidth = 0
+	if c.BandwidthStr != "" {
+		if c.Bandwidth, err = S2B(c.BandwidthStr); err != nil || c.Bandwidth < 0 {
+			return fmt.Errorf("invalid downloader.bandwidth %q", c.BandwidthStr)
+		}
+	}
+	for i, root := range c.FileRoots {
+		if !filepath.IsAbs(root) {
+			return fmt.Errorf("invalid downloader.file_roots: %q is not an absolute path", root)
+		}
+		c.FileRoots[i] = filepath.Clean(root)
+	}
 	return nil
 }
 
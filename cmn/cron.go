@@ -0,0 +1,188 @@
+// Package cmn provides common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSpec is a parsed cron expression. Supported are the standard 5 fields
+//   minute (0-59) hour (0-23) day-of-month (1-31) month (1-12 or JAN-DEC) day-of-week (0-7 or SUN-SAT)
+// where each field is a comma-separated list of `*`, `N`, `N-M`, and `*/S`, `N-M/S` steps,
+// and the following shortcuts:
+//   @yearly (@annually), @monthly, @weekly, @daily (@midnight), @hourly, @every <duration>
+// As in the classic cron, when both day-of-month and day-of-week are restricted
+// the schedule fires when either of them matches.
+
+type CronSpec struct {
+	minute, hour, dom, month, dow uint64 // bitmasks of the allowed values
+	domAny, dowAny                bool
+	every                         time.Duration // @every <duration>
+}
+
+const cronMaxYears = 5 // Next gives up when no activation is found within this many years
+
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // symbolic names for [min..]
+}
+
+var (
+	cronFields = [...]cronField{
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day-of-month", min: 1, max: 31},
+		{name: "month", min: 1, max: 12, names: []string{
+			"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+		{name: "day-of-week", min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+	}
+	cronShortcuts = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+func ParseCron(spec string) (*CronSpec, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %v", spec, err)
+		}
+		if d < time.Minute {
+			return nil, fmt.Errorf("invalid schedule %q: interval must be at least 1m", spec)
+		}
+		return &CronSpec{every: d}, nil
+	}
+	if strings.HasPrefix(spec, "@") {
+		expanded, ok := cronShortcuts[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("invalid schedule %q: unknown shortcut", spec)
+		}
+		spec = expanded
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid schedule %q: expected %d fields, got %d", spec, len(cronFields), len(fields))
+	}
+	var (
+		masks [len(cronFields)]uint64
+		err   error
+	)
+	for i, f := range fields {
+		if masks[i], err = cronFields[i].parse(f); err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %v", spec, err)
+		}
+	}
+	c := &CronSpec{
+		minute: masks[0], hour: masks[1], dom: masks[2], month: masks[3], dow: masks[4],
+		domAny: strings.HasPrefix(fields[2], "*"), dowAny: strings.HasPrefix(fields[4], "*"),
+	}
+	if c.dow&(1<<7) != 0 { // 7 is Sunday as well
+		c.dow |= 1
+	}
+	return c, nil
+}
+
+func (f *cronField) parse(s string) (mask uint64, err error) {
+	for _, part := range strings.Split(s, ",") {
+		var (
+			lo, hi = f.min, f.max
+			step   = 1
+			rng    = part
+		)
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			rng = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid %s step %q", f.name, part)
+			}
+		}
+		if rng != "*" {
+			if i := strings.IndexByte(rng, '-'); i >= 0 {
+				if lo, err = f.value(rng[:i]); err != nil {
+					return
+				}
+				if hi, err = f.value(rng[i+1:]); err != nil {
+					return
+				}
+			} else {
+				if lo, err = f.value(rng); err != nil {
+					return
+				}
+				if step == 1 {
+					hi = lo
+				}
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid %s range %q", f.name, part)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return
+}
+
+func (f *cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q (expecting %d-%d)", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next returns the first activation time that is strictly after `t` (with
+// minute resolution), or zero time if there is none.
+func (c *CronSpec) Next(t time.Time) time.Time {
+	if c.every != 0 {
+		return t.Add(c.every)
+	}
+	var (
+		loc     = t.Location()
+		maxYear = t.Year() + cronMaxYears
+	)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for t.Year() <= maxYear {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *CronSpec) dayMatches(t time.Time) bool {
+	var (
+		dom = c.dom&(1<<uint(t.Day())) != 0
+		dow = c.dow&(1<<uint(t.Weekday())) != 0
+	)
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,55 @@
+// Package provides common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+
+package cmn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// Wednesday
+	from := time.Date(2020, time.September, 16, 10, 17, 30, 0, time.UTC)
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2020, 9, 16, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2020, 9, 16, 10, 30, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2020, 9, 17, 3, 0, 0, 0, time.UTC)},
+		{"30 1-4/2 * * *", time.Date(2020, 9, 17, 1, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * sat,sun", time.Date(2020, 9, 19, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2020, 9, 20, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 20 * 5", time.Date(2020, 9, 18, 0, 0, 0, 0, time.UTC)}, // day-of-month OR day-of-week
+		{"@hourly", time.Date(2020, 9, 16, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2020, 9, 20, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90m", from.Add(90 * time.Minute)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+	for _, test := range tests {
+		c, err := ParseCron(test.spec)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", test.spec, err)
+		}
+		if next := c.Next(from); !next.Equal(test.next) {
+			t.Errorf("%q: expected next %v, got %v", test.spec, test.next, next)
+		}
+	}
+}
+
+func TestCronParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *",
+		"* * * * 8", "5-1 * * * *", "*/0 * * * *", "x * * * *", "@often", "@every 10s", "@every x",
+	} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("expected %q to fail", spec)
+		}
+	}
+}
@@ -39,6 +39,12 @@ type (
 		used int32
 		oos  bool
 	}
+	ErrorQuotaExceeded struct {
+		bck   Bck
+		what  string // "size" or "objects"
+		used  int64
+		limit int64
+	}
 
 	BucketAccessDenied struct{ errAccessDenied }
 	ObjectAccessDenied struct{ errAccessDenied }
@@ -222,6 +228,19 @@ func (e *ErrorCapacityExceeded) Error() string {
 	return fmt.Sprintf("low on free space: used capacity %d%% exceeded high watermark(%d%%)", e.used, e.high)
 }
 
+func NewErrorQuotaExceeded(bck Bck, what string, used, limit int64) *ErrorQuotaExceeded {
+	return &ErrorQuotaExceeded{bck: bck, what: what, used: used, limit: limit}
+}
+
+func (e *ErrorQuotaExceeded) Error() string {
+	return fmt.Sprintf("bucket %s: %s quota exceeded (%d > %d)", e.bck, e.what, e.used, e.limit)
+}
+
+func IsErrQuotaExceeded(err error) bool {
+	var e *ErrorQuotaExceeded
+	return errors.As(err, &e)
+}
+
 func (e InvalidCksumError) Error() string {
 	return fmt.Sprintf("checksum: expected [%s], actual [%s]", e.expectedHash, e.actualHash)
 }
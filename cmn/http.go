@@ -36,6 +36,9 @@ const (
 	HeaderAccept                = "Accept"
 	HeaderLocation              = "Location"
 	HeaderETag                  = "ETag" // Ref: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/ETag
+	HeaderContentMD5            = "Content-MD5"
+	HeaderIfRange               = "If-Range"
+	HeaderLastModified          = "Last-Modified"
 )
 
 // Ref: https://www.iana.org/assignments/media-types/media-types.xhtml
@@ -27,6 +27,11 @@ type (
 		io.ReadCloser
 		Open() (io.ReadCloser, error)
 	}
+	// ReadAtOpenCloser adds random access (e.g., range reads) to ReadOpenCloser.
+	ReadAtOpenCloser interface {
+		ReadOpenCloser
+		io.ReaderAt
+	}
 	WriterAt interface {
 		io.Writer
 		io.WriterAt
@@ -85,13 +90,14 @@ type (
 )
 
 var (
-	_ io.Reader      = &nopReader{}
-	_ ReadOpenCloser = &FileHandle{}
-	_ ReadSizer      = &SizedReader{}
-	_ ReadOpenCloser = &SectionHandle{}
-	_ ReadOpenCloser = &FileSectionHandle{}
-	_ ReadOpenCloser = &nopOpener{}
-	_ ReadOpenCloser = &ByteHandle{}
+	_ io.Reader        = &nopReader{}
+	_ ReadOpenCloser   = &FileHandle{}
+	_ ReadAtOpenCloser = &FileHandle{}
+	_ ReadSizer        = &SizedReader{}
+	_ ReadOpenCloser   = &SectionHandle{}
+	_ ReadOpenCloser   = &FileSectionHandle{}
+	_ ReadOpenCloser   = &nopOpener{}
+	_ ReadOpenCloser   = &ByteHandle{}
 )
 
 ///////////////
@@ -9,6 +9,8 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+
+	jsoniter "github.com/json-iterator/go"
 )
 
 const (
@@ -96,6 +98,13 @@ func (f *field) SetValue(src interface{}, force ...bool) error {
 			dst.Set(reflect.New(dst.Type().Elem())) // set pointer to default value
 			dst = dst.Elem()                        // dereference pointer
 			goto reflectDst
+		case reflect.Slice, reflect.Map:
+			// e.g. `lifecycle.rules` - the value is expected to be JSON
+			if s == "" {
+				dst.Set(reflect.Zero(dst.Type()))
+			} else if err := jsoniter.Unmarshal([]byte(s), dst.Addr().Interface()); err != nil {
+				return fmt.Errorf("invalid value for %q (expecting JSON): %v", f.name, err)
+			}
 		default:
 			AssertMsg(false, fmt.Sprintf("field.name: %s, field.type: %s", f.listTag, dst.Kind()))
 		}
@@ -177,6 +177,65 @@ var _ = Describe("API", func() {
 					Access: 1024,
 				},
 			),
+			Entry("quota",
+				cmn.BucketProps{
+					Quota: cmn.QuotaConf{SoftSize: 512, HardSize: 1024},
+				},
+				cmn.BucketPropsToUpdate{
+					Quota: &cmn.QuotaConfToUpdate{
+						HardSize:    api.Int64(2048),
+						HardObjects: api.Int64(100),
+					},
+				},
+				cmn.BucketProps{
+					Quota: cmn.QuotaConf{SoftSize: 512, HardSize: 2048, HardObjects: 100},
+				},
+			),
 		)
 	})
+
+	Describe("Quota", func() {
+		DescribeTable("should validate quota",
+			func(quota cmn.QuotaConf, valid bool) {
+				err := quota.ValidateAsProps(nil)
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+			Entry("unlimited", cmn.QuotaConf{}, true),
+			Entry("soft only", cmn.QuotaConf{SoftSize: 1024, SoftObjects: 10}, true),
+			Entry("soft below hard", cmn.QuotaConf{SoftSize: 1024, HardSize: 2048}, true),
+			Entry("soft above hard", cmn.QuotaConf{SoftSize: 4096, HardSize: 2048}, false),
+			Entry("soft above hard (objects)", cmn.QuotaConf{SoftObjects: 11, HardObjects: 10}, false),
+			Entry("negative", cmn.QuotaConf{HardObjects: -1}, false),
+		)
+	})
+
+	Describe("Replicate", func() {
+		DescribeTable("should validate replication",
+			func(conf cmn.ReplicateConf, valid bool) {
+				err := conf.ValidateAsProps(nil)
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+			Entry("disabled", cmn.ReplicateConf{}, true),
+			Entry("enabled", cmn.ReplicateConf{Enabled: true, Remote: "teamZ"}, true),
+			Entry("enabled with bucket", cmn.ReplicateConf{Enabled: true, Remote: "teamZ", Bucket: "replica"}, true),
+			Entry("no remote", cmn.ReplicateConf{Enabled: true}, false),
+			Entry("invalid bucket", cmn.ReplicateConf{Enabled: true, Remote: "teamZ", Bucket: "a/b"}, false),
+		)
+
+		It("should resolve destination bucket", func() {
+			bck := cmn.Bck{Name: "src", Provider: cmn.ProviderAIS}
+			conf := cmn.ReplicateConf{Enabled: true, Remote: "teamZ"}
+			Expect(conf.DstBck(bck)).To(Equal(cmn.Bck{Name: "src", Provider: cmn.ProviderAIS, Ns: cmn.Ns{UUID: "teamZ"}}))
+			conf.Bucket = "dst"
+			Expect(conf.DstBck(bck).Name).To(Equal("dst"))
+		})
+	})
 })
@@ -70,9 +70,13 @@ var _ = Describe("IterFields", func() {
 					"ec.batch_size":    32,
 					"ec.objsize_limit": int64(0),
 					"ec.compression":   "",
+					"ec.codec":         "",
+					"ec.local_groups":  0,
 
 					"versioning.enabled":           false,
 					"versioning.validate_warm_get": false,
+					"versioning.max_history":       0,
+					"versioning.history_time":      "",
 
 					"checksum.type":              cmn.ChecksumXXHash,
 					"checksum.validate_warm_get": false,
@@ -86,6 +90,22 @@ var _ = Describe("IterFields", func() {
 					"lru.out_of_space":      int64(0),
 					"lru.dont_evict_time":   "",
 					"lru.capacity_upd_time": "",
+					"lru.policy":            "",
+					"lru.pinned_prefixes":   []string(nil),
+
+					"lifecycle.rules": []cmn.LifecycleRule(nil),
+
+					"quota.soft_size":    int64(0),
+					"quota.hard_size":    int64(0),
+					"quota.soft_objects": int64(0),
+					"quota.hard_objects": int64(0),
+
+					"sse.enabled": false,
+					"sse.key_id":  "",
+
+					"replicate.enabled": false,
+					"replicate.remote":  "",
+					"replicate.bucket":  "",
 
 					"extra.original_url": "",
 					"extra.cloud_region": "",
@@ -121,9 +141,13 @@ var _ = Describe("IterFields", func() {
 					"ec.data_slices":   (*int)(nil),
 					"ec.objsize_limit": (*int64)(nil),
 					"ec.compression":   (*string)(nil),
+					"ec.codec":         (*string)(nil),
+					"ec.local_groups":  (*int)(nil),
 
 					"versioning.enabled":           (*bool)(nil),
 					"versioning.validate_warm_get": (*bool)(nil),
+					"versioning.max_history":       (*int)(nil),
+					"versioning.history_time":      (*string)(nil),
 
 					"checksum.type":              api.String(cmn.ChecksumXXHash),
 					"checksum.validate_warm_get": (*bool)(nil),
@@ -131,10 +155,26 @@ var _ = Describe("IterFields", func() {
 					"checksum.validate_obj_move": (*bool)(nil),
 					"checksum.enable_read_range": (*bool)(nil),
 
-					"lru.enabled":      (*bool)(nil),
-					"lru.lowwm":        (*int64)(nil),
-					"lru.highwm":       (*int64)(nil),
-					"lru.out_of_space": (*int64)(nil),
+					"lru.enabled":         (*bool)(nil),
+					"lru.lowwm":           (*int64)(nil),
+					"lru.highwm":          (*int64)(nil),
+					"lru.out_of_space":    (*int64)(nil),
+					"lru.policy":          (*string)(nil),
+					"lru.pinned_prefixes": (*[]string)(nil),
+
+					"lifecycle.rules": (*[]cmn.LifecycleRule)(nil),
+
+					"quota.soft_size":    (*int64)(nil),
+					"quota.hard_size":    (*int64)(nil),
+					"quota.soft_objects": (*int64)(nil),
+					"quota.hard_objects": (*int64)(nil),
+
+					"sse.enabled": (*bool)(nil),
+					"sse.key_id":  (*string)(nil),
+
+					"replicate.enabled": (*bool)(nil),
+					"replicate.remote":  (*string)(nil),
+					"replicate.bucket":  (*string)(nil),
 
 					"access": api.AccessAttrs(1024),
 				},
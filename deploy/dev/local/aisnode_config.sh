@@ -54,6 +54,8 @@ cat > $AIS_CONF_FILE <<EOL
 		"out_of_space":      95,
 		"dont_evict_time":   "120m",
 		"capacity_upd_time": "10m",
+		"policy":            "atime",
+		"pinned_prefixes":   [],
 		"enabled":           true
 	},
 	"disk":{
@@ -84,7 +86,9 @@ cat > $AIS_CONF_FILE <<EOL
 	},
 	"versioning": {
 		"enabled":           true,
-		"validate_warm_get": false
+		"validate_warm_get": false,
+		"max_history":       0,
+		"history_time":      ""
 	},
 	"fspaths": {
 		$AIS_FS_PATHS
@@ -139,7 +143,14 @@ cat > $AIS_CONF_FILE <<EOL
 		"timeout_factor": 3
 	},
 	"downloader": {
-		"timeout": "1h"
+		"timeout":    "1h",
+		"bandwidth":  "0",
+		"file_roots": [],
+		"sftp": {
+			"user":             "",
+			"key_file":         "",
+			"known_hosts_file": ""
+		}
 	},
 	"distributed_sort": {
 		"duplicated_records":    "ignore",
@@ -150,6 +161,19 @@ cat > $AIS_CONF_FILE <<EOL
 		"dsorter_mem_threshold": "100GB",
 		"compression":           "${COMPRESSION:-never}",
 		"call_timeout":          "10m"
+	},
+	"stats": {
+		"exporter": "${AIS_STATS_EXPORTER:-statsd}"
+	},
+	"key_provider": {
+		"type":     "${AIS_KEY_PROVIDER:-}",
+		"key_file": "${AIS_KEY_FILE:-}",
+		"url":      "",
+		"token":    ""
+	},
+	"scrub": {
+		"interval": "${AIS_SCRUB_INTERVAL:-0}",
+		"rate":     "50MB"
 	}
 }
 EOL
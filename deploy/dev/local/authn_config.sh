@@ -15,7 +15,13 @@ cat > $AUTHN_CONF_FILE <<EOL
 	},
 	"auth": {
 		"secret": "$AIS_SECRET_KEY",
-		"expiration_time": "${AUTHN_TTL:-24h}"
+		"expiration_time": "${AUTHN_TTL:-24h}",
+		"oidc": {
+			"issuer":    "${AUTHN_OIDC_ISSUER}",
+			"jwks_url":  "${AUTHN_OIDC_JWKS_URL}",
+			"client_id": "${AUTHN_OIDC_CLIENT_ID}",
+			"groups":    {}
+		}
 	},
 	"timeout": {
 		"default_timeout": "30s"
@@ -19,10 +19,20 @@ import (
 )
 
 const (
-	DlTypeSingle DlType = "single"
-	DlTypeRange  DlType = "range"
-	DlTypeMulti  DlType = "multi"
-	DlTypeCloud  DlType = "cloud"
+	DlTypeSingle   DlType = "single"
+	DlTypeRange    DlType = "range"
+	DlTypeMulti    DlType = "multi"
+	DlTypeCloud    DlType = "cloud"
+	DlTypeManifest DlType = "manifest"
+
+	// manifest formats (see DlManifestBody)
+	ManifestFormatCSV   = "csv"
+	ManifestFormatJSONL = "jsonl"
+
+	// job priorities (see DlBase.Priority)
+	DlPriorityMin     = 1
+	DlPriorityMax     = 10
+	DlPriorityDefault = 5
 
 	DownloadProgressInterval = 10 * time.Second
 )
@@ -52,8 +62,10 @@ type (
 		Total         int       `json:"total"`          // total number of tasks, negative if unknown
 		AllDispatched bool      `json:"all_dispatched"` // if true, dispatcher has already scheduled all tasks for given job
 		Aborted       bool      `json:"aborted"`
+		Paused        bool      `json:"paused"`
 		StartedTime   time.Time `json:"started_time"`
 		FinishedTime  time.Time `json:"finished_time"`
+		ManifestLines int64     `json:"manifest_lines,omitempty"` // manifest lines processed (manifest download only)
 	}
 
 	DlJobInfos []*DlJobInfo
@@ -74,6 +86,11 @@ func (j *DlJobInfo) Aggregate(rhs *DlJobInfo) {
 	j.Total += rhs.Total
 	j.AllDispatched = j.AllDispatched && rhs.AllDispatched
 	j.Aborted = j.Aborted || rhs.Aborted
+	j.Paused = j.Paused || rhs.Paused
+	// Every target reports the manifest line up to which it has received its rows - report the slowest one.
+	if j.ManifestLines > rhs.ManifestLines {
+		j.ManifestLines = rhs.ManifestLines
+	}
 	if j.StartedTime.After(rhs.StartedTime) {
 		j.StartedTime = rhs.StartedTime
 	}
@@ -147,6 +164,8 @@ func (j DlJobInfo) String() string {
 
 	if j.JobFinished() {
 		sb.WriteString("finished")
+	} else if j.Paused {
+		sb.WriteString(fmt.Sprintf("paused (%d files still to be downloaded)", j.PendingCnt()))
 	} else {
 		sb.WriteString(fmt.Sprintf("%d files still being downloaded", j.PendingCnt()))
 	}
@@ -199,6 +218,11 @@ type DlBase struct {
 	Timeout          string   `json:"timeout"`
 	ProgressInterval string   `json:"progress_interval"`
 	Limits           DlLimits `json:"limits"`
+	// Objects of higher priority jobs are downloaded first; the priority is
+	// also the weight of the job in the cluster-wide download bandwidth
+	// (`downloader.bandwidth`) shared by the concurrently running jobs:
+	// [DlPriorityMin, DlPriorityMax]; 0 - DlPriorityDefault.
+	Priority int `json:"priority,omitempty"`
 }
 
 func (b *DlBase) Validate() error {
@@ -216,6 +240,9 @@ func (b *DlBase) Validate() error {
 	if b.Limits.BytesPerHour < 0 {
 		return fmt.Errorf("'limit.bytes_per_hour' must be non-negative (got: %d)", b.Limits.BytesPerHour)
 	}
+	if b.Priority != 0 && (b.Priority < DlPriorityMin || b.Priority > DlPriorityMax) {
+		return fmt.Errorf("'priority' must be in the range [%d, %d] (got: %d)", DlPriorityMin, DlPriorityMax, b.Priority)
+	}
 	return nil
 }
 
@@ -242,7 +269,7 @@ func (b *DlSingleObj) Validate() error {
 	return nil
 }
 
-// Internal status/delete request body
+// Internal status/delete/pause/resume request body
 type DlAdminBody struct {
 	ID              string `json:"id"`
 	Regex           string `json:"regex"`
@@ -315,11 +342,11 @@ func (b *DlSingleBody) Describe() string {
 	if b.Description != "" {
 		return b.Description
 	}
-	return fmt.Sprintf("%s -> %s/%s", b.Link, b.Bck, b.ObjName)
+	return fmt.Sprintf("%s -> %s/%s", redactLink(b.Link), b.Bck, b.ObjName)
 }
 
 func (b *DlSingleBody) String() string {
-	return fmt.Sprintf("Link: %q, Bucket: %q, ObjName: %q.", b.Link, b.Bck, b.ObjName)
+	return fmt.Sprintf("Link: %q, Bucket: %q, ObjName: %q.", redactLink(b.Link), b.Bck, b.ObjName)
 }
 
 // Range request
@@ -386,7 +413,7 @@ func (b *DlMultiBody) ExtractPayload() (cmn.SimpleKVs, error) {
 				objName := path.Base(link)
 				if objName == "." || objName == "/" {
 					// should we continue and let the use worry about this after?
-					return nil, fmt.Errorf("can not extract a valid `object_name` from the provided download 'link': %q", link)
+					return nil, fmt.Errorf("can not extract a valid `object_name` from the provided download 'link': %q", redactLink(link))
 				}
 				objects[objName] = link
 			default:
@@ -431,3 +458,49 @@ func (b *DlCloudBody) Describe() string {
 	}
 	return fmt.Sprintf("cloud prefetch -> %s", b.Bck)
 }
+
+// Manifest request
+type DlManifestBody struct {
+	DlBase
+	ManifestBck cmn.Bck `json:"manifest_bucket"` // defaults to the destination bucket
+	Manifest    string  `json:"manifest"`        // name of the manifest object
+	Format      string  `json:"format"`          // csv or jsonl (by default, as per the manifest's extension)
+}
+
+func (b *DlManifestBody) Validate() error {
+	if err := b.DlBase.Validate(); err != nil {
+		return err
+	}
+	if b.Manifest == "" {
+		return errors.New("missing 'manifest' in the request body")
+	}
+	if b.ManifestBck.Name == "" {
+		b.ManifestBck = b.Bck
+	}
+	if b.Format == "" {
+		switch path.Ext(b.Manifest) {
+		case ".csv":
+			b.Format = ManifestFormatCSV
+		case ".jsonl", ".ndjson":
+			b.Format = ManifestFormatJSONL
+		default:
+			return fmt.Errorf("cannot determine the format of manifest %q - specify 'format' (%q or %q)",
+				b.Manifest, ManifestFormatCSV, ManifestFormatJSONL)
+		}
+	}
+	if b.Format != ManifestFormatCSV && b.Format != ManifestFormatJSONL {
+		return fmt.Errorf("invalid manifest format %q (expecting %q or %q)", b.Format, ManifestFormatCSV, ManifestFormatJSONL)
+	}
+	return nil
+}
+
+func (b *DlManifestBody) Describe() string {
+	if b.Description != "" {
+		return b.Description
+	}
+	return fmt.Sprintf("%s/%s -> %s", b.ManifestBck, b.Manifest, b.Bck)
+}
+
+func (b *DlManifestBody) String() string {
+	return fmt.Sprintf("bucket: %q, manifest: %q/%q", b.Bck, b.ManifestBck, b.Manifest)
+}
@@ -46,12 +46,18 @@ type (
 	WebResource struct {
 		ObjName string
 		Link    string
+
+		size  int64     // expected size, if known
+		cksum *srcCksum // expected checksum, if known
 	}
 
 	DstElement struct {
 		ObjName string
 		Version string
 		Link    string
+
+		size  int64
+		cksum *srcCksum
 	}
 
 	DiffResolverResult struct {
@@ -167,6 +173,8 @@ func (dr *DiffResolver) PushDst(v interface{}) {
 		d = &DstElement{
 			ObjName: x.ObjName,
 			Link:    x.Link,
+			size:    x.size,
+			cksum:   x.cksum,
 		}
 	default:
 		cmn.Assertf(false, "%T", x)
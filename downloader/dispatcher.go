@@ -27,12 +27,18 @@ type (
 	dispatcher struct {
 		parent *Downloader
 
-		joggers  map[string]*jogger     // mpath -> jogger
-		abortJob map[string]*cmn.StopCh // jobID -> abort job chan
+		joggers  map[string]*jogger             // mpath -> jogger
+		abortJob map[string]*cmn.StopCh         // jobID -> abort job chan
+		jobs     map[string]DlJob               // jobID -> job being dispatched
+		parked   map[string][]*singleObjectTask // jobID -> tasks put aside while paused
 
 		adminCh            chan *request
 		dispatchDownloadCh chan DlJob
 
+		// Number of concurrent job dispatches - it basically limits the number
+		// of goroutines so they won't go out of hand.
+		sema *cmn.DynSemaphore
+
 		stopCh *cmn.StopCh
 		sync.RWMutex
 	}
@@ -48,17 +54,15 @@ func newDispatcher(parent *Downloader) *dispatcher {
 
 		stopCh:   cmn.NewStopCh(),
 		abortJob: make(map[string]*cmn.StopCh, jobsChSize),
+		jobs:     make(map[string]DlJob, jobsChSize),
+		parked:   make(map[string][]*singleObjectTask),
 		adminCh:  make(chan *request),
 	}
 }
 
 func (d *dispatcher) run() (err error) {
-	var (
-		// Number of concurrent job dispatches - it basically limits the number
-		// of goroutines so they won't go out of hand.
-		sema       = cmn.NewDynSemaphore(5 * fs.NumAvail())
-		group, ctx = errgroup.WithContext(context.Background())
-	)
+	group, ctx := errgroup.WithContext(context.Background())
+	d.sema = cmn.NewDynSemaphore(5 * fs.NumAvail())
 
 	availablePaths, _ := fs.Get()
 	for mpath := range availablePaths {
@@ -84,6 +88,10 @@ Loop:
 				d.dispatchRemove(req)
 			case actList:
 				d.dispatchList(req)
+			case actPause:
+				d.dispatchPause(req)
+			case actResume:
+				d.dispatchResume(req)
 			default:
 				cmn.Assertf(false, "%v; %v", req, req.action)
 			}
@@ -95,11 +103,14 @@ Loop:
 			// may not saturate the full downloader throughput).
 			d.Lock()
 			d.abortJob[job.ID()] = cmn.NewStopCh()
+			d.jobs[job.ID()] = job
 			d.Unlock()
 
-			sema.Acquire()
+			// NOTE: acquiring in the goroutine so that the loop keeps serving
+			//  admin requests (e.g., resume) when all dispatches are busy.
 			group.Go(func() error {
-				defer sema.Release()
+				d.sema.Acquire()
+				defer d.sema.Release()
 				if !d.dispatchDownload(job) {
 					return cmn.NewAbortedError("dispatcher")
 				}
@@ -141,6 +152,7 @@ func (d *dispatcher) cleanUpAborted(jobID string) {
 		ch.Close()
 		delete(d.abortJob, jobID)
 	}
+	delete(d.jobs, jobID)
 	d.Unlock()
 }
 
@@ -241,6 +253,8 @@ func (d *dispatcher) dispatchDownload(job DlJob) (ok bool) {
 					diffResolver.PushDst(&WebResource{
 						ObjName: obj.objName,
 						Link:    obj.link,
+						size:    obj.size,
+						cksum:   obj.cksum,
 					})
 				} else {
 					diffResolver.PushDst(&CloudResource{
@@ -264,6 +278,8 @@ func (d *dispatcher) dispatchDownload(job DlJob) (ok bool) {
 					objName:   dst.ObjName,
 					link:      dst.Link,
 					fromCloud: dst.Link == "",
+					size:      dst.size,
+					cksum:     dst.cksum,
 				}
 			} else {
 				src := result.Src
@@ -303,7 +319,10 @@ func (d *dispatcher) dispatchDownload(job DlJob) (ok bool) {
 				continue
 			}
 
-			err, ok := d.blockingDispatchDownloadSingle(t)
+			err, ok := d.waitResumed(job)
+			if err == nil && ok {
+				err, ok = d.blockingDispatchDownloadSingle(t)
+			}
 			if err != nil {
 				glog.Errorf("Download job %q failed, couldn't download object %q, aborting; err: %s", job.ID(), obj.objName, err.Error())
 				dlStore.setAborted(job.ID())
@@ -395,6 +414,71 @@ func (d *dispatcher) blockingDispatchDownloadSingle(task *singleObjectTask) (err
 	}
 }
 
+// waitResumed blocks while the job is paused and then dispatches (again) the
+// tasks that the joggers have put aside in the meantime (see jogger.park).
+// Parked tasks remain pending until they are taken back, so that the downloader
+// does not time out while the job is paused.
+func (d *dispatcher) waitResumed(job DlJob) (err error, ok bool) {
+	if ch := job.throttler().pausedCh(); ch != nil {
+		// a paused job must not hold the dispatch slot (see `run`)
+		d.sema.Release()
+		select {
+		case <-ch:
+		case <-d.jobAbortedCh(job.ID()).Listen():
+			d.sema.Acquire()
+			for _, t := range d.unpark(job.ID()) {
+				t.discardPart()
+				d.parent.DecPending()
+			}
+			return nil, true
+		case <-d.stopCh.Listen():
+			d.sema.Acquire()
+			for _, t := range d.unpark(job.ID()) {
+				t.discardPart()
+				t.markFailed(internalErrorMsg)
+				d.parent.DecPending()
+			}
+			return nil, false
+		}
+		d.sema.Acquire()
+	}
+	tasks := d.unpark(job.ID())
+	for i, t := range tasks {
+		err, ok = d.blockingDispatchDownloadSingle(t)
+		d.parent.DecPending() // (the queue counts the task again)
+		if err == nil && ok {
+			continue
+		}
+		for _, t := range tasks[i+1:] {
+			t.discardPart()
+			t.markFailed(internalErrorMsg)
+			d.parent.DecPending()
+		}
+		return
+	}
+	return nil, true
+}
+
+func (d *dispatcher) park(t *singleObjectTask) {
+	d.Lock()
+	d.parked[t.id()] = append(d.parked[t.id()], t)
+	d.Unlock()
+}
+
+func (d *dispatcher) unpark(jobID string) (tasks []*singleObjectTask) {
+	d.Lock()
+	tasks = d.parked[jobID]
+	delete(d.parked, jobID)
+	d.Unlock()
+	return
+}
+
+func (d *dispatcher) hasParked(jobID string) bool {
+	d.RLock()
+	defer d.RUnlock()
+	return len(d.parked[jobID]) > 0
+}
+
 func (d *dispatcher) dispatchRemove(req *request) {
 	jInfo, err := d.parent.checkJob(req)
 	if err != nil {
@@ -408,6 +492,7 @@ func (d *dispatcher) dispatchRemove(req *request) {
 		return
 	}
 
+	abortSplit(req.id)
 	dlStore.delJob(req.id)
 	req.writeResp(nil)
 }
@@ -419,6 +504,7 @@ func (d *dispatcher) dispatchAbort(req *request) {
 	}
 
 	d.jobAbortedCh(req.id).Close()
+	abortSplit(req.id)
 
 	for _, j := range d.joggers {
 		j.abortJob(req.id)
@@ -428,6 +514,36 @@ func (d *dispatcher) dispatchAbort(req *request) {
 	req.writeResp(nil)
 }
 
+func (d *dispatcher) dispatchPause(req *request) {
+	if _, err := d.parent.checkJob(req); err != nil {
+		return
+	}
+	d.RLock()
+	job, ok := d.jobs[req.id]
+	d.RUnlock()
+	// NOTE: the job that is not being dispatched (i.e., finished or aborted) is
+	//  ignored - other targets may still have objects to download.
+	if ok && job.throttler().pause() {
+		dlStore.setPaused(req.id, true)
+		glog.Infof("Download job %q paused", req.id)
+	}
+	req.writeResp(nil)
+}
+
+func (d *dispatcher) dispatchResume(req *request) {
+	if _, err := d.parent.checkJob(req); err != nil {
+		return
+	}
+	d.RLock()
+	job, ok := d.jobs[req.id]
+	d.RUnlock()
+	if ok && job.throttler().resume() {
+		dlStore.setPaused(req.id, false)
+		glog.Infof("Download job %q resumed", req.id)
+	}
+	req.writeResp(nil)
+}
+
 func (d *dispatcher) dispatchStatus(req *request) {
 	var (
 		finishedTasks []TaskDlInfo
@@ -504,6 +620,10 @@ func (d *dispatcher) pending(reqID string) bool {
 // PRECONDITION: All tasks should be dispatched.
 func (d *dispatcher) waitFor(job DlJob) {
 	for ; ; time.Sleep(time.Second) {
+		if d.hasParked(job.ID()) {
+			d.waitResumed(job)
+			continue
+		}
 		if !d.pending(job.ID()) {
 			break
 		}
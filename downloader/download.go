@@ -38,6 +38,8 @@ import (
 //   * Download    - to download a new object from a URL
 //   * Abort       - to abort a previously requested download (currently queued or currently downloading)
 //   * Status      - to request the status of a previously requested download
+//   * Pause       - to pause a download (its tasks are put aside until resumed)
+//   * Resume      - to resume a paused download
 // The Download, Abort and Status requests are encapsulated into an internal
 // request object, added to a dispatcher's request queue and then are dispatched by dispatcher
 // to the correct jogger. The remaining operations are private to the Downloader and
@@ -112,6 +114,8 @@ const (
 	actAbort  = "ABORT"
 	actStatus = "STATUS"
 	actList   = "LIST"
+	actPause  = "PAUSE"
+	actResume = "RESUME"
 
 	jobsChSize = 1000
 )
@@ -155,7 +159,7 @@ type (
 	// objects are used by Downloader to process the request, and are then
 	// dispatched to the correct jogger to be handled.
 	request struct {
-		action     string         // one of: adminAbort, adminList, adminStatus, adminRemove
+		action     string         // one of: adminAbort, adminList, adminStatus, adminRemove, adminPause, adminResume
 		id         string         // id of the job task
 		regex      *regexp.Regexp // regex of descriptions to return if id is empty
 		responseCh chan *response // where the outcome of the request is written
@@ -322,6 +326,36 @@ func (d *Downloader) RemoveJob(id string) (resp interface{}, err error, statusCo
 	return r.resp, r.err, r.statusCode
 }
 
+func (d *Downloader) PauseJob(id string) (resp interface{}, err error, statusCode int) {
+	d.IncPending()
+	defer d.DecPending()
+	req := &request{
+		action:     actPause,
+		id:         id,
+		responseCh: make(chan *response, 1),
+	}
+	d.dispatcher.adminCh <- req
+
+	// await the response
+	r := <-req.responseCh
+	return r.resp, r.err, r.statusCode
+}
+
+func (d *Downloader) ResumeJob(id string) (resp interface{}, err error, statusCode int) {
+	d.IncPending()
+	defer d.DecPending()
+	req := &request{
+		action:     actResume,
+		id:         id,
+		responseCh: make(chan *response, 1),
+	}
+	d.dispatcher.adminCh <- req
+
+	// await the response
+	r := <-req.responseCh
+	return r.resp, r.err, r.statusCode
+}
+
 func (d *Downloader) JobStatus(id string, onlyActive bool) (resp interface{}, err error, statusCode int) {
 	d.IncPending()
 	defer d.DecPending()
@@ -115,10 +115,17 @@ func (is *infoStore) setAllDispatched(id string, dispatched bool) {
 	jInfo.AllDispatched.Store(dispatched)
 }
 
+func (is *infoStore) setManifestLines(id string, lines int64) {
+	jInfo, err := is.getJob(id)
+	cmn.AssertNoErr(err)
+	jInfo.ManifestLines.Store(lines)
+}
+
 func (is *infoStore) markFinished(id string) {
 	jInfo, err := is.getJob(id)
 	cmn.AssertNoErr(err)
 	jInfo.FinishedTime.Store(time.Now())
+	jInfo.Paused.Store(false)
 	cmn.Assert(jInfo.valid())
 }
 
@@ -131,6 +138,12 @@ func (is *infoStore) setAborted(id string) {
 	//       that all tasks have been stopped and all resources were freed.
 }
 
+func (is *infoStore) setPaused(id string, paused bool) {
+	jInfo, err := is.getJob(id)
+	cmn.AssertNoErr(err)
+	jInfo.Paused.Store(paused)
+}
+
 func (is *infoStore) delJob(id string) {
 	delete(is.jobInfo, id)
 	is.downloaderDB.delete(id)
@@ -5,10 +5,17 @@
 package downloader
 
 import (
+	"bufio"
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
 	"path"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/atomic"
@@ -26,6 +33,7 @@ var (
 	_ DlJob = &sliceDlJob{}
 	_ DlJob = &cloudBucketDlJob{}
 	_ DlJob = &rangeDlJob{}
+	_ DlJob = &manifestDlJob{}
 )
 
 var errAISBckReq = errors.New("regular download requires ais bucket")
@@ -35,6 +43,8 @@ type (
 		objName   string
 		link      string
 		fromCloud bool
+		size      int64     // expected size (0 if unknown)
+		cksum     *srcCksum // expected checksum (nil if unknown)
 	}
 
 	DlJob interface {
@@ -115,6 +125,23 @@ type (
 		continuationToken string
 	}
 
+	manifestDlJob struct {
+		baseDlJob
+		t   cluster.Target
+		ctx context.Context
+
+		mbck     *cluster.Bck // bucket of the manifest
+		manifest string       // name of the manifest object
+
+		mtx    sync.Mutex
+		r      io.ReadCloser // manifest rows of this target (opened on first genNext)
+		rows   *bufio.Reader
+		closed bool
+
+		done bool
+		objs []dlObj // objects' metas which are ready to be downloaded
+	}
+
 	downloadJobInfo struct {
 		ID          string `json:"id"`
 		Description string `json:"description"`
@@ -126,10 +153,13 @@ type (
 		Total        int          `json:"total"`
 
 		Aborted       atomic.Bool `json:"aborted"`
+		Paused        atomic.Bool `json:"paused"`
 		AllDispatched atomic.Bool `json:"all_dispatched"`
 
 		StartedTime  time.Time   `json:"started_time"`
 		FinishedTime atomic.Time `json:"finished_time"`
+
+		ManifestLines atomic.Int64 `json:"manifest_lines"`
 	}
 )
 
@@ -170,7 +200,7 @@ func (j *baseDlJob) cleanup() {
 	nl.OnFinished(j.Notif(), nil)
 }
 
-func newBaseDlJob(t cluster.Target, id string, bck *cluster.Bck, timeout, desc string, limits DlLimits, dlXact *Downloader) *baseDlJob {
+func newBaseDlJob(t cluster.Target, id string, bck *cluster.Bck, timeout, desc string, limits DlLimits, priority int, dlXact *Downloader) *baseDlJob {
 	// TODO: this might be inaccurate if we download 1 or 2 objects because then
 	//  other targets will have limits but will not use them.
 	if limits.BytesPerHour > 0 {
@@ -183,7 +213,7 @@ func newBaseDlJob(t cluster.Target, id string, bck *cluster.Bck, timeout, desc s
 		bck:         bck,
 		timeout:     td,
 		description: desc,
-		t:           newThrottler(limits),
+		t:           newThrottler(t.Sowner(), limits, priority),
 		dlXact:      dlXact,
 	}
 }
@@ -213,7 +243,7 @@ func newMultiDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlMul
 		objs cmn.SimpleKVs
 		err  error
 	)
-	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits, dlXact)
+	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits, payload.Priority, dlXact)
 	if objs, err = payload.ExtractPayload(); err != nil {
 		return nil, err
 	}
@@ -233,7 +263,7 @@ func newSingleDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlSi
 		objs cmn.SimpleKVs
 		err  error
 	)
-	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits, dlXact)
+	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits, payload.Priority, dlXact)
 	if objs, err = payload.ExtractPayload(); err != nil {
 		return nil, err
 	}
@@ -354,7 +384,7 @@ func newCloudBucketDlJob(ctx context.Context, t cluster.Target, id string, bck *
 	if !bck.IsCloud() {
 		return nil, errors.New("bucket download requires a cloud bucket")
 	}
-	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits, dlXact)
+	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits, payload.Priority, dlXact)
 	job := &cloudBucketDlJob{
 		baseDlJob: *base,
 		t:         t,
@@ -407,7 +437,7 @@ func newRangeDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlRan
 		return nil, err
 	}
 
-	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits, dlXact)
+	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits, payload.Priority, dlXact)
 	cnt, err := countObjects(t, pt, payload.Subdir, base.bck)
 	if err != nil {
 		return nil, err
@@ -422,6 +452,213 @@ func newRangeDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlRan
 	return job, nil
 }
 
+func newManifestDlJob(ctx context.Context, t cluster.Target, id string, bck *cluster.Bck, payload *DlManifestBody, dlXact *Downloader) (*manifestDlJob, error) {
+	if !bck.IsAIS() {
+		return nil, errAISBckReq
+	}
+	mbck := cluster.NewBckEmbed(payload.ManifestBck)
+	if err := mbck.Init(t.Bowner(), t.Snode()); err != nil {
+		return nil, err
+	}
+	if err := mbck.Allow(cmn.AccessGET); err != nil {
+		return nil, err
+	}
+	lom := &cluster.LOM{T: t, ObjName: payload.Manifest}
+	if err := lom.Init(mbck.Bck); err != nil {
+		return nil, err
+	}
+	si, err := cluster.HrwTarget(lom.Uname(), t.Sowner().Get())
+	if err != nil {
+		return nil, err
+	}
+	// Fail early (rather than in the dispatcher) if there's no manifest.
+	var exists bool
+	if si.ID() == t.Snode().ID() {
+		exists = lom.Load() == nil
+	} else if exists, err = t.HeadObjT2T(lom, si); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, cmn.NewNotFoundError("manifest %s", lom)
+	}
+
+	// The target that stores the manifest splits it for all targets.
+	if si.ID() == t.Snode().ID() {
+		if err := startManifestSplit(t, id, bck, lom, payload.Format); err != nil {
+			return nil, err
+		}
+	}
+
+	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits, payload.Priority, dlXact)
+	job := &manifestDlJob{
+		baseDlJob: *base,
+		t:         t,
+		ctx:       ctx,
+		mbck:      mbck,
+		manifest:  payload.Manifest,
+	}
+	return job, nil
+}
+
+// Every target downloads the objects of the manifest it is responsible for
+// as the manifest is being read, so the number of those is not known upfront.
+func (j *manifestDlJob) Len() int { return -1 }
+
+func (j *manifestDlJob) genNext() ([]dlObj, bool, error) {
+	if j.done {
+		return nil, false, nil
+	}
+	if err := j.getNextObjs(); err != nil {
+		return nil, false, err
+	}
+	return j.objs, true, nil
+}
+
+// Reads the manifest rows of this target until enough objects to download
+// are found or the manifest is over.
+func (j *manifestDlJob) getNextObjs() error {
+	var (
+		smap = j.t.Sowner().Get()
+		sid  = j.t.Snode().ID()
+	)
+	if j.rows == nil {
+		if err := j.open(); err != nil {
+			return err
+		}
+	}
+	j.objs = j.objs[:0]
+	for len(j.objs) < downloadBatchSize {
+		row, err := readSpoolRow(j.rows)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF // the rows always end with `End`
+			}
+			return fmt.Errorf("failed to read manifest %s rows: %w", j.manifest, err)
+		}
+		if row.Err != "" {
+			j.reportRow(&errManifestRow{line: row.Line, err: errors.New(row.Err)})
+			continue
+		}
+		if row.Link == "" {
+			dlStore.setManifestLines(j.ID(), row.Line)
+			if row.End {
+				j.done = true
+				break
+			}
+			continue
+		}
+		if err := row.validate(); err != nil {
+			j.reportRow(&errManifestRow{line: row.Line, err: err})
+			continue
+		}
+		obj, err := makeDlObj(smap, sid, j.bck, row.ObjName, row.Link)
+		if err != nil {
+			// NOTE: the rows are split by the cluster map at the start of the
+			//  job; objects that have moved to another target are skipped.
+			if err != errInvalidTarget {
+				j.reportRow(&errManifestRow{line: row.Line, err: err})
+			}
+			continue
+		}
+		obj.size, obj.cksum = row.Size, row.cksum
+		j.objs = append(j.objs, obj)
+	}
+	return nil
+}
+
+// open opens the manifest rows of this target - locally, if this target
+// splits the manifest, or via the target that does.
+func (j *manifestDlJob) open() (err error) {
+	var (
+		r   io.ReadCloser
+		lom = &cluster.LOM{T: j.t, ObjName: j.manifest}
+		sid = j.t.Snode().ID()
+	)
+	if err = lom.Init(j.mbck.Bck); err != nil {
+		return
+	}
+	si, err := cluster.HrwTarget(lom.Uname(), j.t.Sowner().Get())
+	if err != nil {
+		return
+	}
+	if si.ID() == sid {
+		r, err = ManifestRows(j.ID(), sid)
+	} else {
+		r, err = j.openRows(si)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open manifest %s: %w", lom, err)
+	}
+
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	if j.closed {
+		cmn.Close(r)
+		return cmn.NewAbortedError("manifest download " + j.ID())
+	}
+	j.r = r
+	j.rows = bufio.NewReaderSize(r, 16*cmn.KiB)
+	return nil
+}
+
+// openRows requests the rows of this target from the target that splits the
+// manifest - waiting for it to start, if need be.
+func (j *manifestDlJob) openRows(si *cluster.Snode) (io.ReadCloser, error) {
+	var (
+		query  = make(url.Values)
+		header = make(http.Header)
+	)
+	query.Set(cmn.URLParamUUID, j.ID())
+	query.Set(cmn.URLParamTargetID, j.t.Snode().ID())
+	header.Set(cmn.HeaderCallerID, j.t.Snode().ID())
+	reqArgs := cmn.ReqArgs{
+		Method: http.MethodGet,
+		Base:   si.URL(cmn.NetworkIntraData),
+		Path:   cmn.JoinWords(cmn.Version, cmn.Download, cmn.Records),
+		Query:  query,
+		Header: header,
+	}
+	for deadline := time.Now().Add(splitWaitTime); ; {
+		req, err := reqArgs.Req()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := clientForURL(reqArgs.Base).Do(req.WithContext(j.ctx)) // nolint:bodyclose // closed by the caller
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode < http.StatusBadRequest {
+			return resp.Body, nil
+		}
+		b, _ := ioutil.ReadAll(resp.Body)
+		cmn.Close(resp.Body)
+		if resp.StatusCode != http.StatusNotFound || time.Now().After(deadline) {
+			httpErr, _ := cmn.NewHTTPError(req, string(b), resp.StatusCode)
+			return nil, httpErr
+		}
+		time.Sleep(splitWaitTime / 50)
+	}
+}
+
+// reportRow records malformed manifest row as a (failed) download task;
+// malformed rows are received only by the target that splits the manifest,
+// so that each is recorded once.
+func (j *manifestDlJob) reportRow(err *errManifestRow) {
+	dlStore.incScheduled(j.ID())
+	dlStore.persistError(j.ID(), fmt.Sprintf("%s:%d", j.manifest, err.line), err.err.Error())
+	dlStore.incErrorCnt(j.ID())
+}
+
+func (j *manifestDlJob) cleanup() {
+	j.mtx.Lock()
+	if j.r != nil {
+		cmn.Close(j.r)
+	}
+	j.closed = true
+	j.mtx.Unlock()
+	j.baseDlJob.cleanup()
+}
+
 func (d *downloadJobInfo) ToDlJobInfo() DlJobInfo {
 	return DlJobInfo{
 		ID:            d.ID,
@@ -433,8 +670,10 @@ func (d *downloadJobInfo) ToDlJobInfo() DlJobInfo {
 		Total:         d.Total,
 		AllDispatched: d.AllDispatched.Load(),
 		Aborted:       d.Aborted.Load(),
+		Paused:        d.Paused.Load(),
 		StartedTime:   d.StartedTime,
 		FinishedTime:  d.FinishedTime.Load(),
+		ManifestLines: d.ManifestLines.Load(),
 	}
 }
 
@@ -5,6 +5,7 @@
 package downloader
 
 import (
+	"container/heap"
 	"context"
 	"sync"
 
@@ -21,7 +22,18 @@ type (
 		sync.RWMutex
 		ch chan *singleObjectTask // for pending downloads
 		m  map[string]queueEntry  // jobID -> set of request uid
+
+		// accessed only by the jogger
+		pq  taskHeap // tasks taken from `ch`, ordered by job priority
+		seq int64
+	}
+
+	prioTask struct {
+		t    *singleObjectTask
+		prio int
+		seq  int64 // FIFO within the same priority
 	}
+	taskHeap []prioTask
 
 	// Each jogger corresponds to an mpath. All types of download requests
 	// corresponding to the jogger's mpath are forwarded to the jogger. Joggers
@@ -74,17 +86,27 @@ func (j *jogger) jog() {
 			j.mtx.Unlock()
 			continue
 		}
+		if t.job.throttler().paused() {
+			t.job.throttler().release()
+			j.mtx.Unlock()
+			j.park(t)
+			continue
+		}
 		j.task = t
 		j.mtx.Unlock()
 
-		t.download()
+		paused := t.download()
 		t.job.throttler().release()
 
 		j.mtx.Lock()
-		j.task.persist()
+		if !paused {
+			j.task.persist()
+		}
 		j.task = nil
 		j.mtx.Unlock()
-		if exists := j.q.delete(t); exists {
+		if paused {
+			j.park(t)
+		} else if exists := j.q.delete(t); exists {
 			j.parent.parent.DecPending()
 		}
 	}
@@ -93,6 +115,16 @@ func (j *jogger) jog() {
 	j.terminateCh.Close()
 }
 
+// park puts aside the task of the paused job so that the jogger can proceed
+// with other jobs; the dispatcher takes it back upon resume (see dispatcher.waitResumed).
+// NOTE: the task remains pending until then.
+func (j *jogger) park(t *singleObjectTask) {
+	j.parent.park(t) // NOTE: before removing from the queue (see dispatcher.waitFor)
+	if exists := j.q.delete(t); !exists {
+		j.parent.parent.IncPending()
+	}
+}
+
 // stop terminates the jogger and waits for it to finish.
 func (j *jogger) stop() {
 	glog.Infof("Stopping jogger for mpath: %s", j.mpath)
@@ -168,8 +200,8 @@ func (q *queue) putCh(t *singleObjectTask) (ok bool, ch chan<- *singleObjectTask
 
 // Get tries to find first task which was not yet Aborted
 func (q *queue) get() (foundTask *singleObjectTask, skip bool) {
-	t, ok := <-q.ch
-	if !ok {
+	t := q.next()
+	if t == nil {
 		return nil, false
 	}
 
@@ -190,6 +222,37 @@ func (q *queue) get() (foundTask *singleObjectTask, skip bool) {
 	return t, false
 }
 
+// next returns the task of the highest priority job among the ones that
+// have been queued so far; tasks of the same priority are served in FIFO order.
+func (q *queue) next() *singleObjectTask {
+	if q.pq.Len() == 0 {
+		t, ok := <-q.ch
+		if !ok {
+			return nil
+		}
+		q.push(t)
+	}
+	// NOTE: `ch` capacity still bounds the number of tasks put aside
+Drain:
+	for q.pq.Len() < queueChSize {
+		select {
+		case t, ok := <-q.ch:
+			if !ok {
+				break Drain
+			}
+			q.push(t)
+		default:
+			break Drain
+		}
+	}
+	return heap.Pop(&q.pq).(prioTask).t
+}
+
+func (q *queue) push(t *singleObjectTask) {
+	q.seq++
+	heap.Push(&q.pq, prioTask{t: t, prio: t.job.throttler().priority, seq: q.seq})
+}
+
 func (q *queue) delete(t *singleObjectTask) bool {
 	q.Lock()
 	exists := q.exists(t.id(), t.uid())
@@ -273,3 +336,25 @@ func (q *queue) close() {
 		close(q.ch)
 	}
 }
+
+//////////////
+// taskHeap //
+//////////////
+
+func (h taskHeap) Len() int { return len(h) }
+func (h taskHeap) Less(i, j int) bool {
+	if h[i].prio != h[j].prio {
+		return h[i].prio > h[j].prio
+	}
+	return h[i].seq < h[j].seq
+}
+func (h taskHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *taskHeap) Push(x interface{}) { *h = append(*h, x.(prioTask)) }
+func (h *taskHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = prioTask{}
+	*h = old[:n-1]
+	return x
+}
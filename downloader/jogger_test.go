@@ -0,0 +1,59 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestQueuePriority(t *testing.T) {
+	var (
+		q    = newQueue()
+		bck  = cluster.NewBck("bck", cmn.ProviderAIS, cmn.NsGlobal)
+		jobs = make(map[int]DlJob)
+	)
+	for _, prio := range []int{DlPriorityMin, DlPriorityDefault, DlPriorityMax} {
+		jobs[prio] = &sliceDlJob{baseDlJob: baseDlJob{
+			id:  fmt.Sprintf("job-%d", prio),
+			bck: bck,
+			t:   newThrottler(nil, DlLimits{}, prio),
+		}}
+	}
+	put := func(prio, i int) {
+		task := &singleObjectTask{job: jobs[prio], obj: dlObj{objName: fmt.Sprintf("%d-%d", prio, i)}}
+		ok, ch := q.putCh(task)
+		tassert.Fatalf(t, ok, "expected task %s to be queued", task.obj.objName)
+		ch <- task
+	}
+	for i := 0; i < 3; i++ {
+		put(DlPriorityMin, i)
+		put(DlPriorityDefault, i)
+	}
+	put(DlPriorityMax, 0)
+
+	expected := []string{"10-0", "5-0", "5-1", "5-2", "1-0"}
+	for _, name := range expected {
+		task, skip := q.get()
+		tassert.Fatalf(t, task != nil && !skip, "expected task %s", name)
+		tassert.Errorf(t, task.obj.objName == name, "expected task %s, got %s", name, task.obj.objName)
+		q.delete(task)
+	}
+	// a task of higher priority job overtakes the ones that have been queued
+	put(DlPriorityMax, 1)
+	for _, name := range []string{"10-1", "1-1", "1-2"} {
+		task, _ := q.get()
+		tassert.Errorf(t, task.obj.objName == name, "expected task %s, got %s", name, task.obj.objName)
+		q.delete(task)
+	}
+
+	q.close()
+	task, _ := q.get()
+	tassert.Errorf(t, task == nil, "expected no tasks after close")
+}
@@ -0,0 +1,357 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// The manifest is read exactly once - by the target that stores it (the
+// holder). The holder splits the rows by the targets responsible for the
+// objects and spools them to per-target files (manifestSplit). Every target
+// then reads only its own rows: the holder - locally, other targets - via
+// GET /v1/download/records of the holder which tails the spool while it is
+// being written (see ManifestRows).
+
+const (
+	splitFlushLines = 1000             // flush the spools (and report progress) every so many manifest lines
+	splitWaitTime   = 10 * time.Second // how long a target waits for the holder to start splitting the manifest
+)
+
+var errSplitNotFound = errors.New("manifest rows not found")
+
+type (
+	// spoolRow is a single line of the spool: a manifest row, a malformed
+	// row (only in the holder's spool), or - when there's no link - the
+	// number of the manifest lines read so far.
+	spoolRow struct {
+		manifestRow
+		Line int64  `json:"line"`
+		Err  string `json:"error,omitempty"`
+		End  bool   `json:"end,omitempty"` // the manifest is over
+	}
+
+	manifestSplit struct {
+		id   string
+		smap *cluster.Smap
+		bck  *cluster.Bck
+		sid  string // the holder (receives malformed rows)
+
+		mtx     sync.Mutex
+		cond    *sync.Cond
+		spools  map[string]*spool // target ID => rows of the target
+		done    bool              // all rows spooled
+		err     error             // failed or aborted
+		aborted atomic.Bool
+	}
+
+	spool struct {
+		fqn      string
+		file     *os.File
+		w        *bufio.Writer
+		written  int64 // bytes written (by the split)
+		size     int64 // bytes flushed and available to the reader
+		consumed bool
+	}
+
+	// spoolReader reads the spool while it is being written.
+	spoolReader struct {
+		split *manifestSplit
+		sp    *spool
+		f     *os.File
+		off   int64
+	}
+)
+
+var splits = struct {
+	sync.Mutex
+	m map[string]*manifestSplit // job ID => split
+}{m: make(map[string]*manifestSplit)}
+
+func newManifestSplit(id string, smap *cluster.Smap, bck *cluster.Bck, sid string, spoolFQN func(tid string) string) (*manifestSplit, error) {
+	s := &manifestSplit{
+		id:     id,
+		smap:   smap,
+		bck:    bck,
+		sid:    sid,
+		spools: make(map[string]*spool, len(smap.Tmap)),
+	}
+	s.cond = sync.NewCond(&s.mtx)
+	for tid := range smap.Tmap {
+		sp := &spool{fqn: spoolFQN(tid)}
+		file, err := cmn.CreateFile(sp.fqn)
+		if err != nil {
+			for _, sp := range s.spools {
+				cmn.Close(sp.file)
+			}
+			s.abort(err)
+			return nil, err
+		}
+		sp.file, sp.w = file, bufio.NewWriter(file)
+		s.spools[tid] = sp
+	}
+	return s, nil
+}
+
+// startManifestSplit opens the manifest stored by this target and starts
+// splitting it for the job.
+func startManifestSplit(t cluster.Target, id string, bck *cluster.Bck, lom *cluster.LOM, format string) error {
+	var r io.ReadCloser
+	lom.Lock(false)
+	err := lom.Load()
+	if err == nil {
+		r, err = lom.Open()
+	}
+	lom.Unlock(false)
+	if err != nil {
+		return fmt.Errorf("failed to open manifest %s: %w", lom, err)
+	}
+	spoolFQN := func(tid string) string {
+		return fs.CSM.GenContentFQN(lom.FQN, fs.WorkfileType, fs.WorkfileDlRows+"-"+tid)
+	}
+	s, err := newManifestSplit(id, t.Sowner().Get(), bck, t.Snode().ID(), spoolFQN)
+	if err != nil {
+		cmn.Close(r)
+		return err
+	}
+	splits.Lock()
+	splits.m[id] = s
+	splits.Unlock()
+	go s.run(r, format)
+	return nil
+}
+
+// ManifestRows returns the manifest rows of the given target; the rows are
+// read while the manifest is being split, and the reader returns io.EOF only
+// when all of them are read.
+func ManifestRows(id, tid string) (io.ReadCloser, error) {
+	splits.Lock()
+	s, ok := splits.m[id]
+	splits.Unlock()
+	if !ok {
+		return nil, errSplitNotFound
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	sp, ok := s.spools[tid]
+	if !ok || sp.consumed {
+		return nil, errSplitNotFound
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	f, err := os.Open(sp.fqn)
+	if err != nil {
+		return nil, err
+	}
+	return &spoolReader{split: s, sp: sp, f: f}, nil
+}
+
+// abortSplit stops splitting the manifest (if this target does it) and
+// removes the spools.
+func abortSplit(id string) {
+	splits.Lock()
+	s, ok := splits.m[id]
+	splits.Unlock()
+	if ok {
+		s.abort(cmn.NewAbortedError("manifest download " + id))
+	}
+}
+
+func (s *manifestSplit) run(r io.ReadCloser, format string) {
+	err := s.split(newManifestReader(r, format))
+	cmn.Close(r)
+	for _, sp := range s.spools {
+		cmn.Close(sp.file)
+	}
+	if err != nil {
+		if !s.aborted.Load() {
+			glog.Errorf("manifest download %s: %v", s.id, err)
+		}
+		s.abort(err)
+		return
+	}
+	s.mtx.Lock()
+	s.done = true
+	s.cond.Broadcast()
+	s.mtx.Unlock()
+}
+
+func (s *manifestSplit) split(mr *manifestReader) error {
+	var flushed int64
+	for !s.aborted.Load() {
+		row, err := mr.next()
+		if err == io.EOF {
+			return s.flush(mr.line, true)
+		}
+		if err != nil {
+			rowErr := &errManifestRow{}
+			if !errors.As(err, &rowErr) {
+				return err
+			}
+			err = s.write(s.sid, &spoolRow{Line: rowErr.line, Err: rowErr.err.Error()})
+		} else {
+			err = s.route(row, mr.line)
+		}
+		if err != nil {
+			return err
+		}
+		if mr.line >= flushed+splitFlushLines {
+			if err := s.flush(mr.line, false); err != nil {
+				return err
+			}
+			flushed = mr.line
+		}
+	}
+	return nil
+}
+
+// route spools the row to the target responsible for its object.
+func (s *manifestSplit) route(row *manifestRow, line int64) error {
+	objName, err := normalizeObjName(row.ObjName)
+	if err != nil {
+		return s.write(s.sid, &spoolRow{Line: line, Err: err.Error()})
+	}
+	si, err := cluster.HrwTarget(s.bck.MakeUname(objName), s.smap)
+	if err != nil {
+		return err
+	}
+	row.ObjName = objName
+	return s.write(si.ID(), &spoolRow{manifestRow: *row, Line: line})
+}
+
+func (s *manifestSplit) write(tid string, row *spoolRow) error {
+	sp := s.spools[tid]
+	b, err := jsoniter.Marshal(row)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if _, err := sp.w.Write(b); err != nil {
+		return err
+	}
+	sp.written += int64(len(b))
+	return nil
+}
+
+// flush makes the rows spooled so far available to the readers, along with
+// the number of the manifest lines read.
+func (s *manifestSplit) flush(line int64, end bool) error {
+	for tid, sp := range s.spools {
+		if err := s.write(tid, &spoolRow{Line: line, End: end}); err != nil {
+			return err
+		}
+		if err := sp.w.Flush(); err != nil {
+			return err
+		}
+	}
+	s.mtx.Lock()
+	for _, sp := range s.spools {
+		sp.size = sp.written
+	}
+	s.cond.Broadcast()
+	s.mtx.Unlock()
+	return nil
+}
+
+func (s *manifestSplit) abort(err error) {
+	s.aborted.Store(true)
+	s.mtx.Lock()
+	if s.err == nil {
+		s.err = err
+	}
+	for _, sp := range s.spools {
+		if !sp.consumed {
+			sp.consumed = true
+			cmn.RemoveFile(sp.fqn)
+		}
+	}
+	s.cond.Broadcast()
+	s.mtx.Unlock()
+	s.unregister()
+}
+
+func (s *manifestSplit) unregister() {
+	splits.Lock()
+	if splits.m[s.id] == s {
+		delete(splits.m, s.id)
+	}
+	splits.Unlock()
+}
+
+// readSpoolRow reads the next row of the spool; returns io.EOF when there
+// are no more rows.
+func readSpoolRow(r *bufio.Reader) (*spoolRow, error) {
+	b, err := r.ReadBytes('\n')
+	if err == io.EOF && len(b) > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	row := &spoolRow{}
+	return row, jsoniter.Unmarshal(b, row)
+}
+
+func (r *spoolReader) Read(b []byte) (n int, err error) {
+	s := r.split
+	s.mtx.Lock()
+	for r.off >= r.sp.size && !s.done && s.err == nil {
+		s.cond.Wait()
+	}
+	size, err := r.sp.size, s.err
+	s.mtx.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	if r.off >= size {
+		return 0, io.EOF
+	}
+	if int64(len(b)) > size-r.off {
+		b = b[:size-r.off]
+	}
+	n, err = r.f.ReadAt(b, r.off)
+	r.off += int64(n)
+	if err == io.EOF && n == len(b) {
+		err = nil
+	}
+	return
+}
+
+// Close removes the spool once it's been read in its entirety (the rows
+// are not needed anymore); the split is over when all spools are read.
+func (r *spoolReader) Close() error {
+	err := r.f.Close()
+	s := r.split
+	s.mtx.Lock()
+	if !s.done || r.off < r.sp.size || r.sp.consumed {
+		s.mtx.Unlock()
+		return err
+	}
+	r.sp.consumed = true
+	cmn.RemoveFile(r.sp.fqn)
+	all := true
+	for _, sp := range s.spools {
+		all = all && sp.consumed
+	}
+	s.mtx.Unlock()
+	if all {
+		s.unregister()
+	}
+	return err
+}
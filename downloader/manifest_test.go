@@ -0,0 +1,137 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestManifestSplit(t *testing.T) {
+	const (
+		holder  = "t1"
+		numObjs = 3*splitFlushLines + 17
+	)
+	dir, err := ioutil.TempDir("", "manifest-split")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+
+	smap := &cluster.Smap{Tmap: make(cluster.NodeMap)}
+	for _, tid := range []string{holder, "t2", "t3"} {
+		smap.Tmap[tid] = &cluster.Snode{DaemonID: tid}
+		smap.Tmap[tid].Digest()
+	}
+	bck := cluster.NewBck("bck", cmn.ProviderAIS, cmn.NsGlobal)
+
+	var sb strings.Builder
+	sb.WriteString("link,object_name\n")
+	for i := 0; i < numObjs; i++ {
+		fmt.Fprintf(&sb, "http://example.com/%d.jpg,obj-%d\n", i, i)
+	}
+	sb.WriteString(",malformed\n")
+	manifest := sb.String()
+	lines := int64(numObjs + 2)
+
+	s, err := newManifestSplit("job", smap, bck, holder, func(tid string) string { return filepath.Join(dir, tid) })
+	tassert.CheckFatal(t, err)
+	splits.Lock()
+	splits.m["job"] = s
+	splits.Unlock()
+
+	// readers start before the split does - and tail the spools
+	var (
+		wg   sync.WaitGroup
+		mtx  sync.Mutex
+		objs = make(map[string]string, numObjs) // object => target
+		errs = make(map[string]int)             // target => malformed rows
+	)
+	for tid := range smap.Tmap {
+		r, err := ManifestRows("job", tid)
+		tassert.CheckFatal(t, err)
+		wg.Add(1)
+		go func(tid string, r io.ReadCloser) {
+			defer wg.Done()
+			defer r.Close()
+			rows := bufio.NewReader(r)
+			for {
+				row, err := readSpoolRow(rows)
+				if err != nil {
+					t.Errorf("%s: failed to read rows: %v", tid, err)
+					return
+				}
+				mtx.Lock()
+				switch {
+				case row.Err != "":
+					errs[tid]++
+				case row.Link != "":
+					si, err := cluster.HrwTarget(bck.MakeUname(row.ObjName), smap)
+					tassert.Errorf(t, err == nil && si.ID() == tid, "%s: received row of %s", tid, row.ObjName)
+					_, dup := objs[row.ObjName]
+					tassert.Errorf(t, !dup, "%s: duplicate row of %s", tid, row.ObjName)
+					objs[row.ObjName] = tid
+				}
+				mtx.Unlock()
+				if row.End {
+					tassert.Errorf(t, row.Line == lines, "%s: expected %d lines, got %d", tid, lines, row.Line)
+					_, err := readSpoolRow(rows)
+					tassert.Errorf(t, err == io.EOF, "%s: expected EOF after the last row, got %v", tid, err)
+					return
+				}
+			}
+		}(tid, r)
+	}
+	s.run(ioutil.NopCloser(strings.NewReader(manifest)), ManifestFormatCSV)
+	wg.Wait()
+
+	tassert.Errorf(t, len(objs) == numObjs, "expected %d objects, got %d", numObjs, len(objs))
+	tassert.Errorf(t, errs[holder] == 1 && len(errs) == 1, "expected a single malformed row at %s, got %v", holder, errs)
+
+	// all spools have been read - and removed
+	splits.Lock()
+	_, ok := splits.m["job"]
+	splits.Unlock()
+	tassert.Errorf(t, !ok, "expected the split to be over")
+	files, err := ioutil.ReadDir(dir)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(files) == 0, "expected the spools to be removed, got %d", len(files))
+}
+
+func TestManifestSplitAbort(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest-split")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+
+	smap := &cluster.Smap{Tmap: cluster.NodeMap{"t1": &cluster.Snode{DaemonID: "t1"}}}
+	s, err := newManifestSplit("job", smap, cluster.NewBck("bck", cmn.ProviderAIS, cmn.NsGlobal), "t1",
+		func(tid string) string { return filepath.Join(dir, tid) })
+	tassert.CheckFatal(t, err)
+	splits.Lock()
+	splits.m["job"] = s
+	splits.Unlock()
+
+	r, err := ManifestRows("job", "t1")
+	tassert.CheckFatal(t, err)
+	defer r.Close()
+	go abortSplit("job")
+	_, err = ioutil.ReadAll(r) // blocks until aborted
+	tassert.Errorf(t, errors.As(err, &cmn.AbortedError{}), "expected aborted error, got %v", err)
+	_, err = ManifestRows("job", "t1")
+	tassert.Errorf(t, err == errSplitNotFound, "expected no rows after abort, got %v", err)
+	files, err := ioutil.ReadDir(dir)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(files) == 0, "expected the spools to be removed, got %d", len(files))
+}
@@ -0,0 +1,453 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Minimal (read-only) client of the SSH File Transfer Protocol, version 3:
+// https://tools.ietf.org/html/draft-ietf-secsh-filexfer-02
+// Supports exactly what's needed to download a file: STAT, OPEN, READ, and CLOSE.
+
+const (
+	sftpVersion = 3
+
+	sftpReadSize   = 32 * 1024 // the max that all servers are required to support
+	sftpReadWindow = 16        // max outstanding READ requests
+	sftpMaxPacket  = 256 * 1024
+
+	// packet types
+	sftpFxpInit    = 1
+	sftpFxpVersion = 2
+	sftpFxpOpen    = 3
+	sftpFxpClose   = 4
+	sftpFxpRead    = 5
+	sftpFxpStat    = 17
+	sftpFxpStatus  = 101
+	sftpFxpHandle  = 102
+	sftpFxpData    = 103
+	sftpFxpAttrs   = 105
+
+	// status codes
+	sftpFxEOF        = 1
+	sftpFxNoSuchFile = 2
+	sftpFxPermDenied = 3
+
+	sftpFxfRead = 0x1 // open flags: read
+
+	// attribute flags
+	sftpAttrSize      = 0x1
+	sftpAttrUIDGID    = 0x2
+	sftpAttrPerms     = 0x4
+	sftpAttrACModTime = 0x8
+
+	sftpPermsTypeMask = 0o170000 // (as in S_IFMT)
+	sftpPermsRegular  = 0o100000
+
+	sftpStatusMsgLimit = 256
+)
+
+type (
+	// sftpClient is a single SFTP session (SSH channel); the requests are
+	// sent and the responses read by one goroutine at a time.
+	sftpClient struct {
+		session *ssh.Session
+		w       io.WriteCloser
+		r       *bufio.Reader
+		id      uint32
+		once    sync.Once
+	}
+
+	sftpStatusErr struct {
+		code uint32
+		msg  string
+	}
+
+	sftpReq struct {
+		id     uint32
+		offset int64
+		size   int
+	}
+
+	// sftpReader reads the file [offset, size) with up to sftpReadWindow
+	// READ requests in flight
+	sftpReader struct {
+		c       *sftpClient
+		handle  string
+		offset  int64 // next offset to request
+		size    int64
+		pending []sftpReq         // in order of offsets
+		resps   map[uint32][]byte // responses that arrived out of order
+		data    []byte            // unread part of the current response
+		done    chan struct{}
+		err     error
+	}
+
+	sftpBuf []byte
+)
+
+func (e *sftpStatusErr) Error() string {
+	return fmt.Sprintf("sftp: %s (status %d)", e.msg, e.code)
+}
+
+func (e *sftpStatusErr) Is(target error) bool {
+	switch target {
+	case os.ErrNotExist:
+		return e.code == sftpFxNoSuchFile
+	case os.ErrPermission:
+		return e.code == sftpFxPermDenied
+	}
+	return false
+}
+
+func newSFTPClient(conn *ssh.Client) (c *sftpClient, err error) {
+	c = &sftpClient{}
+	if c.session, err = conn.NewSession(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			c.close()
+		}
+	}()
+	if c.w, err = c.session.StdinPipe(); err != nil {
+		return
+	}
+	stdout, err := c.session.StdoutPipe()
+	if err != nil {
+		return
+	}
+	c.r = bufio.NewReaderSize(stdout, sftpReadSize+64)
+	if err = c.session.RequestSubsystem("sftp"); err != nil {
+		return
+	}
+	if err = c.send(sftpFxpInit, sftpBuf(nil).u32(sftpVersion)); err != nil {
+		return
+	}
+	typ, b, err := c.recv()
+	if err != nil {
+		return
+	}
+	if typ != sftpFxpVersion {
+		return nil, fmt.Errorf("sftp: unexpected packet %d (expecting version)", typ)
+	}
+	if version, _, ok := parseU32(b); !ok || version < sftpVersion {
+		return nil, fmt.Errorf("sftp: unsupported protocol version %d", version)
+	}
+	return c, nil
+}
+
+func (c *sftpClient) close() {
+	c.once.Do(func() { c.session.Close() })
+}
+
+func (c *sftpClient) nextID() uint32 { c.id++; return c.id }
+
+func (c *sftpClient) send(typ byte, payload sftpBuf) error {
+	pkt := sftpBuf(make([]byte, 0, 5+len(payload))).u32(uint32(1 + len(payload)))
+	pkt = append(pkt, typ)
+	pkt = append(pkt, payload...)
+	_, err := c.w.Write(pkt)
+	return err
+}
+
+func (c *sftpClient) recv() (typ byte, payload []byte, err error) {
+	var hdr [5]byte
+	if _, err = io.ReadFull(c.r, hdr[:]); err != nil {
+		// a response is always expected: the session closed (e.g., download
+		// canceled) must not look like the end of file
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return
+	}
+	length := binary.BigEndian.Uint32(hdr[:4])
+	if length < 1 || length > sftpMaxPacket {
+		return 0, nil, fmt.Errorf("sftp: invalid packet length %d", length)
+	}
+	payload = make([]byte, length-1)
+	if _, err = io.ReadFull(c.r, payload); err != nil {
+		return
+	}
+	return hdr[4], payload, nil
+}
+
+// call sends the request and returns the response (type and payload without request ID).
+func (c *sftpClient) call(typ byte, payload sftpBuf) (byte, []byte, error) {
+	id := c.nextID()
+	if err := c.send(typ, sftpBuf(nil).u32(id).bytes(payload)); err != nil {
+		return 0, nil, err
+	}
+	rtyp, b, err := c.recv()
+	if err != nil {
+		return 0, nil, err
+	}
+	rid, b, ok := parseU32(b)
+	if !ok || rid != id {
+		return 0, nil, fmt.Errorf("sftp: unexpected response ID %d (expecting %d)", rid, id)
+	}
+	return rtyp, b, nil
+}
+
+func (c *sftpClient) stat(path string) (size int64, mtime time.Time, err error) {
+	typ, b, err := c.call(sftpFxpStat, sftpBuf(nil).str(path))
+	if err != nil {
+		return
+	}
+	switch typ {
+	case sftpFxpAttrs:
+		return parseSFTPAttrs(b, path)
+	case sftpFxpStatus:
+		err = parseSFTPStatus(b)
+	default:
+		err = fmt.Errorf("sftp: unexpected packet %d (expecting attrs)", typ)
+	}
+	return
+}
+
+// open opens the file for reading at the offset; closing the reader closes the session.
+func (c *sftpClient) open(ctx context.Context, path string, offset int64) (io.ReadCloser, error) {
+	size, _, err := c.stat(path)
+	if err != nil {
+		return nil, err
+	}
+	// pflags (read) and empty attrs
+	typ, b, err := c.call(sftpFxpOpen, sftpBuf(nil).str(path).u32(sftpFxfRead).u32(0))
+	if err != nil {
+		return nil, err
+	}
+	switch typ {
+	case sftpFxpHandle:
+	case sftpFxpStatus:
+		return nil, parseSFTPStatus(b)
+	default:
+		return nil, fmt.Errorf("sftp: unexpected packet %d (expecting handle)", typ)
+	}
+	handle, _, ok := parseStr(b)
+	if !ok {
+		return nil, errors.New("sftp: invalid handle")
+	}
+	r := &sftpReader{
+		c:      c,
+		handle: handle,
+		offset: offset,
+		size:   size,
+		resps:  make(map[uint32][]byte, sftpReadWindow),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.close() // unblocks the reader
+		case <-r.done:
+		}
+	}()
+	return r, nil
+}
+
+func (r *sftpReader) Read(p []byte) (n int, err error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	for len(r.data) == 0 {
+		if err = r.next(); err != nil {
+			r.err = err
+			return 0, err
+		}
+	}
+	n = copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// next fills up the window of READ requests and receives the data of the first one.
+func (r *sftpReader) next() error {
+	for len(r.pending) < sftpReadWindow && r.offset < r.size {
+		size := sftpReadSize
+		if rem := r.size - r.offset; rem < int64(size) {
+			size = int(rem)
+		}
+		if err := r.request(r.offset, size, false); err != nil {
+			return err
+		}
+		r.offset += int64(size)
+	}
+	if len(r.pending) == 0 {
+		return io.EOF
+	}
+	req := r.pending[0]
+	r.pending = r.pending[1:]
+	b, ok := r.resps[req.id]
+	for !ok {
+		typ, pkt, err := r.c.recv()
+		if err != nil {
+			return err
+		}
+		id, payload, okID := parseU32(pkt)
+		if !okID {
+			return errors.New("sftp: invalid response")
+		}
+		switch typ {
+		case sftpFxpData:
+		case sftpFxpStatus:
+			// EOF before the end (the file has shrunk) shows as the size mismatch
+			if err := parseSFTPStatus(payload); !errors.Is(err, io.EOF) {
+				return err
+			}
+			payload = nil
+		default:
+			return fmt.Errorf("sftp: unexpected packet %d (expecting data)", typ)
+		}
+		if id == req.id {
+			b, ok = payload, true
+		} else {
+			r.resps[id] = payload
+		}
+	}
+	delete(r.resps, req.id)
+	if b == nil { // EOF
+		r.offset, r.pending = r.size, r.pending[:0]
+		return io.EOF
+	}
+	data, _, okData := parseStr(b)
+	if !okData || len(data) > req.size {
+		return errors.New("sftp: invalid data")
+	}
+	if len(data) < req.size { // short read - request the rest (ahead of the others)
+		if err := r.request(req.offset+int64(len(data)), req.size-len(data), true); err != nil {
+			return err
+		}
+	}
+	r.data = []byte(data)
+	return nil
+}
+
+func (r *sftpReader) request(offset int64, size int, first bool) error {
+	id := r.c.nextID()
+	payload := sftpBuf(nil).u32(id).str(r.handle).u64(uint64(offset)).u32(uint32(size))
+	if err := r.c.send(sftpFxpRead, payload); err != nil {
+		return err
+	}
+	req := sftpReq{id: id, offset: offset, size: size}
+	if first {
+		r.pending = append([]sftpReq{req}, r.pending...)
+	} else {
+		r.pending = append(r.pending, req)
+	}
+	return nil
+}
+
+func (r *sftpReader) Close() error {
+	close(r.done)
+	r.c.close() // (the handle is closed along with the session)
+	return nil
+}
+
+/////////////
+// helpers //
+/////////////
+
+func (b sftpBuf) u32(v uint32) sftpBuf {
+	var tmp [4]byte
+	binary.BigEndian.PutUint32(tmp[:], v)
+	return append(b, tmp[:]...)
+}
+
+func (b sftpBuf) u64(v uint64) sftpBuf {
+	var tmp [8]byte
+	binary.BigEndian.PutUint64(tmp[:], v)
+	return append(b, tmp[:]...)
+}
+
+func (b sftpBuf) str(s string) sftpBuf   { return append(b.u32(uint32(len(s))), s...) }
+func (b sftpBuf) bytes(p []byte) sftpBuf { return append(b, p...) }
+
+func parseU32(b []byte) (uint32, []byte, bool) {
+	if len(b) < 4 {
+		return 0, b, false
+	}
+	return binary.BigEndian.Uint32(b), b[4:], true
+}
+
+func parseU64(b []byte) (uint64, []byte, bool) {
+	if len(b) < 8 {
+		return 0, b, false
+	}
+	return binary.BigEndian.Uint64(b), b[8:], true
+}
+
+func parseStr(b []byte) (string, []byte, bool) {
+	n, b, ok := parseU32(b)
+	if !ok || uint32(len(b)) < n {
+		return "", b, false
+	}
+	return string(b[:n]), b[n:], true
+}
+
+func parseSFTPStatus(b []byte) error {
+	code, b, ok := parseU32(b)
+	if !ok {
+		return errors.New("sftp: invalid status")
+	}
+	if code == sftpFxEOF {
+		return io.EOF
+	}
+	msg, _, _ := parseStr(b)
+	if len(msg) > sftpStatusMsgLimit {
+		msg = msg[:sftpStatusMsgLimit]
+	}
+	return &sftpStatusErr{code: code, msg: msg}
+}
+
+func parseSFTPAttrs(b []byte, path string) (size int64, mtime time.Time, err error) {
+	var (
+		flags, v uint32
+		v64      uint64
+		ok       bool
+	)
+	invalid := fmt.Errorf("sftp: invalid attributes of %q", path)
+	if flags, b, ok = parseU32(b); !ok {
+		return 0, mtime, invalid
+	}
+	if flags&sftpAttrSize == 0 {
+		return 0, mtime, fmt.Errorf("sftp: size of %q is unknown", path)
+	}
+	if v64, b, ok = parseU64(b); !ok {
+		return 0, mtime, invalid
+	}
+	size = int64(v64)
+	if flags&sftpAttrUIDGID != 0 {
+		if len(b) < 8 {
+			return 0, mtime, invalid
+		}
+		b = b[8:]
+	}
+	if flags&sftpAttrPerms != 0 {
+		if v, b, ok = parseU32(b); !ok {
+			return 0, mtime, invalid
+		}
+		if v&sftpPermsTypeMask != sftpPermsRegular {
+			return 0, mtime, fmt.Errorf("sftp: %q is not a regular file", path)
+		}
+	}
+	if flags&sftpAttrACModTime != 0 {
+		if len(b) < 8 {
+			return 0, mtime, invalid
+		}
+		mtime = time.Unix(int64(binary.BigEndian.Uint32(b[4:8])), 0)
+	}
+	return size, mtime, nil
+}
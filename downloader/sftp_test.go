@@ -0,0 +1,331 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/tutils/tassert"
+	"golang.org/x/crypto/ssh"
+)
+
+const sftpFxOK = 0
+
+type (
+	// in-process SSH server with a minimal (read-only) SFTP subsystem
+	sftpTestServer struct {
+		ln      net.Listener
+		signer  ssh.Signer
+		files   map[string][]byte // path => content
+		denied  map[string]bool   // OPEN fails with "permission denied"
+		shrunk  map[string]int    // served only that many bytes (while STAT reports the full size)
+		maxRead int               // short reads: max bytes returned by a single READ
+		reorder bool              // respond to READ requests out of order
+		stall   bool              // never respond to READ requests
+	}
+)
+
+func newSFTPTestServer(t *testing.T) *sftpTestServer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	tassert.CheckFatal(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	tassert.CheckFatal(t, err)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	tassert.CheckFatal(t, err)
+	s := &sftpTestServer{
+		ln:     ln,
+		signer: signer,
+		files:  make(map[string][]byte),
+		denied: make(map[string]bool),
+		shrunk: make(map[string]int),
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(_ ssh.ConnMetadata, _ []byte) (*ssh.Permissions, error) { return nil, nil },
+	}
+	config.AddHostKey(signer)
+	go s.accept(config)
+	return s
+}
+
+func (s *sftpTestServer) close() { s.ln.Close() }
+
+func (s *sftpTestServer) dial(t *testing.T) *ssh.Client {
+	client, err := ssh.Dial("tcp", s.ln.Addr().String(), &ssh.ClientConfig{
+		User:            "user",
+		Auth:            []ssh.AuthMethod{ssh.Password("password")},
+		HostKeyCallback: ssh.FixedHostKey(s.signer.PublicKey()),
+		Timeout:         sftpDialTimeout,
+	})
+	tassert.CheckFatal(t, err)
+	return client
+}
+
+func (s *sftpTestServer) accept(config *ssh.ServerConfig) {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			_, chans, reqs, err := ssh.NewServerConn(conn, config)
+			if err != nil {
+				return
+			}
+			go ssh.DiscardRequests(reqs)
+			for nch := range chans {
+				if nch.ChannelType() != "session" {
+					nch.Reject(ssh.UnknownChannelType, "unknown channel type")
+					continue
+				}
+				ch, reqs, err := nch.Accept()
+				if err != nil {
+					continue
+				}
+				go func() {
+					for req := range reqs {
+						ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+						req.Reply(ok, nil)
+						if ok {
+							go s.serve(ch)
+						}
+					}
+				}()
+			}
+		}()
+	}
+}
+
+func (s *sftpTestServer) serve(ch ssh.Channel) {
+	defer ch.Close()
+	pkts := make(chan []byte, sftpReadWindow)
+	go func() {
+		defer close(pkts)
+		for {
+			var hdr [4]byte
+			if _, err := io.ReadFull(ch, hdr[:]); err != nil {
+				return
+			}
+			pkt := make([]byte, binary.BigEndian.Uint32(hdr[:]))
+			if _, err := io.ReadFull(ch, pkt); err != nil {
+				return
+			}
+			pkts <- pkt
+		}
+	}()
+	var held []byte // the response held back to be sent after the next one
+	for {
+		var timeout <-chan time.Time
+		if held != nil {
+			timeout = time.After(5 * time.Millisecond)
+		}
+		select {
+		case pkt, ok := <-pkts:
+			if !ok {
+				return
+			}
+			if s.stall && pkt[0] == sftpFxpRead {
+				continue
+			}
+			resp := s.handle(pkt[0], pkt[1:])
+			if s.reorder && pkt[0] == sftpFxpRead {
+				if held == nil {
+					held = resp
+					continue
+				}
+				ch.Write(resp)
+				resp, held = held, nil
+			}
+			ch.Write(resp)
+		case <-timeout:
+			ch.Write(held)
+			held = nil
+		}
+	}
+}
+
+func (s *sftpTestServer) handle(typ byte, b []byte) []byte {
+	if typ == sftpFxpInit {
+		return sftpTestPacket(sftpFxpVersion, sftpBuf(nil).u32(sftpVersion))
+	}
+	id, b, _ := parseU32(b)
+	status := func(code uint32, msg string) []byte {
+		return sftpTestPacket(sftpFxpStatus, sftpBuf(nil).u32(id).u32(code).str(msg).str(""))
+	}
+	switch typ {
+	case sftpFxpStat:
+		path, _, _ := parseStr(b)
+		data, ok := s.files[path]
+		if !ok {
+			return status(sftpFxNoSuchFile, "no such file")
+		}
+		attrs := sftpBuf(nil).u32(id).u32(sftpAttrSize | sftpAttrPerms).u64(uint64(len(data))).u32(sftpPermsRegular | 0o644)
+		return sftpTestPacket(sftpFxpAttrs, attrs)
+	case sftpFxpOpen:
+		path, _, _ := parseStr(b)
+		if s.denied[path] {
+			return status(sftpFxPermDenied, "permission denied")
+		}
+		if _, ok := s.files[path]; !ok {
+			return status(sftpFxNoSuchFile, "no such file")
+		}
+		return sftpTestPacket(sftpFxpHandle, sftpBuf(nil).u32(id).str(path))
+	case sftpFxpRead:
+		handle, b, _ := parseStr(b)
+		offset, b, _ := parseU64(b)
+		size, _, _ := parseU32(b)
+		data := s.files[handle]
+		if n, ok := s.shrunk[handle]; ok {
+			data = data[:n]
+		}
+		if offset >= uint64(len(data)) {
+			return status(sftpFxEOF, "EOF")
+		}
+		data = data[offset:]
+		if int(size) < len(data) {
+			data = data[:size]
+		}
+		if s.maxRead > 0 && s.maxRead < len(data) {
+			data = data[:s.maxRead]
+		}
+		return sftpTestPacket(sftpFxpData, sftpBuf(nil).u32(id).str(string(data)))
+	case sftpFxpClose:
+		return status(sftpFxOK, "")
+	}
+	return status(8 /*SSH_FX_OP_UNSUPPORTED*/, "unsupported")
+}
+
+func sftpTestPacket(typ byte, payload sftpBuf) []byte {
+	pkt := sftpBuf(nil).u32(uint32(1 + len(payload)))
+	pkt = append(pkt, typ)
+	return append(pkt, payload...)
+}
+
+func sftpTestRead(t *testing.T, s *sftpTestServer, path string, offset int64) ([]byte, error) {
+	client := s.dial(t)
+	defer client.Close()
+	c, err := newSFTPClient(client)
+	tassert.CheckFatal(t, err)
+	r, err := c.open(context.Background(), path, offset)
+	if err != nil {
+		c.close()
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+func TestSFTPClient(t *testing.T) {
+	data := make([]byte, sftpReadWindow*sftpReadSize*2+1234)
+	_, err := rand.Read(data)
+	tassert.CheckFatal(t, err)
+
+	newServer := func(t *testing.T) *sftpTestServer {
+		s := newSFTPTestServer(t)
+		s.files["/data/obj"] = data
+		s.files["/data/empty"] = []byte{}
+		s.files["/data/denied"] = data[:10]
+		s.denied["/data/denied"] = true
+		return s
+	}
+
+	tests := []struct {
+		name    string
+		offset  int64
+		maxRead int
+		reorder bool
+	}{
+		{name: "whole"},
+		{name: "resume", offset: sftpReadSize*3 + 17},
+		{name: "resume-at-end", offset: int64(len(data))},
+		{name: "short-reads", maxRead: sftpReadSize/3 + 1},
+		{name: "short-reads-resume", offset: 12345, maxRead: 1000},
+		{name: "out-of-order", reorder: true},
+		{name: "out-of-order-short-reads", offset: 99, maxRead: sftpReadSize / 2, reorder: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newServer(t)
+			defer s.close()
+			s.maxRead, s.reorder = test.maxRead, test.reorder
+			b, err := sftpTestRead(t, s, "/data/obj", test.offset)
+			tassert.CheckFatal(t, err)
+			tassert.Fatalf(t, bytes.Equal(b, data[test.offset:]),
+				"expected %d bytes at offset %d, got %d (or different content)", len(data)-int(test.offset), test.offset, len(b))
+		})
+	}
+
+	t.Run("stat", func(t *testing.T) {
+		s := newServer(t)
+		defer s.close()
+		client := s.dial(t)
+		defer client.Close()
+		c, err := newSFTPClient(client)
+		tassert.CheckFatal(t, err)
+		defer c.close()
+		size, _, err := c.stat("/data/obj")
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, size == int64(len(data)), "expected size %d, got %d", len(data), size)
+		_, _, err = c.stat("/data/missing")
+		tassert.Errorf(t, errors.Is(err, os.ErrNotExist), "expected not-exist error, got %v", err)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		s := newServer(t)
+		defer s.close()
+		b, err := sftpTestRead(t, s, "/data/empty", 0)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, len(b) == 0, "expected no data, got %d bytes", len(b))
+	})
+
+	t.Run("EOF", func(t *testing.T) {
+		// the file has shrunk after STAT: premature EOF ends the read
+		s := newServer(t)
+		defer s.close()
+		s.shrunk["/data/obj"] = sftpReadSize*2 + 5
+		b, err := sftpTestRead(t, s, "/data/obj", 7)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, bytes.Equal(b, data[7:sftpReadSize*2+5]), "expected %d bytes, got %d", sftpReadSize*2-2, len(b))
+	})
+
+	t.Run("status-errors", func(t *testing.T) {
+		s := newServer(t)
+		defer s.close()
+		_, err := sftpTestRead(t, s, "/data/missing", 0)
+		tassert.Errorf(t, errors.Is(err, os.ErrNotExist), "expected not-exist error, got %v", err)
+		_, err = sftpTestRead(t, s, "/data/denied", 0)
+		tassert.Errorf(t, errors.Is(err, os.ErrPermission), "expected permission error, got %v", err)
+		var statusErr *sftpStatusErr
+		tassert.Errorf(t, errors.As(err, &statusErr) && statusErr.msg == "permission denied",
+			"expected status message, got %v", err)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		s := newServer(t)
+		defer s.close()
+		s.stall = true
+		client := s.dial(t)
+		defer client.Close()
+		c, err := newSFTPClient(client)
+		tassert.CheckFatal(t, err)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		r, err := c.open(ctx, "/data/obj", 0)
+		tassert.CheckFatal(t, err)
+		defer r.Close()
+		_, err = ioutil.ReadAll(r)
+		tassert.Errorf(t, err != nil, "expected read of the canceled download to fail")
+	})
+}
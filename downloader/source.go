@@ -0,0 +1,343 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/hk"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Non-HTTP sources of the objects to download. Each source is identified by
+// the scheme of the link (e.g. `sftp://host/dir/file`) and supports reading
+// the object at a given offset, so that an interrupted download is resumed
+// (see singleObjectTask.fetchSource).
+
+const (
+	sftpDefaultPort = "22"
+	sftpDialTimeout = 15 * time.Second
+	sftpIdleTimeout = 5 * time.Minute // idle connections are closed after that
+)
+
+type (
+	dlSource interface {
+		// stat returns the size and the modification time of the object
+		stat(ctx context.Context, u *url.URL) (size int64, mtime time.Time, err error)
+		// open returns the content of the object starting at the offset
+		open(ctx context.Context, u *url.URL, offset int64) (io.ReadCloser, error)
+	}
+
+	// file://<path> - a file on the target, e.g. on NFS export mounted on
+	// every target; the path must be under one of `downloader.file_roots`.
+	fileSource struct{}
+
+	// sftp://[user[:password]@]host[:port]/<path>
+	sftpSource struct {
+		mtx     sync.Mutex
+		clients map[string]*sftpConn // user@host:port => connection
+		hkOnce  sync.Once
+	}
+	sftpConn struct {
+		*ssh.Client
+		lastUsed atomic.Int64
+	}
+)
+
+// registered sources: scheme => source
+var dlSources = map[string]dlSource{
+	cmn.FileScheme: &fileSource{},
+	cmn.SFTPScheme: &sftpSource{clients: make(map[string]*sftpConn)},
+}
+
+// sourceFor returns the source of the link, or nil for HTTP(S) links.
+func sourceFor(link string) (dlSource, *url.URL) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, nil
+	}
+	src, ok := dlSources[strings.ToLower(u.Scheme)]
+	if !ok {
+		return nil, nil
+	}
+	return src, u
+}
+
+func isHTTPLink(link string) bool {
+	src, _ := sourceFor(link)
+	return src == nil
+}
+
+// stat for either HTTP(S) link or non-HTTP source (see compareObjects)
+func roiFromAnyLink(link string) (roi remoteObjInfo, err error) {
+	src, u := sourceFor(link)
+	if src == nil {
+		resp, err := headLink(link)
+		if err != nil {
+			return roi, err
+		}
+		return roiFromLink(link, resp), nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), headReqTimeout)
+	defer cancel()
+	size, _, err := src.stat(ctx, u)
+	if err != nil {
+		return roi, err
+	}
+	roi.size = size
+	roi.md = cmn.SimpleKVs{cluster.SourceObjMD: cluster.SourceWebObjMD}
+	return roi, nil
+}
+
+////////////////
+// fileSource //
+////////////////
+
+func (*fileSource) path(u *url.URL) (string, error) {
+	if u.Host != "" && u.Host != "localhost" {
+		return "", fmt.Errorf("%s: %w (remote host %q is not supported - use NFS mount or sftp://)", u, os.ErrPermission, u.Host)
+	}
+	roots := cmn.GCO.Get().Downloader.FileRoots
+	if len(roots) == 0 {
+		return "", fmt.Errorf("%s: %w (file downloads are disabled - downloader.file_roots is empty)", u, os.ErrPermission)
+	}
+	// check the path as is and, again, with symlinks resolved (so that
+	// neither `..` nor symlinks can lead outside of the roots)
+	path := filepath.Clean(u.Path)
+	if !underRoots(path, roots, false) {
+		return "", fmt.Errorf("%s: %w (%q is outside of downloader.file_roots)", u, os.ErrPermission, path)
+	}
+	path, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	if !underRoots(path, roots, true) {
+		return "", fmt.Errorf("%s: %w (%q is outside of downloader.file_roots)", u, os.ErrPermission, path)
+	}
+	return path, nil
+}
+
+func underRoots(path string, roots []string, evalSymlinks bool) bool {
+	for _, root := range roots {
+		if evalSymlinks {
+			if r, err := filepath.EvalSymlinks(root); err == nil {
+				root = r
+			}
+		}
+		if path == root || strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (fsrc *fileSource) stat(_ context.Context, u *url.URL) (int64, time.Time, error) {
+	path, err := fsrc.path(u)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	finfo, err := os.Stat(path)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if !finfo.Mode().IsRegular() {
+		return 0, time.Time{}, fmt.Errorf("%s: not a regular file", u)
+	}
+	return finfo.Size(), finfo.ModTime(), nil
+}
+
+func (fsrc *fileSource) open(_ context.Context, u *url.URL, offset int64) (io.ReadCloser, error) {
+	path, err := fsrc.path(u)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		cmn.Close(file)
+		return nil, err
+	}
+	return file, nil
+}
+
+////////////////
+// sftpSource //
+////////////////
+
+func (s *sftpSource) stat(ctx context.Context, u *url.URL) (size int64, mtime time.Time, err error) {
+	err = s.do(ctx, u, func(c *sftpClient) error {
+		size, mtime, err = c.stat(u.Path)
+		return err
+	})
+	return
+}
+
+func (s *sftpSource) open(ctx context.Context, u *url.URL, offset int64) (r io.ReadCloser, err error) {
+	conn, err := s.conn(u)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newSFTPClient(conn.Client)
+	if err != nil {
+		s.evict(u, conn)
+		return nil, err
+	}
+	if r, err = c.open(ctx, u.Path, offset); err != nil {
+		c.close()
+	}
+	return
+}
+
+// do runs the callback with a new SFTP session (channel) over the cached
+// connection to the server.
+func (s *sftpSource) do(ctx context.Context, u *url.URL, cb func(c *sftpClient) error) error {
+	conn, err := s.conn(u)
+	if err != nil {
+		return err
+	}
+	c, err := newSFTPClient(conn.Client)
+	if err != nil {
+		s.evict(u, conn)
+		return err
+	}
+	defer c.close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.close() // unblocks the callback
+		case <-done:
+		}
+	}()
+	return cb(c)
+}
+
+func sftpAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = sftpDefaultPort
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+func (s *sftpSource) key(u *url.URL) string { return u.User.String() + "@" + sftpAddr(u) }
+
+// conn returns the connection to the server: cached or a new one.
+func (s *sftpSource) conn(u *url.URL) (*sftpConn, error) {
+	s.hkOnce.Do(func() { hk.Reg("downloader-sftp", s.housekeep, sftpIdleTimeout) })
+	key := s.key(u)
+	s.mtx.Lock()
+	conn, ok := s.clients[key]
+	s.mtx.Unlock()
+	if !ok {
+		client, err := sftpDial(u)
+		if err != nil {
+			return nil, err
+		}
+		s.mtx.Lock()
+		if conn, ok = s.clients[key]; ok { // lost the race
+			s.mtx.Unlock()
+			cmn.Close(client)
+		} else {
+			conn = &sftpConn{Client: client}
+			s.clients[key] = conn
+			s.mtx.Unlock()
+		}
+	}
+	conn.lastUsed.Store(time.Now().UnixNano())
+	return conn, nil
+}
+
+// evict closes and forgets the (presumably, broken) connection.
+func (s *sftpSource) evict(u *url.URL, conn *sftpConn) {
+	key := s.key(u)
+	s.mtx.Lock()
+	if s.clients[key] == conn {
+		delete(s.clients, key)
+	}
+	s.mtx.Unlock()
+	cmn.Close(conn.Client)
+}
+
+func (s *sftpSource) housekeep() time.Duration {
+	now := time.Now().UnixNano()
+	s.mtx.Lock()
+	for key, conn := range s.clients {
+		if time.Duration(now-conn.lastUsed.Load()) > sftpIdleTimeout {
+			delete(s.clients, key)
+			cmn.Close(conn.Client)
+		}
+	}
+	s.mtx.Unlock()
+	return sftpIdleTimeout
+}
+
+func sftpDial(u *url.URL) (*ssh.Client, error) {
+	conf := cmn.GCO.Get().Downloader.SFTP
+	if conf.KnownHostsFile == "" {
+		return nil, fmt.Errorf("%s: cannot verify host key (downloader.sftp.known_hosts_file is empty)", u.Host)
+	}
+	hostKeyCallback, err := knownhosts.New(conf.KnownHostsFile)
+	if err != nil {
+		return nil, err
+	}
+	user := u.User.Username()
+	if user == "" {
+		user = conf.User
+	}
+	if user == "" {
+		return nil, fmt.Errorf("%s: no user (specify in the link or in downloader.sftp.user)", u.Host)
+	}
+	var auth []ssh.AuthMethod
+	if conf.KeyFile != "" {
+		b, err := ioutil.ReadFile(conf.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := ssh.ParsePrivateKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("invalid downloader.sftp.key_file %q: %v", conf.KeyFile, err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if password, ok := u.User.Password(); ok {
+		auth = append(auth, ssh.Password(password))
+	}
+	if len(auth) == 0 {
+		return nil, errors.New("sftp: no credentials (specify downloader.sftp.key_file or password in the link)")
+	}
+	client, err := ssh.Dial("tcp", sftpAddr(u), &ssh.ClientConfig{
+		User:            user,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sftpDialTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if glog.V(4) {
+		glog.Infof("connected to sftp://%s@%s", user, sftpAddr(u))
+	}
+	return client, nil
+}
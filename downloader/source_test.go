@@ -0,0 +1,108 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestFileSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dl-file-source")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		root    = filepath.Join(dir, "root")
+		outside = filepath.Join(dir, "outside")
+	)
+	tassert.CheckFatal(t, os.MkdirAll(filepath.Join(root, "sub"), 0o755))
+	tassert.CheckFatal(t, os.MkdirAll(outside, 0o755))
+	tassert.CheckFatal(t, ioutil.WriteFile(filepath.Join(root, "sub", "obj"), []byte("0123456789"), 0o644))
+	tassert.CheckFatal(t, ioutil.WriteFile(filepath.Join(outside, "obj"), []byte("secret"), 0o644))
+	tassert.CheckFatal(t, os.Symlink(filepath.Join(outside, "obj"), filepath.Join(root, "link")))
+
+	config := cmn.GCO.BeginUpdate()
+	config.Downloader.FileRoots = nil
+	cmn.GCO.CommitUpdate(config)
+
+	src, _ := sourceFor("file://" + filepath.Join(root, "sub", "obj"))
+	tassert.Fatalf(t, src != nil, "expected file source")
+	tassert.Fatalf(t, isHTTPLink("https://example.com/obj"), "expected HTTP link")
+
+	ctx := context.Background()
+	_, u := sourceFor("file://" + filepath.Join(root, "sub", "obj"))
+	_, _, err = src.stat(ctx, u)
+	tassert.Errorf(t, err != nil, "expected error with no file roots")
+
+	config = cmn.GCO.BeginUpdate()
+	config.Downloader.FileRoots = []string{root}
+	cmn.GCO.CommitUpdate(config)
+	defer func() {
+		config := cmn.GCO.BeginUpdate()
+		config.Downloader.FileRoots = nil
+		cmn.GCO.CommitUpdate(config)
+	}()
+
+	size, _, err := src.stat(ctx, u)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, size == 10, "expected size 10, got %d", size)
+
+	r, err := src.open(ctx, u, 4)
+	tassert.CheckFatal(t, err)
+	b, err := ioutil.ReadAll(r)
+	r.Close()
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, string(b) == "456789", "expected %q, got %q", "456789", b)
+
+	for _, link := range []string{
+		"file://" + filepath.Join(outside, "obj"),
+		"file://" + root + "/../outside/obj",
+		"file://" + filepath.Join(root, "link"), // symlink pointing outside
+		"file://" + root + "-other/obj",
+		"file://" + filepath.Join(root, "sub"), // not a regular file
+		"file://otherhost" + filepath.Join(root, "sub", "obj"),
+	} {
+		_, u := sourceFor(link)
+		_, _, err := src.stat(ctx, u)
+		tassert.Errorf(t, err != nil, "expected error for %q", link)
+		if link != "file://"+filepath.Join(root, "sub") {
+			tassert.Errorf(t, errors.Is(err, os.ErrPermission), "expected permission error for %q, got %v", link, err)
+		}
+	}
+}
+
+func TestSFTPParse(t *testing.T) {
+	mtime := time.Unix(1600000000, 0)
+	attrs := sftpBuf(nil).u32(sftpAttrSize | sftpAttrUIDGID | sftpAttrPerms | sftpAttrACModTime).
+		u64(1234).u32(1000).u32(1000).u32(sftpPermsRegular | 0o644).u32(0).u32(uint32(mtime.Unix()))
+	size, mt, err := parseSFTPAttrs(attrs, "obj")
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, size == 1234, "expected size 1234, got %d", size)
+	tassert.Errorf(t, mt.Equal(mtime), "expected mtime %v, got %v", mtime, mt)
+
+	dirAttrs := sftpBuf(nil).u32(sftpAttrSize | sftpAttrPerms).u64(4096).u32(0o040755)
+	_, _, err = parseSFTPAttrs(dirAttrs, "dir")
+	tassert.Errorf(t, err != nil, "expected error for directory")
+
+	_, _, err = parseSFTPAttrs(sftpBuf(nil).u32(sftpAttrSize).u32(1), "obj")
+	tassert.Errorf(t, err != nil, "expected error for truncated attributes")
+
+	err = parseSFTPStatus(sftpBuf(nil).u32(sftpFxEOF))
+	tassert.Errorf(t, err == io.EOF, "expected EOF, got %v", err)
+	err = parseSFTPStatus(sftpBuf(nil).u32(sftpFxNoSuchFile).str("no such file"))
+	tassert.Errorf(t, errors.Is(err, os.ErrNotExist), "expected not-exist error, got %v", err)
+	err = parseSFTPStatus(sftpBuf(nil).u32(sftpFxPermDenied).str("denied"))
+	tassert.Errorf(t, errors.Is(err, os.ErrPermission), "expected permission error, got %v", err)
+}
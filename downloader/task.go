@@ -6,11 +6,18 @@ package downloader
 
 import (
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/atomic"
@@ -24,9 +31,15 @@ import (
 
 const (
 	retryCnt         = 10               // number of retries to external resource
+	retryBackoff     = time.Second      // initial delay between retries (doubles each time)
+	maxRetryBackoff  = 30 * time.Second // (see retryBackoff)
 	reqTimeoutFactor = 1.2              // newTimeout = prevTimeout * reqTimeoutFactor
 	headReqTimeout   = 15 * time.Second // timeout for HEAD request to get the Content-Length
 	internalErrorMsg = "internal server error"
+
+	sidecarSuffix  = ".sha256"    // checksum file that may accompany the object
+	sidecarMinSize = 16 * cmn.MiB // objects smaller than that are not worth the extra request
+	sidecarMaxSize = 4 * cmn.KiB  // sanity
 )
 
 // List of HTTP status codes on which we should
@@ -56,10 +69,41 @@ type (
 
 		downloadCtx context.Context    // context with cancel function
 		cancelFunc  context.CancelFunc // used to cancel the download after the request commences
+
+		// resumable download (see tryDownloadLocal)
+		partFQN   string        // partially downloaded object
+		validator string        // ETag or Last-Modified of the source (If-Range)
+		roi       remoteObjInfo // as per the first response
+		cksum     *srcCksum     // published by the source (nil if none)
+		resumed   bool          // the download was resumed at least once
+
+		// checksum of the part computed while downloading (see writePart)
+		hash   hash.Hash
+		hashed int64 // number of bytes of the part the hash has been computed over
+	}
+
+	errCksumMismatch struct {
+		expected *srcCksum
+		actual   string
+	}
+
+	errSizeMismatch struct {
+		expected int64
+		actual   int64
 	}
 )
 
-func (t *singleObjectTask) download() {
+func (e *errCksumMismatch) Error() string {
+	return fmt.Sprintf("checksum mismatch: expected %s, got %s", e.expected, e.actual)
+}
+
+func (e *errSizeMismatch) Error() string {
+	return fmt.Sprintf("size mismatch: expected %d, got %d", e.expected, e.actual)
+}
+
+// download returns true if the download has been interrupted by the pause of
+// the job, in which case it is to be continued upon resume (see jogger.park).
+func (t *singleObjectTask) download() (paused bool) {
 	lom := &cluster.LOM{T: t.parent.t, ObjName: t.obj.objName}
 	err := lom.Init(t.job.Bck())
 	if err == nil {
@@ -84,6 +128,9 @@ func (t *singleObjectTask) download() {
 	t.ended.Store(time.Now())
 
 	if err != nil {
+		if t.job.throttler().paused() {
+			return true
+		}
 		t.markFailed(err.Error())
 		return
 	}
@@ -96,13 +143,38 @@ func (t *singleObjectTask) download() {
 	)
 	t.parent.ObjectsInc()
 	t.parent.BytesAdd(t.currentSize.Load())
+	return false
 }
 
-func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Duration) error {
-	workFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut)
+// tryDownloadLocal downloads the object into a work file (t.partFQN) that
+// persists across retries: each retry resumes from where the previous one has
+// stopped (via HTTP Range or, for non-HTTP sources, at the offset), provided
+// the source supports it and hasn't changed in the meantime (If-Range). Once
+// downloaded (and verified against the source's checksum, if any, computed
+// while downloading), the work file is PUT into the bucket.
+func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Duration) (err error) {
 	ctx, cancel := context.WithTimeout(t.downloadCtx, timeout)
 	defer cancel()
 
+	if src, u := sourceFor(t.obj.link); src != nil {
+		err = t.fetchSource(ctx, src, u)
+	} else {
+		err = t.fetchHTTP(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	if size, total := t.partSize(), t.totalSize.Load(); total > 0 && size != total {
+		return fmt.Errorf("%s: %w (downloaded %d out of %d bytes)", t, io.ErrUnexpectedEOF, size, total)
+	}
+	if err := t.verify(); err != nil {
+		return err
+	}
+	return t.putPart(lom)
+}
+
+// fetchHTTP downloads (the rest of) the object from HTTP(S) link into the work file.
+func (t *singleObjectTask) fetchHTTP(ctx context.Context) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.obj.link, nil)
 	if err != nil {
 		return err
@@ -114,53 +186,330 @@ func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Durat
 		req.Header.Add("User-Agent", cmn.GcsUA)
 	}
 
+	offset := t.partSize()
+	if offset > 0 {
+		req.Header.Set(cmn.HeaderRange, fmt.Sprintf("%s%d-", cmn.HeaderRangeValPrefix, offset))
+		if t.validator != "" {
+			req.Header.Set(cmn.HeaderIfRange, t.validator)
+		}
+	}
+
 	resp, err := clientForURL(t.obj.link).Do(req)
 	if err != nil {
 		return err
 	}
 	defer cmn.Close(resp.Body)
 
-	if resp.StatusCode >= http.StatusBadRequest {
-		return fmt.Errorf("request failed with %d status code (%s)", resp.StatusCode, http.StatusText(resp.StatusCode))
+	switch {
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		if total := t.totalSize.Load(); total == offset {
+			break // already downloaded (in its entirety)
+		}
+		t.restart()
+		return fmt.Errorf("%s: failed to resume at offset %d - restarting", t, offset)
+	case resp.StatusCode >= http.StatusBadRequest:
+		return &cmn.HTTPError{
+			Status:  resp.StatusCode,
+			Message: fmt.Sprintf("request failed with %d status code (%s)", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		}
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		start, total, err := parseContentRange(resp.Header.Get(cmn.HeaderContentRange))
+		if err != nil {
+			return err
+		}
+		if start != offset {
+			t.restart()
+			return fmt.Errorf("%s: requested offset %d, got %d - restarting", t, offset, start)
+		}
+		if total > 0 {
+			t.setTotalSize(total)
+		}
+		t.resumed = true
+		glog.Infof("%s: resuming at offset %d", t, offset)
+		if err := t.writePart(ctx, resp.Body, true /*append*/); err != nil {
+			return err
+		}
+	default:
+		// (re)starting from scratch: first attempt, or the source does not
+		// support ranges, or it has changed since the previous attempt
+		if offset > 0 {
+			glog.Warningf("%s: cannot resume at offset %d (status %d) - restarting", t, offset, resp.StatusCode)
+			t.restart()
+		}
+		t.roi = roiFromLink(t.obj.link, resp)
+		t.cksum = cksumFromHeader(resp.Header, t.roi.md[cluster.SourceObjMD])
+		if (t.cksum == nil || t.cksum.weak) && t.obj.cksum == nil && (t.roi.size < 0 || t.roi.size >= sidecarMinSize) {
+			if cksum := t.sidecarCksum(ctx); cksum != nil {
+				t.cksum = cksum
+			}
+		}
+		t.validator = ""
+		if etag := resp.Header.Get(cmn.HeaderETag); etag != "" && !strings.HasPrefix(etag, "W/") {
+			t.validator = etag
+		} else if lm := resp.Header.Get(cmn.HeaderLastModified); lm != "" {
+			t.validator = lm
+		}
+		t.setTotalSize(t.roi.size)
+		if err := t.writePart(ctx, resp.Body, false /*append*/); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+// fetchSource downloads (the rest of) the object from non-HTTP source into the work file.
+func (t *singleObjectTask) fetchSource(ctx context.Context, src dlSource, u *url.URL) error {
+	size, mtime, err := src.stat(ctx, u)
+	if err != nil {
+		return err
+	}
 	var (
-		r   = t.wrapReader(ctx, resp.Body)
-		roi = roiFromLink(t.obj.link, resp)
+		offset    = t.partSize()
+		validator = fmt.Sprintf("%d-%d", size, mtime.UnixNano())
 	)
+	if offset > 0 && (validator != t.validator || offset > size) {
+		glog.Warningf("%s: cannot resume at offset %d (the source has changed) - restarting", t, offset)
+		t.restart()
+		offset = 0
+	}
+	if offset == 0 {
+		t.roi = remoteObjInfo{size: size, md: cmn.SimpleKVs{cluster.SourceObjMD: cluster.SourceWebObjMD}}
+		t.cksum = nil
+		t.validator = validator
+	} else {
+		t.resumed = true
+		glog.Infof("%s: resuming at offset %d", t, offset)
+	}
+	t.setTotalSize(size)
+	if offset > 0 && offset == size {
+		return nil // already downloaded (in its entirety)
+	}
+	r, err := src.open(ctx, u, offset)
+	if err != nil {
+		return err
+	}
+	defer cmn.Close(r)
+	return t.writePart(ctx, r, offset > 0 /*append*/)
+}
 
-	t.setTotalSize(roi.size)
+func (t *singleObjectTask) writePart(ctx context.Context, body io.ReadCloser, appnd bool) error {
+	var (
+		file *os.File
+		err  error
+	)
+	if appnd {
+		file, err = os.OpenFile(t.partFQN, os.O_WRONLY|os.O_APPEND, 0)
+	} else {
+		file, err = cmn.CreateFile(t.partFQN)
+	}
+	if err != nil {
+		return err
+	}
+	var (
+		w       io.Writer = cmn.WriterOnly{Writer: file}
+		offset            = t.partSize()
+		written int64
+	)
+	if t.initHash(appnd, offset) {
+		w = io.MultiWriter(w, t.hash)
+	}
+	buf, slab := t.parent.t.MMSA().Alloc()
+	r := t.wrapReader(ctx, body)
+	written, err = io.CopyBuffer(w, r, buf)
+	cmn.Close(r)
+	slab.Free(buf)
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		t.hash = nil // the part may have been written past the hash
+	} else if t.hash != nil {
+		t.hashed = offset + written
+	}
+	return err
+}
 
-	lom.SetCustomMD(roi.md)
-	params := cluster.PutObjectParams{
-		Reader:       r,
-		WorkFQN:      workFQN,
-		RecvType:     cluster.ColdGet,
-		Started:      t.started.Load(),
-		WithFinalize: true,
+// initHash prepares the hash of the part to be written at the given offset;
+// returns false if there is nothing to verify the downloaded object against
+// or the hash is not in sync with the part (see verify).
+func (t *singleObjectTask) initHash(appnd bool, offset int64) bool {
+	cksum := t.expectedCksum()
+	if cksum == nil {
+		t.hash = nil
+		return false
+	}
+	if !appnd {
+		t.hash, t.hashed = newHash(cksum.ty), 0
 	}
-	err = t.parent.t.PutObject(lom, params)
+	return t.hash != nil && t.hashed == offset
+}
+
+// expectedCksum returns the checksum from the manifest, if any, or the one
+// published by the source.
+func (t *singleObjectTask) expectedCksum() *srcCksum {
+	if t.obj.cksum != nil {
+		return t.obj.cksum
+	}
+	return t.cksum
+}
+
+func newHash(ty string) hash.Hash {
+	if ty == cmn.ChecksumMD5 {
+		return md5.New()
+	}
+	return sha256.New()
+}
+
+// verify checks the downloaded object against the size and checksum from the
+// manifest, if any, or the checksum published by the source: Content-MD5 header,
+// `.sha256` sidecar file or ETag header. The checksum is computed while
+// downloading - the part is read back only if the hash is out of sync (e.g.,
+// after a failed write).
+func (t *singleObjectTask) verify() error {
+	size := t.partSize()
+	if t.obj.size > 0 && size != t.obj.size {
+		return &errSizeMismatch{expected: t.obj.size, actual: size}
+	}
+	cksum := t.expectedCksum()
+	if cksum == nil {
+		return nil
+	}
+	if t.hash == nil || t.hashed != size {
+		if err := t.rehash(cksum.ty); err != nil {
+			return err
+		}
+	}
+	if actual := hex.EncodeToString(t.hash.Sum(nil)); actual != cksum.value {
+		err := &errCksumMismatch{expected: cksum, actual: actual}
+		if !cksum.weak {
+			return err
+		}
+		glog.Warningf("%s: %v (ETag of the source is not necessarily MD5) - ignoring", t, err)
+		return nil
+	}
+	if glog.V(4) {
+		glog.Infof("%s: verified %s", t, cksum)
+	}
+	return nil
+}
+
+// rehash computes the checksum of the part from scratch.
+func (t *singleObjectTask) rehash(ty string) error {
+	file, err := os.Open(t.partFQN)
 	if err != nil {
 		return err
 	}
-	if err := lom.Load(); err != nil {
+	defer cmn.Close(file)
+	h := newHash(ty)
+	buf, slab := t.parent.t.MMSA().Alloc()
+	n, err := io.CopyBuffer(h, file, buf)
+	slab.Free(buf)
+	if err != nil {
 		return err
 	}
+	t.hash, t.hashed = h, n
 	return nil
 }
 
+func (t *singleObjectTask) sidecarCksum(ctx context.Context) *srcCksum {
+	link, err := sidecarLink(t.obj.link)
+	if err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, headReqTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil
+	}
+	resp, err := clientForURL(link).Do(req)
+	if err != nil {
+		return nil
+	}
+	defer cmn.Close(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, sidecarMaxSize))
+	if err != nil {
+		return nil
+	}
+	value, ok := parseSidecar(b)
+	if !ok {
+		glog.Warningf("%s: ignoring invalid checksum file %s", t, redactLink(link))
+		return nil
+	}
+	return &srcCksum{ty: cmn.ChecksumSHA256, value: value, from: redactLink(link)}
+}
+
+// putPart PUTs the (downloaded and verified) work file into the bucket
+func (t *singleObjectTask) putPart(lom *cluster.LOM) error {
+	fh, err := cmn.NewFileHandle(t.partFQN)
+	if err != nil {
+		return err
+	}
+	lom.SetCustomMD(t.roi.md)
+	params := cluster.PutObjectParams{
+		Reader:       fh, // (closed by PutObject)
+		WorkFQN:      fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut),
+		RecvType:     cluster.ColdGet,
+		Started:      t.started.Load(),
+		WithFinalize: true,
+		CheckQuota:   true,
+	}
+	if err := t.parent.t.PutObject(lom, params); err != nil {
+		return err
+	}
+	return lom.Load()
+}
+
 func (t *singleObjectTask) downloadLocal(lom *cluster.LOM) (err error) {
 	var (
-		httpErr = &cmn.HTTPError{}
-		timeout = t.initialTimeout()
+		httpErr  = &cmn.HTTPError{}
+		cksumErr = &errCksumMismatch{}
+		sizeErr  = &errSizeMismatch{}
+		timeout  = t.initialTimeout()
+		backoff  = retryBackoff
 	)
+	if t.partFQN == "" { // otherwise, continuing after the pause
+		t.partFQN = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileDlPart)
+		if err := cmn.RemoveFile(t.partFQN); err != nil { // leftover, if any
+			return err
+		}
+	}
+	defer func() {
+		if errors.Is(err, errJobPaused) {
+			return // keep the downloaded part until resumed
+		}
+		if errRm := cmn.RemoveFile(t.partFQN); errRm != nil {
+			glog.Errorf("%s: failed to remove %s, err: %v", t, t.partFQN, errRm)
+		}
+	}()
 	for i := 0; i < retryCnt; i++ {
+		prevSize := t.partSize()
 		err = t.tryDownloadLocal(lom, timeout)
 		if err == nil {
 			return nil
 		} else if errors.Is(err, context.Canceled) || errors.Is(err, errThrottlerStopped) {
 			// Download was canceled or stopped, so just return.
 			return err
+		} else if errors.Is(err, errJobPaused) {
+			// Will be continued upon resume.
+			return err
+		} else if cmn.IsErrQuotaExceeded(err) {
+			// Retrying won't help.
+			return err
+		} else if errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrPermission) {
+			// Non-HTTP source: same as 404 and 403 (see terminalStatuses).
+			return err
+		} else if errors.As(err, &cksumErr) || errors.As(err, &sizeErr) {
+			if !t.resumed {
+				return err
+			}
+			// the resumed download may have been spliced from different
+			// versions of the object - one more time, from scratch
+			glog.Warningf("%s [retries: %d/%d]: %v (resumed download), restarting...", t, i, retryCnt, err)
+			t.restart()
 		} else if errors.Is(err, context.DeadlineExceeded) {
 			glog.Warningf("%s [retries: %d/%d]: context exceeded with timeout (%v), increasing and retrying...", t, i, retryCnt, timeout)
 			timeout = time.Duration(float64(timeout) * reqTimeoutFactor)
@@ -177,11 +526,51 @@ func (t *singleObjectTask) downloadLocal(lom *cluster.LOM) (err error) {
 			glog.Warningf("%s [retries: %d/%d]: unexpected error (%v), retrying...", t, i, retryCnt, err)
 		}
 
-		t.reset()
+		// back off unless the last attempt has made progress
+		if t.partSize() > prevSize {
+			backoff = retryBackoff
+			continue
+		}
+		select {
+		case <-time.After(backoff):
+		case <-t.downloadCtx.Done():
+			return t.downloadCtx.Err()
+		}
+		backoff = cmn.MinDuration(2*backoff, maxRetryBackoff)
 	}
 	return
 }
 
+// partSize returns the size of the partially downloaded object
+func (t *singleObjectTask) partSize() int64 {
+	finfo, err := os.Stat(t.partFQN)
+	if err != nil {
+		return 0
+	}
+	return finfo.Size()
+}
+
+// discardPart removes the partially downloaded object of the task that will
+// not be continued (see dispatcher.waitResumed)
+func (t *singleObjectTask) discardPart() {
+	if t.partFQN == "" {
+		return
+	}
+	if err := cmn.RemoveFile(t.partFQN); err != nil {
+		glog.Errorf("%s: failed to remove %s, err: %v", t, t.partFQN, err)
+	}
+}
+
+// restart discards the partially downloaded object
+func (t *singleObjectTask) restart() {
+	if err := cmn.RemoveFile(t.partFQN); err != nil {
+		glog.Errorf("%s: failed to remove %s, err: %v", t, t.partFQN, err)
+	}
+	t.resumed = false
+	t.hash = nil
+	t.reset()
+}
+
 func (t *singleObjectTask) wrapReader(ctx context.Context, r io.ReadCloser) io.ReadCloser {
 	// Create a custom reader to monitor progress every time we read from response body stream.
 	r = &progressReader{
@@ -270,6 +659,6 @@ func (t *singleObjectTask) ToTaskDlInfo() TaskDlInfo {
 func (t *singleObjectTask) String() (str string) {
 	return fmt.Sprintf(
 		"{id: %q, obj_name: %q, link: %q, from_cloud: %v, bucket: %q}",
-		t.id(), t.obj.objName, t.obj.link, t.obj.fromCloud, t.job.Bck(),
+		t.id(), t.obj.objName, redactLink(t.obj.link), t.obj.fromCloud, t.job.Bck(),
 	)
 }
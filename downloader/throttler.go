@@ -8,14 +8,25 @@ import (
 	"context"
 	"errors"
 	"io"
+	"sync"
 	"time"
 
+	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 )
 
-var errThrottlerStopped = errors.New("throttler has been stopped")
+var (
+	errThrottlerStopped = errors.New("throttler has been stopped")
+	errJobPaused        = errors.New("download job has been paused")
+
+	// the download bandwidth of the target (see bwBudget)
+	dlBudget = &bwBudget{active: make(map[*throttler]int, 4)}
+)
 
 type (
+	// throttler controls the resources of a single job: the number of
+	// concurrent downloads (connections), the bandwidth (both job's own limit
+	// and its share of the cluster-wide budget), and pausing.
 	throttler struct {
 		sema *cmn.DynSemaphore
 
@@ -24,21 +35,44 @@ type (
 		giveBackCh        chan int
 		ticker            *time.Ticker
 		stopCh            *cmn.StopCh
-	}
 
-	throughputThrottler interface {
-		acquireAllowance(ctx context.Context, n int) error
+		smap     cluster.Sowner
+		priority int
+		bw       rateLimiter // job's share of the target's bandwidth budget
+
+		mtx      sync.Mutex
+		resumeCh chan struct{} // non-nil while paused; closed upon resume
 	}
 
 	throttledReader struct {
-		t   throughputThrottler
-		ctx context.Context
-		r   io.ReadCloser
+		t    *throttler
+		ctx  context.Context
+		r    io.ReadCloser
+		once sync.Once
+	}
+
+	// bwBudget divides the download bandwidth of the target - the cluster-wide
+	// `downloader.bandwidth` divided by the number of targets - among the
+	// active (currently downloading) jobs in proportion to their priorities.
+	bwBudget struct {
+		mtx    sync.Mutex
+		active map[*throttler]int // job => number of its active readers
+	}
+
+	// rateLimiter is a simple token bucket (with the burst of one second).
+	rateLimiter struct {
+		mtx    sync.Mutex
+		rate   float64 // bytes per second; 0 - unlimited
+		tokens float64
+		last   time.Time
 	}
 )
 
-func newThrottler(limits DlLimits) *throttler {
-	t := &throttler{}
+func newThrottler(smap cluster.Sowner, limits DlLimits, priority int) *throttler {
+	if priority == 0 {
+		priority = DlPriorityDefault
+	}
+	t := &throttler{smap: smap, priority: priority}
 	if limits.Connections > 0 {
 		t.sema = cmn.NewDynSemaphore(limits.Connections)
 	}
@@ -106,10 +140,10 @@ func (t *throttler) release() {
 	t.sema.Release()
 }
 
+// wrapReader throttles the reader and makes it fail when the job gets paused;
+// the reader is accounted in the bandwidth budget until closed.
 func (t *throttler) wrapReader(ctx context.Context, r io.ReadCloser) io.ReadCloser {
-	if t.maxBytesPerMinute == 0 {
-		return r
-	}
+	dlBudget.join(t, t.bandwidth())
 	return &throttledReader{
 		t:   t,
 		ctx: ctx,
@@ -117,7 +151,53 @@ func (t *throttler) wrapReader(ctx context.Context, r io.ReadCloser) io.ReadClos
 	}
 }
 
+// bandwidth returns the download bandwidth budget of the target (0 - unlimited).
+func (t *throttler) bandwidth() int64 {
+	bw := cmn.GCO.Get().Downloader.Bandwidth
+	if bw > 0 && t.smap != nil {
+		if cnt := t.smap.Get().CountTargets(); cnt > 1 {
+			bw /= int64(cnt)
+		}
+	}
+	return bw
+}
+
+// pause returns false if the job is already paused.
+func (t *throttler) pause() bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.resumeCh != nil {
+		return false
+	}
+	t.resumeCh = make(chan struct{})
+	return true
+}
+
+// resume returns false if the job is not paused.
+func (t *throttler) resume() bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.resumeCh == nil {
+		return false
+	}
+	close(t.resumeCh)
+	t.resumeCh = nil
+	return true
+}
+
+// pausedCh returns the channel that gets closed when the job is resumed, or
+// nil if the job is not paused.
+func (t *throttler) pausedCh() <-chan struct{} {
+	t.mtx.Lock()
+	ch := t.resumeCh
+	t.mtx.Unlock()
+	return ch
+}
+
+func (t *throttler) paused() bool { return t.pausedCh() != nil }
+
 func (t *throttler) stop() {
+	dlBudget.remove(t)
 	if t.ticker != nil {
 		t.ticker.Stop()
 	}
@@ -146,12 +226,113 @@ func (t *throttler) acquireAllowance(ctx context.Context, n int) error {
 }
 
 func (tr *throttledReader) Read(p []byte) (n int, err error) {
-	if err := tr.t.acquireAllowance(tr.ctx, len(p)); err != nil {
-		return 0, err
+	if tr.t.paused() {
+		return 0, errJobPaused
+	}
+	if tr.t.maxBytesPerMinute > 0 {
+		if err := tr.t.acquireAllowance(tr.ctx, len(p)); err != nil {
+			return 0, err
+		}
 	}
-	return tr.r.Read(p)
+	n, err = tr.r.Read(p)
+	if n > 0 {
+		if errW := tr.t.bw.wait(tr.ctx, n); errW != nil {
+			return n, errW
+		}
+	}
+	return
 }
 
 func (tr *throttledReader) Close() (err error) {
+	tr.once.Do(func() { dlBudget.leave(tr.t, tr.t.bandwidth()) })
 	return tr.r.Close()
 }
+
+//////////////
+// bwBudget //
+//////////////
+
+func (b *bwBudget) join(t *throttler, bandwidth int64) {
+	b.mtx.Lock()
+	b.active[t]++
+	b.rebalance(bandwidth)
+	b.mtx.Unlock()
+}
+
+func (b *bwBudget) leave(t *throttler, bandwidth int64) {
+	b.mtx.Lock()
+	if b.active[t]--; b.active[t] <= 0 {
+		delete(b.active, t)
+		t.bw.setRate(0)
+	}
+	b.rebalance(bandwidth)
+	b.mtx.Unlock()
+}
+
+// remove forgets the job regardless of its readers (that should be all closed by now).
+func (b *bwBudget) remove(t *throttler) {
+	b.mtx.Lock()
+	if _, ok := b.active[t]; ok {
+		delete(b.active, t)
+		b.rebalance(t.bandwidth())
+	}
+	b.mtx.Unlock()
+}
+
+// NOTE: Should be called under `b.mtx` lock.
+func (b *bwBudget) rebalance(bandwidth int64) {
+	var total int
+	for t := range b.active {
+		total += t.priority
+	}
+	for t := range b.active {
+		var rate float64
+		if bandwidth > 0 {
+			rate = float64(bandwidth) * float64(t.priority) / float64(total)
+		}
+		t.bw.setRate(rate)
+	}
+}
+
+/////////////////
+// rateLimiter //
+/////////////////
+
+func (l *rateLimiter) setRate(rate float64) {
+	l.mtx.Lock()
+	if l.rate == 0 {
+		l.tokens, l.last = rate, time.Now() // start with a full bucket
+	}
+	l.rate = rate
+	l.mtx.Unlock()
+}
+
+// wait charges the bucket with `n` (already read) bytes and, if the bucket
+// runs out of tokens, waits for as long as it takes to refill.
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	l.mtx.Lock()
+	if l.rate == 0 {
+		l.mtx.Unlock()
+		return nil
+	}
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mtx.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,91 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestBandwidthBudget(t *testing.T) {
+	var (
+		budget = &bwBudget{active: make(map[*throttler]int)}
+		low    = newThrottler(nil, DlLimits{}, 2)
+		high   = newThrottler(nil, DlLimits{}, 6)
+		dflt   = newThrottler(nil, DlLimits{}, 0)
+	)
+	tassert.Errorf(t, dflt.priority == DlPriorityDefault, "expected default priority, got %d", dflt.priority)
+
+	budget.join(low, 800)
+	tassert.Errorf(t, low.bw.rate == 800, "expected the entire bandwidth, got %v", low.bw.rate)
+
+	budget.join(high, 800)
+	budget.join(high, 800) // second reader of the same job
+	tassert.Errorf(t, low.bw.rate == 200, "expected 1/4 of the bandwidth, got %v", low.bw.rate)
+	tassert.Errorf(t, high.bw.rate == 600, "expected 3/4 of the bandwidth, got %v", high.bw.rate)
+
+	budget.leave(high, 800)
+	tassert.Errorf(t, high.bw.rate == 600, "expected 3/4 of the bandwidth, got %v", high.bw.rate)
+	budget.leave(low, 800)
+	tassert.Errorf(t, low.bw.rate == 0, "expected no limit for inactive job, got %v", low.bw.rate)
+	tassert.Errorf(t, high.bw.rate == 800, "expected the entire bandwidth, got %v", high.bw.rate)
+
+	budget.leave(high, 0) // no limit (anymore)
+	tassert.Errorf(t, len(budget.active) == 0, "expected no active jobs, got %d", len(budget.active))
+}
+
+func TestRateLimiter(t *testing.T) {
+	var (
+		l     = &rateLimiter{}
+		ctx   = context.Background()
+		start = time.Now()
+	)
+	tassert.CheckFatal(t, l.wait(ctx, 1<<30)) // unlimited
+	l.setRate(100 * 1024)
+	for i := 0; i < 3; i++ {
+		tassert.CheckFatal(t, l.wait(ctx, 50*1024))
+	}
+	// 150KiB at 100KiB/s, starting with the full bucket (100KiB)
+	elapsed := time.Since(start)
+	tassert.Errorf(t, elapsed >= 400*time.Millisecond, "expected to be throttled, took %v", elapsed)
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	err := l.wait(ctx, 1024*1024)
+	tassert.Errorf(t, errors.Is(err, context.Canceled), "expected canceled, got %v", err)
+}
+
+func TestThrottlerPause(t *testing.T) {
+	th := newThrottler(nil, DlLimits{}, 0)
+	defer th.stop()
+	tassert.Fatalf(t, th.pausedCh() == nil, "expected not paused")
+	tassert.Fatalf(t, th.pause(), "expected to pause")
+	tassert.Fatalf(t, !th.pause(), "expected to be already paused")
+
+	ch := th.pausedCh()
+	r := th.wrapReader(context.Background(), ioutil.NopCloser(strings.NewReader("data")))
+	_, err := ioutil.ReadAll(r)
+	tassert.Errorf(t, errors.Is(err, errJobPaused), "expected paused, got %v", err)
+	r.Close()
+
+	tassert.Fatalf(t, th.resume(), "expected to resume")
+	tassert.Fatalf(t, !th.resume(), "expected to be already resumed")
+	select {
+	case <-ch:
+	default:
+		t.Fatal("expected the channel to be closed upon resume")
+	}
+	r = th.wrapReader(context.Background(), ioutil.NopCloser(strings.NewReader("data")))
+	b, err := ioutil.ReadAll(r)
+	r.Close()
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, string(b) == "data", "expected %q, got %q", "data", b)
+}
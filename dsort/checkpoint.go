@@ -0,0 +1,536 @@
+// Package dsort provides distributed massively parallel resharding for very large datasets.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package dsort
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// Checkpoints allow resuming a dSort job (e.g. aborted due to a target
+// restart) from the last phase completed by all the targets rather than
+// from scratch. Each target persists its own progress in the local db:
+//
+//  1. extracted records (in chunks) - records refer to their contents either by
+//     the offset in the (uncompressed) input shards or, if offsets are not
+//     supported, to the contents extracted to disk, see: checkpointExtraction,
+//  2. metadata of the shards to be created (received at the end of phase 3.),
+//  3. names of the shards which have been already created.
+//
+// Upon resume the proxy collects the checkpoints of all the targets (see:
+// mergeCheckpoints) and starts a new job which skips the phases completed by
+// all the targets as well as the shards that have been already created. The
+// new job takes over the checkpoint of the resumed one.
+
+const (
+	phaseNone int32 = iota
+	phaseExtracted
+	phaseDistributed
+)
+
+const (
+	checkpointsKey       = "checkpoints"
+	checkpointRecordsKey = "checkpoint-records"
+	checkpointShardsKey  = "checkpoint-shards"
+	checkpointCreatedKey = "checkpoint-created"
+
+	// number of records stored under a single key
+	checkpointRecordsChunk = 10000
+
+	// checkpoints of the jobs which were neither resumed nor removed
+	checkpointTTL = 7 * 24 * time.Hour
+)
+
+type (
+	checkpoint struct {
+		Phase        int32              `json:"phase"`
+		RS           *ParsedRequestSpec `json:"rs"`
+		Targets      []string           `json:"targets"`
+		Extracted    int64              `json:"extracted"` // number of extracted objects
+		Compressed   int64              `json:"compressed"`
+		Uncompressed int64              `json:"uncompressed"`
+		Saved        time.Time          `json:"saved"`
+	}
+
+	// CheckpointInfo is reported by the target to the proxy upon resume.
+	CheckpointInfo struct {
+		Phase   int32              `json:"phase"`
+		RS      *ParsedRequestSpec `json:"rs"`
+		Targets []string           `json:"targets"`
+		Created []string           `json:"created,omitempty"`
+		// Number of objects, per owner (target ID), in the shards which are
+		// yet to be created by the target.
+		Refs map[string]int64 `json:"refs,omitempty"`
+	}
+
+	// ResumeMsg describes the job to be resumed (see: ParsedRequestSpec.Resume).
+	ResumeMsg struct {
+		ManagerUUID string           `json:"manager_uuid"`
+		Phase       int32            `json:"phase"`             // last phase completed by all the targets
+		Created     []string         `json:"created,omitempty"` // shards created by all the targets
+		Refs        map[string]int64 `json:"refs,omitempty"`    // number of objects yet to be loaded, per owner
+	}
+
+	// resumeState is the state of the resumed job loaded from the checkpoint.
+	resumeState struct {
+		msg      *ResumeMsg
+		cp       *checkpoint
+		phase    int32 // phase from which the job is resumed on this target
+		records  *extract.Records
+		metadata *CreationPhaseMetadata
+		created  []string // shards created by this target
+	}
+)
+
+/////////////////////
+// checkpoint (db) //
+/////////////////////
+
+func (mg *ManagerGroup) saveCheckpoint(managerUUID string, cp *checkpoint) error {
+	return mg.db.Set(dsortCollection, path.Join(checkpointsKey, managerUUID), cp)
+}
+
+func (mg *ManagerGroup) loadCheckpoint(managerUUID string) (*checkpoint, error) {
+	cp := &checkpoint{}
+	if err := mg.db.Get(dsortCollection, path.Join(checkpointsKey, managerUUID), cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+func (mg *ManagerGroup) saveCheckpointBlob(key string, v msgp.Encodable) error {
+	buf := &bytes.Buffer{}
+	w := msgp.NewWriterSize(buf, serializationBufSize)
+	if err := v.EncodeMsg(w); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return mg.db.Set(dsortCollection, key, buf.Bytes())
+}
+
+func (mg *ManagerGroup) loadCheckpointBlob(key string, v msgp.Decodable) error {
+	var b []byte
+	if err := mg.db.Get(dsortCollection, key, &b); err != nil {
+		return err
+	}
+	return v.DecodeMsg(msgp.NewReaderSize(bytes.NewReader(b), serializationBufSize))
+}
+
+// saveCheckpointRecords stores the records in chunks (see: checkpointRecordsChunk)
+// so that the size of a single db value does not grow with the number of records.
+func (mg *ManagerGroup) saveCheckpointRecords(managerUUID string, records *extract.Records) error {
+	var idx int
+	for start := 0; idx == 0 || start < records.Len(); start += checkpointRecordsChunk {
+		var (
+			end = cmn.Min(start+checkpointRecordsChunk, records.Len())
+			key = path.Join(checkpointRecordsKey, managerUUID, strconv.Itoa(idx))
+		)
+		if err := mg.saveCheckpointBlob(key, records.Slice(start, end)); err != nil {
+			return err
+		}
+		idx++
+	}
+	// remove the chunks left over from the previous checkpoint (if any)
+	mg.removeCheckpointRecords(managerUUID, idx)
+	return nil
+}
+
+func (mg *ManagerGroup) loadCheckpointRecords(managerUUID string) (*extract.Records, error) {
+	records := extract.NewRecords(0)
+	for idx := 0; ; idx++ {
+		var (
+			chunk = extract.NewRecords(0)
+			key   = path.Join(checkpointRecordsKey, managerUUID, strconv.Itoa(idx))
+		)
+		if err := mg.loadCheckpointBlob(key, chunk); err != nil {
+			if dbdriver.IsErrNotFound(err) && idx > 0 {
+				return records, nil
+			}
+			return nil, err
+		}
+		records.Insert(chunk.All()...)
+	}
+}
+
+// removeCheckpointRecords removes the chunks of the records starting from
+// the given index.
+func (mg *ManagerGroup) removeCheckpointRecords(managerUUID string, idx int) {
+	// Delete only returns err when record does not exist
+	for ; ; idx++ {
+		if err := mg.db.Delete(dsortCollection, path.Join(checkpointRecordsKey, managerUUID, strconv.Itoa(idx))); err != nil {
+			return
+		}
+	}
+}
+
+func (mg *ManagerGroup) saveCreated(managerUUID, shardName string) error {
+	return mg.db.SetString(dsortCollection, path.Join(checkpointCreatedKey, managerUUID, shardName), "")
+}
+
+func (mg *ManagerGroup) loadCreated(managerUUID string) ([]string, error) {
+	prefix := path.Join(checkpointCreatedKey, managerUUID) + "/"
+	records, err := mg.db.GetAll(dsortCollection, prefix)
+	if err != nil && !dbdriver.IsErrNotFound(err) {
+		return nil, err
+	}
+	created := make([]string, 0, len(records))
+	for key := range records {
+		created = append(created, key[len(prefix):])
+	}
+	sort.Strings(created)
+	return created, nil
+}
+
+// removeCheckpoint removes the checkpoint of the job (if exists).
+func (mg *ManagerGroup) removeCheckpoint(managerUUID string) {
+	// Delete only returns err when record does not exist, which should be ignored
+	_ = mg.db.Delete(dsortCollection, path.Join(checkpointsKey, managerUUID))
+	mg.removeCheckpointRecords(managerUUID, 0)
+	_ = mg.db.Delete(dsortCollection, path.Join(checkpointShardsKey, managerUUID))
+	created, err := mg.loadCreated(managerUUID)
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	for _, shardName := range created {
+		_ = mg.db.Delete(dsortCollection, path.Join(checkpointCreatedKey, managerUUID, shardName))
+	}
+}
+
+// discardCheckpoint removes the checkpoint of the job which is not going to be
+// resumed together with the contents extracted to disk which it refers to.
+func (mg *ManagerGroup) discardCheckpoint(managerUUID string) {
+	if cp, err := mg.loadCheckpoint(managerUUID); err == nil && cp.RS != nil && ctx.node != nil {
+		var (
+			daemonID = ctx.node.DaemonID
+			rs       = cp.RS
+		)
+		if records, err := mg.loadCheckpointRecords(managerUUID); err == nil {
+			extract.RemoveExtracted(rs.Bucket, rs.Provider, daemonID, records)
+		}
+		md := &CreationPhaseMetadata{}
+		if err := mg.loadCheckpointBlob(path.Join(checkpointShardsKey, managerUUID), md); err == nil {
+			for _, shard := range md.Shards {
+				extract.RemoveExtracted(rs.Bucket, rs.Provider, daemonID, shard.Records)
+			}
+		}
+	}
+	mg.removeCheckpoint(managerUUID)
+}
+
+// removeStaleCheckpoints removes checkpoints of the jobs which are not running
+// and which have not been resumed in time.
+//
+// PRECONDITION: `mg.mtx` must be locked.
+func (mg *ManagerGroup) removeStaleCheckpoints() error {
+	records, err := mg.db.GetAll(dsortCollection, checkpointsKey)
+	if err != nil {
+		if dbdriver.IsErrNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	for key, r := range records {
+		var cp checkpoint
+		if err := jsoniter.Unmarshal([]byte(r), &cp); err != nil {
+			return err
+		}
+		managerUUID := path.Base(key)
+		if _, running := mg.managers[managerUUID]; running {
+			continue
+		}
+		if time.Since(cp.Saved) > checkpointTTL {
+			mg.discardCheckpoint(managerUUID)
+		}
+	}
+	return nil
+}
+
+// CheckpointInfo returns the information about the checkpoint of the job
+// that is required to resume it.
+func (mg *ManagerGroup) CheckpointInfo(managerUUID string) (*CheckpointInfo, error) {
+	if m, exists := mg.Get(managerUUID); exists && m.inProgress() {
+		return nil, errors.Errorf("%s job %q is still in progress", cmn.DSortName, managerUUID)
+	}
+	cp, err := mg.loadCheckpoint(managerUUID)
+	if err != nil {
+		return nil, err
+	}
+	info := &CheckpointInfo{Phase: cp.Phase, RS: cp.RS, Targets: cp.Targets}
+	if cp.Phase < phaseDistributed {
+		return info, nil
+	}
+	if info.Created, err = mg.loadCreated(managerUUID); err != nil {
+		return nil, err
+	}
+	md := &CreationPhaseMetadata{}
+	if err := mg.loadCheckpointBlob(path.Join(checkpointShardsKey, managerUUID), md); err != nil {
+		return nil, err
+	}
+	md.skipCreated(info.Created)
+	info.Refs = make(map[string]int64)
+	for _, shard := range md.Shards {
+		for _, record := range shard.Records.All() {
+			info.Refs[record.DaemonID] += int64(len(record.Objects))
+		}
+	}
+	return info, nil
+}
+
+// skipCreated removes the shards which have been already created.
+func (md *CreationPhaseMetadata) skipCreated(created []string) {
+	if len(created) == 0 {
+		return
+	}
+	skip := cmn.NewStringSet(created...)
+	shards := md.Shards[:0]
+	for _, shard := range md.Shards {
+		if !skip.Contains(shard.Name) {
+			shards = append(shards, shard)
+		}
+	}
+	md.Shards = shards
+	for _, shardName := range created {
+		delete(md.SendOrder, shardName)
+	}
+}
+
+// mergeCheckpoints combines checkpoints reported by all the targets into the
+// resume message. Job can be resumed only by the same set of targets.
+func mergeCheckpoints(managerUUID string, infos map[string]*CheckpointInfo) (*ParsedRequestSpec, *ResumeMsg, error) {
+	if len(infos) == 0 {
+		return nil, nil, errors.Errorf("%s job %q: no checkpoints", cmn.DSortName, managerUUID)
+	}
+	var (
+		rs      *ParsedRequestSpec
+		created = make(cmn.StringSet)
+		msg     = &ResumeMsg{ManagerUUID: managerUUID, Phase: phaseDistributed, Refs: make(map[string]int64)}
+	)
+	for tid, info := range infos {
+		if len(info.Targets) != len(infos) {
+			return nil, nil, errors.Errorf("%s job %q was started with %d targets, got %d",
+				cmn.DSortName, managerUUID, len(info.Targets), len(infos))
+		}
+		for _, id := range info.Targets {
+			if _, ok := infos[id]; !ok {
+				return nil, nil, errors.Errorf("%s job %q: target %s (reported by %s) is not present in the cluster",
+					cmn.DSortName, managerUUID, id, tid)
+			}
+		}
+		if info.Phase < msg.Phase {
+			msg.Phase = info.Phase
+		}
+		created.Add(info.Created...)
+		for id, refs := range info.Refs {
+			msg.Refs[id] += refs
+		}
+		rs = info.RS
+	}
+	if msg.Phase < phaseDistributed {
+		// shards are yet to be distributed
+		msg.Refs = nil
+		return rs, msg, nil
+	}
+	msg.Created = created.Keys()
+	sort.Strings(msg.Created)
+	return rs, msg, nil
+}
+
+//////////////////////////
+// checkpoint (manager) //
+//////////////////////////
+
+func (m *Manager) newCheckpoint(phase int32) *checkpoint {
+	rs := *m.rs
+	rs.Resume = nil
+	targets := make([]string, 0, len(m.smap.Tmap))
+	for tid := range m.smap.Tmap {
+		targets = append(targets, tid)
+	}
+	sort.Strings(targets)
+
+	metrics := m.Metrics.Extraction
+	metrics.Lock()
+	extracted := metrics.ExtractedRecordCnt
+	metrics.Unlock()
+	return &checkpoint{
+		Phase:        phase,
+		RS:           &rs,
+		Targets:      targets,
+		Extracted:    extracted,
+		Compressed:   m.totalCompressedSize(),
+		Uncompressed: m.totalUncompressedSize(),
+		Saved:        time.Now(),
+	}
+}
+
+// checkpoint persists the progress of this target together with the state of
+// the completed phase (see: save). Failure to checkpoint does not fail the job -
+// it only cannot be resumed from the given phase.
+func (m *Manager) checkpoint(phase int32, save func() error) {
+	if m.mg == nil {
+		return
+	}
+	if save != nil {
+		if err := save(); err != nil {
+			glog.Errorf("%s %s: failed to checkpoint phase %d, err: %v", cmn.DSortName, m.ManagerUUID, phase, err)
+			return
+		}
+	}
+	if err := m.mg.saveCheckpoint(m.ManagerUUID, m.newCheckpoint(phase)); err != nil {
+		glog.Errorf("%s %s: failed to checkpoint phase %d, err: %v", cmn.DSortName, m.ManagerUUID, phase, err)
+		return
+	}
+	m.checkpointed.Store(phase)
+}
+
+// checkpointExtraction persists the records extracted by this target. Records
+// which refer to their contents in memory are made durable upon resume by
+// referring to the offset in the input shards (or in the decompressed tarballs
+// in case of .tar.gz) - or, if offsets are not supported (e.g. .zip), the
+// contents are spilled to disk beforehand.
+func (m *Manager) checkpointExtraction() {
+	m.checkpoint(phaseExtracted, func() error {
+		if err := m.recManager.SpillToDisk(); err != nil {
+			return err
+		}
+		return m.mg.saveCheckpointRecords(m.ManagerUUID, m.recManager.Records)
+	})
+}
+
+// checkpointDistribution persists the metadata of the shards to be created.
+// Since the shards refer to the records extracted by all the targets, it is
+// only useful when the extraction has been checkpointed as well.
+func (m *Manager) checkpointDistribution() {
+	if m.checkpointed.Load() < phaseExtracted {
+		return
+	}
+	m.checkpoint(phaseDistributed, func() error {
+		return m.mg.saveCheckpointBlob(path.Join(checkpointShardsKey, m.ManagerUUID), &m.creationPhase.metadata)
+	})
+}
+
+func (m *Manager) checkpointCreated(shardName string) {
+	if m.mg == nil || m.checkpointed.Load() < phaseDistributed {
+		return
+	}
+	if err := m.mg.saveCreated(m.ManagerUUID, shardName); err != nil {
+		glog.Errorf("%s %s: failed to checkpoint shard %q, err: %v", cmn.DSortName, m.ManagerUUID, shardName, err)
+	}
+}
+
+// initResume loads the state of the resumed job from this target's checkpoint.
+//
+// PRECONDITION: `m.recManager` must be initialized.
+func (m *Manager) initResume(msg *ResumeMsg) (err error) {
+	r := &resumeState{msg: msg}
+	if r.cp, err = m.mg.loadCheckpoint(msg.ManagerUUID); err != nil {
+		return fmt.Errorf("failed to load checkpoint of %s job %q, err: %v", cmn.DSortName, msg.ManagerUUID, err)
+	}
+	switch {
+	case msg.Phase >= phaseDistributed:
+		md := &CreationPhaseMetadata{}
+		if err := m.mg.loadCheckpointBlob(path.Join(checkpointShardsKey, msg.ManagerUUID), md); err != nil {
+			return fmt.Errorf("failed to load shards of %s job %q, err: %v", cmn.DSortName, msg.ManagerUUID, err)
+		}
+		if r.created, err = m.mg.loadCreated(msg.ManagerUUID); err != nil {
+			return err
+		}
+		// NOTE: records of the created shards are made durable as well so
+		// that their contents (if extracted to disk) are taken over.
+		for _, shard := range md.Shards {
+			m.recManager.MakeDurable(shard.Records)
+		}
+		for _, shard := range md.SendOrder {
+			m.recManager.MakeDurable(shard.Records)
+		}
+		md.skipCreated(msg.Created)
+		r.phase, r.metadata = phaseDistributed, md
+	case r.cp.Phase >= phaseExtracted:
+		records, err := m.mg.loadCheckpointRecords(msg.ManagerUUID)
+		if err != nil {
+			return fmt.Errorf("failed to load records of %s job %q, err: %v", cmn.DSortName, msg.ManagerUUID, err)
+		}
+		m.recManager.MakeDurable(records)
+		r.phase, r.records = phaseExtracted, records
+	}
+	m.resume = r
+	return nil
+}
+
+// restore restores the state of the resumed job (see: initResume) and takes
+// over its checkpoint. Returns the phase from which the job is resumed.
+func (m *Manager) restore() int32 {
+	r := m.resume
+	if r == nil {
+		m.checkpoint(phaseNone, nil)
+		return phaseNone
+	}
+	glog.Infof("%s %s: resuming %q from phase %d", cmn.DSortName, m.ManagerUUID, r.msg.ManagerUUID, r.phase)
+	m.compression.compressed.Store(r.cp.Compressed)
+	m.compression.uncompressed.Store(r.cp.Uncompressed)
+
+	switch r.phase {
+	case phaseDistributed:
+		// Records are no longer needed: all the targets have received
+		// the metadata of the shards they create.
+		m.checkpointed.Store(phaseExtracted)
+		m.creationPhase.metadata = *r.metadata
+		m.checkpointDistribution()
+		for _, shardName := range r.created {
+			m.checkpointCreated(shardName)
+		}
+	case phaseExtracted:
+		m.recManager.EnqueueRecords(r.records)
+		m.recManager.MergeEnqueuedRecords()
+		m.checkpointExtraction()
+	default:
+		m.checkpoint(phaseNone, nil)
+	}
+
+	m.mg.removeCheckpoint(r.msg.ManagerUUID)
+	return r.phase
+}
+
+// skipExtraction replaces phase 1. when the records have been restored.
+func (m *Manager) skipExtraction(phase int32) {
+	metrics := m.Metrics.Extraction
+	metrics.begin()
+	metrics.Lock()
+	metrics.TotalCnt = m.rs.InputFormat.Template.Count()
+	metrics.ExtractedRecordCnt = m.resume.cp.Extracted
+	metrics.Unlock()
+	metrics.finish()
+
+	m.dsorter.postExtraction()
+	if phase < phaseDistributed {
+		m.incrementRef(m.resume.cp.Extracted)
+	}
+}
+
+// skipDistribution replaces phases 2. and 3. when the metadata of the shards
+// has been restored.
+func (m *Manager) skipDistribution() {
+	metrics := m.Metrics.Sorting
+	metrics.begin()
+	metrics.finish()
+
+	m.incrementRef(m.resume.msg.Refs[m.ctx.node.DaemonID])
+	m.startShardCreation <- struct{}{}
+}
@@ -0,0 +1,278 @@
+// Package dsort provides distributed massively parallel resharding for very large datasets.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package dsort
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	"github.com/NVIDIA/aistore/dsort/filetype"
+	"github.com/NVIDIA/aistore/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checkpoint", func() {
+	var (
+		mgrp    *ManagerGroup
+		validRS = &ParsedRequestSpec{Extension: cmn.ExtTar, Algorithm: &SortAlgorithm{Kind: SortKindNone}, MaxMemUsage: cmn.ParsedQuantity{Type: cmn.QuantityPercent, Value: 0}, DSorterType: DSorterGeneralType}
+	)
+
+	newRecord := func(name string, objCnt int) *extract.Record {
+		record := &extract.Record{Name: name, DaemonID: "target"}
+		for i := 0; i < objCnt; i++ {
+			ext := []string{".txt", ".cls", ".jpg"}[i]
+			record.Objects = append(record.Objects, &extract.RecordObj{
+				ContentPath: name + ext,
+				StoreType:   extract.SGLStoreType,
+				Offset:      1024,
+				Size:        10,
+				Extension:   ext,
+			})
+		}
+		return record
+	}
+
+	newShard := func(name string, records ...*extract.Record) *extract.Shard {
+		shard := &extract.Shard{Name: name, Records: extract.NewRecords(len(records))}
+		shard.Records.Insert(records...)
+		return shard
+	}
+
+	// startJob initializes the job and checkpoints it up to the distribution
+	// phase with the first shard being created.
+	startJob := func(managerUUID string) *Manager {
+		m, err := mgrp.Add(managerUUID)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(m.init(validRS)).To(Succeed())
+		m.unlock()
+
+		Expect(m.restore()).To(Equal(phaseNone))
+		m.recManager.Records.Insert(newRecord("shard|rec1", 2), newRecord("shard|rec2", 1))
+		m.checkpointExtraction()
+		Expect(m.checkpointed.Load()).To(Equal(phaseExtracted))
+
+		m.creationPhase.metadata = CreationPhaseMetadata{
+			Shards: []*extract.Shard{
+				newShard("out1.tar", newRecord("shard|rec1", 2)),
+				newShard("out2.tar", newRecord("shard|rec2", 1)),
+			},
+		}
+		m.checkpointDistribution()
+		Expect(m.checkpointed.Load()).To(Equal(phaseDistributed))
+		m.checkpointCreated("out1.tar")
+		m.setInProgressTo(false)
+		return m
+	}
+
+	BeforeEach(func() {
+		err := cmn.CreateDir(testingConfigDir)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		config := cmn.GCO.BeginUpdate()
+		config.Confdir = testingConfigDir
+		cmn.GCO.CommitUpdate(config)
+		mgrp = NewManagerGroup(dbdriver.NewDBMock())
+
+		fs.Init()
+		fs.Add(testingConfigDir, "daeID")
+
+		ctx.smapOwner = newTestSmap("target")
+		ctx.node = ctx.smapOwner.Get().Tmap["target"]
+		ctx.node.DaemonID = "target"
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(testingConfigDir)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("should report checkpoint of the job", func() {
+		startJob("uuid")
+
+		info, err := mgrp.CheckpointInfo("uuid")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(info.Phase).To(Equal(phaseDistributed))
+		Expect(info.Targets).To(Equal([]string{"target"}))
+		Expect(info.Created).To(Equal([]string{"out1.tar"}))
+		Expect(info.Refs).To(Equal(map[string]int64{"target": 1}))
+		Expect(info.RS.Extension).To(Equal(cmn.ExtTar))
+	})
+
+	It("should not report checkpoint of the job in progress", func() {
+		m := startJob("uuid")
+		m.setInProgressTo(true)
+
+		_, err := mgrp.CheckpointInfo("uuid")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should resume from distributed phase and take over the checkpoint", func() {
+		startJob("uuid")
+		info, err := mgrp.CheckpointInfo("uuid")
+		Expect(err).ShouldNot(HaveOccurred())
+		rs, msg, err := mergeCheckpoints("uuid", map[string]*CheckpointInfo{"target": info})
+		Expect(err).ShouldNot(HaveOccurred())
+		rs.Resume = msg
+
+		m, err := mgrp.Add("uuid2")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(m.init(rs)).To(Succeed())
+		m.unlock()
+
+		Expect(m.resume.metadata.Shards).To(HaveLen(1))
+		shard := m.resume.metadata.Shards[0]
+		Expect(shard.Name).To(Equal("out2.tar"))
+		obj := shard.Records.All()[0].Objects[0]
+		Expect(obj.StoreType).To(Equal(extract.OffsetStoreType))
+		Expect(obj.ContentPath).To(Equal("shard.tar"))
+		Expect(obj.MetadataSize).To(Equal(m.extractCreator.MetadataSize()))
+
+		Expect(m.restore()).To(Equal(phaseDistributed))
+		_, err = mgrp.loadCheckpoint("uuid")
+		Expect(dbdriver.IsErrNotFound(err)).To(BeTrue())
+
+		m.setInProgressTo(false)
+		info, err = mgrp.CheckpointInfo("uuid2")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(info.Phase).To(Equal(phaseDistributed))
+		Expect(info.Created).To(Equal([]string{"out1.tar"}))
+		Expect(info.Refs).To(Equal(map[string]int64{"target": 1}))
+	})
+
+	It("should resume from extraction phase", func() {
+		startJob("uuid")
+		rs := *validRS
+		rs.Resume = &ResumeMsg{ManagerUUID: "uuid", Phase: phaseExtracted}
+
+		m, err := mgrp.Add("uuid2")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(m.init(&rs)).To(Succeed())
+		m.unlock()
+
+		Expect(m.restore()).To(Equal(phaseExtracted))
+		Expect(m.recManager.Records.Len()).To(Equal(2))
+		for _, record := range m.recManager.Records.All() {
+			for _, obj := range record.Objects {
+				Expect(obj.StoreType).To(Equal(extract.OffsetStoreType))
+			}
+		}
+		Expect(m.checkpointed.Load()).To(Equal(phaseExtracted))
+	})
+
+	It("should store records in chunks", func() {
+		m, err := mgrp.Add("uuid")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(m.init(validRS)).To(Succeed())
+		m.unlock()
+
+		Expect(m.restore()).To(Equal(phaseNone))
+		for i := 0; i <= checkpointRecordsChunk; i++ {
+			m.recManager.Records.Insert(newRecord(fmt.Sprintf("shard|rec%d", i), 1))
+		}
+		m.checkpointExtraction()
+		Expect(m.checkpointed.Load()).To(Equal(phaseExtracted))
+
+		records, err := mgrp.loadCheckpointRecords("uuid")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(records.Len()).To(Equal(checkpointRecordsChunk + 1))
+		_, exists := records.Find(fmt.Sprintf("shard|rec%d", checkpointRecordsChunk))
+		Expect(exists).To(BeTrue())
+
+		mgrp.removeCheckpoint("uuid")
+		_, err = mgrp.loadCheckpointRecords("uuid")
+		Expect(dbdriver.IsErrNotFound(err)).To(BeTrue())
+		m.setInProgressTo(false)
+	})
+
+	It("should resume from extraction phase with records extracted to disk", func() {
+		rs := *validRS
+		rs.Extension = cmn.ExtZip
+		rs.Bucket = "bucket"
+		rs.Provider = cmn.ProviderAIS
+		fs.CSM.RegisterContentType(fs.ObjectType, &fs.ObjectContentResolver{})
+		fs.CSM.RegisterContentType(filetype.DSortFileType, &filetype.DSortFile{})
+
+		m, err := mgrp.Add("uuid")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(m.init(&rs)).To(Succeed())
+		m.unlock()
+
+		Expect(m.restore()).To(Equal(phaseNone))
+		record := newRecord("shard|rec1", 1)
+		record.Objects[0].StoreType = extract.DiskStoreType
+		m.recManager.Records.Insert(record)
+		m.checkpointExtraction()
+		Expect(m.checkpointed.Load()).To(Equal(phaseExtracted))
+		m.setInProgressTo(false)
+
+		resumeRS := rs
+		resumeRS.Resume = &ResumeMsg{ManagerUUID: "uuid", Phase: phaseExtracted}
+		m, err = mgrp.Add("uuid2")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(m.init(&resumeRS)).To(Succeed())
+		m.unlock()
+
+		Expect(m.restore()).To(Equal(phaseExtracted))
+		obj := m.recManager.Records.All()[0].Objects[0]
+		Expect(obj.StoreType).To(Equal(extract.DiskStoreType))
+		_, adopted := m.recManager.ExtractionPaths().Load(m.recManager.FullContentPath(obj))
+		Expect(adopted).To(BeTrue())
+		m.setInProgressTo(false)
+	})
+
+	It("should remove checkpoint when manager is removed", func() {
+		m := startJob("uuid")
+		m.Metrics.Archived.Store(true)
+
+		Expect(mgrp.Remove("uuid")).To(Succeed())
+		_, err := mgrp.CheckpointInfo("uuid")
+		Expect(dbdriver.IsErrNotFound(err)).To(BeTrue())
+		created, err := mgrp.loadCreated("uuid")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(created).To(BeEmpty())
+	})
+
+	Context("merge", func() {
+		It("should resume from the last phase completed by all targets", func() {
+			infos := map[string]*CheckpointInfo{
+				"t1": {Phase: phaseDistributed, Targets: []string{"t1", "t2"}, Created: []string{"a"}, Refs: map[string]int64{"t1": 1}},
+				"t2": {Phase: phaseExtracted, Targets: []string{"t1", "t2"}},
+			}
+			_, msg, err := mergeCheckpoints("uuid", infos)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(msg.Phase).To(Equal(phaseExtracted))
+			Expect(msg.Created).To(BeEmpty())
+			Expect(msg.Refs).To(BeEmpty())
+		})
+
+		It("should merge created shards and references", func() {
+			infos := map[string]*CheckpointInfo{
+				"t1": {Phase: phaseDistributed, Targets: []string{"t1", "t2"}, Created: []string{"b"}, Refs: map[string]int64{"t1": 1, "t2": 2}},
+				"t2": {Phase: phaseDistributed, Targets: []string{"t1", "t2"}, Created: []string{"a"}, Refs: map[string]int64{"t2": 3}},
+			}
+			_, msg, err := mergeCheckpoints("uuid", infos)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(msg.Phase).To(Equal(phaseDistributed))
+			Expect(msg.Created).To(Equal([]string{"a", "b"}))
+			Expect(msg.Refs).To(Equal(map[string]int64{"t1": 1, "t2": 5}))
+		})
+
+		It("should fail when targets have changed", func() {
+			infos := map[string]*CheckpointInfo{
+				"t1": {Phase: phaseDistributed, Targets: []string{"t1", "t3"}},
+				"t2": {Phase: phaseDistributed, Targets: []string{"t1", "t2"}},
+			}
+			_, _, err := mergeCheckpoints("uuid", infos)
+			Expect(err).Should(HaveOccurred())
+
+			_, _, err = mergeCheckpoints("uuid", map[string]*CheckpointInfo{"t1": infos["t1"]})
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
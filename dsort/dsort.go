@@ -15,7 +15,6 @@ import (
 	"math"
 	"net/http"
 	"net/url"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -87,11 +86,49 @@ func (m *Manager) start() (err error) {
 		return err
 	}
 
+	resumed := m.restore()
+
 	// Phase 1.
-	if err := m.extractLocalShards(); err != nil {
+	if resumed >= phaseExtracted {
+		m.skipExtraction(resumed)
+	} else {
+		if err := m.extractLocalShards(); err != nil {
+			return err
+		}
+		m.checkpointExtraction()
+	}
+
+	// Phases 2. and 3.
+	if resumed >= phaseDistributed {
+		m.skipDistribution()
+	} else if err := m.distribute(); err != nil {
+		return err
+	}
+
+	cmn.FreeMemToOS()
+
+	// Wait for signal to start shard creations. This will happen when manager
+	// notice that the specification for shards to be created locally was received.
+	select {
+	case <-m.startShardCreation:
+		break
+	case <-m.listenAborted():
+		return newDsortAbortedError(m.ManagerUUID)
+	}
+
+	// After each target participates in the cluster-wide record distribution,
+	// start listening for the signal to start creating shards locally.
+	if err := m.dsorter.createShardsLocally(); err != nil {
 		return err
 	}
 
+	glog.Infof("finished %s %s successfully", cmn.DSortName, m.ManagerUUID)
+	return nil
+}
+
+// distribute distributes the records across the targets (phase 2.) and then
+// the shards to be created (phase 3.).
+func (m *Manager) distribute() error {
 	s := binary.BigEndian.Uint64(m.rs.TargetOrderSalt)
 	targetOrder := randomTargetOrder(s, m.smap.Tmap)
 	glog.V(4).Infof("final target in targetOrder => URL: %s, Daemon ID: %s",
@@ -121,25 +158,6 @@ func (m *Manager) start() (err error) {
 			return err
 		}
 	}
-
-	cmn.FreeMemToOS()
-
-	// Wait for signal to start shard creations. This will happen when manager
-	// notice that the specification for shards to be created locally was received.
-	select {
-	case <-m.startShardCreation:
-		break
-	case <-m.listenAborted():
-		return newDsortAbortedError(m.ManagerUUID)
-	}
-
-	// After each target participates in the cluster-wide record distribution,
-	// start listening for the signal to start creating shards locally.
-	if err := m.dsorter.createShardsLocally(); err != nil {
-		return err
-	}
-
-	glog.Infof("finished %s %s successfully", cmn.DSortName, m.ManagerUUID)
 	return nil
 }
 
@@ -188,7 +206,7 @@ func (m *Manager) extractShard(name string, metrics *LocalExtraction) func() err
 		}
 
 		lom.Lock(false)
-		f, err := os.Open(lom.FQN)
+		f, err := lom.Open()
 		if err != nil {
 			phaseInfo.adjuster.releaseSema(lom.ParsedFQN.MpathInfo)
 			lom.Unlock(false)
@@ -219,7 +237,7 @@ func (m *Manager) extractShard(name string, metrics *LocalExtraction) func() err
 
 		m.dsorter.postShardExtraction(expectedUncompressedSize) // schedule unreserving reserved memory on next memory update
 		if err != nil {
-			return errors.Errorf("error in ExtractShard, file: %s, err: %v", f.Name(), err)
+			return errors.Errorf("error in ExtractShard, file: %s, err: %v", lom.FQN, err)
 		}
 
 		metrics.Lock()
@@ -412,7 +430,7 @@ func (m *Manager) createShard(s *extract.Shard) (err error) {
 			goto exit
 		}
 
-		file, err := cmn.NewFileHandle(lom.FQN)
+		file, err := lom.Open()
 		if err != nil {
 			return err
 		}
@@ -448,6 +466,8 @@ func (m *Manager) createShard(s *extract.Shard) (err error) {
 	}
 
 exit:
+	m.checkpointCreated(shardName)
+
 	metrics.Lock()
 	metrics.CreatedCnt++
 	if si.DaemonID != m.ctx.node.DaemonID {
@@ -631,7 +651,7 @@ func (m *Manager) generateShardsWithTemplate(maxSize int64) ([]*extract.Shard, e
 			return nil, errors.Errorf("number of shards to be created exceeds expected number of shards (%d)", shardCount)
 		}
 		shard := &extract.Shard{
-			Name: name + m.rs.Extension,
+			Name: name + m.rs.OutputExtension,
 		}
 
 		shard.Size = curShardSize
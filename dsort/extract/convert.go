@@ -0,0 +1,138 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"archive/tar"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Output compression (see `RequestSpec.OutputCompression`) - applies only to
+// compressed output formats (.tgz and .zip).
+const (
+	CompressionDefault = ""        // .tgz: fastest, .zip: deflate with default level
+	CompressionNone    = "none"    // .tgz: gzip without compression, .zip: store
+	CompressionFastest = "fastest" // best speed
+	CompressionBest    = "best"    // best compression
+)
+
+var SupportedCompressions = []string{CompressionDefault, CompressionNone, CompressionFastest, CompressionBest}
+
+// interface guard
+var _ ExtractCreator = &convertExtractCreator{}
+
+type (
+	// convertExtractCreator extracts shards of one format and creates shards of
+	// another. Metadata of the extracted records (e.g., tar headers) is specific
+	// to the input format and therefore is replaced with the (default) metadata
+	// of the output format generated from the record names. Everything but
+	// CreateShard is delegated to the input format.
+	convertExtractCreator struct {
+		ExtractCreator // input
+		output         ExtractCreator
+	}
+
+	// skipWriter discards the first `skip` bytes written to it
+	skipWriter struct {
+		w    io.Writer
+		skip int64
+	}
+)
+
+func ConvertExtractCreator(input, output ExtractCreator) ExtractCreator {
+	return &convertExtractCreator{ExtractCreator: input, output: output}
+}
+
+func (c *convertExtractCreator) CreateShard(s *Shard, w io.Writer, loadContent LoadContentFunc) (int64, error) {
+	var (
+		records = make([]*Record, 0, s.Records.Len())
+		objs    = make(map[*RecordObj]*RecordObj, s.Records.Len()) // output => input
+		mds     = make(map[*RecordObj][]byte, s.Records.Len())
+	)
+	for _, rec := range s.Records.All() {
+		nrec := &Record{Key: rec.Key, Name: rec.Name, DaemonID: rec.DaemonID, Objects: make([]*RecordObj, 0, len(rec.Objects))}
+		for _, obj := range rec.Objects {
+			nobj := *obj
+			md := c.metadata(recordName(rec) + obj.Extension)
+			nobj.MetadataSize = int64(len(md))
+			if nobj.StoreType == OffsetStoreType {
+				// cannot be copied as is (raw) into the output shard
+				nobj.StoreType = DiskStoreType
+			}
+			objs[&nobj], mds[&nobj] = obj, md
+			nrec.Objects = append(nrec.Objects, &nobj)
+		}
+		records = append(records, nrec)
+	}
+
+	convert := func(w io.Writer, rec *Record, nobj *RecordObj) (int64, error) {
+		obj, md := objs[nobj], mds[nobj]
+		if _, err := w.Write(md); err != nil {
+			return 0, err
+		}
+		n, err := loadContent(&skipWriter{w: w, skip: obj.MetadataSize}, rec, obj)
+		return int64(len(md)) + cmn.MaxI64(n-obj.MetadataSize, 0), err
+	}
+	return c.output.CreateShard(&Shard{Size: s.Size, Records: &Records{arr: records}, Name: s.Name}, w, convert)
+}
+
+// metadata generates the metadata of the output format
+func (c *convertExtractCreator) metadata(name string) []byte {
+	switch c.output.(type) {
+	case *tarExtractCreator, *targzExtractCreator:
+		return cmn.MustMarshal(tarFileHeader{Name: name, Typeflag: tar.TypeReg, Mode: 0o644})
+	case *zipExtractCreator:
+		return cmn.MustMarshal(zipFileHeader{Name: name})
+	default:
+		return nil // (tfrecord, msgpack) no metadata
+	}
+}
+
+func (sw *skipWriter) Write(p []byte) (int, error) {
+	if sw.skip >= int64(len(p)) {
+		sw.skip -= int64(len(p))
+		return len(p), nil
+	}
+	skipped := int(sw.skip)
+	sw.skip = 0
+	n, err := sw.w.Write(p[skipped:])
+	return skipped + n, err
+}
+
+// recordName returns the name of the record within its (input) shard.
+func recordName(rec *Record) string {
+	// record name is unique across shards: "<shard>|<name>"
+	if i := strings.IndexByte(rec.Name, '|'); i >= 0 {
+		return rec.Name[i+1:]
+	}
+	return rec.Name
+}
+
+func gzipLevel(compression string) int {
+	switch compression {
+	case CompressionNone:
+		return gzip.NoCompression
+	case CompressionBest:
+		return gzip.BestCompression
+	default:
+		return gzip.BestSpeed
+	}
+}
+
+func flateLevel(compression string) int {
+	switch compression {
+	case CompressionFastest:
+		return flate.BestSpeed
+	case CompressionBest:
+		return flate.BestCompression
+	default:
+		return flate.DefaultCompression
+	}
+}
@@ -0,0 +1,198 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// recordsCollector extracts records to memory
+type recordsCollector struct {
+	records  *Records
+	contents map[string][]byte // by record name + extension
+}
+
+func newRecordsCollector() *recordsCollector {
+	return &recordsCollector{records: NewRecords(10), contents: make(map[string][]byte)}
+}
+
+func (c *recordsCollector) ExtractRecordWithBuffer(args extractRecordArgs) (int64, error) {
+	b, err := ioutil.ReadAll(args.r)
+	if err != nil {
+		return 0, err
+	}
+	ext := Ext(args.recordName)
+	name := strings.TrimSuffix(args.shardName, Ext(args.shardName)) + "|" + strings.TrimSuffix(args.recordName, ext)
+	c.records.Insert(&Record{Name: name, Objects: []*RecordObj{{Size: int64(len(b)), Extension: ext}}})
+	c.contents[name+ext] = b
+	return int64(len(b)), nil
+}
+
+func (c *recordsCollector) loadContent(w io.Writer, rec *Record, obj *RecordObj) (int64, error) {
+	return io.Copy(w, bytes.NewReader(c.contents[rec.Name+obj.Extension]))
+}
+
+func extractFrom(ec ExtractCreator, shardName string, b []byte) (*recordsCollector, int, error) {
+	var (
+		c   = newRecordsCollector()
+		lom = &cluster.LOM{ParsedFQN: fs.ParsedFQN{ObjName: shardName}}
+	)
+	_, cnt, err := ec.ExtractShard(lom, io.NewSectionReader(bytes.NewReader(b), 0, int64(len(b))), c, false)
+	return c, cnt, err
+}
+
+func makeTFRecord(records ...string) []byte {
+	buf := &bytes.Buffer{}
+	for _, rec := range records {
+		hdr := tfrHeader(uint64(len(rec)))
+		buf.Write(hdr[:])
+		buf.WriteString(rec)
+		var footer [tfrFooterLen]byte
+		binary.LittleEndian.PutUint32(footer[:], tfrMaskCRC(crc32.Checksum([]byte(rec), crc32c)))
+		buf.Write(footer[:])
+	}
+	return buf.Bytes()
+}
+
+var _ = Describe("Formats", func() {
+	t := cluster.NewTargetMock(nil)
+	t.SmallMMSA() // NOTE: page MMSA relies on (initialized) small MMSA for small allocations
+
+	Context("tfrecord", func() {
+		ec := NewTFRecordExtractCreator(t)
+
+		It("should extract and create shard", func() {
+			b := makeTFRecord("first", "", "third record")
+			c, cnt, err := extractFrom(ec, "shard.tfrecord", b)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cnt).To(Equal(3))
+			Expect(c.contents["shard|000000002"]).To(Equal([]byte("third record")))
+
+			out := &bytes.Buffer{}
+			written, err := ec.CreateShard(&Shard{Records: c.records}, out, c.loadContent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written).To(BeEquivalentTo(out.Len()))
+			Expect(out.Bytes()).To(Equal(b))
+		})
+
+		It("should detect corrupted records", func() {
+			b := makeTFRecord("first", "second")
+			b[len(b)-tfrFooterLen-1] ^= 1 // data
+			_, _, err := extractFrom(ec, "shard.tfrecord", b)
+			Expect(err).To(HaveOccurred())
+
+			b = makeTFRecord("first")
+			b[0] ^= 1 // length
+			_, _, err = extractFrom(ec, "shard.tfrecord", b)
+			Expect(err).To(HaveOccurred())
+
+			b = makeTFRecord("first")
+			_, _, err = extractFrom(ec, "shard.tfrecord", b[:len(b)-1])
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("msgpack", func() {
+		ec := NewMsgpackExtractCreator(t)
+
+		It("should extract and create shard", func() {
+			var b []byte
+			b = msgp.AppendMapHeader(b, 3)
+			b = msgp.AppendString(b, msgpackKeyField)
+			b = msgp.AppendString(b, "sample1")
+			b = msgp.AppendString(b, "jpg")
+			b = msgp.AppendBytes(b, []byte{0xff, 0xd8, 0xff})
+			b = msgp.AppendString(b, "cls")
+			b = msgp.AppendString(b, "7") // str is extracted as well
+			b = msgp.AppendMapHeader(b, 2)
+			b = msgp.AppendString(b, msgpackKeyField)
+			b = msgp.AppendString(b, "sample2")
+			b = msgp.AppendString(b, "jpg")
+			b = msgp.AppendBytes(b, []byte{0xff, 0xd8})
+
+			c, cnt, err := extractFrom(ec, "shard.msgpack", b)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cnt).To(Equal(3))
+			Expect(c.records.Len()).To(Equal(2))
+			Expect(c.contents["shard|sample1.cls"]).To(Equal([]byte("7")))
+
+			out := &bytes.Buffer{}
+			written, err := ec.CreateShard(&Shard{Records: c.records}, out, c.loadContent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written).To(BeEquivalentTo(out.Len()))
+
+			again, cnt, err := extractFrom(ec, "other.msgpack", out.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cnt).To(Equal(3))
+			for name, content := range c.contents {
+				Expect(again.contents[strings.Replace(name, "shard|", "other|", 1)]).To(Equal(content))
+			}
+		})
+
+		It("should fail without key", func() {
+			var b []byte
+			b = msgp.AppendMapHeader(b, 1)
+			b = msgp.AppendString(b, "jpg")
+			b = msgp.AppendBytes(b, []byte{0xff})
+			_, _, err := extractFrom(ec, "shard.msgpack", b)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("convert", func() {
+		It("should convert tfrecord to msgpack", func() {
+			var (
+				input  = NewTFRecordExtractCreator(t)
+				output = NewMsgpackExtractCreator(t)
+				ec     = ConvertExtractCreator(input, output)
+			)
+			c, _, err := extractFrom(ec, "shard.tfrecord", makeTFRecord("first", "second"))
+			Expect(err).NotTo(HaveOccurred())
+
+			out := &bytes.Buffer{}
+			written, err := ec.CreateShard(&Shard{Records: c.records}, out, c.loadContent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written).To(BeEquivalentTo(out.Len()))
+
+			again, cnt, err := extractFrom(output, "shard.msgpack", out.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cnt).To(Equal(2))
+			Expect(again.records.Len()).To(Equal(2))
+		})
+
+		It("should skip input metadata", func() {
+			var (
+				output = NewTFRecordExtractCreator(t)
+				ec     = ConvertExtractCreator(NewTarExtractCreator(t), output)
+				md     = []byte("metadata")
+				rec    = &Record{Name: "shard|first", Objects: []*RecordObj{{
+					Size: 5, MetadataSize: int64(len(md)), Extension: ".txt",
+				}}}
+				records = NewRecords(1)
+			)
+			records.Insert(rec)
+			loadContent := func(w io.Writer, _ *Record, _ *RecordObj) (int64, error) {
+				return io.Copy(w, io.MultiReader(bytes.NewReader(md), strings.NewReader("first")))
+			}
+
+			out := &bytes.Buffer{}
+			written, err := ec.CreateShard(&Shard{Records: records}, out, loadContent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written).To(BeEquivalentTo(out.Len()))
+			Expect(out.Bytes()).To(Equal(makeTFRecord("first")))
+		})
+	})
+})
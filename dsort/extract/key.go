@@ -12,6 +12,7 @@ import (
 	"io"
 	"io/ioutil"
 	"strconv"
+	"time"
 
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/pkg/errors"
@@ -33,10 +34,15 @@ type (
 	SingleKeyExtractor struct {
 		name string
 		buf  *bytes.Buffer
+
+		// used by key expressions (see: KeyExpr)
+		ext   string
+		size  int64
+		mtime time.Time
 	}
 
 	KeyExtractor interface {
-		PrepareExtractor(name string, r cmn.ReadSizer, ext string) (cmn.ReadSizer, *SingleKeyExtractor, bool)
+		PrepareExtractor(name string, r cmn.ReadSizer, ext string, mtime time.Time) (cmn.ReadSizer, *SingleKeyExtractor, bool)
 
 		// ExtractKey extracts key from either name or reader (file/sgl)
 		ExtractKey(ske *SingleKeyExtractor) (interface{}, error)
@@ -63,7 +69,7 @@ func (ke *md5KeyExtractor) ExtractKey(ske *SingleKeyExtractor) (interface{}, err
 	return s, nil
 }
 
-func (ke *md5KeyExtractor) PrepareExtractor(name string, r cmn.ReadSizer, ext string) (cmn.ReadSizer, *SingleKeyExtractor, bool) {
+func (ke *md5KeyExtractor) PrepareExtractor(name string, r cmn.ReadSizer, ext string, _ time.Time) (cmn.ReadSizer, *SingleKeyExtractor, bool) {
 	return r, &SingleKeyExtractor{name: name}, false
 }
 
@@ -71,7 +77,7 @@ func NewNameKeyExtractor() (KeyExtractor, error) {
 	return &nameKeyExtractor{}, nil
 }
 
-func (ke *nameKeyExtractor) PrepareExtractor(name string, r cmn.ReadSizer, ext string) (cmn.ReadSizer, *SingleKeyExtractor, bool) {
+func (ke *nameKeyExtractor) PrepareExtractor(name string, r cmn.ReadSizer, ext string, _ time.Time) (cmn.ReadSizer, *SingleKeyExtractor, bool) {
 	return r, &SingleKeyExtractor{name: name}, false
 }
 
@@ -87,7 +93,7 @@ func NewContentKeyExtractor(ty, ext string) (KeyExtractor, error) {
 	return &contentKeyExtractor{ty: ty, ext: ext}, nil
 }
 
-func (ke *contentKeyExtractor) PrepareExtractor(name string, r cmn.ReadSizer, ext string) (cmn.ReadSizer, *SingleKeyExtractor, bool) {
+func (ke *contentKeyExtractor) PrepareExtractor(name string, r cmn.ReadSizer, ext string, _ time.Time) (cmn.ReadSizer, *SingleKeyExtractor, bool) {
 	if ke.ext != ext {
 		return r, nil, false
 	}
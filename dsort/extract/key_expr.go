@@ -0,0 +1,267 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// Key expressions compute the (multi-)key of a record from several sources.
+// The key of a record is a list of values - one per expression - and records
+// are ordered by the first value, then by the second one, and so on. Since
+// the values may come from different objects of the record (e.g. mtime of
+// the ".jpg" and a field of the ".json"), the keys extracted from the
+// objects are partial and get merged together with the objects.
+
+const (
+	KeySourceName  = "name"  // (regex capture group of) the record name
+	KeySourceMtime = "mtime" // modification time (unix seconds) of the record's object
+	KeySourceSize  = "size"  // size of the record's object
+	KeySourceJSON  = "json"  // field of the record's (sidecar) JSON object
+)
+
+var supportedKeySources = []string{KeySourceName, KeySourceMtime, KeySourceSize, KeySourceJSON}
+
+type (
+	KeyExpr struct {
+		Source string `json:"source" yaml:"source"`
+
+		// Source: name - regular expression matched against the record name
+		// (without extension); the key is either the whole match or the
+		// given capture group. Default: whole name.
+		Regex string `json:"regex,omitempty" yaml:"regex,omitempty"`
+		Group int    `json:"group,omitempty" yaml:"group,omitempty"`
+
+		// Source: mtime, size, json - extension of the record's object
+		Extension string `json:"extension,omitempty" yaml:"extension,omitempty"`
+
+		// Source: json - dot-separated path to the field, e.g.: "meta.score"
+		Field string `json:"field,omitempty" yaml:"field,omitempty"`
+
+		// Source: name, json - supported: supportedFormatTypes (default: string)
+		FormatType string `json:"format_type,omitempty" yaml:"format_type,omitempty"`
+
+		Decreasing bool `json:"decreasing,omitempty" yaml:"decreasing,omitempty"`
+	}
+
+	exprKeyExtractor struct {
+		exprs []KeyExpr
+		res   []*regexp.Regexp // by expression index (source: name)
+		paths [][]interface{}  // by expression index (source: json)
+	}
+)
+
+func (e *KeyExpr) formatType() string {
+	switch e.Source {
+	case KeySourceMtime, KeySourceSize:
+		return FormatTypeInt
+	default:
+		if e.FormatType == "" {
+			return FormatTypeString
+		}
+		return e.FormatType
+	}
+}
+
+func (e *KeyExpr) validate() (re *regexp.Regexp, err error) {
+	if !cmn.StringInSlice(e.Source, supportedKeySources) {
+		return nil, fmt.Errorf("invalid key source %q, should be one of: %+v", e.Source, supportedKeySources)
+	}
+	if err := ValidateAlgorithmFormatType(e.formatType()); err != nil {
+		return nil, err
+	}
+	switch e.Source {
+	case KeySourceName:
+		if e.Regex == "" {
+			return nil, nil
+		}
+		if re, err = regexp.Compile(e.Regex); err != nil {
+			return nil, fmt.Errorf("invalid key regex %q: %v", e.Regex, err)
+		}
+		if e.Group < 0 || e.Group > re.NumSubexp() {
+			return nil, fmt.Errorf("invalid key regex group %d (regex %q has %d group(s))", e.Group, e.Regex, re.NumSubexp())
+		}
+	case KeySourceJSON:
+		if e.Field == "" {
+			return nil, fmt.Errorf("key source %q requires field", e.Source)
+		}
+		fallthrough
+	default:
+		if e.Extension == "" || e.Extension[0] != '.' {
+			return nil, fmt.Errorf("key source %q requires extension in format: .ext", e.Source)
+		}
+	}
+	return re, nil
+}
+
+func (e *KeyExpr) String() string {
+	switch e.Source {
+	case KeySourceName:
+		return e.Source
+	case KeySourceJSON:
+		return e.Source + "(" + e.Extension + ":" + e.Field + ")"
+	default:
+		return e.Source + "(" + e.Extension + ")"
+	}
+}
+
+func NewExprKeyExtractor(exprs []KeyExpr) (KeyExtractor, error) {
+	if len(exprs) == 0 {
+		return nil, errors.New("at least one key expression must be provided")
+	}
+	ke := &exprKeyExtractor{
+		exprs: exprs,
+		res:   make([]*regexp.Regexp, len(exprs)),
+		paths: make([][]interface{}, len(exprs)),
+	}
+	for i := range exprs {
+		re, err := exprs[i].validate()
+		if err != nil {
+			return nil, err
+		}
+		ke.res[i] = re
+		if exprs[i].Source == KeySourceJSON {
+			for _, part := range strings.Split(exprs[i].Field, ".") {
+				ke.paths[i] = append(ke.paths[i], part)
+			}
+		}
+	}
+	return ke, nil
+}
+
+func (ke *exprKeyExtractor) PrepareExtractor(name string, r cmn.ReadSizer, ext string, mtime time.Time) (cmn.ReadSizer, *SingleKeyExtractor, bool) {
+	ske := &SingleKeyExtractor{name: strings.TrimSuffix(name, ext), ext: ext, size: r.Size(), mtime: mtime}
+	for _, expr := range ke.exprs {
+		if expr.Source == KeySourceJSON && expr.Extension == ext {
+			ske.buf = &bytes.Buffer{}
+			tee := cmn.NewSizedReader(io.TeeReader(r, ske.buf), r.Size())
+			return tee, ske, true
+		}
+	}
+	return r, ske, false
+}
+
+// ExtractKey returns partial key - values which cannot be extracted from
+// the given object are nil.
+func (ke *exprKeyExtractor) ExtractKey(ske *SingleKeyExtractor) (interface{}, error) {
+	var (
+		b   []byte
+		key = make([]interface{}, len(ke.exprs))
+	)
+	if ske.buf != nil {
+		b, _ = ioutil.ReadAll(ske.buf)
+		ske.buf = nil
+	}
+	for i, expr := range ke.exprs {
+		var (
+			value string
+			err   error
+		)
+		switch expr.Source {
+		case KeySourceName:
+			value = ske.name
+			if re := ke.res[i]; re != nil {
+				match := re.FindStringSubmatch(ske.name)
+				if match == nil {
+					return nil, errors.Errorf("record name %q does not match key regex %q", ske.name, expr.Regex)
+				}
+				value = match[expr.Group]
+			}
+		case KeySourceMtime:
+			if expr.Extension == ske.ext {
+				key[i] = ske.mtime.Unix()
+			}
+			continue
+		case KeySourceSize:
+			if expr.Extension == ske.ext {
+				key[i] = ske.size
+			}
+			continue
+		case KeySourceJSON:
+			if expr.Extension != ske.ext {
+				continue
+			}
+			v := jsoniter.Get(b, ke.paths[i]...)
+			if v.LastError() != nil {
+				return nil, errors.Errorf("record %q: field %q not found in %q: %v", ske.name, expr.Field, ske.ext, v.LastError())
+			}
+			value = v.ToString()
+		}
+		if key[i], err = parseKey(value, expr.formatType()); err != nil {
+			return nil, errors.Errorf("record %q: invalid %s key %q: %v", ske.name, expr.String(), value, err)
+		}
+	}
+	return key, nil
+}
+
+func parseKey(key, formatType string) (interface{}, error) {
+	switch formatType {
+	case FormatTypeInt:
+		return strconv.ParseInt(key, 10, 64)
+	case FormatTypeFloat:
+		return strconv.ParseFloat(key, 64)
+	default:
+		return key, nil
+	}
+}
+
+// mergeKeys fills the missing values of the partial key with the values of the other one.
+func mergeKeys(key, other []interface{}) {
+	for i := range key {
+		if key[i] == nil && i < len(other) {
+			key[i] = other[i]
+		}
+	}
+}
+
+// compareKeys compares single values of multi-keys.
+func compareKeys(lhs, rhs interface{}, formatType string) int {
+	switch formatType {
+	case FormatTypeInt:
+		l, r := keyToInt64(lhs), keyToInt64(rhs)
+		switch {
+		case l < r:
+			return -1
+		case l > r:
+			return 1
+		}
+		return 0
+	case FormatTypeFloat:
+		l, r := lhs.(float64), rhs.(float64)
+		switch {
+		case l < r:
+			return -1
+		case l > r:
+			return 1
+		}
+		return 0
+	default:
+		return strings.Compare(lhs.(string), rhs.(string))
+	}
+}
+
+// keyToInt64 handles the keys which were parsed as float64 - javascript does
+// not support int64 type and it fallbacks to float64 (see: Records.Less).
+func keyToInt64(key interface{}) int64 {
+	switch v := key.(type) {
+	case int64:
+		return v
+	case uint64:
+		return int64(v)
+	default:
+		return int64(key.(float64))
+	}
+}
@@ -0,0 +1,102 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type tarFile struct {
+	name    string
+	content string
+	mtime   time.Time
+}
+
+func makeTar(files ...tarFile) []byte {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, f := range files {
+		hdr := &tar.Header{Name: f.name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(f.content)), ModTime: f.mtime}
+		Expect(tw.WriteHeader(hdr)).NotTo(HaveOccurred())
+		_, err := tw.Write([]byte(f.content))
+		Expect(err).NotTo(HaveOccurred())
+	}
+	Expect(tw.Close()).NotTo(HaveOccurred())
+	return buf.Bytes()
+}
+
+var _ = Describe("KeyExpr", func() {
+	t := cluster.NewTargetMock(nil)
+	t.SmallMMSA() // NOTE: page MMSA relies on (initialized) small MMSA for small allocations
+
+	mtime := time.Unix(1600000000, 0)
+
+	extract := func(exprs []KeyExpr, b []byte) (*RecordManager, error) {
+		ke, err := NewExprKeyExtractor(exprs)
+		Expect(err).NotTo(HaveOccurred())
+		var (
+			ec  = NewTarExtractCreator(t)
+			rm  = NewRecordManager(t, "target", "bck", cmn.ProviderAIS, cmn.ExtTar, ec, ke, func(string) error { return nil })
+			lom = &cluster.LOM{ParsedFQN: fs.ParsedFQN{ObjName: "shard.tar"}}
+		)
+		_, _, err = ec.ExtractShard(lom, io.NewSectionReader(bytes.NewReader(b), 0, int64(len(b))), rm, false)
+		return rm, err
+	}
+
+	It("should extract key from name, header and sidecar", func() {
+		b := makeTar(
+			tarFile{name: "sample-0042.jpg", content: "jpeg", mtime: mtime},
+			tarFile{name: "sample-0042.json", content: `{"meta": {"score": 0.5}}`, mtime: mtime},
+		)
+		rm, err := extract([]KeyExpr{
+			{Source: KeySourceName, Regex: `-(\d+)$`, Group: 1, FormatType: FormatTypeInt},
+			{Source: KeySourceMtime, Extension: ".jpg"},
+			{Source: KeySourceSize, Extension: ".jpg", Decreasing: true},
+			{Source: KeySourceJSON, Extension: ".json", Field: "meta.score", FormatType: FormatTypeFloat},
+		}, b)
+		Expect(err).NotTo(HaveOccurred())
+		defer rm.Cleanup()
+		Expect(rm.Records.Len()).To(Equal(1))
+		Expect(rm.Records.All()[0].Key).To(Equal([]interface{}{int64(42), mtime.Unix(), int64(4), 0.5}))
+	})
+
+	It("should fail when name does not match", func() {
+		b := makeTar(tarFile{name: "sample.jpg", content: "jpeg", mtime: mtime})
+		rm, err := extract([]KeyExpr{{Source: KeySourceName, Regex: `\d+`}}, b)
+		defer rm.Cleanup()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail when field is missing", func() {
+		b := makeTar(tarFile{name: "sample.json", content: `{"score": 1}`, mtime: mtime})
+		rm, err := extract([]KeyExpr{{Source: KeySourceJSON, Extension: ".json", Field: "meta.score"}}, b)
+		defer rm.Cleanup()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail to create invalid expressions", func() {
+		for _, exprs := range [][]KeyExpr{
+			nil,
+			{{Source: "owner"}},
+			{{Source: KeySourceName, Regex: "("}},
+			{{Source: KeySourceName, Regex: `(\d+)`, Group: 2}},
+			{{Source: KeySourceName, FormatType: "bool"}},
+			{{Source: KeySourceSize}},
+			{{Source: KeySourceJSON, Extension: ".json"}},
+		} {
+			_, err := NewExprKeyExtractor(exprs)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+})
@@ -12,7 +12,9 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
@@ -40,6 +42,7 @@ type (
 		metadata      []byte        // metadata of the record
 		extractMethod cmn.Bits      // method which needs to be used to extract a record
 		offset        int64         // offset of the body in the shard
+		mtime         time.Time     // modification time of the record (zero if not stored by the format)
 		buf           []byte        // helper buffer for `CopyBuffer` methods
 	}
 
@@ -75,6 +78,7 @@ type (
 		keyExtractor    KeyExtractor
 		contents        *sync.Map
 		extractionPaths *sync.Map // Keys correspond to all paths to record contents on disk.
+		keepExtracted   atomic.Bool
 
 		enqueued struct {
 			mu      sync.Mutex
@@ -130,7 +134,7 @@ func (rm *RecordManager) ExtractRecordWithBuffer(args extractRecordArgs) (size i
 		cmn.Assert(args.w != nil)
 	}
 
-	r, ske, needRead := rm.keyExtractor.PrepareExtractor(args.recordName, args.r, ext)
+	r, ske, needRead := rm.keyExtractor.PrepareExtractor(args.recordName, args.r, ext, args.mtime)
 	if args.extractMethod.Has(ExtractToMem) {
 		mdSize = int64(len(args.metadata))
 		storeType = SGLStoreType
@@ -355,6 +359,87 @@ func (rm *RecordManager) ChangeStoreType(fullContentPath, newStoreType string, v
 	return
 }
 
+// SpillToDisk moves all the contents extracted to memory to disk so that the
+// records do not refer to the contents which do not outlive the target.
+// Formats which support offsets do not need it - see: MakeDurable.
+func (rm *RecordManager) SpillToDisk() error {
+	if rm.extractCreator.SupportsOffset() || rm.contentsCnt() == 0 {
+		return nil
+	}
+	buf, slab := rm.t.MMSA().Alloc()
+	rm.contents.Range(func(key, value interface{}) bool {
+		rm.ChangeStoreType(key.(string), DiskStoreType, value, buf)
+		return true
+	})
+	slab.Free(buf)
+	if cnt := rm.contentsCnt(); cnt > 0 {
+		return fmt.Errorf("failed to spill %d extracted contents to disk", cnt)
+	}
+	return nil
+}
+
+func (rm *RecordManager) contentsCnt() (cnt int) {
+	rm.contents.Range(func(_, _ interface{}) bool {
+		cnt++
+		return true
+	})
+	return
+}
+
+// KeepExtracted prevents the contents extracted to disk from being removed
+// in Cleanup - they are referred to by the records of the checkpointed job.
+func (rm *RecordManager) KeepExtracted() {
+	rm.keepExtracted.Store(true)
+}
+
+// MakeDurable prepares the records restored from the checkpoint to be used by
+// the job. If offsets are supported, the store type of all the objects is
+// changed to OffsetStoreType so that they refer to their contents in the input
+// shards rather than to the extracted contents which do not outlive the job.
+// Otherwise, the contents extracted to disk by this target (see: SpillToDisk)
+// are taken over so that they are removed when the job finishes.
+func (rm *RecordManager) MakeDurable(records *Records) {
+	for _, record := range records.All() {
+		shardName, _ := rm.parseRecordUniqueName(record.Name)
+		for _, obj := range record.Objects {
+			switch {
+			case obj.StoreType == OffsetStoreType:
+				continue
+			case rm.extractCreator.SupportsOffset():
+				obj.StoreType = OffsetStoreType
+				obj.ContentPath = shardName
+				obj.MetadataSize = rm.extractCreator.MetadataSize()
+			case record.DaemonID == rm.daemonID:
+				cmn.Assertf(obj.StoreType == DiskStoreType, "%s: %s", record.Name, obj.StoreType)
+				rm.extractionPaths.Store(rm.FullContentPath(obj), struct{}{})
+			}
+		}
+	}
+}
+
+// RemoveExtracted removes the contents extracted to disk by the given target
+// for the records of the job which is not going to be resumed.
+func RemoveExtracted(bucket, provider, daemonID string, records *Records) {
+	for _, record := range records.All() {
+		if record.DaemonID != daemonID {
+			continue
+		}
+		for _, obj := range record.Objects {
+			if obj.StoreType != DiskStoreType {
+				continue
+			}
+			ct, err := cluster.NewCTFromBO(bucket, provider, obj.ContentPath, nil)
+			if err != nil {
+				glog.Error(err)
+				return
+			}
+			if err := os.Remove(ct.Make(filetype.DSortFileType)); err != nil && !os.IsNotExist(err) {
+				glog.Errorf("could not remove extracted content %q, err: %v", obj.ContentPath, err)
+			}
+		}
+	}
+}
+
 func (rm *RecordManager) RecordContents() *sync.Map {
 	return rm.contents
 }
@@ -366,8 +451,10 @@ func (rm *RecordManager) ExtractionPaths() *sync.Map {
 func (rm *RecordManager) Cleanup() {
 	rm.Records.Drain()
 	rm.extractionPaths.Range(func(k, v interface{}) bool {
-		if err := os.RemoveAll(k.(string)); err != nil {
-			glog.Errorf("could not remove extraction path (%v) from previous run, err: %v", k, err)
+		if !rm.keepExtracted.Load() {
+			if err := os.RemoveAll(k.(string)); err != nil {
+				glog.Errorf("could not remove extraction path (%v) from previous run, err: %v", k, err)
+			}
 		}
 		rm.extractionPaths.Delete(k)
 		return true
@@ -0,0 +1,187 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/pkg/errors"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// Msgpack shard (WebDataset style) is a stream of msgpack maps, one per
+// sample (record):
+//
+//   {"__key__": "sample001", "jpg": <bin>, "cls": <bin>, ...}
+//
+// The "__key__" entry must come first; all other entries are the record's
+// objects - their keys are the objects' extensions and their values are the
+// objects' contents (either bin or str). Upon shard creation all contents are
+// written as bin.
+
+const msgpackKeyField = "__key__"
+
+// interface guard
+var _ ExtractCreator = &msgpackExtractCreator{}
+
+type (
+	msgpackExtractCreator struct {
+		t cluster.Target
+	}
+
+	// countWriter counts bytes written to the underlying writer
+	countWriter struct {
+		w io.Writer
+		n int64
+	}
+)
+
+func (cw *countWriter) Write(p []byte) (n int, err error) {
+	n, err = cw.w.Write(p)
+	cw.n += int64(n)
+	return
+}
+
+func NewMsgpackExtractCreator(t cluster.Target) ExtractCreator {
+	return &msgpackExtractCreator{t: t}
+}
+
+// ExtractShard reads the msgpack stream and extracts its records.
+func (mc *msgpackExtractCreator) ExtractShard(lom *cluster.LOM, r *io.SectionReader, extractor RecordExtractor,
+	toDisk bool) (extractedSize int64, extractedCount int, err error) {
+	var (
+		size int64
+		fqn  = lom.ParsedFQN
+		mr   = msgp.NewReader(r)
+	)
+
+	buf, slab := mc.t.MMSA().Alloc(r.Size())
+	defer slab.Free(buf)
+
+	extractMethod := ExtractToMem
+	if toDisk {
+		extractMethod = ExtractToDisk
+	}
+
+	for idx := 0; ; idx++ {
+		if _, err = mr.NextType(); err != nil {
+			if err == io.EOF {
+				return extractedSize, extractedCount, nil
+			}
+			return extractedSize, extractedCount, err
+		}
+		cnt, err := mr.ReadMapHeader()
+		if err != nil {
+			return extractedSize, extractedCount, errors.Errorf("sample #%d: %v", idx, err)
+		}
+		if field, err := mr.ReadString(); err != nil || field != msgpackKeyField {
+			return extractedSize, extractedCount, errors.Errorf("sample #%d: expected %q as the first entry (err: %v)",
+				idx, msgpackKeyField, err)
+		}
+		key, err := mr.ReadString()
+		if err != nil {
+			return extractedSize, extractedCount, errors.Errorf("sample #%d: invalid %q: %v", idx, msgpackKeyField, err)
+		}
+
+		for i := uint32(1); i < cnt; i++ {
+			field, err := mr.ReadString()
+			if err != nil {
+				return extractedSize, extractedCount, errors.Errorf("sample %q: %v", key, err)
+			}
+			length, err := mc.readValueHeader(mr)
+			if err != nil {
+				return extractedSize, extractedCount, errors.Errorf("sample %q, field %q: %v", key, field, err)
+			}
+
+			data := io.LimitReader(mr, int64(length))
+			args := extractRecordArgs{
+				shardName:     fqn.ObjName,
+				fileType:      fqn.ContentType,
+				recordName:    key + "." + field,
+				r:             cmn.NewSizedReader(data, int64(length)),
+				extractMethod: extractMethod,
+				buf:           buf,
+			}
+			if size, err = extractor.ExtractRecordWithBuffer(args); err != nil {
+				return extractedSize, extractedCount, err
+			}
+			// the object may not have been read in its entirety (e.g. duplicate)
+			if _, err = io.CopyBuffer(ioutil.Discard, data, buf); err != nil {
+				return extractedSize, extractedCount, err
+			}
+
+			extractedSize += size
+			extractedCount++
+		}
+	}
+}
+
+func (*msgpackExtractCreator) readValueHeader(mr *msgp.Reader) (uint32, error) {
+	t, err := mr.NextType()
+	if err != nil {
+		return 0, err
+	}
+	switch t {
+	case msgp.BinType:
+		return mr.ReadBytesHeader()
+	case msgp.StrType:
+		return mr.ReadStringHeader()
+	default:
+		return 0, errors.Errorf("unsupported value type %s (expected bin or str)", t)
+	}
+}
+
+// CreateShard creates a new msgpack shard based on the Shard - one map per record.
+func (mc *msgpackExtractCreator) CreateShard(s *Shard, w io.Writer, loadContent LoadContentFunc) (written int64, err error) {
+	var (
+		cw = &countWriter{w: w}
+		mw = msgp.NewWriter(cw)
+	)
+	for _, rec := range s.Records.All() {
+		key := recordName(rec)
+		if err = mw.WriteMapHeader(uint32(len(rec.Objects) + 1)); err != nil {
+			return cw.n, err
+		}
+		if err = mw.WriteString(msgpackKeyField); err != nil {
+			return cw.n, err
+		}
+		if err = mw.WriteString(key); err != nil {
+			return cw.n, err
+		}
+		for _, obj := range rec.Objects {
+			if err = mw.WriteString(strings.TrimPrefix(obj.Extension, ".")); err != nil {
+				return cw.n, err
+			}
+			if err = mw.WriteBytesHeader(uint32(obj.Size)); err != nil {
+				return cw.n, err
+			}
+			n, err := loadContent(mw, rec, obj)
+			if err != nil {
+				return cw.n, err
+			}
+			if n != obj.Size {
+				return cw.n, errors.Errorf("record %q: expected %d bytes, got %d", rec.Name, obj.Size, n)
+			}
+		}
+	}
+	err = mw.Flush()
+	return cw.n, err
+}
+
+func (mc *msgpackExtractCreator) UsingCompression() bool {
+	return false
+}
+
+func (mc *msgpackExtractCreator) SupportsOffset() bool {
+	return false
+}
+
+func (mc *msgpackExtractCreator) MetadataSize() int64 {
+	return 0 // field names are kept as the objects' extensions
+}
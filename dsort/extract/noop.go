@@ -10,11 +10,19 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 )
 
-var _ ExtractCreator = &nopExtractCreator{}
+var (
+	_ ExtractCreator = &nopExtractCreator{}
+	_ ExtractCreator = &noOffsetExtractCreator{}
+)
 
-type nopExtractCreator struct {
-	internal ExtractCreator
-}
+type (
+	nopExtractCreator struct {
+		internal ExtractCreator
+	}
+	noOffsetExtractCreator struct {
+		ExtractCreator
+	}
+)
 
 func NopExtractCreator(internal ExtractCreator) ExtractCreator {
 	return &nopExtractCreator{internal: internal}
@@ -53,3 +61,12 @@ func (t *nopExtractCreator) SupportsOffset() bool {
 func (t *nopExtractCreator) MetadataSize() int64 {
 	return t.internal.MetadataSize()
 }
+
+// NoOffsetExtractCreator wraps the creator so that records are never read
+// directly from the shard files (by offset) - used when the shards are
+// encrypted at rest (see cmn.SSEConf).
+func NoOffsetExtractCreator(internal ExtractCreator) ExtractCreator {
+	return &noOffsetExtractCreator{internal}
+}
+
+func (*noOffsetExtractCreator) SupportsOffset() bool { return false }
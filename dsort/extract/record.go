@@ -86,6 +86,10 @@ func (r *Record) mergeObjects(other *Record) {
 	cmn.Assert(r.Name == other.Name)
 	if r.Key == nil && other.Key != nil {
 		r.Key = other.Key
+	} else if key, ok := r.Key.([]interface{}); ok { // partial key (see: KeyExpr)
+		if otherKey, ok := other.Key.([]interface{}); ok {
+			mergeKeys(key, otherKey)
+		}
 	}
 	r.Objects = append(r.Objects, other.Objects...)
 }
@@ -244,6 +248,30 @@ func (r *Records) Less(i, j int, formatType string) (bool, error) {
 	return false, nil
 }
 
+// LessExprs compares the multi-keys (see: KeyExpr) of the records value by
+// value, each in the direction determined by the corresponding expression.
+func (r *Records) LessExprs(i, j int, exprs []KeyExpr) (bool, error) {
+	lhs, lok := r.arr[i].Key.([]interface{})
+	rhs, rok := r.arr[j].Key.([]interface{})
+	if !lok || len(lhs) != len(exprs) {
+		return false, errors.Errorf("key is missing for %q", r.arr[i].Name)
+	} else if !rok || len(rhs) != len(exprs) {
+		return false, errors.Errorf("key is missing for %q", r.arr[j].Name)
+	}
+
+	for k := range exprs {
+		if lhs[k] == nil {
+			return false, errors.Errorf("key %s is missing for %q", exprs[k].String(), r.arr[i].Name)
+		} else if rhs[k] == nil {
+			return false, errors.Errorf("key %s is missing for %q", exprs[k].String(), r.arr[j].Name)
+		}
+		if cmp := compareKeys(lhs[k], rhs[k], exprs[k].formatType()); cmp != 0 {
+			return (cmp < 0) != exprs[k].Decreasing, nil
+		}
+	}
+	return false, nil
+}
+
 func (r *Records) objectCount() int {
 	return r.totalObjectCount
 }
@@ -190,6 +190,7 @@ func (t *tarExtractCreator) ExtractShard(lom *cluster.LOM, r *io.SectionReader,
 				metadata:      bmeta,
 				extractMethod: extractMethod,
 				offset:        offset,
+				mtime:         header.ModTime,
 				buf:           buf,
 			}
 			if size, err = extractor.ExtractRecordWithBuffer(args); err != nil {
@@ -21,7 +21,8 @@ import (
 var _ ExtractCreator = &targzExtractCreator{}
 
 type targzExtractCreator struct {
-	t cluster.Target
+	t     cluster.Target
+	level int // gzip compression level of the created shards
 }
 
 // ExtractShard reads the tarball f and extracts its metadata.
@@ -96,6 +97,7 @@ func (t *targzExtractCreator) ExtractShard(lom *cluster.LOM, r *io.SectionReader
 				metadata:      bmeta,
 				extractMethod: extractMethod,
 				offset:        offset,
+				mtime:         header.ModTime,
 				buf:           buf,
 			}
 			if size, err = extractor.ExtractRecordWithBuffer(args); err != nil {
@@ -114,8 +116,8 @@ func (t *targzExtractCreator) ExtractShard(lom *cluster.LOM, r *io.SectionReader
 	}
 }
 
-func NewTargzExtractCreator(t cluster.Target) ExtractCreator {
-	return &targzExtractCreator{t: t}
+func NewTargzExtractCreator(t cluster.Target, compression string) ExtractCreator {
+	return &targzExtractCreator{t: t, level: gzipLevel(compression)}
 }
 
 // CreateShard creates a new shard locally based on the Shard.
@@ -124,7 +126,7 @@ func (t *targzExtractCreator) CreateShard(s *Shard, tarball io.Writer, loadConte
 	var (
 		n         int64
 		needFlush bool
-		gzw, _    = gzip.NewWriterLevel(tarball, gzip.BestSpeed)
+		gzw, _    = gzip.NewWriterLevel(tarball, t.level)
 		tw        = tar.NewWriter(gzw)
 		rdReader  = newTarRecordDataReader(t.t)
 	)
@@ -0,0 +1,197 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/pkg/errors"
+)
+
+// TFRecord file is a sequence of records, each of which has the following format:
+//
+//   uint64 length
+//   uint32 masked crc32c of length
+//   byte   data[length]
+//   uint32 masked crc32c of data
+//
+// (all integers are little-endian). Records do not have names - the name of
+// a record is its (zero-padded) index in the shard. Both checksums are
+// validated upon extraction.
+
+const (
+	tfrHeaderLen = 8 + 4
+	tfrFooterLen = 4
+	tfrMaskDelta = 0xa282ead8
+)
+
+var (
+	crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+	// interface guard
+	_ ExtractCreator = &tfrecordExtractCreator{}
+)
+
+type (
+	tfrecordExtractCreator struct {
+		t cluster.Target
+	}
+
+	// crcReader computes crc32c of everything read through it
+	crcReader struct {
+		r   io.Reader
+		crc uint32
+		n   int64
+	}
+
+	// crcWriter computes crc32c of everything written through it
+	crcWriter struct {
+		w   io.Writer
+		crc uint32
+	}
+)
+
+func tfrMaskCRC(crc uint32) uint32 {
+	return ((crc >> 15) | (crc << 17)) + tfrMaskDelta
+}
+
+func tfrHeader(length uint64) (hdr [tfrHeaderLen]byte) {
+	binary.LittleEndian.PutUint64(hdr[:8], length)
+	binary.LittleEndian.PutUint32(hdr[8:], tfrMaskCRC(crc32.Checksum(hdr[:8], crc32c)))
+	return
+}
+
+func (cr *crcReader) Read(p []byte) (n int, err error) {
+	n, err = cr.r.Read(p)
+	cr.crc = crc32.Update(cr.crc, crc32c, p[:n])
+	cr.n += int64(n)
+	return
+}
+
+func (cw *crcWriter) Write(p []byte) (n int, err error) {
+	n, err = cw.w.Write(p)
+	cw.crc = crc32.Update(cw.crc, crc32c, p[:n])
+	return
+}
+
+func NewTFRecordExtractCreator(t cluster.Target) ExtractCreator {
+	return &tfrecordExtractCreator{t: t}
+}
+
+// ExtractShard reads the TFRecord file and validates the checksums of all its records.
+func (tc *tfrecordExtractCreator) ExtractShard(lom *cluster.LOM, r *io.SectionReader, extractor RecordExtractor,
+	toDisk bool) (extractedSize int64, extractedCount int, err error) {
+	var (
+		size   int64
+		hdr    [tfrHeaderLen]byte
+		footer [tfrFooterLen]byte
+		fqn    = lom.ParsedFQN
+	)
+
+	buf, slab := tc.t.MMSA().Alloc(r.Size())
+	defer slab.Free(buf)
+
+	extractMethod := ExtractToMem
+	if toDisk {
+		extractMethod = ExtractToDisk
+	}
+
+	for idx := 0; ; idx++ {
+		if _, err = io.ReadFull(r, hdr[:]); err != nil {
+			if err == io.EOF {
+				return extractedSize, extractedCount, nil
+			}
+			return extractedSize, extractedCount, errors.Errorf("record #%d: failed to read header: %v", idx, err)
+		}
+		length := binary.LittleEndian.Uint64(hdr[:8])
+		if tfrMaskCRC(crc32.Checksum(hdr[:8], crc32c)) != binary.LittleEndian.Uint32(hdr[8:]) {
+			return extractedSize, extractedCount, errors.Errorf("record #%d: length checksum mismatch", idx)
+		}
+		if length > uint64(r.Size()) {
+			return extractedSize, extractedCount, errors.Errorf("record #%d: invalid length %d", idx, length)
+		}
+
+		cr := &crcReader{r: io.LimitReader(r, int64(length))}
+		args := extractRecordArgs{
+			shardName:     fqn.ObjName,
+			fileType:      fqn.ContentType,
+			recordName:    fmt.Sprintf("%09d", idx),
+			r:             cmn.NewSizedReader(cr, int64(length)),
+			extractMethod: extractMethod,
+			buf:           buf,
+		}
+		if size, err = extractor.ExtractRecordWithBuffer(args); err != nil {
+			return extractedSize, extractedCount, err
+		}
+		// the record may not have been read in its entirety (e.g. duplicate)
+		if _, err = io.CopyBuffer(ioutil.Discard, cr, buf); err != nil {
+			return extractedSize, extractedCount, err
+		}
+		if cr.n != int64(length) {
+			return extractedSize, extractedCount, errors.Errorf("record #%d: %v", idx, io.ErrUnexpectedEOF)
+		}
+		if _, err = io.ReadFull(r, footer[:]); err != nil {
+			return extractedSize, extractedCount, errors.Errorf("record #%d: failed to read data checksum: %v", idx, err)
+		}
+		if tfrMaskCRC(cr.crc) != binary.LittleEndian.Uint32(footer[:]) {
+			return extractedSize, extractedCount, errors.Errorf("record #%d: data checksum mismatch", idx)
+		}
+
+		extractedSize += size
+		extractedCount++
+	}
+}
+
+// CreateShard creates a new TFRecord file based on the Shard - checksums are
+// recomputed on the fly.
+func (tc *tfrecordExtractCreator) CreateShard(s *Shard, w io.Writer, loadContent LoadContentFunc) (written int64, err error) {
+	var (
+		n      int64
+		footer [tfrFooterLen]byte
+	)
+	for _, rec := range s.Records.All() {
+		for _, obj := range rec.Objects {
+			hdr := tfrHeader(uint64(obj.Size))
+			if _, err = w.Write(hdr[:]); err != nil {
+				return written, err
+			}
+			written += tfrHeaderLen
+
+			cw := &crcWriter{w: w}
+			if n, err = loadContent(cw, rec, obj); err != nil {
+				return written + n, err
+			}
+			if n != obj.Size {
+				return written + n, errors.Errorf("record %q: expected %d bytes, got %d", rec.Name, obj.Size, n)
+			}
+			written += n
+
+			binary.LittleEndian.PutUint32(footer[:], tfrMaskCRC(cw.crc))
+			if _, err = w.Write(footer[:]); err != nil {
+				return written, err
+			}
+			written += tfrFooterLen
+		}
+	}
+	return written, nil
+}
+
+func (tc *tfrecordExtractCreator) UsingCompression() bool {
+	return false
+}
+
+func (tc *tfrecordExtractCreator) SupportsOffset() bool {
+	return false
+}
+
+func (tc *tfrecordExtractCreator) MetadataSize() int64 {
+	return 0 // records do not have metadata (other than the length)
+}
@@ -6,6 +6,7 @@ package extract
 
 import (
 	"archive/zip"
+	"compress/flate"
 	"io"
 
 	"github.com/NVIDIA/aistore/cluster"
@@ -20,7 +21,8 @@ var _ ExtractCreator = &zipExtractCreator{}
 
 type (
 	zipExtractCreator struct {
-		t cluster.Target
+		t           cluster.Target
+		compression string // of the created shards
 	}
 
 	zipFileHeader struct {
@@ -38,13 +40,14 @@ type (
 		metadataBuf  []byte
 		header       zipFileHeader
 		zipWriter    *zip.Writer
+		method       uint16 // zip.Store or zip.Deflate
 
 		writer io.Writer
 	}
 )
 
-func newZipRecordDataReader(t cluster.Target) *zipRecordDataReader {
-	rd := &zipRecordDataReader{}
+func newZipRecordDataReader(t cluster.Target, method uint16) *zipRecordDataReader {
+	rd := &zipRecordDataReader{method: method}
 	rd.metadataBuf, rd.slab = t.SmallMMSA().Alloc()
 	return rd
 }
@@ -82,7 +85,7 @@ func (rd *zipRecordDataReader) Write(p []byte) (int, error) {
 		}
 
 		rd.header = metadata
-		writer, err := rd.zipWriter.Create(rd.header.Name)
+		writer, err := rd.zipWriter.CreateHeader(&zip.FileHeader{Name: rd.header.Name, Method: rd.method})
 		if err != nil {
 			return int(remainingMetadataSize), err
 		}
@@ -147,6 +150,7 @@ func (z *zipExtractCreator) ExtractShard(lom *cluster.LOM, r *io.SectionReader,
 				r:             cmn.NewSizedReader(file, int64(header.UncompressedSize64)),
 				metadata:      bmeta,
 				extractMethod: extractMethod,
+				mtime:         header.Modified,
 				buf:           buf,
 			}
 			if size, err = extractor.ExtractRecordWithBuffer(args); err != nil {
@@ -163,8 +167,8 @@ func (z *zipExtractCreator) ExtractShard(lom *cluster.LOM, r *io.SectionReader,
 	return extractedSize, extractedCount, nil
 }
 
-func NewZipExtractCreator(t cluster.Target) ExtractCreator {
-	return &zipExtractCreator{t: t}
+func NewZipExtractCreator(t cluster.Target, compression string) ExtractCreator {
+	return &zipExtractCreator{t: t, compression: compression}
 }
 
 // CreateShard creates a new shard locally based on the Shard.
@@ -174,7 +178,16 @@ func (z *zipExtractCreator) CreateShard(s *Shard, w io.Writer, loadContent LoadC
 	zw := zip.NewWriter(w)
 	defer cmn.Close(zw)
 
-	rdReader := newZipRecordDataReader(z.t)
+	method := zip.Deflate
+	if z.compression == CompressionNone {
+		method = zip.Store
+	} else if z.compression != CompressionDefault {
+		level := flateLevel(z.compression)
+		zw.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(out, level)
+		})
+	}
+	rdReader := newZipRecordDataReader(z.t, method)
 	for _, rec := range s.Records.All() {
 		for _, obj := range rec.Objects {
 			rdReader.reinit(zw, obj.Size, obj.MetadataSize)
@@ -19,6 +19,7 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
 	"github.com/NVIDIA/aistore/dsort/extract"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/sys"
@@ -46,7 +47,11 @@ func ProxySortHandler(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case http.MethodPost:
-		proxyStartSortHandler(w, r)
+		if len(apiItems) == 1 && apiItems[0] == cmn.Resume {
+			proxyResumeSortHandler(w, r)
+		} else {
+			proxyStartSortHandler(w, r)
+		}
 	case http.MethodGet:
 		proxyGetHandler(w, r)
 	case http.MethodDelete:
@@ -83,36 +88,97 @@ func proxyStartSortHandler(w http.ResponseWriter, r *http.Request) {
 	// This would also be helpful for Downloader (in the middle of downloading
 	// large file the bucket can be easily deleted).
 
-	bck := cluster.NewBck(parsedRS.Bucket, parsedRS.Provider, cmn.NsGlobal)
-	if err = bck.Init(ctx.bmdOwner, nil); err != nil { // TODO: ctx.t.Snode()
+	if err, errCode := initBuckets(parsedRS); err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, err.Error(), errCode)
+		return
+	}
+
+	parsedRS.DSorterType, err = determineDSorterType(parsedRS)
+	if err != nil {
 		cmn.InvalidHandlerWithMsg(w, r, err.Error())
 		return
 	}
-	if err := bck.Allow(cmn.AccessObjLIST); err != nil {
-		cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusForbidden)
+
+	startSort(w, r, parsedRS)
+}
+
+// POST /v1/sort/resume
+func proxyResumeSortHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkHTTPMethod(w, r, http.MethodPost) {
 		return
 	}
-	if err := bck.Allow(cmn.AccessGET); err != nil {
-		cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusForbidden)
+	_, err := checkRESTItems(w, r, 0, cmn.Version, cmn.Sort, cmn.Resume)
+	if err != nil {
 		return
 	}
 
-	bck = cluster.NewBck(parsedRS.OutputBucket, parsedRS.OutputProvider, cmn.NsGlobal)
-	if err = bck.Init(ctx.bmdOwner, nil); err != nil {
+	var (
+		targets     = ctx.smapOwner.Get().Tmap
+		query       = r.URL.Query()
+		managerUUID = query.Get(cmn.URLParamUUID)
+		path        = cmn.JoinWords(cmn.Version, cmn.Sort, cmn.Checkpoint, managerUUID)
+		responses   = broadcast(http.MethodGet, path, nil, nil, targets)
+	)
+
+	// All the targets must have the checkpoint - records and shards are spread
+	// across all of them.
+	infos := make(map[string]*CheckpointInfo, len(targets))
+	for _, resp := range responses {
+		if resp.err != nil {
+			s := fmt.Sprintf("target %s: %v", resp.si.DaemonID, resp.err)
+			cmn.InvalidHandlerWithMsg(w, r, s, resp.statusCode)
+			return
+		}
+		info := &CheckpointInfo{}
+		if err := js.Unmarshal(resp.res, info); err != nil {
+			cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		infos[resp.si.DaemonID] = info
+	}
+
+	parsedRS, msg, err := mergeCheckpoints(managerUUID, infos)
+	if err != nil {
 		cmn.InvalidHandlerWithMsg(w, r, err.Error())
 		return
 	}
-	if err = bck.Allow(cmn.AccessPUT); err != nil {
-		cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusForbidden)
+	if err, errCode := initBuckets(parsedRS); err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, err.Error(), errCode)
 		return
 	}
+	parsedRS.Resume = msg
 
-	parsedRS.DSorterType, err = determineDSorterType(parsedRS)
-	if err != nil {
-		cmn.InvalidHandlerWithMsg(w, r, err.Error())
-		return
+	glog.Infof("[%s] resuming %s job from phase %d", managerUUID, cmn.DSortName, msg.Phase)
+	startSort(w, r, parsedRS)
+}
+
+// initBuckets checks that input and output buckets of the job exist and are
+// accessible.
+func initBuckets(parsedRS *ParsedRequestSpec) (err error, errCode int) {
+	bck := cluster.NewBck(parsedRS.Bucket, parsedRS.Provider, cmn.NsGlobal)
+	if err = bck.Init(ctx.bmdOwner, nil); err != nil { // TODO: ctx.t.Snode()
+		return err, http.StatusBadRequest
+	}
+	if err = bck.Allow(cmn.AccessObjLIST); err != nil {
+		return err, http.StatusForbidden
 	}
+	if err = bck.Allow(cmn.AccessGET); err != nil {
+		return err, http.StatusForbidden
+	}
+	parsedRS.Encrypted = bck.Props.SSE.Enabled
 
+	bck = cluster.NewBck(parsedRS.OutputBucket, parsedRS.OutputProvider, cmn.NsGlobal)
+	if err = bck.Init(ctx.bmdOwner, nil); err != nil {
+		return err, http.StatusBadRequest
+	}
+	if err = bck.Allow(cmn.AccessPUT); err != nil {
+		return err, http.StatusForbidden
+	}
+	return nil, 0
+}
+
+// startSort broadcasts the job to all the targets and responds with its UUID.
+func startSort(w http.ResponseWriter, r *http.Request, parsedRS *ParsedRequestSpec) {
 	b, err := js.Marshal(parsedRS)
 	if err != nil {
 		s := fmt.Sprintf("unable to marshal RequestSpec: %+v, err: %v", parsedRS, err)
@@ -406,6 +472,8 @@ func SortHandler(w http.ResponseWriter, r *http.Request) {
 		metricsHandler(w, r)
 	case cmn.FinishedAck:
 		finishedAckHandler(w, r)
+	case cmn.Checkpoint:
+		checkpointHandler(w, r)
 	default:
 		cmn.InvalidHandlerWithMsg(w, r, "invalid path")
 	}
@@ -545,6 +613,7 @@ func shardsHandler(managers *ManagerGroup) http.HandlerFunc {
 		}
 
 		dsortManager.creationPhase.metadata = *tmpMetadata
+		dsortManager.checkpointDistribution()
 		dsortManager.startShardCreation <- struct{}{}
 	}
 }
@@ -713,6 +782,39 @@ func metricsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// checkpointHandler is the handler called for the HTTP endpoint /v1/sort/checkpoint.
+// A valid GET to this endpoint returns information about the checkpoint of
+// the dSort job, required by the proxy to resume it.
+func checkpointHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkHTTPMethod(w, r, http.MethodGet) {
+		return
+	}
+	apiItems, err := checkRESTItems(w, r, 1, cmn.Version, cmn.Sort, cmn.Checkpoint)
+	if err != nil {
+		return
+	}
+
+	managerUUID := apiItems[0]
+	info, err := Managers.CheckpointInfo(managerUUID)
+	if err != nil {
+		if dbdriver.IsErrNotFound(err) {
+			s := fmt.Sprintf("invalid request: checkpoint of %s job %q does not exist", cmn.DSortName, managerUUID)
+			cmn.InvalidHandlerWithMsg(w, r, s, http.StatusNotFound)
+			return
+		}
+		cmn.InvalidHandlerWithMsg(w, r, err.Error())
+		return
+	}
+
+	body := cmn.MustMarshal(info)
+	if _, err := w.Write(body); err != nil {
+		glog.Error(err)
+		// When we fail write we cannot call InvalidHandler since it will be
+		// double header write.
+		return
+	}
+}
+
 // finishedAckHandler is the handler called for the HTTP endpoint /v1/sort/finished-ack.
 // A valid PUT to this endpoint acknowledges that daemonID has finished dSort operation.
 func finishedAckHandler(w http.ResponseWriter, r *http.Request) {
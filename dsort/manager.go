@@ -117,6 +117,8 @@ type (
 		}
 		refCount        atomic.Int64 // Reference counter used to determine if we can do cleanup
 		state           progressState
+		checkpointed    atomic.Int32 // last phase checkpointed by this target (see: checkpoint.go)
+		resume          *resumeState // set when the job resumes another one
 		extractionPhase struct {
 			adjuster *concAdjuster
 		}
@@ -188,6 +190,12 @@ func (m *Manager) init(rs *ParsedRequestSpec) error {
 		return err
 	}
 
+	if rs.Resume != nil {
+		if err := m.initResume(rs.Resume); err != nil {
+			return err
+		}
+	}
+
 	// NOTE: Total size of the records metadata can sometimes be large
 	// and so this is why we need such a long timeout.
 	config := cmn.GCO.Get()
@@ -362,6 +370,10 @@ func (m *Manager) finalCleanup() {
 	// The reason why this is not in regular cleanup is because we are only sure
 	// that this can be freed once we cleanup streams - streams are asynchronous
 	// and we may have race between in-flight request and cleanup.
+	if m.aborted() && m.checkpointed.Load() >= phaseExtracted {
+		// Contents extracted to disk are referred to by the checkpoint.
+		m.recManager.KeepExtracted()
+	}
 	m.recManager.Cleanup()
 
 	m.creationPhase.metadata.SendOrder = nil
@@ -375,6 +387,9 @@ func (m *Manager) finalCleanup() {
 	m.state.cleanWait.Signal()
 	m.unlock()
 
+	if !m.aborted() {
+		m.mg.removeCheckpoint(m.ManagerUUID)
+	}
 	m.mg.persist(m.ManagerUUID)
 	glog.Infof("%s %s final cleanup has been finished in %v", cmn.DSortName, m.ManagerUUID, time.Since(now))
 }
@@ -434,6 +449,8 @@ func (m *Manager) setExtractCreator() (err error) {
 		keyExtractor, err = extract.NewContentKeyExtractor(m.rs.Algorithm.FormatType, m.rs.Algorithm.Extension)
 	case SortKindMD5:
 		keyExtractor, err = extract.NewMD5KeyExtractor()
+	case SortKindExpression:
+		keyExtractor, err = extract.NewExprKeyExtractor(m.rs.Algorithm.Keys)
 	default:
 		keyExtractor, err = extract.NewNameKeyExtractor()
 	}
@@ -447,15 +464,16 @@ func (m *Manager) setExtractCreator() (err error) {
 	}
 
 	var extractCreator extract.ExtractCreator
-	switch m.rs.Extension {
-	case cmn.ExtTar:
-		extractCreator = extract.NewTarExtractCreator(m.ctx.t)
-	case cmn.ExtTarTgz, cmn.ExtTgz:
-		extractCreator = extract.NewTargzExtractCreator(m.ctx.t)
-	case cmn.ExtZip:
-		extractCreator = extract.NewZipExtractCreator(m.ctx.t)
-	default:
-		cmn.Assertf(false, "unknown extension %s", m.rs.Extension)
+	if m.rs.OutputExtension == "" {
+		m.rs.OutputExtension = m.rs.Extension
+	}
+	if m.rs.OutputExtension == m.rs.Extension {
+		extractCreator = m.newExtractCreator(m.rs.Extension, m.rs.OutputCompression)
+	} else {
+		extractCreator = extract.ConvertExtractCreator(
+			m.newExtractCreator(m.rs.Extension, extract.CompressionDefault),
+			m.newExtractCreator(m.rs.OutputExtension, m.rs.OutputCompression),
+		)
 	}
 
 	if !m.rs.DryRun {
@@ -463,6 +481,10 @@ func (m *Manager) setExtractCreator() (err error) {
 	} else {
 		m.extractCreator = extract.NopExtractCreator(extractCreator)
 	}
+	// encrypted shards cannot be read by offset
+	if m.rs.Encrypted {
+		m.extractCreator = extract.NoOffsetExtractCreator(m.extractCreator)
+	}
 
 	m.recManager = extract.NewRecordManager(m.ctx.t, m.ctx.node.DaemonID, m.rs.Bucket, m.rs.Provider,
 		m.rs.Extension, m.extractCreator, keyExtractor, onDuplicatedRecords)
@@ -470,6 +492,26 @@ func (m *Manager) setExtractCreator() (err error) {
 	return nil
 }
 
+// newExtractCreator creates extract creator for the given shard format;
+// compression applies to the created shards (if the format is compressed).
+func (m *Manager) newExtractCreator(ext, compression string) extract.ExtractCreator {
+	switch ext {
+	case cmn.ExtTar:
+		return extract.NewTarExtractCreator(m.ctx.t)
+	case cmn.ExtTarTgz, cmn.ExtTgz:
+		return extract.NewTargzExtractCreator(m.ctx.t, compression)
+	case cmn.ExtZip:
+		return extract.NewZipExtractCreator(m.ctx.t, compression)
+	case cmn.ExtTFRecord:
+		return extract.NewTFRecordExtractCreator(m.ctx.t)
+	case cmn.ExtMsgpack:
+		return extract.NewMsgpackExtractCreator(m.ctx.t)
+	default:
+		cmn.Assertf(false, "unknown extension %s", ext)
+		return nil
+	}
+}
+
 // updateFinishedAck marks daemonID as finished. If all daemons ack then the
 // finalCleanup is dispatched in separate goroutine.
 func (m *Manager) updateFinishedAck(daemonID string) {
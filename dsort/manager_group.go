@@ -140,6 +140,7 @@ func (mg *ManagerGroup) Remove(managerUUID string) error {
 
 	key := path.Join(managersKey, managerUUID)
 	_ = mg.db.Delete(dsortCollection, key) // Delete only returns err when record does not exist, which should be ignored
+	mg.discardCheckpoint(managerUUID)
 	return nil
 }
 
@@ -206,5 +207,9 @@ func (mg *ManagerGroup) housekeep() time.Duration {
 		}
 	}
 
+	if err := mg.removeStaleCheckpoints(); err != nil {
+		glog.Error(err)
+		return retryInterval
+	}
 	return regularInterval
 }
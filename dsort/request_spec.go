@@ -26,7 +26,10 @@ const (
 
 var (
 	errMissingBucket            = errors.New("missing field 'bucket'")
-	errInvalidExtension         = errors.New("extension must be one of '.tar', '.tar.gz', or '.tgz'")
+	errInvalidExtension         = fmt.Errorf("extension must be one of: %+v", supportedExtensions)
+	errInvalidOutputExtension   = fmt.Errorf("output extension must be one of: %+v", supportedExtensions)
+	errInvalidOutputCompression = fmt.Errorf("output compression must be one of: %+v", extract.SupportedCompressions)
+	errUnusedOutputCompression  = fmt.Errorf("output compression applies only to compressed output formats: %+v", compressedExtensions)
 	errNegOutputShardSize       = errors.New("output shard size must be >= 0")
 	errEmptyOutputShardSize     = errors.New("output shard size must be set (cannot be 0)")
 	errNegativeConcurrencyLimit = fmt.Errorf("concurrency max limit must be 0 (limits will be calculated) or > 0")
@@ -42,7 +45,10 @@ var (
 )
 
 // supportedExtensions is a list of supported extensions by dSort
-var supportedExtensions = []string{cmn.ExtTar, cmn.ExtTgz, cmn.ExtTarTgz, cmn.ExtZip}
+var supportedExtensions = []string{cmn.ExtTar, cmn.ExtTgz, cmn.ExtTarTgz, cmn.ExtZip, cmn.ExtTFRecord, cmn.ExtMsgpack}
+
+// compressedExtensions is a list of supported extensions for which output compression can be set
+var compressedExtensions = []string{cmn.ExtTgz, cmn.ExtTarTgz, cmn.ExtZip}
 
 // TODO: maybe this struct should be composed of `type` and `template` where
 // template is interface and each template has it's own struct. Then we could
@@ -82,6 +88,10 @@ type RequestSpec struct {
 	OutputBucket string `json:"output_bucket" yaml:"output_bucket"`
 	// Default: alphanumeric, increasing
 	Algorithm SortAlgorithm `json:"algorithm" yaml:"algorithm"`
+	// Default: same as `extension` field
+	OutputExtension string `json:"output_extension" yaml:"output_extension"`
+	// Default: "" (format specific, see: extract.CompressionDefault)
+	OutputCompression string `json:"output_compression" yaml:"output_compression"`
 	// Default: ""
 	OrderFileURL string `json:"order_file" yaml:"order_file"`
 	// Default: "\t"
@@ -116,6 +126,8 @@ type ParsedRequestSpec struct {
 	Provider            string                `json:"provider"`
 	OutputProvider      string                `json:"output_provider"`
 	Extension           string                `json:"extension"`
+	OutputExtension     string                `json:"output_extension"`
+	OutputCompression   string                `json:"output_compression"`
 	OutputShardSize     int64                 `json:"output_shard_size,string"`
 	InputFormat         *parsedInputTemplate  `json:"input_format"`
 	OutputFormat        *parsedOutputTemplate `json:"output_format"`
@@ -128,6 +140,8 @@ type ParsedRequestSpec struct {
 	CreateConcMaxLimit  int                   `json:"create_concurrency_max_limit"`
 	StreamMultiplier    int                   `json:"stream_multiplier"` // TODO: should be removed
 	ExtendedMetrics     bool                  `json:"extended_metrics"`
+	Encrypted           bool                  `json:"encrypted"`        // input bucket has SSE enabled (set by proxy)
+	Resume              *ResumeMsg            `json:"resume,omitempty"` // job to resume (set by proxy)
 
 	// debug
 	DSorterType string `json:"dsorter_type"`
@@ -148,6 +162,9 @@ type SortAlgorithm struct {
 	// Kind: content
 	Extension  string `json:"extension"`
 	FormatType string `json:"format_type"`
+
+	// Kind: expression - records are ordered by the first key, then by the second one, and so on
+	Keys []extract.KeyExpr `json:"keys"`
 }
 
 // Parse returns a non-nil error if a RequestSpec is invalid. When RequestSpec
@@ -187,6 +204,22 @@ func (rs *RequestSpec) Parse() (*ParsedRequestSpec, error) {
 	}
 	parsedRS.Extension = rs.Extension
 
+	parsedRS.OutputExtension = rs.OutputExtension
+	if parsedRS.OutputExtension == "" {
+		parsedRS.OutputExtension = parsedRS.Extension
+	}
+	if !validateExtension(parsedRS.OutputExtension) {
+		return nil, errInvalidOutputExtension
+	}
+	if !cmn.StringInSlice(rs.OutputCompression, extract.SupportedCompressions) {
+		return nil, errInvalidOutputCompression
+	}
+	if rs.OutputCompression != extract.CompressionDefault && rs.OutputCompression != extract.CompressionNone &&
+		!cmn.StringInSlice(parsedRS.OutputExtension, compressedExtensions) {
+		return nil, errUnusedOutputCompression
+	}
+	parsedRS.OutputCompression = rs.OutputCompression
+
 	parsedRS.OutputShardSize, err = cmn.S2B(rs.OutputShardSize)
 	if err != nil {
 		return nil, err
@@ -334,6 +367,10 @@ func parseAlgorithm(algo SortAlgorithm) (parsedAlgo *SortAlgorithm, err error) {
 		if err := extract.ValidateAlgorithmFormatType(algo.FormatType); err != nil {
 			return nil, err
 		}
+	} else if algo.Kind == SortKindExpression {
+		if _, err := extract.NewExprKeyExtractor(algo.Keys); err != nil {
+			return nil, err
+		}
 	} else {
 		algo.FormatType = extract.FormatTypeString
 	}
@@ -11,6 +11,7 @@ import (
 	"math"
 
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
 	"github.com/NVIDIA/aistore/fs"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -44,6 +45,7 @@ var _ = Describe("RequestSpec", func() {
 			Expect(parsed.Provider).To(Equal(cmn.ProviderAIS))
 			Expect(parsed.OutputProvider).To(Equal(cmn.ProviderAIS))
 			Expect(parsed.Extension).To(Equal(cmn.ExtTar))
+			Expect(parsed.OutputExtension).To(Equal(cmn.ExtTar))
 
 			Expect(parsed.InputFormat.Template).To(Equal(cmn.ParsedTemplate{
 				Prefix: "prefix-",
@@ -157,6 +159,25 @@ var _ = Describe("RequestSpec", func() {
 			Expect(parsed.Extension).To(Equal(cmn.ExtZip))
 		})
 
+		It("should parse spec with output extension and compression", func() {
+			rs := RequestSpec{
+				Bucket:            "test",
+				Extension:         cmn.ExtTar,
+				OutputExtension:   cmn.ExtZip,
+				OutputCompression: extract.CompressionBest,
+				InputFormat:       "prefix-{0010..0111}-suffix",
+				OutputFormat:      "prefix-{0010..0111}-suffix",
+				OutputShardSize:   "10KB",
+				Algorithm:         SortAlgorithm{Kind: SortKindNone},
+			}
+			parsed, err := rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(parsed.Extension).To(Equal(cmn.ExtTar))
+			Expect(parsed.OutputExtension).To(Equal(cmn.ExtZip))
+			Expect(parsed.OutputCompression).To(Equal(extract.CompressionBest))
+		})
+
 		It("should parse spec with %06d syntax", func() {
 			rs := RequestSpec{
 				Bucket:          "test",
@@ -360,6 +381,62 @@ var _ = Describe("RequestSpec", func() {
 			Expect(err).To(Equal(errInvalidExtension))
 		})
 
+		It("should fail due to invalid output extension", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTar,
+				OutputExtension: ".jpg",
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+			}
+			_, err := rs.Parse()
+			Expect(err).Should(HaveOccurred())
+			Expect(err).To(Equal(errInvalidOutputExtension))
+		})
+
+		It("should fail due to invalid output compression", func() {
+			rs := RequestSpec{
+				Bucket:            "test",
+				Extension:         cmn.ExtTgz,
+				OutputCompression: "lz4",
+				InputFormat:       "prefix-{0010..0111}-suffix",
+				OutputFormat:      "prefix-{0010..0111}-suffix",
+				OutputShardSize:   "10KB",
+				Algorithm:         SortAlgorithm{Kind: SortKindNone},
+			}
+			_, err := rs.Parse()
+			Expect(err).Should(HaveOccurred())
+			Expect(err).To(Equal(errInvalidOutputCompression))
+
+			rs.OutputExtension = cmn.ExtTFRecord
+			rs.OutputCompression = extract.CompressionBest
+			_, err = rs.Parse()
+			Expect(err).Should(HaveOccurred())
+			Expect(err).To(Equal(errUnusedOutputCompression))
+		})
+
+		It("should fail due to invalid key expression", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm: SortAlgorithm{Kind: SortKindExpression, Keys: []extract.KeyExpr{
+					{Source: extract.KeySourceName, Regex: `(\d+)`, Group: 1, FormatType: extract.FormatTypeInt},
+					{Source: extract.KeySourceJSON, Extension: ".json"}, // missing field
+				}},
+			}
+			_, err := rs.Parse()
+			Expect(err).Should(HaveOccurred())
+
+			rs.Algorithm.Keys[1].Field = "meta.score"
+			_, err = rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
 		It("should fail due to invalid mem usage specification", func() {
 			rs := RequestSpec{
 				Bucket:          "test",
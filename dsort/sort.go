@@ -19,11 +19,13 @@ const (
 	SortKindAlphanumeric = "alphanumeric" // sort the records (decreasing or increasing)
 	SortKindNone         = "none"         // none, used for resharding
 	SortKindMD5          = "md5"
-	SortKindShuffle      = "shuffle" // shuffle randomly, can be used with seed to get reproducible results
-	SortKindContent      = "content" // sort by content of given file
+	SortKindShuffle      = "shuffle"    // shuffle randomly, can be used with seed to get reproducible results
+	SortKindContent      = "content"    // sort by content of given file
+	SortKindExpression   = "expression" // sort by keys computed from record's name, metadata and contents
 )
 
-var supportedAlgorithms = []string{sortKindEmpty, SortKindAlphanumeric, SortKindMD5, SortKindShuffle, SortKindContent, SortKindNone}
+var supportedAlgorithms = []string{sortKindEmpty, SortKindAlphanumeric, SortKindMD5, SortKindShuffle, SortKindContent,
+	SortKindExpression, SortKindNone}
 
 type (
 	alphaByKey struct {
@@ -32,9 +34,18 @@ type (
 		formatType string
 		err        error
 	}
+
+	exprByKey struct {
+		*extract.Records
+		keys []extract.KeyExpr
+		err  error
+	}
 )
 
-var _ sort.Interface = &alphaByKey{}
+var (
+	_ sort.Interface = &alphaByKey{}
+	_ sort.Interface = &exprByKey{}
+)
 
 func (s *alphaByKey) Less(i, j int) bool {
 	var (
@@ -54,6 +65,14 @@ func (s *alphaByKey) Less(i, j int) bool {
 	return less
 }
 
+func (s *exprByKey) Less(i, j int) bool {
+	less, err := s.Records.LessExprs(i, j, s.keys)
+	if err != nil {
+		s.err = err
+	}
+	return less
+}
+
 // sortRecords sorts records by each Record.Key in the order determined by sort algorithm.
 func sortRecords(r *extract.Records, algo *SortAlgorithm) (err error) {
 	if algo.Kind == SortKindNone {
@@ -72,6 +91,13 @@ func sortRecords(r *extract.Records, algo *SortAlgorithm) (err error) {
 			j := rand.Intn(i + 1)
 			r.Swap(i, j)
 		}
+	} else if algo.Kind == SortKindExpression {
+		keys := &exprByKey{r, algo.Keys, nil}
+		sort.Sort(keys)
+
+		if keys.err != nil {
+			return keys.err
+		}
 	} else {
 		keys := &alphaByKey{r, algo.Decreasing, algo.FormatType, nil}
 		sort.Sort(keys)
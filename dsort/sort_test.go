@@ -95,4 +95,37 @@ var _ = Describe("SortRecords", func() {
 		err := sortRecords(fm, &SortAlgorithm{Decreasing: true, FormatType: extract.FormatTypeString})
 		Expect(err).To(HaveOccurred())
 	})
+
+	Context("expression", func() {
+		algo := &SortAlgorithm{Kind: SortKindExpression, Keys: []extract.KeyExpr{
+			{Source: extract.KeySourceName},
+			{Source: extract.KeySourceSize, Extension: ".jpg", Decreasing: true},
+		}}
+
+		It("should sort records by multiple keys", func() {
+			expected := createRecords(
+				[]interface{}{"abc", int64(20)}, []interface{}{"abc", int64(10)}, []interface{}{"def", int64(30)},
+			)
+			fm := createRecords(
+				[]interface{}{"def", int64(30)}, []interface{}{"abc", int64(10)}, []interface{}{"abc", int64(20)},
+			)
+			err := sortRecords(fm, algo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fm).To(Equal(expected))
+		})
+
+		It("should sort records when int keys were parsed as floats", func() {
+			expected := createRecords([]interface{}{"abc", float64(20)}, []interface{}{"abc", int64(10)})
+			fm := createRecords([]interface{}{"abc", int64(10)}, []interface{}{"abc", float64(20)})
+			err := sortRecords(fm, algo)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fm).To(Equal(expected))
+		})
+
+		It("should return error when some keys are missing", func() {
+			fm := createRecords([]interface{}{"abc", nil}, []interface{}{"abc", int64(10)})
+			err := sortRecords(fm, algo)
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,321 @@
+// Package ec provides erasure coding (EC) based data protection for AIStore.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ec
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs/mpather"
+	"github.com/NVIDIA/aistore/xaction"
+	"github.com/NVIDIA/aistore/xaction/xreg"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// EC scrubber walks the metafiles of the bucket and, for every object this
+// target is the main one for, verifies that the object, its slices and replicas
+// exist on the targets they are supposed to be and are not damaged:
+//   - the main object is missing or damaged: restore it from slices/replicas
+//     (restoring also re-creates missing slices/replicas)
+//   - a slice or replica is missing, damaged, or outdated: re-encode the object
+//     (all slices/replicas are rewritten)
+
+type (
+	// Implements `xreg.BucketEntryProvider` and `xreg.BucketEntry` interface.
+	xactBckScrubProvider struct {
+		xreg.BaseBckEntry
+		xact *XactBckScrub
+
+		t    cluster.Target
+		uuid string
+	}
+
+	XactBckScrub struct {
+		xaction.XactBase
+		t      cluster.Target
+		bck    cmn.Bck
+		wg     *sync.WaitGroup // to wait for EC finishes all objects
+		smap   *cluster.Smap
+		client *http.Client
+		stats  struct {
+			missing  atomic.Int64
+			damaged  atomic.Int64
+			repaired atomic.Int64
+			errors   atomic.Int64
+		}
+	}
+
+	ScrubTargetStats struct {
+		xaction.BaseXactStats
+		Ext ExtECScrubStats `json:"ext"`
+	}
+
+	ExtECScrubStats struct {
+		MissingCount  int64 `json:"ec.scrub.missing.n,string"`  // missing or outdated objects, slices, and replicas
+		DamagedCount  int64 `json:"ec.scrub.damaged.n,string"`  // objects, slices, and replicas with bad checksum
+		RepairedCount int64 `json:"ec.scrub.repaired.n,string"` // objects repaired
+		ErrCount      int64 `json:"ec.scrub.err.n,string"`      // objects that failed to be checked or repaired
+	}
+)
+
+var (
+	// interface guard
+	_ cluster.Xact      = &XactBckScrub{}
+	_ cluster.XactStats = &ScrubTargetStats{}
+
+	errCTMissing = errors.New("missing")
+	errCTDamaged = errors.New("damaged")
+)
+
+func (*xactBckScrubProvider) New(args xreg.XactArgs) xreg.BucketEntry {
+	return &xactBckScrubProvider{t: args.T, uuid: args.UUID}
+}
+
+func (p *xactBckScrubProvider) Start(bck cmn.Bck) error {
+	p.xact = NewXactBckScrub(bck, p.t, p.uuid)
+	return nil
+}
+func (*xactBckScrubProvider) Kind() string        { return cmn.ActECScrub }
+func (p *xactBckScrubProvider) Get() cluster.Xact { return p.xact }
+func (*xactBckScrubProvider) PreRenewHook(previousEntry xreg.BucketEntry) (keep bool, err error) {
+	err = fmt.Errorf("%s is already running", previousEntry.Get())
+	return
+}
+
+func NewXactBckScrub(bck cmn.Bck, t cluster.Target, uuid string) *XactBckScrub {
+	config := cmn.GCO.Get()
+	return &XactBckScrub{
+		XactBase: *xaction.NewXactBaseBck(uuid, cmn.ActECScrub, bck),
+		t:        t,
+		bck:      bck,
+		wg:       &sync.WaitGroup{},
+		smap:     t.Sowner().Get(),
+		client: cmn.NewClient(cmn.TransportArgs{
+			Timeout:    config.Client.Timeout,
+			UseHTTPS:   config.Net.HTTP.UseHTTPS,
+			SkipVerify: config.Net.HTTP.SkipVerify,
+		}),
+	}
+}
+
+func (r *XactBckScrub) Run() (err error) {
+	bck := cluster.NewBckEmbed(r.bck)
+	if err := bck.Init(r.t.Bowner(), r.t.Snode()); err != nil {
+		r.Finish(err)
+		return err
+	}
+	if !bck.Props.EC.Enabled {
+		err = fmt.Errorf("bucket %q does not have EC enabled", r.bck.Name)
+		r.Finish(err)
+		return err
+	}
+	// while rebalancing, slices and replicas are not where they are supposed to be
+	if marked := xreg.GetRebMarked(); marked.Xact != nil || marked.Interrupted {
+		err = fmt.Errorf("%s: cannot scrub %s while rebalance is running or interrupted", r.t.Snode(), r.bck)
+		r.Finish(err)
+		return err
+	}
+
+	jg := mpather.NewJoggerGroup(&mpather.JoggerGroupOpts{
+		T:                     r.t,
+		Bck:                   r.bck,
+		CTs:                   []string{MetaType},
+		VisitCT:               r.scrub,
+		SkipGloballyMisplaced: true, // only the main target checks an object
+		Throttle:              true,
+	})
+	jg.Run()
+
+	select {
+	case <-r.ChanAbort():
+		jg.Stop()
+		err = fmt.Errorf("%s aborted, exiting", r)
+	case <-jg.ListenFinished():
+		err = jg.Stop()
+	}
+	r.wg.Wait() // Need to wait for all async actions to finish.
+
+	glog.Infof("%s: missing %d, damaged %d, repaired %d, errors %d", r, r.stats.missing.Load(),
+		r.stats.damaged.Load(), r.stats.repaired.Load(), r.stats.errors.Load())
+	r.Finish(err)
+	return
+}
+
+func (r *XactBckScrub) Stats() cluster.XactStats {
+	baseStats := r.XactBase.Stats().(*xaction.BaseXactStats)
+	scrubStats := ScrubTargetStats{BaseXactStats: *baseStats}
+	scrubStats.Ext.MissingCount = r.stats.missing.Load()
+	scrubStats.Ext.DamagedCount = r.stats.damaged.Load()
+	scrubStats.Ext.RepairedCount = r.stats.repaired.Load()
+	scrubStats.Ext.ErrCount = r.stats.errors.Load()
+	return &scrubStats
+}
+
+// Checks the object of the metafile, and all its slices or replicas.
+// The errors are counted and logged but do not interrupt the walk.
+func (r *XactBckScrub) scrub(ct *cluster.CT, _ []byte) error {
+	md, err := LoadMetadata(ct.FQN())
+	if err != nil {
+		r.stats.errors.Inc()
+		glog.Error(err)
+		return nil
+	}
+	// the metafile of a slice or a replica on the main target (e.g., after
+	// the cluster map has changed) - it is for rebalance to fix it
+	if md.SliceID != 0 {
+		return nil
+	}
+	lom := &cluster.LOM{T: r.t, ObjName: ct.ObjName()}
+	if err := lom.Init(r.bck); err != nil {
+		r.stats.errors.Inc()
+		glog.Errorf("%s: %v", r, err)
+		return nil
+	}
+
+	// 1. main object
+	if err = lom.Load(); err == nil {
+		err = lom.ValidateContentChecksum()
+	}
+	if err != nil {
+		if cmn.IsObjNotExist(err) {
+			r.stats.missing.Inc()
+		} else {
+			r.stats.damaged.Inc()
+		}
+		glog.Warningf("%s: restoring %s: %v", r, lom, err)
+		if err := ECM.RestoreObject(lom); err != nil {
+			r.stats.errors.Inc()
+			glog.Errorf("%s: failed to restore %s: %v", r, lom, err)
+			return nil
+		}
+		r.stats.repaired.Inc()
+		r.ObjectsInc()
+		r.BytesAdd(lom.Size())
+		return nil
+	}
+
+	// 2. slices or replicas
+	total := md.Parity
+	if !md.IsCopy {
+		total += md.Data
+	}
+	targets, err := cluster.HrwTargetList(lom.Uname(), r.smap, total+1)
+	if err != nil {
+		r.stats.errors.Inc()
+		glog.Errorf("%s: %s: %v", r, lom, err)
+		return nil
+	}
+	var (
+		wg    = &sync.WaitGroup{}
+		valid = atomic.NewBool(true)
+	)
+	for i, si := range targets[1:] {
+		wg.Add(1)
+		go func(sliceID int, si *cluster.Snode) {
+			defer wg.Done()
+			if !r.validateRemote(lom, md, sliceID, si) {
+				valid.Store(false)
+			}
+		}(i+1, si)
+	}
+	wg.Wait()
+	r.ObjectsInc()
+	r.BytesAdd(lom.Size())
+	if valid.Load() {
+		return nil
+	}
+
+	// the counter is decreased by afterECObj callback once the object is
+	// re-encoded; after the walk, the xaction waits until it drops to zero
+	r.wg.Add(1)
+	if err := ECM.EncodeObject(lom, r.afterECObj); err != nil {
+		r.wg.Done()
+		r.stats.errors.Inc()
+		glog.Errorf("%s: failed to re-encode %s: %v", r, lom, err)
+	}
+	return nil
+}
+
+func (r *XactBckScrub) afterECObj(lom *cluster.LOM, err error) {
+	if err == nil {
+		r.stats.repaired.Inc()
+	} else {
+		r.stats.errors.Inc()
+		glog.Errorf("%s: failed to re-encode %s: %v", r, lom, err)
+	}
+	r.wg.Done()
+}
+
+// validateRemote asks the target to validate its slice or replica of the
+// object. Returns false if the slice/replica must be rebuilt.
+func (r *XactBckScrub) validateRemote(lom *cluster.LOM, md *Metadata, sliceID int, si *cluster.Snode) bool {
+	remote, err := r.requestMeta(lom, si)
+	if err == nil {
+		// a slice of another version of the object, or a slice with another ID
+		if remote.ObjCksum != md.ObjCksum || (!md.IsCopy && remote.SliceID != sliceID) {
+			err = fmt.Errorf("%w: found outdated %s", errCTMissing, remote.sliceName())
+		}
+	}
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, errCTDamaged):
+		r.stats.damaged.Inc()
+	case errors.Is(err, errCTMissing):
+		r.stats.missing.Inc()
+	default:
+		// target is unreachable - nothing to repair yet
+		r.stats.errors.Inc()
+		glog.Errorf("%s: failed to validate %s on %s: %v", r, lom, si, err)
+		return true
+	}
+	glog.Warningf("%s: %s on %s: %v", r, lom, si, err)
+	return false
+}
+
+// requestMeta returns the metadata of the validated slice or replica
+func (r *XactBckScrub) requestMeta(lom *cluster.LOM, si *cluster.Snode) (md *Metadata, err error) {
+	var (
+		path  = cmn.JoinWords(cmn.Version, cmn.EC, URLMeta, r.bck.Name, lom.ObjName)
+		query = cmn.AddBckToQuery(url.Values{}, r.bck)
+	)
+	query.Set(cmn.URLParamECValidate, "true")
+	rq, err := http.NewRequest(http.MethodGet, si.URL(cmn.NetworkIntraData)+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	rq.URL.RawQuery = query.Encode()
+	resp, err := r.client.Do(rq) // nolint:bodyclose // closed inside cmn.Close
+	if err != nil {
+		return nil, err
+	}
+	defer cmn.Close(resp.Body)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		md = &Metadata{}
+		err = jsoniter.NewDecoder(resp.Body).Decode(md)
+		return md, err
+	case http.StatusNotFound:
+		return nil, errCTMissing
+	case http.StatusConflict:
+		return nil, errCTDamaged
+	default:
+		return nil, fmt.Errorf("failed to validate %s on %s: %s", lom, si, resp.Status)
+	}
+}
+
+func (md *Metadata) sliceName() string {
+	if md.SliceID == 0 {
+		return "replica"
+	}
+	return fmt.Sprintf("slice %d", md.SliceID)
+}
@@ -0,0 +1,101 @@
+// Package ec provides erasure coding (EC) based data protection for AIStore.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ec
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/klauspost/reedsolomon"
+)
+
+// Codec calculates parity slices of an object and restores its lost slices.
+// Slices are always ordered: data slices first, parity slices next.
+type Codec interface {
+	// Encode reads all data slices and writes all parity slices.
+	Encode(data []io.Reader, parity []io.Writer) error
+	// Reconstruct restores the slices that are missing (`valid[i] == nil`)
+	// and requested (`fill[i] != nil`).
+	Reconstruct(valid []io.Reader, fill []io.Writer) error
+	// RepairSet returns the minimal set of slices to read to restore the
+	// `missing` ones, `avail[i]` tells whether slice `i` can be read.
+	RepairSet(missing []int, avail []bool) ([]int, error)
+}
+
+// Reed-Solomon: any `data` slices restore all the others
+type rsCodec struct {
+	reedsolomon.StreamEncoder
+	data int
+}
+
+// interface guard
+var _ Codec = &rsCodec{}
+
+func (c *rsCodec) RepairSet(missing []int, avail []bool) ([]int, error) {
+	ids := make([]int, 0, c.data)
+	if len(missing) == 0 {
+		return ids, nil
+	}
+	for i, ok := range avail {
+		if len(ids) == c.data {
+			break
+		}
+		if ok {
+			ids = append(ids, i)
+		}
+	}
+	if len(ids) < c.data {
+		return nil, reedsolomon.ErrTooFewShards
+	}
+	return ids, nil
+}
+
+// NewCodec returns the codec the object slices are (to be) encoded with.
+// Metadata without codec is treated as Reed-Solomon for backward compatibility.
+func NewCodec(md *Metadata) (Codec, error) {
+	switch md.Codec {
+	case "", cmn.ECCodecRS:
+		rs, err := reedsolomon.NewStreamC(md.Data, md.Parity, true, true)
+		if err != nil {
+			return nil, err
+		}
+		return &rsCodec{StreamEncoder: rs, data: md.Data}, nil
+	case cmn.ECCodecLRC:
+		return newLRC(md.Data, md.Parity, md.LocalGroups)
+	default:
+		return nil, fmt.Errorf("unknown EC codec %q", md.Codec)
+	}
+}
+
+// SlicesToRestore returns the slices to read to restore the full object:
+// all existing data slices and the slices required to repair the missing
+// ones (see `Codec.RepairSet`). Missing parity slices, if any, are recalculated
+// from the data slices. The result is indexed by slice index (SliceID - 1).
+func SlicesToRestore(md *Metadata, avail []bool) ([]bool, error) {
+	codec, err := NewCodec(md)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		toRead  = make([]bool, len(avail))
+		missing = make([]int, 0, md.Parity)
+	)
+	for i := 0; i < md.Data; i++ {
+		if avail[i] {
+			toRead[i] = true
+		} else {
+			missing = append(missing, i)
+		}
+	}
+	ids, err := codec.RepairSet(missing, avail)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range ids {
+		toRead[i] = true
+	}
+	return toRead, nil
+}
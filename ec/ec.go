@@ -23,6 +23,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/sse"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/xaction/xreg"
 	jsoniter "github.com/json-iterator/go"
@@ -34,6 +35,8 @@ import (
 //		Enable: true|false    # enables or disables protection
 //		DataSlices: [1-32]    # the number of data slices
 //		ParitySlices: [1-32]  # the number of parity slices
+//		Codec: rs|lrc         # Reed-Solomon (default) or Locally Repairable Codes
+//		LocalGroups: 0        # LRC: the number of local groups (and local parity slices)
 //		ObjSizeLimit: 0       # replication versus erasure coding
 //
 // NOTE: replicating small object is cheaper than erasure encoding.
@@ -74,6 +77,8 @@ import (
 //		sliceid - used if the object was encoded, the ordinal number of slice
 //			starting from 1 (0 means 'full copy' - either orignal object or
 //			its replica)
+//		codec, local_groups - erasure code the object was encoded with
+//			(empty codec means Reed-Solomon)
 //
 //
 // How protection works.
@@ -241,6 +246,7 @@ func Init(t cluster.Target) {
 	xreg.RegisterBucketXact(&xactPutProvider{})
 	xreg.RegisterBucketXact(&xactRespondProvider{})
 	xreg.RegisterBucketXact(&xactBckEncodeProvider{})
+	xreg.RegisterBucketXact(&xactBckScrubProvider{})
 
 	if err := initManager(t); err != nil {
 		glog.Fatal(err)
@@ -294,6 +300,12 @@ func freeObject(r interface{}) {
 		}
 		return
 	}
+	if f, ok := r.(*sse.FileHandle); ok {
+		if f != nil {
+			cmn.Close(f)
+		}
+		return
+	}
 	cmn.Assertf(false, "invalid object type: %v", r)
 }
 
@@ -332,6 +344,58 @@ func requestECMeta(bck cmn.Bck, objName string, si *cluster.Snode, client *http.
 	return md, err
 }
 
+// ValidateCT checks that the slice or replica described by the metadata is
+// stored on this target and its content matches the checksum.
+func ValidateCT(t cluster.Target, bck *cluster.Bck, objName string, md *Metadata) error {
+	if md.SliceID == 0 {
+		lom := &cluster.LOM{T: t, ObjName: objName}
+		if err := lom.Init(bck.Bck); err != nil {
+			return err
+		}
+		if err := lom.Load(); err != nil {
+			return err
+		}
+		return lom.ValidateContentChecksum()
+	}
+
+	fqn, _, err := cluster.HrwFQN(bck, SliceType, objName)
+	if err != nil {
+		return err
+	}
+	finfo, err := os.Stat(fqn)
+	if err != nil {
+		return err
+	}
+	if md.CksumType == "" || md.CksumType == cmn.ChecksumNone {
+		return nil
+	}
+	var (
+		file cmn.ReadOpenCloser
+		size = finfo.Size()
+	)
+	if md.SSE != "" {
+		if size, err = sse.PlainSize(size); err == nil {
+			file, err = OpenSlice(fqn, md.SSE, size)
+		}
+	} else {
+		file, err = cmn.NewFileHandle(fqn)
+	}
+	if err != nil {
+		return err
+	}
+	defer cmn.Close(file)
+	buf, slab := mm.Alloc(size)
+	_, cksum, err := cmn.CopyAndChecksum(ioutil.Discard, file, buf, md.CksumType)
+	slab.Free(buf)
+	if err != nil {
+		return err
+	}
+	if expected := cmn.NewCksum(md.CksumType, md.CksumValue); !cksum.Equal(expected) {
+		return cmn.NewBadDataCksumError(expected, &cksum.Cksum, fmt.Sprintf("%s/%s, slice %d", bck, objName, md.SliceID))
+	}
+	return nil
+}
+
 // Saves the main replica to local drives
 func WriteObject(t cluster.Target, lom *cluster.LOM, reader io.Reader, size int64, cksumType string) error {
 	if size > 0 {
@@ -352,18 +416,30 @@ func WriteObject(t cluster.Target, lom *cluster.LOM, reader io.Reader, size int6
 	return t.PutObject(lom, params)
 }
 
-// Saves slice and its metafile
-func WriteSliceAndMeta(t cluster.Target, hdr transport.ObjHdr, data io.Reader, md []byte) error {
+// Saves slice and its metafile; the slice gets encrypted if the bucket has
+// server-side encryption enabled (see sse package)
+func WriteSliceAndMeta(t cluster.Target, hdr transport.ObjHdr, data io.Reader, meta *Metadata) error {
 	ct, err := cluster.NewCTFromBO(hdr.Bck.Name, hdr.Bck.Provider, hdr.ObjName, t.Bowner(), SliceType)
 	if err != nil {
 		return err
 	}
+	size := hdr.ObjAttrs.Size
+	meta.SSE = ""
+	if sseConf := &ct.Bprops().SSE; sseConf.Enabled {
+		key, err := sse.Key(sseConf.KeyID)
+		if err != nil {
+			return err
+		}
+		r := sse.EncryptReader(io.LimitReader(data, size), key)
+		defer r.Close()
+		data, size, meta.SSE = r, sse.CipherSize(size), sseConf.KeyID
+	}
 	tmpFQN := ct.Make(fs.WorkfileType)
-	if err := ct.Write(t, data, hdr.ObjAttrs.Size, tmpFQN); err != nil {
+	if err := ct.Write(t, data, size, tmpFQN); err != nil {
 		return err
 	}
 	ctMeta := ct.Clone(MetaType)
-	err = ctMeta.Write(t, bytes.NewReader(md), -1)
+	err = ctMeta.Write(t, bytes.NewReader(meta.Marshal()), -1)
 	if err != nil {
 		if rmErr := os.Remove(ct.FQN()); rmErr != nil && !os.IsNotExist(rmErr) {
 			glog.Errorf("nested error: save replica -> remove replica: %v", rmErr)
@@ -22,8 +22,8 @@ import (
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/sse"
 	"github.com/NVIDIA/aistore/transport"
-	"github.com/klauspost/reedsolomon"
 )
 
 // a mountpath getJogger: processes GET requests to one mountpath
@@ -148,8 +148,8 @@ func (c *getJogger) copyMissingReplicas(lom *cluster.LOM, reader cmn.ReadOpenClo
 	switch r := reader.(type) {
 	case *memsys.SGL:
 		srcReader = memsys.NewReader(r)
-	case *cmn.FileHandle:
-		srcReader, err = cmn.NewFileHandle(lom.FQN)
+	case *cmn.FileHandle, *sse.FileHandle:
+		srcReader, err = lom.Open()
 	default:
 		cmn.Assertf(false, "unsupported reader type: %v", reader)
 	}
@@ -238,8 +238,16 @@ func (c *getJogger) restoreReplicatedFromDisk(req *Request, meta *Metadata, node
 	var (
 		writer *os.File
 		n      int64
+		key    []byte
 		mm     = c.parent.t.SmallMMSA()
+		sseCfg = &req.LOM.Bprops().SSE
 	)
+	if sseCfg.Enabled {
+		var err error
+		if key, err = sse.Key(sseCfg.KeyID); err != nil {
+			return err
+		}
+	}
 	// try read a replica from targets one by one until the replica is got
 	objFQN := req.LOM.FQN
 	tmpFQN := fs.CSM.GenContentFQN(objFQN, fs.WorkfileType, "ec-restore-repl")
@@ -254,13 +262,16 @@ func (c *getJogger) restoreReplicatedFromDisk(req *Request, meta *Metadata, node
 		}
 		iReqBuf := c.parent.newIntraReq(reqGet, meta).NewPack(mm)
 		lomClone := req.LOM.Clone(tmpFQN)
-		n, err = c.parent.readRemote(lomClone, node, uname, iReqBuf, w)
+		n, err = c.readReplica(lomClone, node, uname, iReqBuf, w, key)
 		mm.Free(iReqBuf)
 		cmn.Close(w)
 
 		if err == nil && n != 0 {
 			// a valid replica is found - break and do not free SGL
 			req.LOM.SetSize(n)
+			if key != nil {
+				req.LOM.SetSSEKeyID(sseCfg.KeyID)
+			}
 			writer = w
 			break
 		}
@@ -291,7 +302,7 @@ func (c *getJogger) restoreReplicatedFromDisk(req *Request, meta *Metadata, node
 
 	// now a client can read the object, but EC needs to restore missing
 	// replicas. So, execute copying replicas in background and return
-	reader, err := cmn.NewFileHandle(objFQN)
+	reader, err := req.LOM.Open()
 	if err != nil {
 		return err
 	}
@@ -300,16 +311,37 @@ func (c *getJogger) restoreReplicatedFromDisk(req *Request, meta *Metadata, node
 	return nil
 }
 
+// reads the replica from a remote target and writes it to the local file,
+// encrypting it on the fly if the key is given
+func (c *getJogger) readReplica(lom *cluster.LOM, daemonID, uname string, req []byte, w io.Writer,
+	key []byte) (int64, error) {
+	if key == nil {
+		return c.parent.readRemote(lom, daemonID, uname, req, w)
+	}
+	encw, err := sse.NewWriter(w, key)
+	if err != nil {
+		return 0, err
+	}
+	n, err := c.parent.readRemote(lom, daemonID, uname, req, encw)
+	if err == nil {
+		err = encw.Close()
+	}
+	return n, err
+}
+
 // Main object is not found and it is clear that it was encoded. Request
-// all data and parity slices from targets in a cluster:
+// data and parity slices from targets in a cluster:
 // * req - original request
 // * meta - reconstructed metadata
 // * nodes - targets that responded with valid metadata, it does not make sense
 //    to request slice from the entire cluster
+// * toRead - the slices to request (by SliceID), nil - all
 // Returns:
-// * []slice - a list of received slices in correct order (missing slices = nil)
+// * []slice - a list of received slices in correct order (missing and
+//    not requested slices = nil)
 // * map[int]string - a map of slice locations: SliceID <-> DaemonID
-func (c *getJogger) requestSlices(req *Request, meta *Metadata, nodes map[string]*Metadata, toDisk bool) ([]*slice, map[int]string, error) {
+func (c *getJogger) requestSlices(req *Request, meta *Metadata, nodes map[string]*Metadata, toRead map[int]bool,
+	toDisk bool) ([]*slice, map[int]string, error) {
 	wgSlices := cmn.NewTimeoutGroup()
 	sliceCnt := meta.Data + meta.Parity
 	slices := make([]*slice, sliceCnt)
@@ -321,6 +353,11 @@ func (c *getJogger) requestSlices(req *Request, meta *Metadata, nodes map[string
 			glog.Warningf("Node %s has invalid slice ID %d", k, v.SliceID)
 			continue
 		}
+		if toRead != nil && !toRead[v.SliceID] {
+			// the slice exists but it is not needed to restore the object
+			idToNode[v.SliceID] = k
+			continue
+		}
 
 		if glog.V(4) {
 			glog.Infof("Slice %s/%s ID %d requesting from %s", req.LOM.Bck(), req.LOM.ObjName, v.SliceID, k)
@@ -468,6 +505,9 @@ func (c *getJogger) restoreMainObj(req *Request, meta *Metadata, slices []*slice
 	// allocate memory for reconstructed(missing) slices - EC requirement,
 	// and open existing slices for reading
 	for i, sl := range slices {
+		if _, ok := idToNode[i+1]; ok && sl == nil {
+			continue // the slice exists on a target but was not requested
+		}
 		if sl != nil && sl.writer != nil {
 			sz := sl.n
 			if glog.V(4) {
@@ -514,7 +554,7 @@ func (c *getJogger) restoreMainObj(req *Request, meta *Metadata, slices []*slice
 	if glog.V(4) {
 		glog.Infof("Reconstructing %s/%s", req.LOM.Bck(), req.LOM.ObjName)
 	}
-	stream, err := reedsolomon.NewStreamC(meta.Data, meta.Parity, true, true)
+	stream, err := NewCodec(meta)
 	if err != nil {
 		return restored, err
 	}
@@ -714,10 +754,49 @@ func (c *getJogger) uploadRestoredSlices(req *Request, meta *Metadata, slices []
 // * req - original request
 // * meta - rebuild object's metadata
 // * nodes - the list of targets that responded with valid metadata
+// First, it downloads only the slices required to restore the object (see
+// `SlicesToRestore`). If they are not enough (e.g., a slice is corrupted),
+// it retries with all slices.
 func (c *getJogger) restoreEncoded(req *Request, meta *Metadata, nodes map[string]*Metadata, toDisk bool) error {
 	if glog.V(4) {
 		glog.Infof("Starting EC restore %s/%s", req.LOM.Bck(), req.LOM.ObjName)
 	}
+	toRead := slicesToRead(meta, nodes)
+	err := c.restoreFromSlices(req, meta, nodes, toRead, toDisk)
+	if err != nil && toRead != nil {
+		glog.Warningf("%s failed to restore %s/%s from the minimal set of slices, requesting all: %v",
+			c.parent.t.Snode(), req.LOM.Bck(), req.LOM.ObjName, err)
+		err = c.restoreFromSlices(req, meta, nodes, nil, toDisk)
+	}
+	return err
+}
+
+// Returns the slices (by SliceID) to request from the targets to restore
+// the object, nil - all slices
+func slicesToRead(meta *Metadata, nodes map[string]*Metadata) map[int]bool {
+	sliceCnt := meta.Data + meta.Parity
+	avail := make([]bool, sliceCnt)
+	for _, md := range nodes {
+		if md.SliceID >= 1 && md.SliceID <= sliceCnt {
+			avail[md.SliceID-1] = true
+		}
+	}
+	toRead, err := SlicesToRestore(meta, avail)
+	if err != nil {
+		return nil
+	}
+	ids := make(map[int]bool, meta.Data)
+	for i, ok := range toRead {
+		if ok {
+			ids[i+1] = true
+		}
+	}
+	return ids
+}
+
+// * toRead - the slices to download, nil - all
+func (c *getJogger) restoreFromSlices(req *Request, meta *Metadata, nodes map[string]*Metadata,
+	toRead map[int]bool, toDisk bool) error {
 
 	// unregister all SGLs from a list of waiting slices for the data to come
 	freeWriters := func() {
@@ -727,8 +806,8 @@ func (c *getJogger) restoreEncoded(req *Request, meta *Metadata, nodes map[strin
 		}
 	}
 
-	// download all slices from the targets that have sent metadata
-	slices, idToNode, err := c.requestSlices(req, meta, nodes, toDisk)
+	// download slices from the targets that have sent metadata
+	slices, idToNode, err := c.requestSlices(req, meta, nodes, toRead, toDisk)
 	if err != nil {
 		freeWriters()
 		return err
@@ -0,0 +1,333 @@
+// Package ec provides erasure coding (EC) based data protection for AIStore.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/klauspost/reedsolomon"
+)
+
+// Locally Repairable Codes (LRC)
+//
+// Data slices are split into `groups` contiguous local groups of (nearly)
+// equal size. Every group is protected by a local parity slice - XOR of the
+// group data slices. In addition, all data slices are protected by
+// `parity - groups` global parity slices computed with Reed-Solomon.
+//
+// Slice layout: data slices, local parity slices (one per group), global
+// parity slices. So, for 12 data slices, 2 local groups, and 2 global parity
+// slices the total number of parity slices is 4.
+//
+// A single lost slice of a group is restored by reading only the rest of the
+// group instead of reading #DataSlices slices - that is what makes wide
+// stripes affordable. Other losses are restored with global parity slices.
+
+const lrcBlockSize = 256 * cmn.KiB // slices are encoded and restored block by block
+
+var errSliceSizeMismatch = errors.New("slice sizes do not match")
+
+type lrc struct {
+	rs      reedsolomon.Encoder
+	groupOf []int // data slice index => local group
+	data    int
+	groups  int
+	global  int
+}
+
+// interface guard
+var _ Codec = &lrc{}
+
+func newLRC(data, parity, groups int) (*lrc, error) {
+	if groups < 1 || groups > data || groups >= parity {
+		return nil, fmt.Errorf("invalid LRC configuration: %d data, %d parity slices, %d local groups",
+			data, parity, groups)
+	}
+	rs, err := reedsolomon.New(data, parity-groups)
+	if err != nil {
+		return nil, err
+	}
+	c := &lrc{rs: rs, data: data, groups: groups, global: parity - groups, groupOf: make([]int, data)}
+	for g := 0; g < groups; g++ {
+		for i := c.groupStart(g); i < c.groupStart(g+1); i++ {
+			c.groupOf[i] = g
+		}
+	}
+	return c, nil
+}
+
+func (c *lrc) total() int                { return c.data + c.groups + c.global }
+func (c *lrc) groupStart(g int) int      { return g * c.data / c.groups }
+func (c *lrc) localParity(g int) int     { return c.data + g }
+func (c *lrc) isGlobalParity(i int) bool { return i >= c.data+c.groups }
+
+// group returns the local group of the slice, -1 for global parity slices
+func (c *lrc) group(i int) int {
+	if i < c.data {
+		return c.groupOf[i]
+	}
+	if c.isGlobalParity(i) {
+		return -1
+	}
+	return i - c.data
+}
+
+// members returns all slices of the local group: data slices and local parity
+func (c *lrc) members(g int) []int {
+	start, end := c.groupStart(g), c.groupStart(g+1)
+	members := make([]int, 0, end-start+1)
+	for i := start; i < end; i++ {
+		members = append(members, i)
+	}
+	return append(members, c.localParity(g))
+}
+
+func (c *lrc) Encode(data []io.Reader, parity []io.Writer) error {
+	if len(data) != c.data || len(parity) != c.groups+c.global {
+		return reedsolomon.ErrTooFewShards
+	}
+	readers := make([]io.Reader, c.total())
+	copy(readers, data)
+	bufs, shards := c.allocShards()
+	for {
+		size, err := readBlock(readers, bufs, shards)
+		if err != nil || size == 0 {
+			return err
+		}
+		for i := c.data; i < c.total(); i++ {
+			shards[i] = bufs[i][:size]
+		}
+		for g := 0; g < c.groups; g++ {
+			c.xorGroup(g, c.localParity(g), shards)
+		}
+		if err := c.rs.Encode(c.rsShards(shards)); err != nil {
+			return err
+		}
+		for i, w := range parity {
+			if _, err := w.Write(shards[c.data+i]); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (c *lrc) Reconstruct(valid []io.Reader, fill []io.Writer) error {
+	if len(valid) != c.total() || len(fill) != c.total() {
+		return reedsolomon.ErrTooFewShards
+	}
+	var (
+		readers = make([]io.Reader, c.total())
+		global  bool
+	)
+	for i, w := range fill {
+		if w == nil {
+			continue
+		}
+		if valid[i] != nil {
+			return reedsolomon.ErrReconstructMismatch
+		}
+		// read only the rest of the group if the slice is the only one lost in it
+		if g := c.group(i); g >= 0 && c.lost(valid, g) == 1 {
+			for _, j := range c.members(g) {
+				readers[j] = valid[j]
+			}
+			continue
+		}
+		global = true
+	}
+	if global {
+		copy(readers, valid)
+	}
+
+	bufs, shards := c.allocShards()
+	for {
+		size, err := readBlock(readers, bufs, shards)
+		if err != nil || size == 0 {
+			return err
+		}
+		if err := c.reconstructBlock(bufs, shards, size, global); err != nil {
+			return err
+		}
+		for i, w := range fill {
+			if w == nil {
+				continue
+			}
+			if _, err := w.Write(shards[i]); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// reconstructBlock restores the lost slices of a single block in place:
+// first, groups that lost only one slice are repaired locally; next, if
+// requested, data slices are restored with global parity slices and all
+// missing parity slices are recalculated.
+func (c *lrc) reconstructBlock(bufs, shards [][]byte, size int, global bool) error {
+	for g := 0; g < c.groups; g++ {
+		lost := -1
+		for _, i := range c.members(g) {
+			if shards[i] == nil {
+				if lost >= 0 {
+					lost = -1
+					break
+				}
+				lost = i
+			}
+		}
+		if lost >= 0 {
+			shards[lost] = bufs[lost][:size]
+			c.xorGroup(g, lost, shards)
+		}
+	}
+	if !global {
+		return nil
+	}
+
+	for i := range shards {
+		if shards[i] == nil && (i < c.data || c.isGlobalParity(i)) {
+			shards[i] = bufs[i][:0]
+		}
+	}
+	rsShards := c.rsShards(shards)
+	if err := c.rs.Reconstruct(rsShards); err != nil {
+		return err
+	}
+	copy(shards, rsShards[:c.data])
+	copy(shards[c.data+c.groups:], rsShards[c.data:])
+	for g := 0; g < c.groups; g++ {
+		if i := c.localParity(g); shards[i] == nil {
+			shards[i] = bufs[i][:size]
+			c.xorGroup(g, i, shards)
+		}
+	}
+	return nil
+}
+
+// RepairSet returns the rest of the local group for a slice that is the only
+// one unavailable in its group. If any of the `missing` slices cannot be
+// repaired locally, all available slices are read - global parity slices
+// are used to restore the data.
+func (c *lrc) RepairSet(missing []int, avail []bool) ([]int, error) {
+	if len(avail) != c.total() {
+		return nil, reedsolomon.ErrTooFewShards
+	}
+	read := make([]bool, c.total())
+	for _, i := range missing {
+		g := c.group(i)
+		if g < 0 || c.unavail(avail, g) != 1 {
+			return c.globalSet(avail)
+		}
+		for _, j := range c.members(g) {
+			read[j] = j != i
+		}
+	}
+	ids := make([]int, 0, c.total())
+	for i, ok := range read {
+		if ok {
+			ids = append(ids, i)
+		}
+	}
+	return ids, nil
+}
+
+// globalSet returns all available slices
+func (c *lrc) globalSet(avail []bool) ([]int, error) {
+	ids := make([]int, 0, c.total())
+	for i, ok := range avail {
+		if ok {
+			ids = append(ids, i)
+		}
+	}
+	if len(ids) < c.data {
+		return nil, reedsolomon.ErrTooFewShards
+	}
+	return ids, nil
+}
+
+// unavail returns the number of slices of the local group that cannot be read
+func (c *lrc) unavail(avail []bool, g int) (n int) {
+	for _, i := range c.members(g) {
+		if !avail[i] {
+			n++
+		}
+	}
+	return
+}
+
+// lost returns the number of missing slices in the local group
+func (c *lrc) lost(valid []io.Reader, g int) (n int) {
+	for _, i := range c.members(g) {
+		if valid[i] == nil {
+			n++
+		}
+	}
+	return
+}
+
+// xorGroup sets the slice to XOR of all the other slices of the local group
+func (c *lrc) xorGroup(g, dst int, shards [][]byte) {
+	first := true
+	for _, i := range c.members(g) {
+		if i == dst {
+			continue
+		}
+		if first {
+			copy(shards[dst], shards[i])
+			first = false
+			continue
+		}
+		xorBytes(shards[dst], shards[i])
+	}
+}
+
+// rsShards returns data and global parity slices - the Reed-Solomon stripe
+func (c *lrc) rsShards(shards [][]byte) [][]byte {
+	rsShards := make([][]byte, 0, c.data+c.global)
+	rsShards = append(rsShards, shards[:c.data]...)
+	return append(rsShards, shards[c.data+c.groups:]...)
+}
+
+func (c *lrc) allocShards() (bufs, shards [][]byte) {
+	bufs = make([][]byte, c.total())
+	for i := range bufs {
+		bufs[i] = make([]byte, lrcBlockSize)
+	}
+	return bufs, make([][]byte, c.total())
+}
+
+// readBlock reads the next block of every slice that has a reader. Returns
+// the size of the block, zero when all slices are read.
+func readBlock(readers []io.Reader, bufs, shards [][]byte) (int, error) {
+	size := -1
+	for i, r := range readers {
+		shards[i] = nil
+		if r == nil {
+			continue
+		}
+		n, err := io.ReadFull(r, bufs[i])
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return 0, err
+		}
+		if size >= 0 && n != size {
+			return 0, errSliceSizeMismatch
+		}
+		size = n
+		shards[i] = bufs[i][:n]
+	}
+	if size < 0 {
+		return 0, reedsolomon.ErrTooFewShards
+	}
+	return size, nil
+}
+
+func xorBytes(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
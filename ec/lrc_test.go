@@ -0,0 +1,192 @@
+// Package ec provides erasure coding (EC) based data protection for AIStore.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ec
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+// failReader fails the test if a slice that is not needed for restoration is read
+type failReader struct {
+	t  *testing.T
+	id int
+}
+
+func (r *failReader) Read([]byte) (int, error) {
+	r.t.Errorf("unexpected read of slice %d", r.id)
+	return 0, errors.New("unexpected read")
+}
+
+func lrcEncode(t *testing.T, codec Codec, data, parity, sliceSize int) [][]byte {
+	slices := make([][]byte, data+parity)
+	readers := make([]io.Reader, data)
+	writers := make([]io.Writer, parity)
+	for i := 0; i < data; i++ {
+		slices[i] = make([]byte, sliceSize)
+		rand.Read(slices[i])
+		readers[i] = bytes.NewReader(slices[i])
+	}
+	bufs := make([]*bytes.Buffer, parity)
+	for i := range writers {
+		bufs[i] = &bytes.Buffer{}
+		writers[i] = bufs[i]
+	}
+	tassert.CheckFatal(t, codec.Encode(readers, writers))
+	for i, buf := range bufs {
+		tassert.Errorf(t, buf.Len() == sliceSize, "parity slice %d: expected size %d, got %d", i, sliceSize, buf.Len())
+		slices[data+i] = buf.Bytes()
+	}
+	return slices
+}
+
+func TestLRCReconstruct(t *testing.T) {
+	const (
+		data, parity, groups = 6, 4, 2 // 2 local and 2 global parity slices
+		sliceSize            = lrcBlockSize + 3*cmn.KiB
+	)
+	codec, err := NewCodec(&Metadata{Data: data, Parity: parity, Codec: cmn.ECCodecLRC, LocalGroups: groups})
+	tassert.CheckFatal(t, err)
+	slices := lrcEncode(t, codec, data, parity, sliceSize)
+
+	tests := []struct {
+		name  string
+		lost  []int
+		local bool // only the rest of the local group must be read
+		fail  bool
+	}{
+		{name: "data slice", lost: []int{1}, local: true},
+		{name: "local parity", lost: []int{7}, local: true},
+		{name: "global parity", lost: []int{9}},
+		{name: "two data slices of a group", lost: []int{0, 2}},
+		{name: "group with its local parity", lost: []int{4, 5, 7}},
+		{name: "one slice of every group and global parity", lost: []int{0, 5, 8}},
+		{name: "too many", lost: []int{0, 1, 2, 6, 8}, fail: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				valid   = make([]io.Reader, data+parity)
+				fill    = make([]io.Writer, data+parity)
+				restore = make(map[int]*bytes.Buffer, len(test.lost))
+				lost    = make(map[int]bool, len(test.lost))
+			)
+			for _, id := range test.lost {
+				restore[id] = &bytes.Buffer{}
+				fill[id] = restore[id]
+				lost[id] = true
+			}
+			group := -1
+			if test.local {
+				group = codec.(*lrc).group(test.lost[0])
+			}
+			for i := range valid {
+				switch {
+				case lost[i]:
+				case test.local && codec.(*lrc).group(i) != group:
+					valid[i] = &failReader{t: t, id: i}
+				default:
+					valid[i] = bytes.NewReader(slices[i])
+				}
+			}
+
+			err := codec.Reconstruct(valid, fill)
+			if test.fail {
+				tassert.Errorf(t, err != nil, "expected reconstruction to fail")
+				return
+			}
+			tassert.CheckFatal(t, err)
+			for id, buf := range restore {
+				tassert.Errorf(t, bytes.Equal(buf.Bytes(), slices[id]), "slice %d restored incorrectly", id)
+			}
+		})
+	}
+}
+
+func TestNewCodec(t *testing.T) {
+	_, err := NewCodec(&Metadata{Data: 2, Parity: 2})
+	tassert.CheckError(t, err)
+	_, err = NewCodec(&Metadata{Data: 2, Parity: 2, Codec: cmn.ECCodecLRC, LocalGroups: 2})
+	tassert.Errorf(t, err != nil, "expected error: no global parity slices")
+	_, err = NewCodec(&Metadata{Data: 2, Parity: 2, Codec: "unknown"})
+	tassert.Errorf(t, err != nil, "expected error: unknown codec")
+}
+
+func TestRepairSet(t *testing.T) {
+	const data, parity, groups = 6, 4, 2 // groups: [0 1 2 6], [3 4 5 7]; global parity: 8, 9
+	lrcCodec, err := NewCodec(&Metadata{Data: data, Parity: parity, Codec: cmn.ECCodecLRC, LocalGroups: groups})
+	tassert.CheckFatal(t, err)
+	rsCodec, err := NewCodec(&Metadata{Data: data, Parity: parity})
+	tassert.CheckFatal(t, err)
+
+	tests := []struct {
+		name    string
+		codec   Codec
+		missing []int
+		unavail []int // besides missing
+		ids     []int
+		fail    bool
+	}{
+		{name: "LRC: data slice", codec: lrcCodec, missing: []int{1}, ids: []int{0, 2, 6}},
+		{name: "LRC: local parity", codec: lrcCodec, missing: []int{7}, ids: []int{3, 4, 5}},
+		{name: "LRC: one slice of every group", codec: lrcCodec, missing: []int{0, 4}, ids: []int{1, 2, 3, 5, 6, 7}},
+		{name: "LRC: global parity", codec: lrcCodec, missing: []int{9}, ids: []int{0, 1, 2, 3, 4, 5, 6, 7, 8}},
+		{
+			name: "LRC: group lost two slices", codec: lrcCodec, missing: []int{1}, unavail: []int{6},
+			ids: []int{0, 2, 3, 4, 5, 7, 8, 9},
+		},
+		{name: "LRC: too many", codec: lrcCodec, missing: []int{0, 1, 2, 3, 4}, fail: true},
+		{name: "RS: data slice", codec: rsCodec, missing: []int{1}, ids: []int{0, 2, 3, 4, 5, 6}},
+		{name: "RS: too many", codec: rsCodec, missing: []int{0, 1, 2, 3, 4}, fail: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			avail := make([]bool, data+parity)
+			for i := range avail {
+				avail[i] = true
+			}
+			for _, i := range append(test.missing, test.unavail...) {
+				avail[i] = false
+			}
+			ids, err := test.codec.RepairSet(test.missing, avail)
+			if test.fail {
+				tassert.Errorf(t, err != nil, "expected error")
+				return
+			}
+			tassert.CheckFatal(t, err)
+			tassert.Errorf(t, reflect.DeepEqual(ids, test.ids), "expected %v, got %v", test.ids, ids)
+		})
+	}
+
+	// single-slice repair reads only the rest of the local group
+	lrc := lrcCodec.(*lrc)
+	for i := 0; i < data+groups; i++ {
+		avail := make([]bool, data+parity)
+		for j := range avail {
+			avail[j] = j != i
+		}
+		ids, err := lrc.RepairSet([]int{i}, avail)
+		tassert.CheckFatal(t, err)
+		group := lrc.members(lrc.group(i))
+		tassert.Errorf(t, len(ids) == len(group)-1, "slice %d: expected %d slices to read, got %v", i, len(group)-1, ids)
+	}
+}
+
+func TestSlicesToRestore(t *testing.T) {
+	md := &Metadata{Data: 6, Parity: 4, Codec: cmn.ECCodecLRC, LocalGroups: 2}
+	avail := []bool{true, false, true, true, true, true, true, true, false, true}
+	toRead, err := SlicesToRestore(md, avail)
+	tassert.CheckFatal(t, err)
+	// all the other data slices and the local parity of the group
+	expected := []bool{true, false, true, true, true, true, true, false, false, false}
+	tassert.Errorf(t, reflect.DeepEqual(toRead, expected), "expected %v, got %v", expected, toRead)
+}
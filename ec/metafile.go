@@ -15,15 +15,18 @@ import (
 
 // Metadata - EC information stored in metafiles for every encoded object
 type Metadata struct {
-	Size       int64  `json:"size"`                      // obj size (after EC'ing sum size of slices differs from the original)
-	ObjCksum   string `json:"obj_chk"`                   // checksum of the original object
-	ObjVersion string `json:"obj_version,omitempty"`     // object version
-	CksumType  string `json:"slice_ck_type,omitempty"`   // slice checksum type
-	CksumValue string `json:"slice_chk_value,omitempty"` // slice checksum of the slice if EC is used
-	Data       int    `json:"data"`                      // the number of data slices
-	Parity     int    `json:"parity"`                    // the number of parity slices
-	SliceID    int    `json:"sliceid,omitempty"`         // 0 for full replica, 1 to N for slices
-	IsCopy     bool   `json:"copy"`                      // object is replicated(true) or encoded(false)
+	Size        int64  `json:"size"`                      // obj size (after EC'ing sum size of slices differs from the original)
+	ObjCksum    string `json:"obj_chk"`                   // checksum of the original object
+	ObjVersion  string `json:"obj_version,omitempty"`     // object version
+	CksumType   string `json:"slice_ck_type,omitempty"`   // slice checksum type
+	CksumValue  string `json:"slice_chk_value,omitempty"` // slice checksum of the slice if EC is used
+	Data        int    `json:"data"`                      // the number of data slices
+	Parity      int    `json:"parity"`                    // the number of parity slices
+	SliceID     int    `json:"sliceid,omitempty"`         // 0 for full replica, 1 to N for slices
+	IsCopy      bool   `json:"copy"`                      // object is replicated(true) or encoded(false)
+	SSE         string `json:"sse,omitempty"`             // ID of the key the slice is encrypted with (local only)
+	Codec       string `json:"codec,omitempty"`           // erasure code the slices are encoded with ("" means Reed-Solomon)
+	LocalGroups int    `json:"local_groups,omitempty"`    // LRC: the number of local groups
 }
 
 var (
@@ -111,7 +114,14 @@ func (md *Metadata) Unpack(unpacker *cmn.ByteUnpack) (err error) {
 	if md.CksumType, err = unpacker.ReadString(); err != nil {
 		return
 	}
-	md.CksumValue, err = unpacker.ReadString()
+	if md.CksumValue, err = unpacker.ReadString(); err != nil {
+		return
+	}
+	if md.Codec, err = unpacker.ReadString(); err != nil {
+		return
+	}
+	i, err = unpacker.ReadUint16()
+	md.LocalGroups = int(i)
 	return
 }
 
@@ -125,11 +135,14 @@ func (md *Metadata) Pack(packer *cmn.BytePack) {
 	packer.WriteString(md.ObjVersion)
 	packer.WriteString(md.CksumType)
 	packer.WriteString(md.CksumValue)
+	packer.WriteString(md.Codec)
+	packer.WriteUint16(uint16(md.LocalGroups))
 }
 
-// int16 is sufficient to keep Data,Parity, and SliceID, so:
-//    int64 + 3*int16 + bool + 4 strings
+// int16 is sufficient to keep Data, Parity, SliceID, and LocalGroups, so:
+//
+//	int64 + 4*int16 + bool + 5 strings
 func (md *Metadata) PackedSize() int {
-	return cmn.SizeofI64 + cmn.SizeofI16*3 + 1 + cmn.SizeofLen*4 +
-		len(md.ObjCksum) + len(md.ObjVersion) + len(md.CksumType) + len(md.CksumValue)
+	return cmn.SizeofI64 + cmn.SizeofI16*4 + 1 + cmn.SizeofLen*5 +
+		len(md.ObjCksum) + len(md.ObjVersion) + len(md.CksumType) + len(md.CksumValue) + len(md.Codec)
 }
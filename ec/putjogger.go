@@ -21,14 +21,13 @@ import (
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/transport"
-	"github.com/klauspost/reedsolomon"
 )
 
 // to avoid starving ecencode xaction, allow to run ecencode after every put batch
 const putBatchSize = 8
 
 type encodeCtx struct {
-	fh            *cmn.FileHandle
+	fh            cmn.ReadAtOpenCloser
 	slices        []*slice
 	sliceSize     int64
 	fileSize      int64
@@ -167,6 +166,9 @@ func (c *putJogger) encode(req *Request) error {
 		ObjCksum:  cksumValue,
 		CksumType: cksumType,
 	}
+	if !req.IsCopy {
+		meta.Codec, meta.LocalGroups = ecConf.Codec, ecConf.LocalGroups
+	}
 
 	// calculate the number of targets required to encode the object
 	// For replicated: ParitySlices + original object
@@ -256,7 +258,7 @@ func (c *putJogger) createCopies(req *Request, metadata *Metadata) error {
 
 	// Because object encoding is called after the main replica is saved to
 	// disk it needs to read it from the local storage
-	fh, err := cmn.NewFileHandle(req.LOM.FQN)
+	fh, err := req.LOM.Open()
 	if err != nil {
 		return err
 	}
@@ -301,12 +303,13 @@ func checksumDataSlices(slices []*slice, wg *sync.WaitGroup, errCh chan error, c
 
 // generateSlicesToMemory gets FQN to the original file and encodes it into EC slices
 // * fqn - the path to original object
+// * codec - erasure code to calculate parity slices
 // * dataSlices - the number of data slices
 // * paritySlices - the number of parity slices
 // Returns:
 // * SGL that hold all the objects data
 // * constructed from the main object slices
-func generateSlicesToMemory(lom *cluster.LOM, dataSlices, paritySlices int) (cmn.ReadOpenCloser, []*slice, error) {
+func generateSlicesToMemory(lom *cluster.LOM, codec Codec, dataSlices, paritySlices int) (cmn.ReadOpenCloser, []*slice, error) {
 	ctx, err := initializeSlices(lom, dataSlices, paritySlices)
 	if err != nil {
 		return ctx.fh, ctx.slices, err
@@ -327,28 +330,24 @@ func generateSlicesToMemory(lom *cluster.LOM, dataSlices, paritySlices int) (cmn
 		}
 	}
 
-	err = finalizeSlices(ctx, lom, sliceWriters, dataSlices, paritySlices)
+	err = finalizeSlices(ctx, lom, codec, sliceWriters, dataSlices)
 	return ctx.fh, ctx.slices, err
 }
 
 func initializeSlices(lom *cluster.LOM, dataSlices, paritySlices int) (*encodeCtx, error) {
 	var (
-		fqn      = lom.FQN
 		totalCnt = paritySlices + dataSlices
 		conf     = lom.CksumConf()
+		err      error
 	)
 	ctx := &encodeCtx{slices: make([]*slice, totalCnt)}
 
-	stat, err := os.Stat(fqn)
-	if err != nil {
-		return ctx, err
-	}
-	ctx.fileSize = stat.Size()
-
-	ctx.fh, err = cmn.NewFileHandle(fqn)
+	// (encrypted objects are transparently decrypted)
+	ctx.fh, err = lom.Open()
 	if err != nil {
 		return ctx, err
 	}
+	ctx.fileSize = lom.Size()
 
 	ctx.sliceSize = SliceSize(ctx.fileSize, dataSlices)
 	padSize := ctx.sliceSize*int64(dataSlices) - ctx.fileSize
@@ -388,14 +387,9 @@ func initializeSlices(lom *cluster.LOM, dataSlices, paritySlices int) (*encodeCt
 	return ctx, nil
 }
 
-func finalizeSlices(ctx *encodeCtx, lom *cluster.LOM, writers []io.Writer, dataSlices, paritySlices int) error {
-	stream, err := reedsolomon.NewStreamC(dataSlices, paritySlices, true, true)
-	if err != nil {
-		return err
-	}
-
+func finalizeSlices(ctx *encodeCtx, lom *cluster.LOM, codec Codec, writers []io.Writer, dataSlices int) error {
 	// Calculate parity slices and their checksums
-	if err := stream.Encode(ctx.readers, writers); err != nil {
+	if err := codec.Encode(ctx.readers, writers); err != nil {
 		return err
 	}
 
@@ -412,12 +406,13 @@ func finalizeSlices(ctx *encodeCtx, lom *cluster.LOM, writers []io.Writer, dataS
 
 // generateSlicesToDisk gets FQN to the original file and encodes it into EC slices
 // * fqn - the path to original object
+// * codec - erasure code to calculate parity slices
 // * dataSlices - the number of data slices
 // * paritySlices - the number of parity slices
 // Returns:
 // * Main object file handle
 // * constructed from the main object slices
-func generateSlicesToDisk(lom *cluster.LOM, dataSlices, paritySlices int) (cmn.ReadOpenCloser, []*slice, error) {
+func generateSlicesToDisk(lom *cluster.LOM, codec Codec, dataSlices, paritySlices int) (cmn.ReadOpenCloser, []*slice, error) {
 	var (
 		fqn  = lom.FQN
 		conf = lom.CksumConf()
@@ -462,7 +457,7 @@ func generateSlicesToDisk(lom *cluster.LOM, dataSlices, paritySlices int) (cmn.R
 		}
 	}
 
-	err = finalizeSlices(ctx, lom, sliceWriters, dataSlices, paritySlices)
+	err = finalizeSlices(ctx, lom, codec, sliceWriters, dataSlices)
 	return ctx.fh, ctx.slices, err
 }
 
@@ -482,15 +477,20 @@ func (c *putJogger) sendSlices(req *Request, meta *Metadata) ([]*slice, error) {
 		return nil, err
 	}
 
+	codec, err := NewCodec(meta)
+	if err != nil {
+		return nil, err
+	}
+
 	// load the data slices from original object and construct parity ones
 	var (
 		objReader cmn.ReadOpenCloser
 		slices    []*slice
 	)
 	if c.toDisk {
-		objReader, slices, err = generateSlicesToDisk(req.LOM, ecConf.DataSlices, ecConf.ParitySlices)
+		objReader, slices, err = generateSlicesToDisk(req.LOM, codec, ecConf.DataSlices, ecConf.ParitySlices)
 	} else {
-		objReader, slices, err = generateSlicesToMemory(req.LOM, ecConf.DataSlices, ecConf.ParitySlices)
+		objReader, slices, err = generateSlicesToMemory(req.LOM, codec, ecConf.DataSlices, ecConf.ParitySlices)
 	}
 
 	if err != nil {
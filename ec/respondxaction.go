@@ -193,14 +193,13 @@ func (r *XactRespond) DispatchResp(iReq intraReq, hdr transport.ObjHdr, object i
 			glog.Infof("Got slice=%t from %s (#%d of %s/%s) v%s, chsum: %s",
 				iReq.isSlice, iReq.sender, iReq.meta.SliceID, hdr.Bck, hdr.ObjName, meta.ObjVersion, meta.CksumValue)
 		}
-		md := meta.Marshal()
 		if iReq.isSlice {
-			err = WriteSliceAndMeta(r.t, hdr, object, md)
+			err = WriteSliceAndMeta(r.t, hdr, object, meta)
 		} else {
 			var lom *cluster.LOM
 			lom, err = LomFromHeader(r.t, hdr)
 			if err == nil {
-				err = WriteReplicaAndMeta(r.t, lom, object, md, hdr.ObjAttrs.CksumType, hdr.ObjAttrs.CksumValue)
+				err = WriteReplicaAndMeta(r.t, lom, object, meta.Marshal(), hdr.ObjAttrs.CksumType, hdr.ObjAttrs.CksumValue)
 			}
 		}
 		if err != nil {
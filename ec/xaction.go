@@ -16,6 +16,7 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/sse"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/transport/bundle"
 	"github.com/NVIDIA/aistore/xaction"
@@ -150,6 +151,12 @@ func (r *xactECBase) newSliceResponse(md *Metadata, attrs *transport.ObjectAttrs
 		return nil, err
 	}
 	attrs.Size = stat.Size()
+	if md.SSE != "" {
+		if attrs.Size, err = sse.PlainSize(attrs.Size); err != nil {
+			return nil, err
+		}
+		return OpenSlice(fqn, md.SSE, attrs.Size)
+	}
 	reader, err = cmn.NewFileHandle(fqn)
 	if err != nil {
 		glog.Warningf("Failed to read file stats: %s", err)
@@ -158,6 +165,20 @@ func (r *xactECBase) newSliceResponse(md *Metadata, attrs *transport.ObjectAttrs
 	return reader, nil
 }
 
+// OpenSlice opens encrypted slice for reading (see WriteSliceAndMeta);
+// `size` is the (plaintext) size of the slice
+func OpenSlice(fqn, keyID string, size int64) (cmn.ReadOpenCloser, error) {
+	key, err := sse.Key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	fh, err := sse.NewFileHandle(fqn, key, size)
+	if err != nil {
+		return nil, err
+	}
+	return fh, nil
+}
+
 // replica/full object request
 func (r *xactECBase) newReplicaResponse(attrs *transport.ObjectAttrs, bck *cluster.Bck, objName string) (reader cmn.ReadOpenCloser, err error) {
 	lom := &cluster.LOM{T: r.t, ObjName: objName}
@@ -170,7 +191,7 @@ func (r *xactECBase) newReplicaResponse(attrs *transport.ObjectAttrs, bck *clust
 		glog.Warning(err)
 		return nil, err
 	}
-	reader, err = cmn.NewFileHandle(lom.FQN)
+	reader, err = lom.Open()
 	if err != nil {
 		return nil, err
 	}
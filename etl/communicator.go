@@ -144,7 +144,7 @@ func (pc *pushComm) doRequest(bck *cluster.Bck, objName string) (*http.Response,
 	}
 
 	// `fh` is closed by Do(req).
-	fh, err := cmn.NewFileHandle(lom.GetFQN())
+	fh, err := lom.Open()
 	if err != nil {
 		return nil, err
 	}
@@ -33,6 +33,10 @@ const (
 	contentTypeLen = 2
 	ObjectType     = "ob"
 	WorkfileType   = "wk"
+	VersionType    = "vr"
+
+	// suffix of the per-object directory of prior versions (see VersionContentResolver)
+	VersionDirSuffix = "%v"
 )
 
 type (
@@ -160,6 +164,7 @@ func (f *ContentSpecMgr) PermToProcess(fqn string) (ok bool) {
 type (
 	ObjectContentResolver   struct{}
 	WorkfileContentResolver struct{}
+	VersionContentResolver  struct{}
 )
 
 func (wf *ObjectContentResolver) PermToMove() bool    { return true }
@@ -211,3 +216,35 @@ func (wf *WorkfileContentResolver) ParseUniqueFQN(base string) (orig string, old
 
 	return base[:tieIndex], filePID != pid, true
 }
+
+// Prior versions of an object are kept in the per-object directory next to
+// the object (same mountpath): <bucket>/%vr/<objname>%v/<version>. They are
+// not evicted - they are removed only when the versioning history of the
+// bucket says so - and they are moved (by rebalance and resilver) along
+// with the object.
+func (vr *VersionContentResolver) PermToMove() bool    { return true }
+func (vr *VersionContentResolver) PermToEvict() bool   { return false }
+func (vr *VersionContentResolver) PermToProcess() bool { return false }
+
+// prefix is the version of the object
+func (vr *VersionContentResolver) GenUniqueFQN(base, prefix string) string {
+	return base + VersionDirSuffix + string(filepath.Separator) + prefix
+}
+
+func (vr *VersionContentResolver) ParseUniqueFQN(base string) (orig string, old, ok bool) {
+	if _, err := strconv.ParseUint(base, 10, 64); err != nil {
+		return "", false, false
+	}
+	return base, false, true
+}
+
+// ParseVersionName returns the object name and the version of the prior
+// version given its name (see ParsedFQN.ObjName) in the VersionType content.
+func ParseVersionName(name string) (objName, version string, ok bool) {
+	dir, version := filepath.Split(name)
+	if version == "" || !strings.HasSuffix(dir, VersionDirSuffix+string(filepath.Separator)) {
+		return
+	}
+	objName = dir[:len(dir)-len(VersionDirSuffix)-1]
+	return objName, version, objName != ""
+}
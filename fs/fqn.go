@@ -25,7 +25,12 @@ const (
 	WorkfileColdget = "cold"   // object GET: coldget
 	WorkfilePut     = "put"    // object PUT
 	WorkfileAppend  = "append" // object APPEND
+	WorkfileMptPart = "mpt"    // S3 multipart upload part
 	WorkfileFSHC    = "fshc"   // FSHC test file
+	WorkfileScrub   = "scrub"  // corrupted object moved aside while being repaired
+	WorkfileDlPart  = "dlpart" // partially downloaded object (resumable download)
+	WorkfileVersion = "ver"    // prior version of an object being migrated (resilver, rebalance)
+	WorkfileDlRows  = "dlrows" // manifest rows of a target (manifest download)
 )
 
 type ParsedFQN struct {
@@ -365,6 +365,29 @@ func TestMakeAndParseFQN(t *testing.T) {
 	}
 }
 
+func TestParseVersionName(t *testing.T) {
+	tests := []struct {
+		name    string
+		objName string
+		version string
+		ok      bool
+	}{
+		{"obj%v/3", "obj", "3", true},
+		{"dir/obj.ext%v/12", "dir/obj.ext", "12", true},
+		{"dir/obj%v/1%v/2", "dir/obj%v/1", "2", true},
+		{"dir/obj/3", "", "", false},
+		{"%v/3", "", "", false},
+		{"obj%v/", "", "", false},
+	}
+	for _, tt := range tests {
+		objName, version, ok := fs.ParseVersionName(tt.name)
+		if ok != tt.ok || objName != tt.objName || version != tt.version {
+			t.Errorf("%q: got (%q, %q, %t), want (%q, %q, %t)",
+				tt.name, objName, version, ok, tt.objName, tt.version, tt.ok)
+		}
+	}
+}
+
 var parsedFQN fs.ParsedFQN
 
 func BenchmarkParseFQN(b *testing.B) {
@@ -193,7 +193,14 @@ func (j *jogger) jog(fqn string, de fs.DirEntry) error {
 	}
 
 	if j.opts.SkipGloballyMisplaced {
-		uname := ct.Bck().MakeUname(ct.ObjName())
+		objName := ct.ObjName()
+		if ct.ContentType() == fs.VersionType {
+			// prior versions belong to where the object does
+			if name, _, ok := fs.ParseVersionName(objName); ok {
+				objName = name
+			}
+		}
+		uname := ct.Bck().MakeUname(objName)
 		tsi, err := cluster.HrwTarget(uname, j.opts.T.Sowner().Get()) // TODO: should we get smap once?
 		if err != nil {
 			return err
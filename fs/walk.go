@@ -137,6 +137,12 @@ func (h *objInfos) Push(x interface{}) {
 		return
 	}
 	info.objName = parsedFQN.ObjName
+	if parsedFQN.ContentType == VersionType {
+		// prior versions are ordered along with the object
+		if objName, _, ok := ParseVersionName(parsedFQN.ObjName); ok {
+			info.objName = objName
+		}
+	}
 	*h = append(*h, info)
 }
 
@@ -273,35 +279,40 @@ func mpathChildren(opts *Options) (children []string, err error) {
 	return
 }
 
+// NOTE: each content type is walked separately (and in parallel) to merge
+// the results sorted by object names.
 func WalkBck(opts *WalkBckOptions) error {
 	var (
 		mpaths, _ = Get()
-		mpathChs  = make([]chan *walkEntry, len(mpaths))
+		mpathChs  = make([]chan *walkEntry, len(mpaths)*len(opts.CTs))
 
 		group, ctx = errgroup.WithContext(context.Background())
 	)
 
-	for i := 0; i < len(mpaths); i++ {
+	for i := 0; i < len(mpathChs); i++ {
 		mpathChs[i] = make(chan *walkEntry, mpathQueueSize)
 	}
 
 	cmn.Assert(opts.Mpath == nil)
 	idx := 0
 	for _, mpath := range mpaths {
-		group.Go(func(idx int, mpath *MountpathInfo) func() error {
-			return func() error {
-				var (
-					o      = opts.Options
-					workCh = mpathChs[idx]
-				)
-				defer close(workCh)
-				o.Mpath = mpath
-				wcb := &walkCb{mpath: mpath, validate: opts.ValidateCallback, ctx: ctx, workCh: workCh}
-				o.Callback = wcb.walkBckMpath
-				return Walk(&o)
-			}
-		}(idx, mpath))
-		idx++
+		for _, ct := range opts.CTs {
+			group.Go(func(idx int, mpath *MountpathInfo, ct string) func() error {
+				return func() error {
+					var (
+						o      = opts.Options
+						workCh = mpathChs[idx]
+					)
+					defer close(workCh)
+					o.Mpath = mpath
+					o.CTs = []string{ct}
+					wcb := &walkCb{mpath: mpath, validate: opts.ValidateCallback, ctx: ctx, workCh: workCh}
+					o.Callback = wcb.walkBckMpath
+					return Walk(&o)
+				}
+			}(idx, mpath, ct))
+			idx++
+		}
 	}
 
 	// TODO: handle case when `opts.Sorted == false`
@@ -0,0 +1,246 @@
+// Package lifecycle enforces per-bucket lifecycle rules: expires objects and
+// removes stale in-progress appends (and multipart uploads).
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/xaction"
+	"github.com/NVIDIA/aistore/xaction/xreg"
+)
+
+// Lifecycle rules are configured on a per-bucket basis (`lifecycle` section of
+// the bucket props, see cmn.LifecycleConf) and are currently supported only
+// for ais buckets. Each rule applies to the objects whose names start with the
+// rule's prefix and may specify:
+//   - expire_days       - objects that were not modified for that many days get deleted
+//   - abort_append_days - in-progress appends (and S3 multipart uploads) that were
+//                         not updated for that many days get discarded
+//
+// The rules are enforced by the lifecycle xaction that runs periodically on
+// each target (see target's housekeeping) and can also be started via the
+// generic xaction API. The xaction runs one jogger per mountpath.
+
+type (
+	InitLifecycle struct {
+		T       cluster.Target
+		Xaction *Xaction
+		// AbortUploads discards stale multipart uploads into the bucket (optional)
+		AbortUploads func(bck *cluster.Bck, rule *cmn.LifecycleRule, now time.Time)
+	}
+
+	// lcJ is a single /jogger/ that traverses a given mountpath and enforces
+	// the lifecycle rules of the buckets
+	lcJ struct {
+		ini       *InitLifecycle
+		mpathInfo *fs.MountpathInfo
+		bck       *cluster.Bck
+		config    *cmn.Config
+		now       time.Time
+	}
+
+	XactProvider struct {
+		xreg.BaseGlobalEntry
+		xact *Xaction
+
+		id string
+	}
+
+	Xaction struct {
+		xaction.XactBase
+	}
+)
+
+func init() {
+	xreg.RegisterGlobalXact(&XactProvider{})
+}
+
+func (*XactProvider) New(args xreg.XactArgs) xreg.GlobalEntry {
+	return &XactProvider{id: args.UUID}
+}
+
+func (p *XactProvider) Start(_ cmn.Bck) error {
+	p.xact = &Xaction{XactBase: *xaction.NewXactBase(xaction.XactBaseID(p.id), cmn.ActLifecycle)}
+	return nil
+}
+func (*XactProvider) Kind() string        { return cmn.ActLifecycle }
+func (p *XactProvider) Get() cluster.Xact { return p.xact }
+
+// keep the one that's already running
+func (*XactProvider) PreRenewHook(_ xreg.GlobalEntry) bool { return true }
+
+func (r *Xaction) Run() error { cmn.Assert(false); return nil }
+
+// Run enforces lifecycle rules of all ais buckets; blocks until done.
+func Run(ini *InitLifecycle) {
+	var (
+		wg                sync.WaitGroup
+		bcks              = make([]*cluster.Bck, 0, 8)
+		provider          = cmn.ProviderAIS
+		config            = cmn.GCO.Get()
+		now               = time.Now()
+		availablePaths, _ = fs.Get()
+	)
+	ini.T.Bowner().Get().Range(&provider, nil, func(bck *cluster.Bck) bool {
+		if bck.Props.Lifecycle.Enabled() {
+			bcks = append(bcks, bck)
+		}
+		return false
+	})
+	glog.Infof("%s: %s started: %d bucket(s)", ini.T.Snode(), ini.Xaction, len(bcks))
+	if len(bcks) == 0 {
+		return
+	}
+	if ini.AbortUploads != nil {
+		for _, bck := range bcks {
+			for i := range bck.Props.Lifecycle.Rules {
+				rule := &bck.Props.Lifecycle.Rules[i]
+				if rule.Enabled && rule.AbortAppendDays > 0 {
+					ini.AbortUploads(bck, rule, now)
+				}
+			}
+		}
+	}
+	for _, mpathInfo := range availablePaths {
+		j := &lcJ{ini: ini, mpathInfo: mpathInfo, config: config, now: now}
+		wg.Add(1)
+		go func(j *lcJ) {
+			defer wg.Done()
+			for _, bck := range bcks {
+				if err := j.jogBck(bck); err != nil {
+					if ini.Xaction.Aborted() {
+						return
+					}
+					if !os.IsNotExist(err) {
+						glog.Errorf("%s: failed to traverse %s, err: %v", j, bck, err)
+					}
+				}
+			}
+		}(j)
+	}
+	wg.Wait()
+}
+
+/////////
+// lcJ //
+/////////
+
+func (j *lcJ) String() string {
+	return fmt.Sprintf("%s: (%s, %s)", j.ini.T.Snode(), j.ini.Xaction, j.mpathInfo)
+}
+
+func (j *lcJ) jogBck(bck *cluster.Bck) error {
+	j.bck = bck
+	opts := &fs.Options{
+		Mpath:    j.mpathInfo,
+		Bck:      bck.Bck,
+		CTs:      []string{fs.WorkfileType, fs.ObjectType},
+		Callback: j.walk,
+		Sorted:   false,
+	}
+	return fs.Walk(opts)
+}
+
+func (j *lcJ) walk(fqn string, de fs.DirEntry) error {
+	if de.IsDir() {
+		return nil
+	}
+	if j.ini.Xaction.Aborted() {
+		return cmn.NewAbortedError(j.String())
+	}
+	lom := &cluster.LOM{T: j.ini.T, FQN: fqn}
+	if err := lom.Init(j.bck.Bck, j.config); err != nil {
+		return nil
+	}
+	if lom.ParsedFQN.ContentType == fs.WorkfileType {
+		j.walkWorkfile(lom)
+		return nil
+	}
+	finfo, err := os.Stat(fqn)
+	if err != nil {
+		return nil
+	}
+	rule := j.rule(func(rule *cmn.LifecycleRule) bool {
+		return rule.Expired(lom.ObjName, finfo.ModTime(), j.now)
+	})
+	if rule == nil {
+		return nil
+	}
+	if err := lom.Load(false); err != nil {
+		return nil
+	}
+	if lom.HasCopies() && lom.IsCopy() {
+		return nil
+	}
+	if !lom.IsHRW() {
+		return nil
+	}
+	size := lom.Size()
+	if err, _ := j.ini.T.DeleteObject(context.Background(), lom, false /*evict*/); err != nil {
+		if !cmn.IsObjNotExist(err) {
+			glog.Errorf("%s: failed to expire %s, err: %v", j, lom, err)
+		}
+		return nil
+	}
+	if glog.FastV(4, glog.SmoduleFS) {
+		glog.Infof("%s: expired %s (rule %q)", j, lom, rule.ID)
+	}
+	j.ini.Xaction.ObjectsInc()
+	j.ini.Xaction.BytesAdd(size)
+	return nil
+}
+
+// in-progress appends are identified by their workfile prefix; the workfile's
+// modification time is the time of the last append
+func (j *lcJ) walkWorkfile(lom *cluster.LOM) {
+	dir, base := filepath.Split(lom.ObjName)
+	if !strings.HasPrefix(base, fs.WorkfileAppend+".") {
+		return
+	}
+	contentResolver := fs.CSM.RegisteredContentTypes[fs.WorkfileType]
+	orig, _, ok := contentResolver.ParseUniqueFQN(base)
+	if !ok {
+		return
+	}
+	finfo, err := os.Stat(lom.FQN)
+	if err != nil {
+		return
+	}
+	objName := dir + orig
+	rule := j.rule(func(rule *cmn.LifecycleRule) bool {
+		return rule.AppendAborted(objName, finfo.ModTime(), j.now)
+	})
+	if rule == nil {
+		return
+	}
+	if err := cmn.RemoveFile(lom.FQN); err != nil {
+		glog.Errorf("%s: failed to remove stale append %q, err: %v", j, lom.FQN, err)
+		return
+	}
+	j.ini.Xaction.ObjectsInc()
+	j.ini.Xaction.BytesAdd(finfo.Size())
+}
+
+// returns the first rule of the bucket that matches
+func (j *lcJ) rule(match func(rule *cmn.LifecycleRule) bool) *cmn.LifecycleRule {
+	rules := j.bck.Props.Lifecycle.Rules
+	for i := range rules {
+		if match(&rules[i]) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
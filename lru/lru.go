@@ -60,11 +60,15 @@ type (
 		Buckets             []cmn.Bck // list of buckets to run LRU
 		GetFSUsedPercentage func(path string) (usedPercentage int64, ok bool)
 		GetFSStats          func(path string) (blocks, bavail uint64, bsize int64, err error)
+		KeepWork            func(fqn string) bool // old workfiles that must not be removed (optional)
 	}
 
-	// minHeap keeps fileInfo sorted by access time with oldest
-	// on top of the heap.
-	minHeap []*cluster.LOM
+	// minHeap keeps LOMs sorted in accordance with the bucket's LRU policy
+	// (see cmn.LRUPolicy*) with the first to evict on top of the heap.
+	minHeap struct {
+		loms []*cluster.LOM
+		less func(a, b *cluster.LOM) bool
+	}
 
 	// parent - contains mpath joggers
 	lruP struct {
@@ -78,12 +82,13 @@ type (
 	lruJ struct {
 		// runtime
 		curSize   int64
-		totalSize int64 // difference between lowWM size and used size
-		newest    int64
+		totalSize int64        // difference between lowWM size and used size
+		last      *cluster.LOM // the last to evict among the ones in the heap
 		heap      *minHeap
 		oldWork   []string
 		misplaced []*cluster.LOM
 		bck       cmn.Bck
+		bckLRU    *cmn.LRUConf // LRU props of the bucket
 		now       int64
 		// init-time
 		p         *lruP
@@ -108,6 +113,20 @@ type (
 		xaction.XactDemandBase
 		Renewed           chan struct{}
 		OkRemoveMisplaced func() bool
+
+		mu      sync.Mutex
+		evicted map[string]*PolicyStats // per LRU policy
+	}
+
+	// XactStats extends the base xaction stats with the number and size
+	// of the objects evicted by each LRU policy
+	XactStats struct {
+		xaction.BaseXactStats
+		Ext map[string]PolicyStats `json:"ext"`
+	}
+	PolicyStats struct {
+		Objects int64 `json:"obj_count,string"`
+		Bytes   int64 `json:"bytes_count,string"`
 	}
 )
 
@@ -153,9 +172,8 @@ func Run(ini *InitLRU) {
 		return
 	}
 	for mpath, mpathInfo := range availablePaths {
-		h := make(minHeap, 0, 64)
 		joggers[mpath] = &lruJ{
-			heap:      &h,
+			heap:      &minHeap{loms: make([]*cluster.LOM, 0, 64)},
 			oldWork:   make([]string, 0, 64),
 			misplaced: make([]*cluster.LOM, 0, 64),
 			stopCh:    make(chan struct{}, 1),
@@ -234,6 +252,38 @@ repeat:
 func (r *Xaction) Run() error { cmn.Assert(false); return nil }
 func (r *Xaction) Renew()     { r.Renewed <- struct{}{} }
 
+func (r *Xaction) addEvicted(policy string, objects, bytes int64) {
+	if policy == "" {
+		policy = cmn.LRUPolicyAtime
+	}
+	r.mu.Lock()
+	if r.evicted == nil {
+		r.evicted = make(map[string]*PolicyStats, 4)
+	}
+	ps, ok := r.evicted[policy]
+	if !ok {
+		ps = &PolicyStats{}
+		r.evicted[policy] = ps
+	}
+	ps.Objects += objects
+	ps.Bytes += bytes
+	r.mu.Unlock()
+}
+
+// override/extend XactBase.Stats()
+func (r *Xaction) Stats() cluster.XactStats {
+	var (
+		baseStats = r.XactDemandBase.Stats().(*xaction.BaseXactStats)
+		lruStats  = &XactStats{BaseXactStats: *baseStats, Ext: make(map[string]PolicyStats, 4)}
+	)
+	r.mu.Lock()
+	for policy, ps := range r.evicted {
+		lruStats.Ext[policy] = *ps
+	}
+	r.mu.Unlock()
+	return lruStats
+}
+
 //////////
 // lruJ //
 //////////
@@ -326,8 +376,10 @@ func (j *lruJ) removeTrash() (err error) {
 
 func (j *lruJ) jogBck() (size int64, err error) {
 	// 1. init per-bucket min-heap (and reuse the slice)
-	h := (*j.heap)[:0]
-	j.heap = &h
+	j.now = time.Now().UnixNano()
+	j.heap.loms = j.heap.loms[:0]
+	j.heap.less = j.lessFunc()
+	j.last = nil
 	heap.Init(j.heap)
 
 	// 2. collect
@@ -338,7 +390,6 @@ func (j *lruJ) jogBck() (size int64, err error) {
 		Callback: j.walk,
 		Sorted:   false,
 	}
-	j.now = time.Now().UnixNano()
 	if err = fs.Walk(opts); err != nil {
 		return
 	}
@@ -365,7 +416,7 @@ func (j *lruJ) walk(fqn string, de fs.DirEntry) error {
 		_, base := filepath.Split(fqn)
 		contentResolver := fs.CSM.RegisteredContentTypes[fs.WorkfileType]
 		_, old, ok := contentResolver.ParseUniqueFQN(base)
-		if ok && old {
+		if ok && old && (j.ini.KeepWork == nil || !j.ini.KeepWork(fqn)) {
 			j.oldWork = append(j.oldWork, fqn)
 		}
 		return nil
@@ -373,6 +424,9 @@ func (j *lruJ) walk(fqn string, de fs.DirEntry) error {
 	if !j.allowDelObj {
 		return nil // ===>
 	}
+	if j.bckLRU.Pinned(lom.ObjName) {
+		return nil
+	}
 	err = lom.Load(false)
 	if err != nil {
 		return nil
@@ -389,14 +443,14 @@ func (j *lruJ) walk(fqn string, de fs.DirEntry) error {
 	}
 
 	// do nothing if the heap's curSize >= totalSize and
-	// the file is more recent then the the heap's newest.
-	if j.curSize >= j.totalSize && lom.AtimeUnix() > j.newest {
+	// the object is to be evicted after the heap's last.
+	if j.curSize >= j.totalSize && j.last != nil && h.less(j.last, lom) {
 		return nil
 	}
 	heap.Push(h, lom)
 	j.curSize += lom.Size()
-	if lom.AtimeUnix() > j.newest {
-		j.newest = lom.AtimeUnix()
+	if j.last == nil || h.less(j.last, lom) {
+		j.last = lom
 	}
 	return nil
 }
@@ -440,7 +494,7 @@ func (j *lruJ) evict() (size int64, err error) {
 				removed = os.Remove(fqn) == nil
 			}
 			if removed {
-				if capCheck, err = j.postRemove(capCheck, lom); err != nil {
+				if capCheck, err = j.postRemove(capCheck, lom.Size()); err != nil {
 					return
 				}
 			}
@@ -450,11 +504,11 @@ func (j *lruJ) evict() (size int64, err error) {
 	// 3.
 	for h.Len() > 0 && j.totalSize > 0 {
 		lom := heap.Pop(h).(*cluster.LOM)
-		if j.evictObj(lom) {
-			bevicted += lom.Size()
-			size += lom.Size()
+		if freed, ok := j.evictObj(lom); ok {
+			bevicted += freed
+			size += freed
 			fevicted++
-			if capCheck, err = j.postRemove(capCheck, lom); err != nil {
+			if capCheck, err = j.postRemove(capCheck, freed); err != nil {
 				return
 			}
 		}
@@ -463,12 +517,15 @@ func (j *lruJ) evict() (size int64, err error) {
 	j.ini.StatsT.Add(stats.LruEvictCount, fevicted)
 	xlru.ObjectsAdd(fevicted)
 	xlru.BytesAdd(bevicted)
+	if fevicted > 0 {
+		xlru.addEvicted(j.bckLRU.Policy, fevicted, bevicted)
+	}
 	return
 }
 
-func (j *lruJ) postRemove(prev int64, lom *cluster.LOM) (capCheck int64, err error) {
-	j.totalSize -= lom.Size()
-	capCheck = prev + lom.Size()
+func (j *lruJ) postRemove(prev, size int64) (capCheck int64, err error) {
+	j.totalSize -= size
+	capCheck = prev + size
 	if err = j.yieldTerm(); err != nil {
 		return
 	}
@@ -507,12 +564,23 @@ func (j *lruJ) _throttle(usedPct int64) (err error) {
 }
 
 // remove local copies that "belong" to different LRU joggers; hence, space accounting may be temporarily not precise
-func (j *lruJ) evictObj(lom *cluster.LOM) (ok bool) {
+// (with LRUPolicyCopiesFirst, mirrored objects are reduced to a single copy instead of being removed)
+func (j *lruJ) evictObj(lom *cluster.LOM) (freed int64, ok bool) {
 	lom.Lock(true)
-	if err := lom.Remove(); err == nil {
-		ok = true
+	if j.bckLRU.Policy == cmn.LRUPolicyCopiesFirst && lom.HasCopies() {
+		freed = lom.Size() * int64(lom.NumCopies()-1)
+		if err := lom.DelAllCopies(); err == nil {
+			ok = true
+		} else {
+			glog.Errorf("%s: failed to remove copies, err: %v", lom, err)
+		}
 	} else {
-		glog.Errorf("%s: failed to remove, err: %v", lom, err)
+		freed = lom.Size()
+		if err := lom.Remove(); err == nil {
+			ok = true
+		} else {
+			glog.Errorf("%s: failed to remove, err: %v", lom, err)
+		}
 	}
 	lom.Unlock(true)
 	return
@@ -581,21 +649,53 @@ func (j *lruJ) allow() (ok bool, err error) {
 		return
 	}
 	ok = b.Props.LRU.Enabled && b.Allow(cmn.AccessObjDELETE) == nil
+	j.bckLRU = &b.Props.LRU
 	return
 }
 
+// returns the eviction order of the bucket's LRU policy
+func (j *lruJ) lessFunc() func(a, b *cluster.LOM) bool {
+	byAtime := func(a, b *cluster.LOM) bool { return a.AtimeUnix() < b.AtimeUnix() }
+	switch j.bckLRU.Policy {
+	case cmn.LRUPolicyLFU:
+		return func(a, b *cluster.LOM) bool {
+			if a.AccessCount() != b.AccessCount() {
+				return a.AccessCount() < b.AccessCount()
+			}
+			return byAtime(a, b)
+		}
+	case cmn.LRUPolicySize:
+		now := j.now
+		return func(a, b *cluster.LOM) bool {
+			wa := float64(a.Size()) * float64(now-a.AtimeUnix())
+			wb := float64(b.Size()) * float64(now-b.AtimeUnix())
+			return wa > wb
+		}
+	case cmn.LRUPolicyCopiesFirst:
+		redundant := func(lom *cluster.LOM) bool { return lom.HasCopies() || lom.Bprops().EC.Enabled }
+		return func(a, b *cluster.LOM) bool {
+			if ra, rb := redundant(a), redundant(b); ra != rb {
+				return ra
+			}
+			return byAtime(a, b)
+		}
+	default:
+		return byAtime
+	}
+}
+
 //////////////
 // min-heap //
 //////////////
 
-func (h minHeap) Len() int            { return len(h) }
-func (h minHeap) Less(i, j int) bool  { return h[i].Atime().Before(h[j].Atime()) }
-func (h minHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
-func (h *minHeap) Push(x interface{}) { *h = append(*h, x.(*cluster.LOM)) }
+func (h *minHeap) Len() int           { return len(h.loms) }
+func (h *minHeap) Less(i, j int) bool { return h.less(h.loms[i], h.loms[j]) }
+func (h *minHeap) Swap(i, j int)      { h.loms[i], h.loms[j] = h.loms[j], h.loms[i] }
+func (h *minHeap) Push(x interface{}) { h.loms = append(h.loms, x.(*cluster.LOM)) }
 func (h *minHeap) Pop() interface{} {
-	old := *h
+	old := h.loms
 	n := len(old)
 	fi := old[n-1]
-	*h = old[0 : n-1]
+	h.loms = old[0 : n-1]
 	return fi
 }
@@ -153,6 +153,16 @@ func saveRandomFilesWithMetadata(t cluster.Target, filesPath string, files []fil
 	}
 }
 
+func setLRUProps(t cluster.Target, update func(conf *cmn.LRUConf)) {
+	props, ok := t.Bowner().Get().Get(cluster.NewBck(bucketName, cmn.ProviderAIS, cmn.NsGlobal))
+	Expect(ok).To(BeTrue())
+	update(&props.LRU)
+}
+
+func setAtime(filename string, atime time.Time) {
+	Expect(os.Chtimes(filename, atime, atime)).NotTo(HaveOccurred())
+}
+
 // Saves random bytes to a file with random name.
 // timestamps and names are not increasing in the same manner
 func saveRandomFiles(t cluster.Target, filesPath string, filesNumber int) {
@@ -298,6 +308,112 @@ var _ = Describe("LRU tests", func() {
 			})
 		})
 
+		Describe("evict files in accordance with LRU policy", func() {
+			It("should evict the largest files first", func() {
+				var (
+					now   = time.Now()
+					small = []fileMetadata{
+						{getRandomFileName(0), cmn.MiB},
+						{getRandomFileName(1), cmn.MiB},
+						{getRandomFileName(2), cmn.MiB},
+					}
+					large = []fileMetadata{
+						{getRandomFileName(3), fileSize},
+						{getRandomFileName(4), fileSize},
+						{getRandomFileName(5), fileSize},
+					}
+				)
+				setLRUProps(t, func(conf *cmn.LRUConf) { conf.Policy = cmn.LRUPolicySize })
+				ini.GetFSStats = func(string) (blocks, bavail uint64, bsize int64, err error) {
+					bsize = blockSize
+					btaken := uint64((3*cmn.MiB + 3*fileSize) / blockSize)
+					blocks = uint64(float64(btaken) / initialDiskUsagePct)
+					bavail = blocks - btaken
+					return
+				}
+				saveRandomFilesWithMetadata(t, filesPath, small)
+				saveRandomFilesWithMetadata(t, filesPath, large)
+				for _, file := range small {
+					setAtime(path.Join(filesPath, file.name), now.Add(-2*time.Hour))
+				}
+				for _, file := range large {
+					setAtime(path.Join(filesPath, file.name), now.Add(-time.Hour))
+				}
+
+				lru.Run(ini)
+
+				files, err := ioutil.ReadDir(filesPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(files)).To(Equal(4))
+				for _, file := range small {
+					Expect(path.Join(filesPath, file.name)).To(BeAnExistingFile())
+				}
+				xstats := ini.Xaction.Stats().(*lru.XactStats)
+				Expect(xstats.Ext[cmn.LRUPolicySize].Objects).To(BeEquivalentTo(2))
+			})
+
+			It("should evict the least frequently accessed files first", func() {
+				const numberOfFiles = 6
+
+				ini.GetFSStats = getMockGetFSStats(numberOfFiles)
+				setLRUProps(t, func(conf *cmn.LRUConf) { conf.Policy = cmn.LRUPolicyLFU })
+
+				hotFiles := []fileMetadata{
+					{getRandomFileName(3), fileSize},
+					{getRandomFileName(4), fileSize},
+					{getRandomFileName(5), fileSize},
+				}
+				saveRandomFilesWithMetadata(t, filesPath, hotFiles)
+				for _, file := range hotFiles {
+					lom := &cluster.LOM{T: t, FQN: path.Join(filesPath, file.name)}
+					Expect(lom.Init(cmn.Bck{})).NotTo(HaveOccurred())
+					Expect(lom.Load(false)).NotTo(HaveOccurred())
+					for i := 0; i < 5; i++ {
+						lom.IncAccessCount()
+					}
+					Expect(lom.Persist()).NotTo(HaveOccurred())
+				}
+				time.Sleep(1 * time.Second)
+				saveRandomFiles(t, filesPath, 3)
+
+				lru.Run(ini)
+
+				files, err := ioutil.ReadDir(filesPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(files)).To(Equal(3))
+				hotFilesNames := namesFromFilesMetadatas(hotFiles)
+				for _, name := range files {
+					Expect(cmn.StringInSlice(name.Name(), hotFilesNames)).To(BeTrue())
+				}
+			})
+
+			It("should not evict pinned files", func() {
+				const numberOfFiles = 6
+
+				ini.GetFSStats = getMockGetFSStats(numberOfFiles)
+				setLRUProps(t, func(conf *cmn.LRUConf) { conf.PinnedPrefixes = []string{"pinned-"} })
+
+				pinnedFiles := []fileMetadata{
+					{"pinned-" + getRandomFileName(3), fileSize},
+					{"pinned-" + getRandomFileName(4), fileSize},
+					{"pinned-" + getRandomFileName(5), fileSize},
+				}
+				saveRandomFilesWithMetadata(t, filesPath, pinnedFiles)
+				time.Sleep(1 * time.Second)
+				saveRandomFiles(t, filesPath, 3)
+
+				lru.Run(ini)
+
+				files, err := ioutil.ReadDir(filesPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(files)).To(Equal(3))
+				pinnedFilesNames := namesFromFilesMetadatas(pinnedFiles)
+				for _, name := range files {
+					Expect(cmn.StringInSlice(name.Name(), pinnedFilesNames)).To(BeTrue())
+				}
+			})
+		})
+
 		Describe("not evict files", func() {
 			It("should do nothing when disk usage is below hwm", func() {
 				const numberOfFiles = 4
@@ -0,0 +1,432 @@
+// Package mirror provides local mirroring and replica management
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/mpather"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/transport/bundle"
+	"github.com/NVIDIA/aistore/xaction"
+	"github.com/NVIDIA/aistore/xaction/xreg"
+)
+
+// XactSyncBck makes the destination bucket identical to the source one, à la `rclone sync`.
+// Unlike copy-bucket, it only copies new and changed objects: an object is considered
+// up-to-date when the destination has an object with the same name (modulo prefix),
+// size, version (when both have one), and checksum (when both are of the same type).
+// When the source is remote, each target lists the source and refreshes its cached copies
+// that differ (by size, version, or checksum) from the remote objects.
+// Optionally, the xaction deletes destination objects that do not exist in the source;
+// with remote source, each target keeps (while listing) the names of only those objects
+// whose destination it is responsible for.
+// In dry-run mode nothing is copied or deleted; instead, the xaction reports (in its
+// extended stats) what would have been copied and deleted.
+
+const maxSyncReportObjs = 1000 // max number of object names in the dry-run report
+
+type (
+	syncBckProvider struct {
+		xact *XactSyncBck
+
+		t     cluster.Target
+		uuid  string
+		phase string
+		args  *xreg.TransferBckArgs
+	}
+	XactSyncBck struct {
+		XactTransferBck
+		slab      *memsys.Slab
+		dstExists bool
+		srcNames  map[string]struct{} // remote source only: names of the destination objects of this target
+		mu        sync.Mutex
+		skipped   atomic.Int64
+		deleted   atomic.Int64
+		toCopy    []string
+		toDelete  []string
+	}
+
+	SyncBckStats struct {
+		xaction.BaseXactStats
+		Ext ExtSyncBckStats `json:"ext"`
+	}
+	ExtSyncBckStats struct {
+		SkippedCount int64    `json:"sync.skipped.n,string"`    // up-to-date objects
+		DeletedCount int64    `json:"sync.deleted.n,string"`    // extraneous objects deleted (or to delete - dry-run)
+		ToCopy       []string `json:"sync.to_copy,omitempty"`   // dry-run: source objects to copy
+		ToDelete     []string `json:"sync.to_delete,omitempty"` // dry-run: destination objects to delete
+	}
+)
+
+// interface guard
+var (
+	_ cluster.Xact      = &XactSyncBck{}
+	_ cluster.XactStats = &SyncBckStats{}
+)
+
+func (e *syncBckProvider) New(args xreg.XactArgs) xreg.BucketEntry {
+	return &syncBckProvider{
+		t:     args.T,
+		uuid:  args.UUID,
+		phase: args.Phase,
+		args:  args.Custom.(*xreg.TransferBckArgs),
+	}
+}
+
+func (e *syncBckProvider) Start(_ cmn.Bck) error {
+	slab, err := e.t.MMSA().GetSlab(memsys.MaxPageSlabSize)
+	cmn.AssertNoErr(err)
+	e.xact = NewXactSyncBck(e.uuid, e.args.BckFrom, e.args.BckTo, e.t, slab, e.args.DM, e.args.Meta)
+	return nil
+}
+func (e *syncBckProvider) Kind() string      { return cmn.ActSyncBucket }
+func (e *syncBckProvider) Get() cluster.Xact { return e.xact }
+func (e *syncBckProvider) PreRenewHook(previousEntry xreg.BucketEntry) (keep bool, err error) {
+	prev := previousEntry.(*syncBckProvider)
+	bckEq := prev.args.BckFrom.Equal(e.args.BckFrom, true /*same BID*/, true /* same backend */)
+	if prev.phase == cmn.ActBegin && e.phase == cmn.ActCommit && bckEq {
+		prev.phase = cmn.ActCommit // transition
+		keep = true
+		return
+	}
+	err = fmt.Errorf("%s(%s=>%s, phase %s): cannot %s(%s=>%s)",
+		prev.xact, prev.args.BckFrom, prev.args.BckTo, prev.phase, e.phase, e.args.BckFrom, e.args.BckTo)
+	return
+}
+func (e *syncBckProvider) PostRenewHook(_ xreg.BucketEntry) {}
+
+//
+// public methods
+//
+
+func NewXactSyncBck(id string, bckFrom, bckTo *cluster.Bck, t cluster.Target, slab *memsys.Slab,
+	dm *bundle.DataMover, meta *cmn.Bck2BckMsg) *XactSyncBck {
+	xact := &XactSyncBck{
+		XactTransferBck: XactTransferBck{bckFrom: bckFrom, bckTo: bckTo, dm: dm, meta: meta},
+		slab:            slab,
+	}
+	opts := &mpather.JoggerGroupOpts{
+		Bck:      bckFrom.Bck,
+		T:        t,
+		CTs:      []string{fs.ObjectType},
+		VisitObj: xact.visitSrc,
+		DoLoad:   mpather.Load,
+		Slab:     slab,
+		Throttle: true,
+	}
+	if bckFrom.IsRemote() {
+		opts.VisitObj = nil // listing remote source instead (see `syncRemote`)
+	}
+	xact.xactBckBase = *newXactBckBase(id, cmn.ActSyncBucket, opts)
+	return xact
+}
+
+func (r *XactSyncBck) Run() (err error) {
+	r.dm.SetXact(r)
+	r.dm.Open()
+	glog.Infoln(r.String(), r.bckFrom.Bck, "=>", r.bckTo.Bck)
+
+	// in dry-run mode the destination may not exist
+	r.dstExists = r.bckTo.Init(r.t.Bowner(), r.t.Snode()) == nil
+	if r.bckFrom.IsRemote() {
+		err = r.syncRemote()
+	} else {
+		r.xactBckBase.runJoggers()
+		err = r.xactBckBase.waitDone()
+	}
+	if err == nil && r.meta.Delete && r.dstExists {
+		err = r.deleteExtra()
+	}
+	r.dm.Close(err)
+	r.dm.UnregRecv()
+
+	r.Finish(err)
+	return
+}
+
+func (r *XactSyncBck) Stats() cluster.XactStats {
+	baseStats := r.XactBase.Stats().(*xaction.BaseXactStats)
+	stats := SyncBckStats{BaseXactStats: *baseStats}
+	stats.Ext.SkippedCount = r.skipped.Load()
+	stats.Ext.DeletedCount = r.deleted.Load()
+	r.mu.Lock()
+	stats.Ext.ToCopy = append([]string(nil), r.toCopy...)
+	stats.Ext.ToDelete = append([]string(nil), r.toDelete...)
+	r.mu.Unlock()
+	return &stats
+}
+
+//
+// private methods
+//
+
+// copy phase: local (ais) source
+func (r *XactSyncBck) visitSrc(lom *cluster.LOM, buf []byte) error {
+	return r.syncObject(lom, buf)
+}
+
+// copy phase: remote source - list all objects and sync those that hash to this target
+func (r *XactSyncBck) syncRemote() error {
+	var (
+		buf = r.slab.Alloc()
+		ctx = context.Background()
+	)
+	defer r.slab.Free(buf)
+	var (
+		smap = r.t.Sowner().Get()
+		sid  = r.t.Snode().ID()
+	)
+	if r.meta.Delete && r.dstExists {
+		r.srcNames = make(map[string]struct{}, 1024)
+	}
+	return r.listRemote(r.bckFrom, "", func(entry *cmn.BucketEntry, local bool) error {
+		// to delete extraneous objects, remember the source names of only
+		// those destination objects this target is responsible for
+		if r.srcNames != nil {
+			si, err := cluster.HrwTarget(r.bckTo.MakeUname(cmn.ObjNameFromBck2BckMsg(entry.Name, r.meta)), smap)
+			if err != nil {
+				return err
+			}
+			if si.ID() == sid {
+				r.srcNames[entry.Name] = struct{}{}
+			}
+		}
+		if !local {
+			return nil
+		}
+		lom := &cluster.LOM{T: r.t, ObjName: entry.Name}
+		if err := lom.Init(r.bckFrom.Bck); err != nil {
+			return err
+		}
+		lom.Lock(false)
+		err := lom.Load(false)
+		lom.Unlock(false)
+		if err != nil && !cmn.IsObjNotExist(err) {
+			return err
+		}
+		if err != nil || !cacheIsFresh(lom, entry) {
+			if r.meta.DryRun {
+				r.addToCopy(entry.Name, entry.Size)
+				return nil
+			}
+			if err, errCode := r.t.GetCold(ctx, lom, true /*prefetch*/); err != nil {
+				if err == cmn.ErrSkip || errCode == http.StatusNotFound {
+					return nil // being fetched by someone else or removed in the meantime
+				}
+				return err
+			}
+			if err := lom.Load(false); err != nil {
+				return err
+			}
+		}
+		return r.syncObject(lom, buf)
+	})
+}
+
+// copies the source object unless the destination already has it
+func (r *XactSyncBck) syncObject(lom *cluster.LOM, buf []byte) error {
+	if r.dstExists {
+		dst := &cluster.LOM{T: r.t, ObjName: cmn.ObjNameFromBck2BckMsg(lom.ObjName, r.meta)}
+		if err := dst.Init(r.bckTo.Bck); err != nil {
+			return err
+		}
+		exists, err := r.exists(dst)
+		if err != nil {
+			return err
+		}
+		if exists && upToDate(lom, dst) {
+			r.skipped.Inc()
+			return nil
+		}
+	}
+	if r.meta.DryRun {
+		r.addToCopy(lom.ObjName, lom.Size())
+		return nil
+	}
+	return r.copyObject(lom, buf)
+}
+
+// delete phase: walk (or list, if remote) the destination and remove
+// objects that do not exist in the source
+func (r *XactSyncBck) deleteExtra() error {
+	if r.bckTo.IsRemote() {
+		return r.listRemote(r.bckTo, r.meta.Prefix, func(entry *cmn.BucketEntry, local bool) error {
+			if !local {
+				return nil
+			}
+			dst := &cluster.LOM{T: r.t, ObjName: entry.Name}
+			if err := dst.Init(r.bckTo.Bck); err != nil {
+				return err
+			}
+			return r.deleteIfExtra(dst)
+		})
+	}
+	r.joggers = mpather.NewJoggerGroup(&mpather.JoggerGroupOpts{
+		Bck:      r.bckTo.Bck,
+		T:        r.t,
+		CTs:      []string{fs.ObjectType},
+		VisitObj: func(lom *cluster.LOM, _ []byte) error { return r.deleteIfExtra(lom) },
+		DoLoad:   mpather.Load,
+		Throttle: true,
+	})
+	r.xactBckBase.runJoggers()
+	return r.xactBckBase.waitDone()
+}
+
+func (r *XactSyncBck) deleteIfExtra(dst *cluster.LOM) error {
+	if !strings.HasPrefix(dst.ObjName, r.meta.Prefix) {
+		return nil // not ours
+	}
+	exists, err := r.srcExists(strings.TrimPrefix(dst.ObjName, r.meta.Prefix))
+	if err != nil || exists {
+		return err
+	}
+	r.deleted.Inc()
+	if r.meta.DryRun {
+		r.mu.Lock()
+		if len(r.toDelete) < maxSyncReportObjs {
+			r.toDelete = append(r.toDelete, dst.ObjName)
+		}
+		r.mu.Unlock()
+		return nil
+	}
+	if err, errCode := r.t.DeleteObject(context.Background(), dst, false /*evict*/); err != nil {
+		if errCode != http.StatusNotFound && !cmn.IsObjNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *XactSyncBck) srcExists(objName string) (bool, error) {
+	if r.srcNames != nil {
+		_, ok := r.srcNames[objName]
+		return ok, nil
+	}
+	lom := &cluster.LOM{T: r.t, ObjName: objName}
+	if err := lom.Init(r.bckFrom.Bck); err != nil {
+		return false, err
+	}
+	return r.exists(lom)
+}
+
+// exists checks (and loads) the object on its HRW target
+func (r *XactSyncBck) exists(lom *cluster.LOM) (bool, error) {
+	si, err := cluster.HrwTarget(lom.Uname(), r.t.Sowner().Get())
+	if err != nil {
+		return false, err
+	}
+	if si.ID() != r.t.Snode().ID() {
+		return r.t.HeadObjT2T(lom, si)
+	}
+	lom.Lock(false)
+	err = lom.Load(false)
+	lom.Unlock(false)
+	if err != nil {
+		if cmn.IsObjNotExist(err) {
+			err = nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// listRemote lists the remote bucket page by page; `local` tells whether
+// the object hashes to this target
+func (r *XactSyncBck) listRemote(bck *cluster.Bck, prefix string,
+	cb func(entry *cmn.BucketEntry, local bool) error) error {
+	var (
+		smap = r.t.Sowner().Get()
+		sid  = r.t.Snode().ID()
+		msg  = &cmn.SelectMsg{
+			Prefix: prefix,
+			Props:  strings.Join([]string{cmn.GetPropsSize, cmn.GetPropsVersion, cmn.GetPropsChecksum}, ","),
+		}
+	)
+	for {
+		objList, err, _ := r.t.Cloud(bck).ListObjects(context.Background(), bck, msg)
+		if err != nil {
+			return err
+		}
+		for _, entry := range objList.Entries {
+			if r.Aborted() {
+				return cmn.NewAbortedError(r.String())
+			}
+			si, err := cluster.HrwTarget(bck.MakeUname(entry.Name), smap)
+			if err != nil {
+				return err
+			}
+			if err := cb(entry, si.ID() == sid); err != nil {
+				return err
+			}
+		}
+		if objList.ContinuationToken == "" {
+			return nil
+		}
+		msg.ContinuationToken = objList.ContinuationToken
+	}
+}
+
+func (r *XactSyncBck) addToCopy(objName string, size int64) {
+	r.ObjectsInc()
+	r.BytesAdd(size)
+	r.mu.Lock()
+	if len(r.toCopy) < maxSyncReportObjs {
+		r.toCopy = append(r.toCopy, objName)
+	}
+	r.mu.Unlock()
+}
+
+// cacheIsFresh returns true if the cached copy of a remote object matches the listed one:
+// same size, same version (if listed), and same checksum (if listed and comparable).
+func cacheIsFresh(lom *cluster.LOM, entry *cmn.BucketEntry) bool {
+	if lom.Size() != entry.Size {
+		return false
+	}
+	if entry.Version != "" && lom.Version() != entry.Version {
+		return false
+	}
+	if entry.Checksum == "" {
+		return true
+	}
+	if cksum := lom.Cksum(); cksum != nil {
+		if _, v := cksum.Get(); v == entry.Checksum {
+			return true
+		}
+	}
+	if md5, ok := lom.GetCustomMD(cluster.MD5ObjMD); ok {
+		return md5 == entry.Checksum
+	}
+	return entry.Version != "" // checksums are not comparable - the same version will do
+}
+
+// upToDate returns true if the destination does not need to be updated: same size
+// and - when both sides have them - same version and checksum.
+func upToDate(src, dst *cluster.LOM) bool {
+	if src.Size() != dst.Size() {
+		return false
+	}
+	if src.Version() != "" && dst.Version() != "" && src.Version() != dst.Version() {
+		return false
+	}
+	if src.Cksum() == nil || dst.Cksum() == nil {
+		return true
+	}
+	ty, _ := src.Cksum().Get()
+	if dty, _ := dst.Cksum().Get(); ty != dty || ty == cmn.ChecksumNone {
+		return true // not comparable
+	}
+	return src.Cksum().Equal(dst.Cksum())
+}
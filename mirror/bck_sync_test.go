@@ -0,0 +1,69 @@
+// Package mirror provides local mirroring and replica management
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package mirror
+
+import (
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SyncBucket", func() {
+	newLOM := func(size int64, version string, cksum *cmn.Cksum) *cluster.LOM {
+		lom := &cluster.LOM{}
+		lom.SetSize(size)
+		lom.SetVersion(version)
+		lom.SetCksum(cksum)
+		return lom
+	}
+
+	It("should compare source and destination", func() {
+		var (
+			xx1 = cmn.NewCksum(cmn.ChecksumXXHash, "1111")
+			xx2 = cmn.NewCksum(cmn.ChecksumXXHash, "2222")
+			md5 = cmn.NewCksum(cmn.ChecksumMD5, "1111")
+		)
+		Expect(upToDate(newLOM(10, "", xx1), newLOM(10, "", xx1))).To(BeTrue())
+		Expect(upToDate(newLOM(10, "", xx1), newLOM(11, "", xx1))).To(BeFalse())
+		Expect(upToDate(newLOM(10, "", xx1), newLOM(10, "", xx2))).To(BeFalse())
+
+		// checksums of different types (or missing) are not compared
+		Expect(upToDate(newLOM(10, "", xx1), newLOM(10, "", md5))).To(BeTrue())
+		Expect(upToDate(newLOM(10, "", xx1), newLOM(10, "", nil))).To(BeTrue())
+		Expect(upToDate(newLOM(10, "", nil), newLOM(11, "", nil))).To(BeFalse())
+
+		// versions are compared when both sides have them
+		Expect(upToDate(newLOM(10, "2", xx1), newLOM(10, "2", xx1))).To(BeTrue())
+		Expect(upToDate(newLOM(10, "2", xx1), newLOM(10, "1", xx1))).To(BeFalse())
+		Expect(upToDate(newLOM(10, "2", xx1), newLOM(10, "", xx1))).To(BeTrue())
+		Expect(upToDate(newLOM(10, "2", xx1), newLOM(10, "2", xx2))).To(BeFalse())
+	})
+
+	It("should detect stale cached copies of remote objects", func() {
+		cksum := cmn.NewCksum(cmn.ChecksumXXHash, "1111")
+		Expect(cacheIsFresh(newLOM(10, "v1", cksum), &cmn.BucketEntry{Size: 10, Version: "v1"})).To(BeTrue())
+		Expect(cacheIsFresh(newLOM(10, "v1", cksum), &cmn.BucketEntry{Size: 10, Version: "v2"})).To(BeFalse())
+		Expect(cacheIsFresh(newLOM(10, "v1", cksum), &cmn.BucketEntry{Size: 12, Version: "v1"})).To(BeFalse())
+
+		// version and checksum - both must match (unless the checksums are not comparable)
+		Expect(cacheIsFresh(newLOM(10, "v1", cksum), &cmn.BucketEntry{Size: 10, Version: "v1", Checksum: "1111"})).To(BeTrue())
+		Expect(cacheIsFresh(newLOM(10, "v1", cksum), &cmn.BucketEntry{Size: 10, Version: "v1", Checksum: "2222"})).To(BeTrue())
+		Expect(cacheIsFresh(newLOM(10, "v2", cksum), &cmn.BucketEntry{Size: 10, Version: "v1", Checksum: "1111"})).To(BeFalse())
+		versioned := newLOM(10, "v1", cksum)
+		versioned.SetCustomMD(cmn.SimpleKVs{cluster.MD5ObjMD: "abcd"})
+		Expect(cacheIsFresh(versioned, &cmn.BucketEntry{Size: 10, Version: "v1", Checksum: "abcd"})).To(BeTrue())
+		Expect(cacheIsFresh(versioned, &cmn.BucketEntry{Size: 10, Version: "v1", Checksum: "dcba"})).To(BeFalse())
+
+		// no version - compare checksums
+		Expect(cacheIsFresh(newLOM(10, "", cksum), &cmn.BucketEntry{Size: 10, Checksum: "1111"})).To(BeTrue())
+		Expect(cacheIsFresh(newLOM(10, "", cksum), &cmn.BucketEntry{Size: 10, Checksum: "2222"})).To(BeFalse())
+
+		lom := newLOM(10, "", cksum)
+		lom.SetCustomMD(cmn.SimpleKVs{cluster.MD5ObjMD: "abcd"})
+		Expect(cacheIsFresh(lom, &cmn.BucketEntry{Size: 10, Checksum: "abcd"})).To(BeTrue())
+		Expect(cacheIsFresh(newLOM(10, "", nil), &cmn.BucketEntry{Size: 10})).To(BeTrue())
+	})
+})
@@ -139,7 +139,7 @@ func (r *XactTransferBck) copyObject(lom *cluster.LOM, buf []byte) error {
 	// TODO: if dry-run show to-be-copied objects
 	copied, size, err := r.Target().CopyObject(lom, params, false /*localOnly*/)
 	if err != nil {
-		if cmn.IsErrOOS(err) {
+		if cmn.IsErrOOS(err) || cmn.IsErrQuotaExceeded(err) {
 			what := fmt.Sprintf("%s(%q)", r.Kind(), r.ID())
 			return cmn.NewAbortedErrorDetails(what, err.Error())
 		}
@@ -30,6 +30,7 @@ type (
 func init() {
 	xreg.RegisterBucketXact(&transferBckProvider{kind: cmn.ActCopyBucket})
 	xreg.RegisterBucketXact(&transferBckProvider{kind: cmn.ActETLBucket})
+	xreg.RegisterBucketXact(&syncBckProvider{})
 	xreg.RegisterBucketXact(&dirPromoteProvider{})
 	xreg.RegisterBucketXact(&mncProvider{})
 	xreg.RegisterBucketXact(&llcProvider{})
@@ -412,7 +412,7 @@ func (r *Xact) traverseBucket(msg *cmn.SelectMsg) {
 	opts := &fs.WalkBckOptions{
 		Options: fs.Options{
 			Bck:      r.Bck(),
-			CTs:      []string{fs.ObjectType},
+			CTs:      wi.CTs(),
 			Callback: cb,
 			Sorted:   true,
 		},
@@ -29,6 +29,7 @@ type (
 		markerDir    string
 		msg          *cmn.SelectMsg
 		timeFormat   string
+		lastDeleted  string // the last listed deleted object (see lsDeleted)
 	}
 
 	PostCallbackFunc func(lom *cluster.LOM)
@@ -81,6 +82,15 @@ func NewWalkInfo(ctx context.Context, t cluster.Target, msg *cmn.SelectMsg) *Wal
 	}
 }
 
+// Returns content types to walk: prior versions are walked along with objects
+// to list versions of deleted objects.
+func (wi *WalkInfo) CTs() []string {
+	if wi.msg.IsFlagSet(cmn.SelectVersions) {
+		return []string{fs.ObjectType, fs.VersionType}
+	}
+	return []string{fs.ObjectType}
+}
+
 func (wi *WalkInfo) needSize() bool      { return wi.propNeeded[cmn.GetPropsSize] }
 func (wi *WalkInfo) needAtime() bool     { return wi.propNeeded[cmn.GetPropsAtime] }
 func (wi *WalkInfo) needCksum() bool     { return wi.propNeeded[cmn.GetPropsChecksum] }
@@ -161,12 +171,41 @@ func (wi *WalkInfo) lsObject(lom *cluster.LOM, objStatus uint16) *cmn.BucketEntr
 	if wi.needSize() {
 		fileInfo.Size = lom.Size()
 	}
+	if wi.msg.IsFlagSet(cmn.SelectVersions) && lom.Bck().IsAIS() {
+		fileInfo.Versions = wi.lsVersions(lom)
+	}
 	if wi.postCallback != nil {
 		wi.postCallback(lom)
 	}
 	return fileInfo
 }
 
+// Returns prior versions of the object retained by the bucket (if any).
+// Atime of a prior version is the time it was overwritten (or deleted).
+func (wi *WalkInfo) lsVersions(lom *cluster.LOM) (entries []*cmn.BucketEntry) {
+	versions, err := lom.PriorVersions()
+	if err != nil || len(versions) == 0 {
+		return
+	}
+	entries = make([]*cmn.BucketEntry, 0, len(versions))
+	for _, v := range versions {
+		entry := &cmn.BucketEntry{Name: lom.ObjName, Version: v.Version, Flags: cmn.EntryIsCached}
+		if wi.needAtime() {
+			entry.Atime = cmn.FormatUnixNano(v.Mtime.UnixNano(), wi.timeFormat)
+		}
+		if wi.needSize() {
+			entry.Size = v.Size
+		}
+		if wi.needCksum() {
+			if vlom, err := lom.LoadVersion(v.Version); err == nil && vlom.Cksum() != nil {
+				_, entry.Checksum = vlom.Cksum().Get()
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return
+}
+
 // Since objwalk returns only "accessible" objects by default, it always needs
 // LOM to check if an object is misplaced etc. On the other hand, skipping LOM
 // loading and checking increases bucket list performance. So, when we need
@@ -210,3 +249,51 @@ func (wi *WalkInfo) Callback(fqn string, de fs.DirEntry) (*cmn.BucketEntry, erro
 	}
 	return wi.lsObject(lom, objStatus), nil
 }
+
+// Adds a deleted object that has prior versions retained by the bucket. Walked
+// are prior versions: the object is listed once (with all its versions) if
+// this target is responsible for it.
+func (wi *WalkInfo) lsDeleted(parsedFQN *fs.ParsedFQN) (*cmn.BucketEntry, error) {
+	objName, _, ok := fs.ParseVersionName(parsedFQN.ObjName)
+	if !ok || objName == wi.lastDeleted {
+		return nil, nil
+	}
+	wi.lastDeleted = objName
+	if wi.prefix != "" && !strings.HasPrefix(objName, wi.prefix) {
+		return nil, nil
+	}
+	if wi.Marker != "" && cmn.TokenIncludesObject(wi.Marker, objName) {
+		return nil, nil
+	}
+	// deleted objects have no properties to filter by
+	if wi.objectFilter != nil {
+		return nil, nil
+	}
+	lom := &cluster.LOM{T: wi.t, ObjName: objName}
+	if err := lom.Init(parsedFQN.Bck); err != nil {
+		return nil, err
+	}
+	// prior versions are yet to be moved (resilvered)
+	if lom.ParsedFQN.MpathInfo.Path != parsedFQN.MpathInfo.Path {
+		return nil, nil
+	}
+	si, err := cluster.HrwTarget(lom.Uname(), wi.smap)
+	if err != nil {
+		return nil, err
+	}
+	if wi.t.Snode().ID() != si.ID() {
+		return nil, nil
+	}
+	if err := lom.Load(false); err == nil || !cmn.IsObjNotExist(err) {
+		return nil, nil // the object exists (listed as such)
+	}
+	versions := wi.lsVersions(lom)
+	if len(versions) == 0 {
+		return nil, nil
+	}
+	return &cmn.BucketEntry{
+		Name:     objName,
+		Flags:    cmn.ObjStatusDeleted,
+		Versions: versions,
+	}, nil
+}
@@ -0,0 +1,147 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Content filters (aka inner select) look into objects' contents and are
+// therefore evaluated by the targets that store the objects - after (and
+// only if) the object passes the metadata filters (see `WhereMsg`). An
+// object that cannot be read is logged and filtered out.
+
+const (
+	ContentRegexF = "content_regex" // object's content matches regular expression
+	ContentJSONF  = "content_json"  // JSON object's field (dot-separated path) equals the value
+	ContentMagicF = "content_magic" // object starts with the given (hex-encoded) bytes
+
+	// objects larger than that never match (regex and JSON filters read
+	// the entire content into memory)
+	maxContentSize = 16 * cmn.MiB
+)
+
+var contentFunctionMeta = map[string]filterMeta{
+	ContentRegexF: {1, stringArg},
+	ContentJSONF:  {2, stringArg},
+	ContentMagicF: {1, stringArg},
+}
+
+func contentFilter(name string, match func(r io.Reader) (bool, error)) cluster.ObjectFilter {
+	return func(lom *cluster.LOM) bool {
+		lom.Lock(false)
+		defer lom.Unlock(false)
+		fh, err := lom.Open()
+		if err != nil {
+			glog.Errorf("%s: %s failed to open %s, err: %v", lom.T.Snode(), name, lom, err)
+			return false
+		}
+		ok, err := match(fh)
+		fh.Close()
+		if err != nil {
+			glog.Errorf("%s: %s failed to read %s, err: %v", lom.T.Snode(), name, lom, err)
+			return false
+		}
+		return ok
+	}
+}
+
+func ContentRegexFilter(expr string) (cluster.ObjectFilter, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid regular expression %q: %v", ContentRegexF, expr, err)
+	}
+	return contentFilter(ContentRegexF, regexMatch(re)), nil
+}
+
+func regexMatch(re *regexp.Regexp) func(r io.Reader) (bool, error) {
+	return func(r io.Reader) (bool, error) {
+		b, err := readContent(r)
+		if err != nil || b == nil {
+			return false, err
+		}
+		return re.Match(b), nil
+	}
+}
+
+func ContentRegexFilterMsg(expr string) *FilterMsg {
+	return NewFilter(ContentRegexF, []string{expr})
+}
+
+func ContentJSONFilter(field, value string) (cluster.ObjectFilter, error) {
+	if field == "" {
+		return nil, fmt.Errorf("%s: field must not be empty", ContentJSONF)
+	}
+	parts := strings.Split(field, ".")
+	path := make([]interface{}, len(parts))
+	for i, part := range parts {
+		path[i] = part
+	}
+	return contentFilter(ContentJSONF, jsonMatch(path, value)), nil
+}
+
+func jsonMatch(path []interface{}, value string) func(r io.Reader) (bool, error) {
+	return func(r io.Reader) (bool, error) {
+		b, err := readContent(r)
+		if err != nil || b == nil {
+			return false, err
+		}
+		v := jsoniter.Get(b, path...)
+		if v.LastError() != nil {
+			return false, nil
+		}
+		return v.ToString() == value, nil
+	}
+}
+
+func ContentJSONFilterMsg(field, value string) *FilterMsg {
+	return NewFilter(ContentJSONF, []string{field, value})
+}
+
+func ContentMagicFilter(hexMagic string) (cluster.ObjectFilter, error) {
+	magic, err := hex.DecodeString(hexMagic)
+	if err != nil || len(magic) == 0 {
+		return nil, fmt.Errorf("%s: invalid hex-encoded bytes %q", ContentMagicF, hexMagic)
+	}
+	return contentFilter(ContentMagicF, magicMatch(magic)), nil
+}
+
+func magicMatch(magic []byte) func(r io.Reader) (bool, error) {
+	return func(r io.Reader) (bool, error) {
+		b := make([]byte, len(magic))
+		if _, err := io.ReadFull(r, b); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return false, nil // shorter than magic
+			}
+			return false, err
+		}
+		return bytes.Equal(b, magic), nil
+	}
+}
+
+func ContentMagicFilterMsg(magic []byte) *FilterMsg {
+	return NewFilter(ContentMagicF, []string{hex.EncodeToString(magic)})
+}
+
+// readContent reads the entire content - or returns nil if the content is
+// larger than maxContentSize.
+func readContent(r io.Reader) ([]byte, error) {
+	b, err := ioutil.ReadAll(io.LimitReader(r, maxContentSize+1))
+	if err != nil || len(b) > maxContentSize {
+		return nil, err
+	}
+	return b, nil
+}
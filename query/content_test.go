@@ -0,0 +1,102 @@
+// Package query provides interface to iterate over objects with additional filtering
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package query
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+var errRead = errors.New("read failed")
+
+// failingReader returns the content followed by the error.
+type failingReader struct {
+	r io.Reader
+}
+
+func (fr *failingReader) Read(p []byte) (int, error) {
+	n, err := fr.r.Read(p)
+	if err == io.EOF {
+		err = errRead
+	}
+	return n, err
+}
+
+func checkMatch(t *testing.T, match func(r io.Reader) (bool, error), content string, expected bool) {
+	ok, err := match(strings.NewReader(content))
+	tassert.CheckError(t, err)
+	tassert.Errorf(t, ok == expected, "expected match=%t for %q", expected, content)
+}
+
+func checkReadError(t *testing.T, match func(r io.Reader) (bool, error), content string) {
+	ok, err := match(&failingReader{strings.NewReader(content)})
+	tassert.Errorf(t, errors.Is(err, errRead), "expected read error, got %v", err)
+	tassert.Errorf(t, !ok, "expected no match on read error")
+}
+
+func TestContentRegex(t *testing.T) {
+	_, err := ContentRegexFilter("[a-")
+	tassert.Errorf(t, err != nil, "expected invalid regular expression")
+
+	match := regexMatch(regexp.MustCompile(`^label:\s+(cat|dog)$`))
+	checkMatch(t, match, "id: 1\nlabel: cat", false) // not multiline
+	checkMatch(t, match, "label: dog", true)
+	checkMatch(t, match, "label: bird", false)
+	checkMatch(t, match, "", false)
+
+	multiline := regexMatch(regexp.MustCompile(`(?m)^label:\s+cat$`))
+	checkMatch(t, multiline, "id: 1\nlabel: cat\n", true)
+
+	// never match objects larger than the limit
+	cat := regexMatch(regexp.MustCompile(`cat`))
+	checkMatch(t, cat, strings.Repeat("x", maxContentSize-3)+"cat", true)
+	checkMatch(t, cat, strings.Repeat("x", maxContentSize)+"cat", false)
+
+	checkReadError(t, cat, "cat")
+}
+
+func TestContentJSON(t *testing.T) {
+	_, err := ContentJSONFilter("", "cat")
+	tassert.Errorf(t, err != nil, "expected error on empty field")
+
+	match := jsonMatch([]interface{}{"meta", "label"}, "cat")
+	checkMatch(t, match, `{"meta": {"label": "cat"}}`, true)
+	checkMatch(t, match, `{"meta": {"label": "dog"}}`, false)
+	checkMatch(t, match, `{"label": "cat"}`, false)
+	checkMatch(t, match, `not a JSON`, false)
+	checkMatch(t, match, "", false)
+
+	width := jsonMatch([]interface{}{"width"}, "640")
+	checkMatch(t, width, `{"width": 640}`, true)
+
+	checkReadError(t, match, `{"meta": {"label": "cat"}}`)
+}
+
+func TestContentMagic(t *testing.T) {
+	for _, magic := range []string{"", "zz", "1f8"} {
+		_, err := ContentMagicFilter(magic)
+		tassert.Errorf(t, err != nil, "expected invalid magic %q", magic)
+	}
+
+	gzipMagic := []byte{0x1f, 0x8b}
+	match := magicMatch(gzipMagic)
+	checkMatch(t, match, string(gzipMagic)+"content", true)
+	checkMatch(t, match, "content", false)
+	checkMatch(t, match, "\x1f", false) // shorter than magic
+	checkMatch(t, match, "", false)
+
+	// the error after the magic has been read is irrelevant
+	ok, err := match(&failingReader{bytes.NewReader(gzipMagic)})
+	tassert.CheckError(t, err)
+	tassert.Errorf(t, ok, "expected match")
+
+	checkReadError(t, match, "\x1f")
+}
@@ -79,7 +79,17 @@ func NewAndFilter(filters ...*FilterMsg) *FilterMsg {
 	}
 }
 
+// ObjFilterFromMsg creates metadata filter (see `WhereMsg`).
 func ObjFilterFromMsg(filter *FilterMsg) (cluster.ObjectFilter, error) {
+	return filterFromMsg(filter, functionMeta)
+}
+
+// ContentFilterFromMsg creates content filter (see `InnerSelectMsg`).
+func ContentFilterFromMsg(filter *FilterMsg) (cluster.ObjectFilter, error) {
+	return filterFromMsg(filter, contentFunctionMeta)
+}
+
+func filterFromMsg(filter *FilterMsg, meta map[string]filterMeta) (cluster.ObjectFilter, error) {
 	if filter == nil {
 		return nil, nil
 	}
@@ -89,7 +99,7 @@ func ObjFilterFromMsg(filter *FilterMsg) (cluster.ObjectFilter, error) {
 			return nil, fmt.Errorf("expected %s filter to have at least 2 inner filters, got %d", filter.Type, len(filter.Filters))
 		}
 
-		filters, err := extractObjectFilters(filter)
+		filters, err := extractObjectFilters(filter, meta)
 		if err != nil {
 			return nil, err
 		}
@@ -98,16 +108,16 @@ func ObjFilterFromMsg(filter *FilterMsg) (cluster.ObjectFilter, error) {
 		}
 		return Or(filters...), nil
 	case FUNCTION:
-		return functionFilterMsgToObjectFilter(filter)
+		return functionFilterMsgToObjectFilter(filter, meta)
 	default:
 		return nil, fmt.Errorf("unknown type %s", filter.Type)
 	}
 }
 
-func extractObjectFilters(filter *FilterMsg) ([]cluster.ObjectFilter, error) {
+func extractObjectFilters(filter *FilterMsg, meta map[string]filterMeta) ([]cluster.ObjectFilter, error) {
 	filters := make([]cluster.ObjectFilter, 0, len(filter.Filters))
 	for _, msgFilter := range filter.Filters {
-		f, err := ObjFilterFromMsg(msgFilter)
+		f, err := filterFromMsg(msgFilter, meta)
 		if err != nil {
 			return nil, err
 		}
@@ -116,7 +126,7 @@ func extractObjectFilters(filter *FilterMsg) ([]cluster.ObjectFilter, error) {
 	return filters, nil
 }
 
-func functionFilterMsgToObjectFilter(filterMsg *FilterMsg) (cluster.ObjectFilter, error) {
+func functionFilterMsgToObjectFilter(filterMsg *FilterMsg, meta map[string]filterMeta) (cluster.ObjectFilter, error) {
 	cmn.Assert(filterMsg.Type == FUNCTION)
 	var (
 		err error
@@ -124,18 +134,24 @@ func functionFilterMsgToObjectFilter(filterMsg *FilterMsg) (cluster.ObjectFilter
 		ok  bool
 	)
 
-	fMeta, ok := functionMeta[filterMsg.FName]
+	fMeta, ok := meta[filterMsg.FName]
 	if !ok {
 		return nil, fmt.Errorf("unknown function name %s", filterMsg.FName)
 	}
 	if len(filterMsg.Args) != fMeta.argsCnt {
-		return nil, fmt.Errorf("expected %d arguments, got %d", functionMeta[filterMsg.FName], len(filterMsg.Args))
+		return nil, fmt.Errorf("expected %d arguments, got %d", fMeta.argsCnt, len(filterMsg.Args))
 	}
 
 	if fMeta.argsType == stringArg {
 		switch filterMsg.FName {
 		case ExtF:
 			return ExtFilter(filterMsg.Args[0]), nil
+		case ContentRegexF:
+			return ContentRegexFilter(filterMsg.Args[0])
+		case ContentJSONF:
+			return ContentJSONFilter(filterMsg.Args[0], filterMsg.Args[1])
+		case ContentMagicF:
+			return ContentMagicFilter(filterMsg.Args[0])
 		default:
 			cmn.Assert(false)
 			return nil, nil
@@ -299,6 +315,14 @@ func ExtFilter(ext string) cluster.ObjectFilter {
 	}
 }
 
+func ExtFilterMsg(ext string) *FilterMsg {
+	return &FilterMsg{
+		Type:  FUNCTION,
+		FName: ExtF,
+		Args:  []string{ext},
+	}
+}
+
 func And(filters ...cluster.ObjectFilter) cluster.ObjectFilter {
 	return func(lom *cluster.LOM) bool {
 		for _, f := range filters {
@@ -34,14 +34,16 @@ type (
 	}
 
 	// OuterSelect -> Look only on objects' metadata.
-	// In the future we might have InnerSelect, which looks into objects' contents
 	OuterSelectMsg struct {
 		Prefix   string `json:"prefix"`
 		Template string `json:"objects_source"`
 	}
 
+	// InnerSelect -> Look into objects' contents (see ContentRegexF and
+	// other content filters).
 	InnerSelectMsg struct {
-		Props string `json:"props"`
+		Props  string     `json:"props"`
+		Filter *FilterMsg `json:"filter"`
 	}
 
 	FromMsg struct {
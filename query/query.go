@@ -98,5 +98,17 @@ func NewQueryFromMsg(node cluster.Node, msg *DefMsg) (q *ObjectsQuery, err error
 	if q.filter, err = ObjFilterFromMsg(msg.Where.Filter); err != nil {
 		return nil, err
 	}
+	if msg.InnerSelect.Filter != nil {
+		contentFilter, err := ContentFilterFromMsg(msg.InnerSelect.Filter)
+		if err != nil {
+			return nil, err
+		}
+		// content is looked into only if metadata matches
+		if q.filter != nil {
+			q.filter = And(q.filter, contentFilter)
+		} else {
+			q.filter = contentFilter
+		}
+	}
 	return q, nil
 }
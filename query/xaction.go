@@ -212,7 +212,7 @@ func (r *ObjectsListingXact) startFromBck() {
 	opts := &fs.WalkBckOptions{
 		Options: fs.Options{
 			Bck:      bck.Bck,
-			CTs:      []string{fs.ObjectType},
+			CTs:      wi.CTs(),
 			Callback: cb,
 			Sorted:   true,
 		},
@@ -27,7 +27,6 @@ import (
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/transport"
 	jsoniter "github.com/json-iterator/go"
-	"github.com/klauspost/reedsolomon"
 )
 
 // TODO: At this moment the module contains duplicated code borrowed from EC
@@ -113,6 +112,8 @@ type (
 		SliceID      int16  `json:"sliceid,omitempty"`
 		DataSlices   int16  `json:"data"`
 		ParitySlices int16  `json:"parity"`
+		Codec        string `json:"codec,omitempty"`
+		LocalGroups  int16  `json:"groups,omitempty"`
 	}
 
 	ctList = map[string][]*rebCT // EC CTs grouped by a rule
@@ -127,6 +128,7 @@ type (
 		sender       *cluster.Snode    // target responsible to send replicas over the cluster (first by HRW)
 		locCT        map[string]*rebCT // CT locations: maps daemonID to CT for faster check what nodes have the CT
 		ctExist      []bool            // marks existing CT: SliceID <=> Exists
+		toRead       []bool            // slices the main target reads to rebuild the object (nil - any `dataSlices`)
 		mainDaemon   string            // hrw target for an object
 		uid          string            // unique identifier for the object (Bucket#Object#IsAIS)
 		bck          cmn.Bck
@@ -310,8 +312,19 @@ func (reb *Manager) sendFromDisk(ct *rebCT, target *cluster.Snode) error {
 	} else {
 		lom = nil // sending slice
 	}
-	// open
-	fh, err := cmn.NewFileHandle(fqn)
+	// open (encrypted objects and slices are sent decrypted)
+	var (
+		fh  cmn.ReadOpenCloser
+		err error
+	)
+	switch {
+	case lom != nil:
+		fh, err = lom.Open()
+	case ct.meta.SSE != "":
+		fh, err = ec.OpenSlice(fqn, ct.meta.SSE, ec.SliceSize(ct.ObjSize, int(ct.DataSlices)))
+	default:
+		fh, err = cmn.NewFileHandle(fqn)
+	}
 	if err != nil {
 		return err
 	}
@@ -438,14 +451,13 @@ func (reb *Manager) saveCTToDisk(data io.Reader, req *pushReq, hdr transport.Obj
 		return nil
 	}
 
-	md := req.md.Marshal()
 	if req.md.SliceID != 0 {
-		err = ec.WriteSliceAndMeta(reb.t, hdr, data, md)
+		err = ec.WriteSliceAndMeta(reb.t, hdr, data, req.md)
 	} else {
 		var lom *cluster.LOM
 		lom, err = ec.LomFromHeader(reb.t, hdr)
 		if err == nil {
-			err = ec.WriteReplicaAndMeta(reb.t, lom, data, md, hdr.ObjAttrs.CksumType, hdr.ObjAttrs.CksumValue)
+			err = ec.WriteReplicaAndMeta(reb.t, lom, data, req.md.Marshal(), hdr.ObjAttrs.CksumType, hdr.ObjAttrs.CksumValue)
 		}
 	}
 	return err
@@ -829,6 +841,8 @@ func (reb *Manager) walkEC(fqn string, de fs.DirEntry) (err error) {
 		SliceID:      int16(md.SliceID),
 		DataSlices:   int16(md.Data),
 		ParitySlices: int16(md.Parity),
+		Codec:        md.Codec,
+		LocalGroups:  int16(md.LocalGroups),
 		realFQN:      fileFQN,
 		hrwFQN:       hrwFQN,
 		meta:         md,
@@ -1047,6 +1061,20 @@ func (reb *Manager) calcLocalProps(bck *cluster.Bck, obj *rebObject, smap *clust
 	}
 	ctFound := obj.foundCT()
 	obj.hasAllSlices = ctCnt >= obj.dataSlices+obj.paritySlices
+	if !obj.isECCopy && !obj.fullObjFound {
+		// all targets calculate the same set, so only the slices from
+		// the set are sent to the main target
+		md := &ec.Metadata{
+			Data:        int(obj.dataSlices),
+			Parity:      int(obj.paritySlices),
+			Codec:       mainSlice.Codec,
+			LocalGroups: int(mainSlice.LocalGroups),
+		}
+		if obj.toRead, err = ec.SlicesToRestore(md, obj.ctExist[1:]); err != nil {
+			glog.Warningf("%s: %v", obj.uid, err)
+			obj.toRead, err = nil, nil
+		}
+	}
 
 	genCount := cmn.Max(ctReq, len(smap.Tmap))
 	obj.hrwTargets, err = cluster.HrwTargetList(bck.MakeUname(obj.objName), smap, genCount)
@@ -1138,6 +1166,11 @@ func (reb *Manager) shouldSendSlice(obj *rebObject) (hasSlice, shouldSend bool)
 	tgtIndex := reb.targetIndex(reb.t.Snode().ID(), obj)
 	shouldSend = tgtIndex >= 0 && tgtIndex < int(obj.dataSlices)
 	hasSlice = obj.hasCT && !obj.isMain && !obj.isECCopy && !obj.fullObjFound
+	if hasSlice && obj.toRead != nil {
+		// send only the slices the main target needs to rebuild the object
+		id := obj.locCT[reb.t.Snode().ID()].SliceID
+		shouldSend = id > 0 && obj.toRead[id-1]
+	}
 	if hasSlice && (bool(glog.FastV(4, glog.SmoduleReb))) {
 		locSlice := obj.locCT[reb.t.Snode().ID()]
 		glog.Infof("should send: %s[%d - %d] - %d : %v / %v", obj.uid, locSlice.SliceID, obj.sliceSize, tgtIndex,
@@ -1284,18 +1317,38 @@ func (reb *Manager) reRequestObj(md *rebArgs, obj *rebObject) error {
 	if obj.ready.Load() == objDone {
 		return nil
 	}
+	if obj.toRead != nil {
+		reb.requestCTs(md, obj)
+		if obj.ready.Load() != objWaiting {
+			return nil
+		}
+		// some of the required slices are unavailable - fetch all the others
+		obj.toRead = nil
+	}
+	reb.requestCTs(md, obj)
+	if obj.ready.Load() == objWaiting {
+		return fmt.Errorf("failed to restore %s/%s - insufficient number of slices", obj.bck, obj.objName)
+	}
+	return nil
+}
 
+// Fetches the slices that have not been received yet: only required ones
+// if `obj.toRead` is set, all otherwise
+func (reb *Manager) requestCTs(md *rebArgs, obj *rebObject) {
 	// detect received
 	toRequest := make(map[string]*sliceGetResp, len(obj.locCT))
 	for daemonID, rec := range obj.locCT {
 		if rec.sgl != nil {
 			continue
 		}
+		if obj.toRead != nil && (rec.SliceID == 0 || !obj.toRead[rec.SliceID-1]) {
+			continue
+		}
 		rec.sgl = reb.t.MMSA().NewSGL(cmn.MinI64(obj.objSize, cmn.MiB))
 		toRequest[daemonID] = &sliceGetResp{sliceID: rec.SliceID, sgl: rec.sgl}
 	}
 	if len(toRequest) == 0 {
-		return nil
+		return
 	}
 
 	// receive all missing slices
@@ -1323,11 +1376,6 @@ func (reb *Manager) reRequestObj(md *rebArgs, obj *rebObject) error {
 	// update object status: e.g, mark an object ready to rebuild if it
 	// received enough slice to do it
 	reb.updateRebuildInfo(obj)
-	state := obj.ready.Load()
-	if state == objWaiting {
-		return fmt.Errorf("failed to restore %s/%s - insufficient number of slices", obj.bck, obj.objName)
-	}
-	return nil
 }
 
 // When the target finishes the current batch, it checks if it has to
@@ -1662,14 +1710,15 @@ func (reb *Manager) rebuildFromSlices(obj *rebObject, conf *cmn.CksumConf) (err
 
 	ecMD := meta.Clone()
 	for i, rd := range readers {
-		if rd != nil {
+		// restore data slices (to save the object) and the slices that do not exist
+		if rd != nil || (i >= int(obj.dataSlices) && obj.ctExist[i+1]) {
 			continue
 		}
 		obj.rebuildSGLs[i] = reb.t.MMSA().NewSGL(cmn.MinI64(obj.sliceSize, cmn.MiB))
 		writers[i] = obj.rebuildSGLs[i]
 	}
 
-	stream, err := reedsolomon.NewStreamC(int(obj.dataSlices), int(obj.paritySlices), true, true)
+	stream, err := ec.NewCodec(meta)
 	if err != nil {
 		return fmt.Errorf("failed to create initialize EC for %q: %v", obj.objName, err)
 	}
@@ -1734,6 +1783,8 @@ func (reb *Manager) rebuildFromSlices(obj *rebObject, conf *cmn.CksumConf) (err
 			SliceID:      int16(sliceID),
 			DataSlices:   int16(ecMD.Data),
 			ParitySlices: int16(ecMD.Parity),
+			Codec:        ecMD.Codec,
+			LocalGroups:  int16(ecMD.LocalGroups),
 			meta:         sliceMD,
 		}
 
@@ -1874,8 +1925,16 @@ func (reb *Manager) updateRebuildInfo(obj *rebObject) {
 	} else if obj.isMain && obj.isECCopy && cnt != 0 {
 		obj.ready.Store(objReceived)
 		// TODO: add to ZIL  missing replicas
+	} else if obj.toRead != nil {
+		// if it is main target, it needs all the slices required to rebuild
+		for _, ct := range obj.locCT {
+			if ct.SliceID > 0 && obj.toRead[ct.SliceID-1] && (ct.sgl == nil || ct.sgl.Size() == 0) {
+				return
+			}
+		}
+		obj.ready.CAS(objWaiting, objReceived)
 	} else if cnt >= int(obj.dataSlices) {
-		// if it is main target, it needs dataSlices slices to rebuild
+		// otherwise, any dataSlices slices
 		obj.ready.CAS(objWaiting, objReceived)
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"path/filepath"
 	"runtime"
 	"sync"
@@ -523,22 +524,36 @@ func (rj *rebalanceJogger) jog(mpathInfo *fs.MountpathInfo) {
 	// sure that `Done` is called even if the jogger crashes to avoid hang up
 	defer rj.wg.Done()
 
-	opts := &fs.Options{
-		Mpath:    mpathInfo,
-		CTs:      []string{fs.ObjectType},
-		Callback: rj.walk,
-		Sorted:   false,
-	}
+	var (
+		opts = &fs.Options{
+			Mpath:    mpathInfo,
+			CTs:      []string{fs.ObjectType},
+			Callback: rj.walk,
+			Sorted:   false,
+		}
+		// prior versions of objects (ais buckets only)
+		vopts = &fs.Options{
+			Mpath:    mpathInfo,
+			CTs:      []string{fs.VersionType},
+			Callback: rj.walkVersion,
+			Sorted:   false,
+		}
+	)
 	rj.m.t.Bowner().Get().Range(nil, nil, func(bck *cluster.Bck) bool {
-		opts.ErrCallback = nil
-		opts.Bck = bck.Bck
-		if err := fs.Walk(opts); err != nil {
-			if rj.xreb.Aborted() {
-				glog.Infof("aborting traversal")
-			} else {
-				glog.Errorf("%s: failed to traverse, err: %v", rj.m.t.Snode(), err)
+		for _, o := range []*fs.Options{opts, vopts} {
+			if o == vopts && !bck.IsAIS() {
+				continue
+			}
+			o.ErrCallback = nil
+			o.Bck = bck.Bck
+			if err := fs.Walk(o); err != nil {
+				if rj.xreb.Aborted() {
+					glog.Infof("aborting traversal")
+				} else {
+					glog.Errorf("%s: failed to traverse, err: %v", rj.m.t.Snode(), err)
+				}
+				return true
 			}
-			return true
 		}
 		return rj.m.xact().Aborted()
 	})
@@ -632,9 +647,127 @@ func (rj *rebalanceJogger) walk(fqn string, de fs.DirEntry) (err error) {
 	return
 }
 
+// Sends prior versions of objects that belong to other targets. Unlike objects,
+// versions are not tracked for retransmission: a version gets removed once
+// acknowledged by the receiver (see recvVersionAck), otherwise it stays and is
+// sent by the next rebalance.
+func (rj *rebalanceJogger) walkVersion(fqn string, de fs.DirEntry) (err error) {
+	var (
+		tsi *cluster.Snode
+		t   = rj.m.t
+	)
+	if rj.xreb.Aborted() || rj.xreb.Finished() {
+		return cmn.NewAbortedErrorDetails("traversal", rj.xreb.String())
+	}
+	if de.IsDir() {
+		return nil
+	}
+	parsedFQN, err := fs.ParseFQN(fqn)
+	if err != nil {
+		return nil
+	}
+	objName, version, ok := fs.ParseVersionName(parsedFQN.ObjName)
+	if !ok {
+		return nil
+	}
+	lom := &cluster.LOM{T: t, FQN: parsedFQN.MpathInfo.MakePathFQN(parsedFQN.Bck, fs.ObjectType, objName)}
+	if err = lom.Init(cmn.Bck{}); err != nil {
+		if cmn.IsErrBucketLevel(err) {
+			return err
+		}
+		if glog.FastV(4, glog.SmoduleReb) {
+			glog.Warningf("%s, err %s - skipping...", lom, err)
+		}
+		return nil
+	}
+	tsi, err = cluster.HrwTarget(lom.Uname(), rj.smap)
+	if err != nil {
+		return err
+	}
+	if tsi.ID() == t.Snode().ID() {
+		return nil
+	}
+	if rj.sema == nil { // rebalance.multiplier == 1
+		err = rj.sendVersion(lom, version, tsi)
+	} else { // // rebalance.multiplier > 1
+		rj.sema.Acquire()
+		go func() {
+			defer rj.sema.Release()
+			if err := rj.sendVersion(lom, version, tsi); err != nil {
+				glog.Error(err)
+			}
+		}()
+	}
+	return
+}
+
+func (rj *rebalanceJogger) sendVersion(lom *cluster.LOM, version string, tsi *cluster.Snode) (err error) {
+	var (
+		vlom                  *cluster.LOM
+		file                  cmn.ReadAtOpenCloser
+		finfo                 os.FileInfo
+		cksumType, cksumValue string
+	)
+	lom.Lock(false) // NOTE: unlock in objSentCallback() unless err
+	defer func() {
+		if err == nil {
+			return
+		}
+		lom.Unlock(false)
+		if glog.FastV(4, glog.SmoduleReb) {
+			glog.Errorf("%s version %s, err: %v", lom, version, err)
+		}
+	}()
+
+	if vlom, err = lom.LoadVersion(version); err != nil {
+		if cmn.IsObjNotExist(err) { // pruned or deleted in the meantime
+			lom.Unlock(false)
+			err = nil
+		}
+		return
+	}
+	if finfo, err = os.Stat(vlom.FQN); err != nil {
+		return
+	}
+	if cksum := vlom.Cksum(); cksum != nil {
+		cksumType, cksumValue = cksum.Get()
+	}
+	if file, err = vlom.Open(); err != nil {
+		return
+	}
+	// transmit
+	var (
+		ack    = regularAck{rebID: rj.m.RebID(), daemonID: rj.m.t.Snode().ID()}
+		mm     = rj.m.t.SmallMMSA()
+		opaque = ack.NewPack(mm, rebMsgVersion)
+		hdr    = transport.ObjHdr{
+			Bck:     lom.Bck().Bck,
+			ObjName: lom.ObjName,
+			Opaque:  opaque,
+			ObjAttrs: transport.ObjectAttrs{
+				Size:       vlom.Size(),
+				Atime:      finfo.ModTime().UnixNano(), // time the version was archived
+				CksumType:  cksumType,
+				CksumValue: cksumValue,
+				Version:    version,
+			},
+		}
+		o = &transport.Obj{Hdr: hdr, Callback: rj.objSentCallback, CmplPtr: unsafe.Pointer(lom)}
+	)
+
+	rj.m.inQueue.Inc()
+	if err = rj.m.dm.Send(o, file, tsi); err != nil {
+		rj.m.inQueue.Dec()
+		mm.Free(opaque)
+		return
+	}
+	rj.m.laterx.Store(true)
+	return
+}
+
 func (rj *rebalanceJogger) send(lom *cluster.LOM, tsi *cluster.Snode, addAck bool) (err error) {
 	var (
-		file                  *cmn.FileHandle
+		file                  cmn.ReadAtOpenCloser
 		cksum                 *cmn.Cksum
 		cksumType, cksumValue string
 	)
@@ -663,7 +796,7 @@ func (rj *rebalanceJogger) send(lom *cluster.LOM, tsi *cluster.Snode, addAck boo
 		return
 	}
 	cksumType, cksumValue = cksum.Get()
-	if file, err = cmn.NewFileHandle(lom.FQN); err != nil {
+	if file, err = lom.Open(); err != nil {
 		return
 	}
 	if addAck {
@@ -674,7 +807,7 @@ func (rj *rebalanceJogger) send(lom *cluster.LOM, tsi *cluster.Snode, addAck boo
 	var (
 		ack    = regularAck{rebID: rj.m.RebID(), daemonID: rj.m.t.Snode().ID()}
 		mm     = rj.m.t.SmallMMSA()
-		opaque = ack.NewPack(mm)
+		opaque = ack.NewPack(mm, rebMsgRegular)
 		hdr    = transport.ObjHdr{
 			Bck:     lom.Bck().Bck,
 			ObjName: lom.ObjName,
@@ -271,7 +271,70 @@ func (reb *Manager) recvObjRegular(hdr transport.ObjHdr, smap *cluster.Smap, unp
 		stats.NamedVal64{Name: stats.RebRxCount, Value: 1},
 		stats.NamedVal64{Name: stats.RebRxSize, Value: hdr.ObjAttrs.Size},
 	)
-	// ACK
+	reb.ackRegular(hdr, smap, tsid, rebMsgRegular)
+}
+
+// Receives a prior version of an object (see rebalanceJogger.walkVersion)
+// and saves it into the object's history.
+func (reb *Manager) recvVersion(hdr transport.ObjHdr, smap *cluster.Smap, unpacker *cmn.ByteUnpack, objReader io.Reader) {
+	defer cmn.DrainReader(objReader)
+
+	ack := &regularAck{}
+	if err := unpacker.ReadAny(ack); err != nil {
+		glog.Errorf("Failed to parse acknowledgement: %v", err)
+		return
+	}
+	if ack.rebID != reb.RebID() {
+		glog.Warningf("received version %s/%s: %s", hdr.Bck, hdr.ObjName, reb.rebIDMismatchMsg(ack.rebID))
+		return
+	}
+	tsid := ack.daemonID // the sender
+	lom := &cluster.LOM{T: reb.t, ObjName: hdr.ObjName}
+	if err := lom.Init(hdr.Bck); err != nil {
+		glog.Error(err)
+		return
+	}
+	marked := xreg.GetRebMarked()
+	if marked.Interrupted || marked.Xact == nil {
+		return
+	}
+	lom.SetVersion(hdr.ObjAttrs.Version)
+
+	workFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileVersion)
+	params := cluster.PutObjectParams{
+		Reader:   ioutil.NopCloser(objReader),
+		WorkFQN:  workFQN,
+		RecvType: cluster.Migrated,
+		Cksum:    cmn.NewCksum(hdr.ObjAttrs.CksumType, hdr.ObjAttrs.CksumValue),
+		Started:  time.Now(),
+	}
+	if err := reb.t.PutObject(lom, params); err != nil {
+		glog.Error(err)
+		return
+	}
+	lom.Lock(true)
+	err := lom.SaveVersion(workFQN, time.Unix(0, hdr.ObjAttrs.Atime))
+	lom.Unlock(true)
+	if err != nil {
+		glog.Errorf("%s: failed to save version %s, err: %v", lom, hdr.ObjAttrs.Version, err)
+		if errRm := cmn.RemoveFile(workFQN); errRm != nil {
+			glog.Errorf("nested error: %v", errRm)
+		}
+		return
+	}
+
+	if glog.FastV(5, glog.SmoduleReb) {
+		glog.Infof("%s: from %s %s version %s", reb.t.Snode(), tsid, lom, hdr.ObjAttrs.Version)
+	}
+	reb.statTracker.AddMany(
+		stats.NamedVal64{Name: stats.RebRxCount, Value: 1},
+		stats.NamedVal64{Name: stats.RebRxSize, Value: hdr.ObjAttrs.Size},
+	)
+	reb.ackRegular(hdr, smap, tsid, rebMsgVersion)
+}
+
+// ACK (kind is either rebMsgRegular or rebMsgVersion)
+func (reb *Manager) ackRegular(hdr transport.ObjHdr, smap *cluster.Smap, tsid string, kind byte) {
 	tsi := smap.GetTarget(tsid)
 	if tsi == nil {
 		glog.Errorf("%s target is not found in smap", tsid)
@@ -282,7 +345,7 @@ func (reb *Manager) recvObjRegular(hdr transport.ObjHdr, smap *cluster.Smap, unp
 			ack = &regularAck{rebID: reb.RebID(), daemonID: reb.t.Snode().ID()}
 			mm  = reb.t.SmallMMSA()
 		)
-		hdr.Opaque = ack.NewPack(mm)
+		hdr.Opaque = ack.NewPack(mm, kind)
 		hdr.ObjAttrs.Size = 0
 		if err := reb.dm.ACK(hdr, reb.rackSentCallback, tsi); err != nil {
 			mm.Free(hdr.Opaque)
@@ -345,6 +408,10 @@ func (reb *Manager) recvObj(w http.ResponseWriter, hdr transport.ObjHdr, objRead
 		reb.recvObjRegular(hdr, smap, unpacker, objReader)
 		return
 	}
+	if act == rebMsgVersion {
+		reb.recvVersion(hdr, smap, unpacker, objReader)
+		return
+	}
 
 	if act != rebMsgEC {
 		glog.Errorf("Invalid ACK type %d, expected %d", act, rebMsgEC)
@@ -461,6 +528,39 @@ func (reb *Manager) recvRegularAck(hdr transport.ObjHdr, unpacker *cmn.ByteUnpac
 	lom.Unlock(true)
 }
 
+// Removes the local copy of the prior version once the receiver has saved it.
+func (reb *Manager) recvVersionAck(hdr transport.ObjHdr, unpacker *cmn.ByteUnpack) {
+	ack := &regularAck{}
+	if err := unpacker.ReadAny(ack); err != nil {
+		glog.Errorf("Failed to parse acknowledge: %v", err)
+		return
+	}
+	if ack.rebID != reb.rebID.Load() {
+		glog.Warningf("ACK from %s: %s", ack.daemonID, reb.rebIDMismatchMsg(ack.rebID))
+		return
+	}
+	mpathInfos, _ := fs.Get()
+	lom := &cluster.LOM{T: reb.t, ObjName: hdr.ObjName}
+	if err := lom.Init(hdr.Bck); err != nil {
+		glog.Error(err)
+		return
+	}
+	// the version may reside on a mountpath other than the object's (resilver pending)
+	lom.Lock(true)
+	for _, mpathInfo := range mpathInfos {
+		vlom := &cluster.LOM{T: reb.t, FQN: mpathInfo.MakePathFQN(hdr.Bck, fs.ObjectType, hdr.ObjName)}
+		if err := vlom.Init(hdr.Bck); err != nil {
+			continue
+		}
+		if err := vlom.DelVersion(hdr.ObjAttrs.Version); err == nil {
+			break
+		} else if !cmn.IsObjNotExist(err) {
+			glog.Errorf("%s: error removing version %s, err: %v", reb.t.Snode(), hdr.ObjAttrs.Version, err)
+		}
+	}
+	lom.Unlock(true)
+}
+
 func (reb *Manager) recvAck(w http.ResponseWriter, hdr transport.ObjHdr, _ io.Reader, err error) {
 	if err != nil {
 		glog.Error(err)
@@ -478,6 +578,10 @@ func (reb *Manager) recvAck(w http.ResponseWriter, hdr transport.ObjHdr, _ io.Re
 		reb.recvECAck(hdr, unpacker)
 		return
 	}
+	if act == rebMsgVersion {
+		reb.recvVersionAck(hdr, unpacker)
+		return
+	}
 	if act != rebMsgRegular {
 		glog.Errorf("Invalid ACK type %d, expected %d", act, rebMsgRegular)
 	}
@@ -17,6 +17,7 @@ const (
 	rebMsgRegular   = iota // regular rebalance: acknowledge/Object
 	rebMsgEC               // EC rebalance: acknowledge/CT/Namespace
 	rebMsgPushStage        // push notification of target moved to the next stage
+	rebMsgVersion          // prior version of an object: acknowledge/Version
 )
 const rebMsgKindSize = 1
 
@@ -64,11 +65,12 @@ func (rack *regularAck) Pack(packer *cmn.BytePack) {
 	packer.WriteString(rack.daemonID)
 }
 
-func (rack *regularAck) NewPack(mm *memsys.MMSA) []byte { // TODO: consider adding as another cmn.Packer interface
+// kind is either rebMsgRegular or rebMsgVersion
+func (rack *regularAck) NewPack(mm *memsys.MMSA, kind byte) []byte { // TODO: consider adding as another cmn.Packer interface
 	l := rebMsgKindSize + rack.PackedSize()
 	buf, _ := mm.Alloc(int64(l))
 	packer := cmn.NewPacker(buf, l)
-	packer.WriteByte(rebMsgRegular)
+	packer.WriteByte(kind)
 	packer.WriteAny(rack)
 	return packer.Bytes()
 }
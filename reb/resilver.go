@@ -23,6 +23,7 @@ import (
 type (
 	joggerCtx struct {
 		xact cluster.Xact
+		t    cluster.Target
 	}
 )
 
@@ -52,10 +53,10 @@ func (reb *Manager) RunResilver(id string, skipGlobMisplaced bool, notifs ...*xa
 	slab, err := reb.t.MMSA().GetSlab(memsys.MaxPageSlabSize)
 	cmn.AssertNoErr(err)
 
-	jctx := &joggerCtx{xact: xact}
+	jctx := &joggerCtx{xact: xact, t: reb.t}
 	jg := mpather.NewJoggerGroup(&mpather.JoggerGroupOpts{
 		T:                     reb.t,
-		CTs:                   []string{fs.ObjectType, ec.SliceType},
+		CTs:                   []string{fs.ObjectType, ec.SliceType, fs.VersionType},
 		VisitObj:              jctx.visitObj,
 		VisitCT:               jctx.visitCT,
 		Slab:                  slab,
@@ -202,7 +203,73 @@ func (rj *joggerCtx) visitObj(lom *cluster.LOM, buf []byte) (err error) {
 	return nil
 }
 
+// Moves a prior version of an object to the object's (HRW) mountpath.
+func (rj *joggerCtx) moveVersion(ct *cluster.CT, buf []byte) {
+	objName, version, ok := fs.ParseVersionName(ct.ObjName())
+	if !ok {
+		return
+	}
+	lom := &cluster.LOM{T: rj.t, ObjName: objName}
+	if err := lom.Init(ct.Bck().Bck); err != nil {
+		glog.Warning(err)
+		return
+	}
+	srcMpath := ct.ParsedFQN().MpathInfo
+	if lom.ParsedFQN.MpathInfo.Path == srcMpath.Path {
+		return
+	}
+	src := &cluster.LOM{T: rj.t, FQN: srcMpath.MakePathFQN(ct.Bck().Bck, fs.ObjectType, objName)}
+	if err := src.Init(ct.Bck().Bck); err != nil {
+		glog.Warning(err)
+		return
+	}
+
+	lom.Lock(true)
+	defer lom.Unlock(true)
+
+	vlom, err := src.LoadVersion(version)
+	if err != nil {
+		if !cmn.IsObjNotExist(err) {
+			glog.Errorf("%s: failed to load version %s: %v", src, version, err)
+		}
+		return
+	}
+	finfo, err := os.Stat(vlom.FQN)
+	if err != nil {
+		glog.Errorf("%s: failed to stat version %s: %v", src, version, err)
+		return
+	}
+	if glog.FastV(4, glog.SmoduleReb) {
+		glog.Infof("Resilver moving %q -> %q", vlom.FQN, lom.VersionFQN(version))
+	}
+	workFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileVersion)
+	if _, _, err = cmn.CopyFile(vlom.FQN, workFQN, buf, cmn.ChecksumNone); err != nil {
+		glog.Errorf("Failed to copy %q -> %q: %v", vlom.FQN, workFQN, err)
+		if errRm := cmn.RemoveFile(workFQN); errRm != nil {
+			glog.Warningf("Failed to cleanup %q: %v", workFQN, errRm)
+		}
+		return
+	}
+	lom.CopyMetadata(vlom)
+	if err = lom.SaveVersion(workFQN, finfo.ModTime()); err != nil {
+		glog.Errorf("%s: failed to save version %s: %v", lom, version, err)
+		if errRm := cmn.RemoveFile(workFQN); errRm != nil {
+			glog.Warningf("Failed to cleanup %q: %v", workFQN, errRm)
+		}
+		return
+	}
+	if err = src.DelVersion(version); err != nil {
+		glog.Warningf("Failed to cleanup %q: %v", vlom.FQN, err)
+	}
+	rj.xact.BytesAdd(vlom.Size())
+	rj.xact.ObjectsInc()
+}
+
 func (rj *joggerCtx) visitCT(ct *cluster.CT, buf []byte) (err error) {
+	if ct.ContentType() == fs.VersionType {
+		rj.moveVersion(ct, buf)
+		return nil
+	}
 	cmn.Assert(ct.ContentType() == ec.SliceType)
 	if !ct.Bprops().EC.Enabled {
 		// Since `%ec` directory is inside a bucket, it is safe to skip
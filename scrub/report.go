@@ -0,0 +1,104 @@
+// Package scrub re-hashes stored objects to detect silent data corruption
+// (bit rot) and repairs the corrupted ones from redundant copies.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package scrub
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/jsp"
+)
+
+// The report of the last scrub run is stored in the target's config directory
+// and survives restarts. It contains the totals and the list of unrecoverable
+// objects (up to maxReportObjs).
+
+const (
+	reportFname   = ".ais.scrub"
+	maxReportObjs = 1000
+)
+
+type (
+	Report struct {
+		XactID        string      `json:"xaction_id"`
+		Started       time.Time   `json:"started"`
+		Finished      time.Time   `json:"finished"`
+		Aborted       bool        `json:"aborted"`
+		Checked       int64       `json:"checked,string"`       // objects and copies re-hashed
+		Bytes         int64       `json:"bytes,string"`         // bytes re-hashed
+		Corrupted     int64       `json:"corrupted,string"`     // objects and copies with bad checksum
+		Repaired      int64       `json:"repaired,string"`      // objects and copies repaired
+		Unrecoverable int64       `json:"unrecoverable,string"` // objects and copies that failed to be repaired
+		Objects       []ObjReport `json:"objects"`              // unrecoverable objects (truncated to maxReportObjs)
+	}
+	ObjReport struct {
+		Bck     cmn.Bck   `json:"bck"`
+		ObjName string    `json:"obj_name"`
+		FQN     string    `json:"fqn"`
+		Err     string    `json:"error"`
+		Time    time.Time `json:"time"`
+	}
+)
+
+var last struct {
+	sync.Mutex
+	report *Report
+	loaded bool
+}
+
+func (r *Xaction) report() *Report {
+	r.mu.Lock()
+	objs := r.lost
+	r.mu.Unlock()
+	if objs == nil {
+		objs = []ObjReport{}
+	}
+	return &Report{
+		XactID:        r.ID().String(),
+		Started:       r.StartTime(),
+		Finished:      time.Now(),
+		Aborted:       r.Aborted(),
+		Checked:       r.ObjCount(),
+		Bytes:         r.BytesCount(),
+		Corrupted:     r.stats.corrupted.Load(),
+		Repaired:      r.stats.repaired.Load(),
+		Unrecoverable: r.stats.unrecoverable.Load(),
+		Objects:       objs,
+	}
+}
+
+func reportPath() string { return filepath.Join(cmn.GCO.Get().Confdir, reportFname) }
+
+func saveReport(report *Report) error {
+	last.Lock()
+	defer last.Unlock()
+	if err := jsp.Save(reportPath(), report, jsp.Plain()); err != nil {
+		return err
+	}
+	last.report, last.loaded = report, true
+	return nil
+}
+
+// LastReport returns the report of the last (finished or aborted) scrub run
+// on this target; nil if the target has never been scrubbed.
+func LastReport() *Report {
+	last.Lock()
+	defer last.Unlock()
+	if !last.loaded {
+		report := &Report{}
+		if _, err := jsp.Load(reportPath(), report, jsp.Plain()); err == nil {
+			last.report = report
+		} else if !os.IsNotExist(err) {
+			glog.Errorf("failed to load scrub report, err: %v", err)
+		}
+		last.loaded = true
+	}
+	return last.report
+}
@@ -0,0 +1,418 @@
+// Package scrub re-hashes stored objects to detect silent data corruption
+// (bit rot) and repairs the corrupted ones from redundant copies.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package scrub
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/ec"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/xaction"
+	"github.com/NVIDIA/aistore/xaction/xreg"
+)
+
+// Checksum validation on GET (see `checksum.validate_warm_get`) catches
+// corruption only when an object is read. The scrub xaction, instead, walks
+// all buckets on all mountpaths (one jogger per mountpath) and recomputes the
+// checksum of every object and every mirror copy. The scrub runs periodically
+// (see `scrub.interval` and target's housekeeping) and can also be started via
+// the generic xaction API.
+//
+// The scrub must not interfere with the workload, so each jogger:
+//   - re-hashes at most `scrub.rate` bytes per second, and
+//   - slows down when the utilization of its mountpath exceeds the thresholds
+//     of the `disk` configuration section.
+//
+// A corrupted object is repaired from (in that order):
+//   1. an intact mirror copy of the object,
+//   2. EC slices or replicas (erasure coded buckets),
+//   3. the remote backend (Cloud buckets and ais buckets with backend).
+// Objects that cannot be repaired are logged and recorded in the scrub report
+// (see Report) that can be queried via API and CLI.
+
+const (
+	rateWindow = 10 * time.Second // period of time over which the rate is averaged
+)
+
+type (
+	InitScrub struct {
+		T       cluster.Target
+		Xaction *Xaction
+	}
+
+	// scrubJ is a single /jogger/ that traverses a given mountpath and
+	// re-hashes all objects stored on it
+	scrubJ struct {
+		ini       *InitScrub
+		mpathInfo *fs.MountpathInfo
+		bck       *cluster.Bck
+		config    *cmn.Config
+		buf       []byte
+		// rate limiting
+		windowStart time.Time
+		windowBytes int64
+	}
+
+	// rateReader throttles the jogger as the object is being read
+	rateReader struct {
+		r io.Reader
+		j *scrubJ
+	}
+
+	XactProvider struct {
+		xreg.BaseGlobalEntry
+		xact *Xaction
+
+		id string
+	}
+
+	Xaction struct {
+		xaction.XactBase
+		stats struct {
+			corrupted     atomic.Int64
+			repaired      atomic.Int64
+			unrecoverable atomic.Int64
+		}
+		mu   sync.Mutex
+		lost []ObjReport // unrecoverable objects
+	}
+
+	TargetStats struct {
+		xaction.BaseXactStats
+		Ext ExtScrubStats `json:"ext"`
+	}
+
+	ExtScrubStats struct {
+		CorruptedCount     int64 `json:"scrub.corrupted.n,string"`     // objects and copies with bad checksum
+		RepairedCount      int64 `json:"scrub.repaired.n,string"`      // objects and copies repaired
+		UnrecoverableCount int64 `json:"scrub.unrecoverable.n,string"` // objects and copies that failed to be repaired
+	}
+)
+
+// interface guard
+var (
+	_ cluster.Xact      = &Xaction{}
+	_ cluster.XactStats = &TargetStats{}
+)
+
+func init() {
+	xreg.RegisterGlobalXact(&XactProvider{})
+}
+
+func (*XactProvider) New(args xreg.XactArgs) xreg.GlobalEntry {
+	return &XactProvider{id: args.UUID}
+}
+
+func (p *XactProvider) Start(_ cmn.Bck) error {
+	p.xact = &Xaction{XactBase: *xaction.NewXactBase(xaction.XactBaseID(p.id), cmn.ActScrub)}
+	return nil
+}
+func (*XactProvider) Kind() string        { return cmn.ActScrub }
+func (p *XactProvider) Get() cluster.Xact { return p.xact }
+
+// keep the one that's already running
+func (*XactProvider) PreRenewHook(_ xreg.GlobalEntry) bool { return true }
+
+func (r *Xaction) Run() error { cmn.Assert(false); return nil }
+
+func (r *Xaction) Stats() cluster.XactStats {
+	baseStats := r.XactBase.Stats().(*xaction.BaseXactStats)
+	stats := TargetStats{BaseXactStats: *baseStats}
+	stats.Ext.CorruptedCount = r.stats.corrupted.Load()
+	stats.Ext.RepairedCount = r.stats.repaired.Load()
+	stats.Ext.UnrecoverableCount = r.stats.unrecoverable.Load()
+	return &stats
+}
+
+func (r *Xaction) addLost(lom *cluster.LOM, err error) {
+	r.stats.unrecoverable.Inc()
+	r.mu.Lock()
+	if len(r.lost) < maxReportObjs {
+		r.lost = append(r.lost, ObjReport{
+			Bck:     lom.Bck().Bck,
+			ObjName: lom.ObjName,
+			FQN:     lom.FQN,
+			Err:     err.Error(),
+			Time:    time.Now(),
+		})
+	}
+	r.mu.Unlock()
+}
+
+// Run re-hashes all objects stored on the target; blocks until done.
+func Run(ini *InitScrub) {
+	var (
+		wg                sync.WaitGroup
+		bcks              = make([]*cluster.Bck, 0, 8)
+		config            = cmn.GCO.Get()
+		availablePaths, _ = fs.Get()
+	)
+	ini.T.Bowner().Get().Range(nil, nil, func(bck *cluster.Bck) bool {
+		bcks = append(bcks, bck)
+		return false
+	})
+	glog.Infof("%s: %s started: %d bucket(s), rate %s/s per mountpath", ini.T.Snode(), ini.Xaction, len(bcks),
+		cmn.B2S(config.Scrub.Rate, 0))
+	for _, mpathInfo := range availablePaths {
+		j := &scrubJ{ini: ini, mpathInfo: mpathInfo, config: config}
+		wg.Add(1)
+		go func(j *scrubJ) {
+			defer wg.Done()
+			buf, slab := j.ini.T.MMSA().Alloc()
+			j.buf = buf
+			defer slab.Free(buf)
+			for _, bck := range bcks {
+				if err := j.jogBck(bck); err != nil {
+					if ini.Xaction.Aborted() {
+						return
+					}
+					if !os.IsNotExist(err) {
+						glog.Errorf("%s: failed to traverse %s, err: %v", j, bck, err)
+					}
+				}
+			}
+		}(j)
+	}
+	wg.Wait()
+
+	r := ini.Xaction
+	glog.Infof("%s: %s finished: checked %d (%s), corrupted %d, repaired %d, unrecoverable %d",
+		ini.T.Snode(), r, r.ObjCount(), cmn.B2S(r.BytesCount(), 2), r.stats.corrupted.Load(),
+		r.stats.repaired.Load(), r.stats.unrecoverable.Load())
+	if err := saveReport(r.report()); err != nil {
+		glog.Errorf("%s: failed to save %s report, err: %v", ini.T.Snode(), r, err)
+	}
+}
+
+////////////
+// scrubJ //
+////////////
+
+func (j *scrubJ) String() string {
+	return fmt.Sprintf("%s: (%s, %s)", j.ini.T.Snode(), j.ini.Xaction, j.mpathInfo)
+}
+
+func (j *scrubJ) jogBck(bck *cluster.Bck) error {
+	j.bck = bck
+	opts := &fs.Options{
+		Mpath:    j.mpathInfo,
+		Bck:      bck.Bck,
+		CTs:      []string{fs.ObjectType},
+		Callback: j.walk,
+		Sorted:   false,
+	}
+	return fs.Walk(opts)
+}
+
+func (j *scrubJ) walk(fqn string, de fs.DirEntry) error {
+	if de.IsDir() {
+		return nil
+	}
+	if j.ini.Xaction.Aborted() {
+		return cmn.NewAbortedError(j.String())
+	}
+	lom := &cluster.LOM{T: j.ini.T, FQN: fqn}
+	if err := lom.Init(j.bck.Bck, j.config); err != nil {
+		return nil
+	}
+	if err := lom.Load(false); err != nil {
+		return nil
+	}
+	// misplaced objects are for rebalance and resilver to take care of
+	if !lom.IsHRW() && !lom.IsCopy() {
+		return nil
+	}
+	if cksum := lom.Cksum(); cksum == nil || cksum.Type() == cmn.ChecksumNone {
+		return nil
+	}
+	j.throttleUtil()
+	cksum, err := j.hash(lom, fqn, true /*throttle*/)
+	if err != nil {
+		if !os.IsNotExist(err) { // (removed or moved in the meantime)
+			glog.Errorf("%s: failed to re-hash %s, err: %v", j, lom, err)
+		}
+		return nil
+	}
+	j.ini.Xaction.ObjectsInc()
+	j.ini.Xaction.BytesAdd(lom.Size())
+	if !cksum.Equal(lom.Cksum()) {
+		j.corrupted(lom)
+	}
+	return nil
+}
+
+// hash recomputes the checksum of the given replica of the object
+func (j *scrubJ) hash(lom *cluster.LOM, fqn string, throttle bool) (*cmn.Cksum, error) {
+	fh, err := lom.OpenFQN(fqn)
+	if err != nil {
+		return nil, err
+	}
+	var r io.Reader = fh
+	if throttle {
+		r = &rateReader{r: fh, j: j}
+	}
+	_, cksum, err := cmn.CopyAndChecksum(ioutil.Discard, r, j.buf, lom.Cksum().Type())
+	cmn.Close(fh)
+	if err != nil {
+		return nil, err
+	}
+	return &cksum.Cksum, nil
+}
+
+// corrupted double-checks the object - it may have been overwritten while
+// being re-hashed - and repairs it
+func (j *scrubJ) corrupted(lom *cluster.LOM) {
+	lom.Lock(false)
+	err := lom.Load(false)
+	if err == nil {
+		var cksum *cmn.Cksum
+		if cksum, err = j.hash(lom, lom.FQN, false /*throttle*/); err == nil && !cksum.Equal(lom.Cksum()) {
+			err = cmn.NewBadDataCksumError(cksum, lom.Cksum(), lom.FQN)
+		}
+	}
+	lom.Unlock(false)
+	if _, ok := err.(*cmn.BadCksumError); !ok {
+		return
+	}
+	r := j.ini.Xaction
+	r.stats.corrupted.Inc()
+	glog.Errorf("%s: %v", j, err)
+
+	if err := j.repair(lom); err != nil {
+		glog.Errorf("%s: failed to repair %s, err: %v", j, lom, err)
+		r.addLost(lom, err)
+		return
+	}
+	r.stats.repaired.Inc()
+	glog.Infof("%s: repaired %s", j, lom)
+}
+
+func (j *scrubJ) repair(lom *cluster.LOM) error {
+	errs := make([]string, 0, 3)
+	if lom.HasCopies() {
+		err := j.fromCopy(lom)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, "mirror: "+err.Error())
+	}
+	// a copy gets repaired from the object itself - once the latter is
+	// repaired (next time around)
+	if lom.IsCopy() {
+		return fmt.Errorf("no intact copies (%s)", strings.Join(errs, "; "))
+	}
+	if lom.ECEnabled() {
+		err := j.fromEC(lom)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, "ec: "+err.Error())
+	}
+	if lom.Bck().IsRemote() {
+		err, _ := j.ini.T.GetCold(context.Background(), lom, true /*prefetch*/)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, "backend: "+err.Error())
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("no redundancy: no mirror copies, EC is disabled, no remote backend")
+	}
+	return fmt.Errorf("%s", strings.Join(errs, "; "))
+}
+
+// fromCopy overwrites the corrupted replica with an intact one
+func (j *scrubJ) fromCopy(lom *cluster.LOM) error {
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	for fqn := range lom.GetCopies() {
+		if fqn == lom.FQN {
+			continue
+		}
+		if cksum, err := j.hash(lom, fqn, false /*throttle*/); err != nil || !cksum.Equal(lom.Cksum()) {
+			continue
+		}
+		src := lom.Clone(fqn)
+		if err := src.Init(cmn.Bck{}, j.config); err != nil {
+			continue
+		}
+		if err := src.Load(false); err != nil {
+			continue
+		}
+		_, err := src.CopyObject(lom.FQN, j.buf)
+		return err
+	}
+	return fmt.Errorf("no intact copies")
+}
+
+// fromEC moves the corrupted object aside (and puts it back if EC fails)
+// and restores it from slices or replicas
+func (j *scrubJ) fromEC(lom *cluster.LOM) error {
+	workFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileScrub)
+	if err := cmn.Rename(lom.FQN, workFQN); err != nil {
+		return err
+	}
+	if err := ec.ECM.RestoreObject(lom); err != nil {
+		if errRename := cmn.Rename(workFQN, lom.FQN); errRename != nil {
+			glog.Errorf("%s: nested err: %v", j, errRename)
+		}
+		return err
+	}
+	if err := cmn.RemoveFile(workFQN); err != nil {
+		glog.Errorf("%s: nested err: %v", j, err)
+	}
+	return nil
+}
+
+// throttleUtil slows the jogger down depending on the mountpath utilization
+func (j *scrubJ) throttleUtil() {
+	var (
+		disk = &j.config.Disk
+		util = fs.GetMpathUtil(j.mpathInfo.Path)
+	)
+	switch {
+	case util >= disk.DiskUtilMaxWM:
+		time.Sleep(cmn.ThrottleMax)
+	case util >= disk.DiskUtilHighWM:
+		time.Sleep(cmn.ThrottleAvg)
+	case util > disk.DiskUtilLowWM:
+		time.Sleep(cmn.ThrottleMin)
+	}
+}
+
+// throttleRate sleeps for as long as the jogger is ahead of the configured rate
+func (j *scrubJ) throttleRate(n int) {
+	rate := j.config.Scrub.Rate
+	if rate <= 0 {
+		return
+	}
+	now := time.Now()
+	if now.Sub(j.windowStart) > rateWindow {
+		j.windowStart, j.windowBytes = now, 0
+	}
+	j.windowBytes += int64(n)
+	expected := time.Duration(float64(j.windowBytes) / float64(rate) * float64(time.Second))
+	if elapsed := now.Sub(j.windowStart); expected > elapsed {
+		time.Sleep(expected - elapsed)
+	}
+}
+
+func (r *rateReader) Read(b []byte) (n int, err error) {
+	n, err = r.r.Read(b)
+	r.j.throttleRate(n)
+	return
+}
@@ -0,0 +1,157 @@
+// Package scrub re-hashes stored objects to detect silent data corruption
+// (bit rot) and repairs the corrupted ones from redundant copies.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package scrub
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+	"github.com/NVIDIA/aistore/xaction"
+)
+
+const scrubTestObjSize = 64 * cmn.KiB
+
+func scrubTestInit(t *testing.T) (tMock cluster.Target, bck *cluster.Bck) {
+	dir, err := ioutil.TempDir("", "scrub-test")
+	tassert.CheckFatal(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	config := cmn.GCO.BeginUpdate()
+	config.TestFSP.Count = 1
+	config.Confdir = dir
+	config.Scrub.Rate = 0
+	config.Disk.DiskUtilLowWM, config.Disk.DiskUtilHighWM, config.Disk.DiskUtilMaxWM = 20, 80, 95
+	config.Disk.IostatTimeShort, config.Disk.IostatTimeLong = 100*time.Millisecond, 2*time.Second
+	cmn.GCO.CommitUpdate(config)
+
+	cluster.InitTarget()
+	fs.Init()
+	fs.DisableFsIDCheck()
+	for _, mpath := range []string{filepath.Join(dir, "mp1"), filepath.Join(dir, "mp2")} {
+		tassert.CheckFatal(t, cmn.CreateDir(mpath))
+		_, err := fs.Add(mpath, "daeID")
+		tassert.CheckFatal(t, err)
+	}
+	_ = fs.CSM.RegisterContentType(fs.ObjectType, &fs.ObjectContentResolver{})
+	_ = fs.CSM.RegisterContentType(fs.WorkfileType, &fs.WorkfileContentResolver{})
+
+	props := &cmn.BucketProps{
+		Cksum:  cmn.CksumConf{Type: cmn.ChecksumXXHash},
+		Mirror: cmn.MirrorConf{Enabled: true, Copies: 2},
+	}
+	bck = cluster.NewBck("scrub-bck", cmn.ProviderAIS, cmn.NsGlobal, props)
+	tMock = cluster.NewTargetMock(cluster.NewBaseBownerMock(bck))
+	return
+}
+
+// createObj creates the object at its default location and, optionally, its copy
+func createObj(t *testing.T, tMock cluster.Target, bck *cluster.Bck, objName string, withCopy bool) *cluster.LOM {
+	data := make([]byte, scrubTestObjSize)
+	rand.Read(data)
+	lom := &cluster.LOM{T: tMock, ObjName: objName}
+	tassert.CheckFatal(t, lom.Init(bck.Bck))
+	tassert.CheckFatal(t, cmn.CreateDir(filepath.Dir(lom.FQN)))
+	tassert.CheckFatal(t, ioutil.WriteFile(lom.FQN, data, 0o644))
+	cksum, err := cmn.ChecksumBytes(data, cmn.ChecksumXXHash)
+	tassert.CheckFatal(t, err)
+	lom.SetSize(scrubTestObjSize)
+	lom.SetCksum(cksum)
+	tassert.CheckFatal(t, lom.Persist())
+	if withCopy {
+		availablePaths, _ := fs.Get()
+		for _, mpathInfo := range availablePaths {
+			if mpathInfo.Path == lom.ParsedFQN.MpathInfo.Path {
+				continue
+			}
+			copyFQN := fs.CSM.FQN(mpathInfo, bck.Bck, fs.ObjectType, objName)
+			tassert.CheckFatal(t, cmn.CreateDir(filepath.Dir(copyFQN)))
+			_, err := lom.CopyObject(copyFQN, make([]byte, cmn.KiB))
+			tassert.CheckFatal(t, err)
+			break
+		}
+		tassert.Fatalf(t, lom.HasCopies(), "%s: expected copies", lom)
+	}
+	return lom
+}
+
+func corrupt(t *testing.T, fqn string) {
+	data, err := ioutil.ReadFile(fqn)
+	tassert.CheckFatal(t, err)
+	data[len(data)/2] ^= 0xff
+	tassert.CheckFatal(t, ioutil.WriteFile(fqn, data, 0o644))
+}
+
+func TestScrub(t *testing.T) {
+	tMock, bck := scrubTestInit(t)
+
+	var (
+		intact   = createObj(t, tMock, bck, "intact", true)
+		mirrored = createObj(t, tMock, bck, "mirrored", true)
+		single   = createObj(t, tMock, bck, "single", false)
+	)
+	intactData, err := ioutil.ReadFile(intact.FQN)
+	tassert.CheckFatal(t, err)
+	goodData, err := ioutil.ReadFile(mirrored.FQN)
+	tassert.CheckFatal(t, err)
+	corrupt(t, mirrored.FQN)
+	corrupt(t, single.FQN)
+
+	xact := &Xaction{XactBase: *xaction.NewXactBase(xaction.XactBaseID("scrub-test"), cmn.ActScrub)}
+	Run(&InitScrub{T: tMock, Xaction: xact})
+
+	// 3 objects + 2 copies
+	tassert.Errorf(t, xact.ObjCount() == 5, "expected 5 objects checked, got %d", xact.ObjCount())
+	stats := xact.Stats().(*TargetStats)
+	tassert.Errorf(t, stats.Ext.CorruptedCount == 2, "expected 2 corrupted, got %d", stats.Ext.CorruptedCount)
+	tassert.Errorf(t, stats.Ext.RepairedCount == 1, "expected 1 repaired, got %d", stats.Ext.RepairedCount)
+	tassert.Errorf(t, stats.Ext.UnrecoverableCount == 1, "expected 1 unrecoverable, got %d",
+		stats.Ext.UnrecoverableCount)
+
+	data, err := ioutil.ReadFile(mirrored.FQN)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bytes.Equal(data, goodData), "%s was not repaired from its copy", mirrored)
+	data, err = ioutil.ReadFile(intact.FQN)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bytes.Equal(data, intactData), "%s was modified", intact)
+
+	// the report must survive restarts
+	last.report, last.loaded = nil, false
+	report := LastReport()
+	tassert.Fatalf(t, report != nil, "expected scrub report")
+	tassert.Errorf(t, report.XactID == "scrub-test", "unexpected xaction ID %q", report.XactID)
+	tassert.Errorf(t, report.Corrupted == 2 && report.Repaired == 1 && report.Unrecoverable == 1,
+		"unexpected report totals: %+v", report)
+	tassert.Fatalf(t, len(report.Objects) == 1, "expected 1 unrecoverable object, got %d", len(report.Objects))
+	tassert.Errorf(t, report.Objects[0].ObjName == single.ObjName && report.Objects[0].FQN == single.FQN,
+		"unexpected unrecoverable object: %+v", report.Objects[0])
+}
+
+func TestScrubRate(t *testing.T) {
+	const (
+		rate = 512 * cmn.KiB
+		size = 256 * cmn.KiB
+	)
+	config := &cmn.Config{}
+	config.Scrub.Rate = rate
+	j := &scrubJ{config: config}
+	started := time.Now()
+	for n := 0; n < size; n += 32 * cmn.KiB {
+		j.throttleRate(32 * cmn.KiB)
+	}
+	elapsed := time.Since(started)
+	expected := time.Duration(size) * time.Second / rate
+	tassert.Errorf(t, elapsed >= expected*9/10, "expected rate %s/s, re-hashed %s in %v",
+		cmn.B2S(rate, 0), cmn.B2S(size, 0), elapsed)
+}
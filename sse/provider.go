@@ -0,0 +1,198 @@
+// Package sse provides server-side encryption (at rest) of object data.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package sse
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Encryption keys are identified by (and stored in the object metadata as)
+// key IDs. The keys themselves come from the key provider configured
+// cluster-wide (see cmn.KeyProviderConf):
+//   - keyfile - local JSON file that maps key IDs to base64 encoded keys;
+//               the file is re-read when a key is not found (to pick up new keys)
+//   - http    - KMS-style key service that returns `{"key": "<base64>"}`
+//               upon `GET <url>/<key_id>` (with optional bearer token)
+//
+// Keys must be 16, 24, or 32 bytes long and are cached in memory for the
+// lifetime of the provider (which gets rebuilt when its configuration changes).
+
+const httpKeyTimeout = 10 * time.Second
+
+type (
+	KeyProvider interface {
+		Key(id string) ([]byte, error)
+	}
+
+	fileProvider struct {
+		path string
+	}
+	httpProvider struct {
+		url    string
+		token  string
+		client *http.Client
+	}
+
+	keyCache struct {
+		mu   sync.RWMutex
+		conf cmn.KeyProviderConf
+		kp   KeyProvider
+		keys map[string][]byte
+	}
+)
+
+var (
+	ErrNoProvider = errors.New("key provider is not configured")
+
+	cache = &keyCache{}
+)
+
+// Key returns the (master) key by its ID using the configured key provider.
+func Key(id string) ([]byte, error) {
+	return cache.key(id, &cmn.GCO.Get().KeyProvider)
+}
+
+// NewKeyProvider creates a key provider as per configuration.
+func NewKeyProvider(conf *cmn.KeyProviderConf) (KeyProvider, error) {
+	switch conf.Type {
+	case cmn.KeyProviderFile:
+		return &fileProvider{path: conf.KeyFile}, nil
+	case cmn.KeyProviderHTTP:
+		client := cmn.NewClient(cmn.TransportArgs{Timeout: httpKeyTimeout})
+		return &httpProvider{url: strings.TrimSuffix(conf.URL, "/"), token: conf.Token, client: client}, nil
+	case "":
+		return nil, ErrNoProvider
+	default:
+		return nil, fmt.Errorf("unknown key provider %q", conf.Type)
+	}
+}
+
+//////////////
+// keyCache //
+//////////////
+
+func (c *keyCache) key(id string, conf *cmn.KeyProviderConf) ([]byte, error) {
+	c.mu.RLock()
+	if c.kp != nil && c.conf == *conf {
+		if key, ok := c.keys[id]; ok {
+			c.mu.RUnlock()
+			return key, nil
+		}
+	}
+	c.mu.RUnlock()
+
+	c.mu.Lock()
+	if c.kp == nil || c.conf != *conf {
+		kp, err := NewKeyProvider(conf)
+		if err != nil {
+			c.mu.Unlock()
+			return nil, err
+		}
+		c.kp, c.conf, c.keys = kp, *conf, make(map[string][]byte, 4)
+	}
+	if key, ok := c.keys[id]; ok {
+		c.mu.Unlock()
+		return key, nil
+	}
+	kp := c.kp
+	c.mu.Unlock()
+
+	// NOTE: fetching the key (e.g., from KMS) without holding the lock - the
+	// cached keys must remain available in the meantime
+	key, err := kp.Key(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateKey(id, key); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if c.kp == kp { // (unless reconfigured in the meantime)
+		c.keys[id] = key
+	}
+	c.mu.Unlock()
+	return key, nil
+}
+
+func validateKey(id string, key []byte) error {
+	switch len(key) {
+	case 16, 24, 32:
+		return nil
+	default:
+		return fmt.Errorf("invalid key %q: expecting 16, 24, or 32 bytes, got %d", id, len(key))
+	}
+}
+
+func decodeKey(id, b64 string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key %q: %v", id, err)
+	}
+	return key, nil
+}
+
+//////////////////
+// fileProvider //
+//////////////////
+
+func (p *fileProvider) Key(id string) ([]byte, error) {
+	b, err := ioutil.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file %q: %v", p.path, err)
+	}
+	keys := make(map[string]string)
+	if err := jsoniter.Unmarshal(b, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse key file %q: %v", p.path, err)
+	}
+	b64, ok := keys[id]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found in %q", id, p.path)
+	}
+	return decodeKey(id, b64)
+}
+
+//////////////////
+// httpProvider //
+//////////////////
+
+func (p *httpProvider) Key(id string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, p.url+"/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.token != "" {
+		req.Header.Set(cmn.HeaderAuthorization, cmn.MakeHeaderAuthnToken(p.token))
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key %q: %v", id, err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key %q: %v", id, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get key %q: %s (%s)", id, resp.Status, strings.TrimSpace(string(b)))
+	}
+	var body struct {
+		Key string `json:"key"`
+	}
+	if err := jsoniter.Unmarshal(b, &body); err != nil {
+		return nil, fmt.Errorf("failed to parse key %q: %v", id, err)
+	}
+	return decodeKey(id, body.Key)
+}
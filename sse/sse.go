@@ -0,0 +1,340 @@
+// Package sse provides server-side encryption (at rest) of object data.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package sse
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// On-disk format of an encrypted object:
+//
+//   | magic ("AIS") | version | salt (16 bytes) | chunk 0 | chunk 1 | ... | chunk N |
+//
+// The object's (plaintext) payload is split into chunks of `chunkSize` bytes
+// (the last one can be shorter), each sealed with AES-GCM separately - that is,
+// each chunk on disk is followed by its authentication tag. This allows to
+// decrypt (and authenticate) any given range of the object without reading
+// the rest of it.
+//
+// The key used to encrypt a given object is derived from the (master) key and
+// the object's random salt: HMAC-SHA256(key, salt). The nonce of each chunk is
+// its index and the additional authenticated data marks the last chunk, so that
+// chunks cannot be reordered nor the object truncated without being detected.
+// Empty object consists of a single (empty) last chunk.
+
+const (
+	magic     = "AIS"
+	version   = 1
+	saltLen   = 16
+	hdrLen    = len(magic) + 1 + saltLen
+	chunkSize = 64 * cmn.KiB
+	tagLen    = 16
+	nonceLen  = 12
+)
+
+type (
+	// Writer encrypts everything written to it and writes the result to the
+	// underlying writer. Close must be called to write the last chunk (Close
+	// does not close the underlying writer).
+	Writer struct {
+		w     io.Writer
+		aead  cipher.AEAD
+		buf   []byte // plaintext of the current chunk
+		out   []byte // sealed chunk
+		idx   uint64
+		err   error
+		nonce [nonceLen]byte
+	}
+
+	// FileHandle opens an encrypted object file and provides (sequential and
+	// random access) reading of its plaintext. It implements the
+	// cmn.ReadAtOpenCloser interface.
+	FileHandle struct {
+		file   *os.File
+		fqn    string
+		key    []byte
+		aead   cipher.AEAD
+		size   int64 // plaintext size
+		offset int64 // Read offset
+
+		mu     sync.Mutex
+		chunk  []byte // the last decrypted chunk
+		cidx   int64  // and its index
+		sealed []byte
+	}
+)
+
+var (
+	ErrCorrupted = errors.New("encrypted object is corrupted")
+
+	_ io.WriteCloser       = &Writer{}
+	_ cmn.ReadAtOpenCloser = &FileHandle{}
+)
+
+// CipherSize returns the size of the encrypted object (on disk) given the
+// size of its plaintext.
+func CipherSize(size int64) int64 {
+	return int64(hdrLen) + size + numChunks(size)*tagLen
+}
+
+// PlainSize is the inverse of CipherSize.
+func PlainSize(csize int64) (int64, error) {
+	var (
+		n     = csize - int64(hdrLen)
+		full  = n / (chunkSize + tagLen)
+		rem   = n % (chunkSize + tagLen)
+		valid = n >= tagLen && (rem >= tagLen || (rem == 0 && full > 0))
+	)
+	if !valid {
+		return 0, fmt.Errorf("%s: invalid size %d", ErrCorrupted, csize)
+	}
+	if rem == 0 {
+		return full * chunkSize, nil
+	}
+	return full*chunkSize + rem - tagLen, nil
+}
+
+func numChunks(size int64) int64 {
+	if size == 0 {
+		return 1
+	}
+	return (size + chunkSize - 1) / chunkSize
+}
+
+func newAEAD(key, salt []byte) (cipher.AEAD, error) {
+	if err := validateKey("", key); err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(salt)
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func setNonce(nonce *[nonceLen]byte, idx uint64) []byte {
+	binary.BigEndian.PutUint64(nonce[nonceLen-8:], idx)
+	return nonce[:]
+}
+
+func aad(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+////////////
+// Writer //
+////////////
+
+// NewWriter generates random salt, writes the header, and returns the writer
+// that encrypts with the given (master) key.
+func NewWriter(w io.Writer, key []byte) (*Writer, error) {
+	var hdr [hdrLen]byte
+	copy(hdr[:], magic)
+	hdr[len(magic)] = version
+	salt := hdr[len(magic)+1:]
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(hdr[:]); err != nil {
+		return nil, err
+	}
+	return &Writer{
+		w:    w,
+		aead: aead,
+		buf:  make([]byte, 0, chunkSize),
+		out:  make([]byte, 0, chunkSize+tagLen),
+	}, nil
+}
+
+func (w *Writer) Write(p []byte) (n int, err error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	for len(p) > 0 {
+		// the full chunk gets sealed only when there's more data - otherwise,
+		// it may well be the last one
+		if len(w.buf) == chunkSize {
+			if err = w.seal(false); err != nil {
+				return
+			}
+		}
+		m := copy(w.buf[len(w.buf):chunkSize], p)
+		w.buf = w.buf[:len(w.buf)+m]
+		p = p[m:]
+		n += m
+	}
+	return
+}
+
+// Close seals and writes the last chunk.
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if err := w.seal(true); err != nil {
+		return err
+	}
+	w.err = os.ErrClosed
+	return nil
+}
+
+func (w *Writer) seal(last bool) error {
+	w.out = w.aead.Seal(w.out[:0], setNonce(&w.nonce, w.idx), w.buf, aad(last))
+	if _, err := w.w.Write(w.out); err != nil {
+		w.err = err
+		return err
+	}
+	w.buf = w.buf[:0]
+	w.idx++
+	return nil
+}
+
+// EncryptReader returns the reader of the encrypted content of `r`. The
+// returned reader must be closed (it is safe to close it before reading to
+// the end).
+func EncryptReader(r io.Reader, key []byte) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		w, err := NewWriter(pw, key)
+		if err == nil {
+			if _, err = io.Copy(w, r); err == nil {
+				err = w.Close()
+			}
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+////////////////
+// FileHandle //
+////////////////
+
+// NewFileHandle opens the encrypted object file; `size` is the object's
+// plaintext size (as per its metadata).
+func NewFileHandle(fqn string, key []byte, size int64) (*FileHandle, error) {
+	file, err := os.Open(fqn)
+	if err != nil {
+		return nil, err
+	}
+	fh, err := newFileHandle(file, fqn, key, size)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return fh, nil
+}
+
+func newFileHandle(file *os.File, fqn string, key []byte, size int64) (*FileHandle, error) {
+	finfo, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if finfo.Size() != CipherSize(size) {
+		return nil, fmt.Errorf("%s: %q: size %d does not match (plaintext) size %d",
+			ErrCorrupted, fqn, finfo.Size(), size)
+	}
+	var hdr [hdrLen]byte
+	if _, err := io.ReadFull(file, hdr[:]); err != nil {
+		return nil, err
+	}
+	if string(hdr[:len(magic)]) != magic || hdr[len(magic)] != version {
+		return nil, fmt.Errorf("%s: %q: invalid header", ErrCorrupted, fqn)
+	}
+	aead, err := newAEAD(key, hdr[len(magic)+1:])
+	if err != nil {
+		return nil, err
+	}
+	return &FileHandle{file: file, fqn: fqn, key: key, aead: aead, size: size, cidx: -1}, nil
+}
+
+// Size returns the plaintext size.
+func (fh *FileHandle) Size() int64 { return fh.size }
+
+func (fh *FileHandle) Read(p []byte) (n int, err error) {
+	n, err = fh.ReadAt(p, fh.offset)
+	fh.offset += int64(n)
+	return
+}
+
+func (fh *FileHandle) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("%q: negative offset %d", fh.fqn, off)
+	}
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+	for n < len(p) {
+		if off >= fh.size {
+			return n, io.EOF
+		}
+		idx := off / chunkSize
+		if err = fh.load(idx); err != nil {
+			return
+		}
+		m := copy(p[n:], fh.chunk[off-idx*chunkSize:])
+		n += m
+		off += int64(m)
+	}
+	return
+}
+
+// load reads and decrypts the chunk (unless already loaded)
+func (fh *FileHandle) load(idx int64) error {
+	if fh.cidx == idx {
+		return nil
+	}
+	var (
+		nonce [nonceLen]byte
+		start = idx * chunkSize
+		plen  = cmn.MinI64(chunkSize, fh.size-start)
+		last  = idx == numChunks(fh.size)-1
+	)
+	if fh.sealed == nil {
+		fh.sealed = make([]byte, chunkSize+tagLen)
+		fh.chunk = make([]byte, 0, chunkSize)
+	}
+	sealed := fh.sealed[:plen+tagLen]
+	if _, err := fh.file.ReadAt(sealed, int64(hdrLen)+idx*(chunkSize+tagLen)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	chunk, err := fh.aead.Open(fh.chunk[:0], setNonce(&nonce, uint64(idx)), sealed, aad(last))
+	if err != nil {
+		fh.cidx = -1
+		return fmt.Errorf("%s: %q: chunk %d: %v", ErrCorrupted, fh.fqn, idx, err)
+	}
+	fh.chunk, fh.cidx = chunk, idx
+	return nil
+}
+
+// Open reopens the file (and starts reading from the beginning).
+func (fh *FileHandle) Open() (io.ReadCloser, error) {
+	return NewFileHandle(fh.fqn, fh.key, fh.size)
+}
+
+func (fh *FileHandle) Close() error { return fh.file.Close() }
@@ -0,0 +1,216 @@
+// Package sse provides server-side encryption (at rest) of object data.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package sse
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func encrypt(t *testing.T, fqn string, key, data []byte) {
+	file, err := os.Create(fqn)
+	tassert.CheckFatal(t, err)
+	defer file.Close()
+	w, err := NewWriter(file, key)
+	tassert.CheckFatal(t, err)
+	// write in odd-sized pieces to cross chunk boundaries
+	for b := data; len(b) > 0; {
+		n := cmn.Min(len(b), 1000+rand.Intn(100*cmn.KiB))
+		_, err = w.Write(b[:n])
+		tassert.CheckFatal(t, err)
+		b = b[n:]
+	}
+	tassert.CheckFatal(t, w.Close())
+}
+
+func TestRoundTrip(t *testing.T) {
+	var (
+		dir = t.TempDir()
+		key = bytes.Repeat([]byte{7}, 32)
+	)
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 3*chunkSize + 17, 4 * chunkSize} {
+		t.Run(fmt.Sprintf("size=%d", size), func(t *testing.T) {
+			var (
+				fqn  = filepath.Join(dir, fmt.Sprintf("obj-%d", size))
+				data = make([]byte, size)
+			)
+			rand.Read(data)
+			encrypt(t, fqn, key, data)
+
+			finfo, err := os.Stat(fqn)
+			tassert.CheckFatal(t, err)
+			tassert.Errorf(t, finfo.Size() == CipherSize(int64(size)),
+				"expected on-disk size %d, got %d", CipherSize(int64(size)), finfo.Size())
+			psize, err := PlainSize(finfo.Size())
+			tassert.CheckFatal(t, err)
+			tassert.Errorf(t, psize == int64(size), "expected plaintext size %d, got %d", size, psize)
+
+			fh, err := NewFileHandle(fqn, key, int64(size))
+			tassert.CheckFatal(t, err)
+			defer fh.Close()
+			b, err := ioutil.ReadAll(fh)
+			tassert.CheckFatal(t, err)
+			tassert.Fatalf(t, bytes.Equal(b, data), "plaintext mismatch")
+
+			if size < 3 {
+				return
+			}
+			// range read
+			off, length := int64(size/3), int64(size/2)
+			b, err = ioutil.ReadAll(io.NewSectionReader(fh, off, length))
+			tassert.CheckFatal(t, err)
+			tassert.Errorf(t, bytes.Equal(b, data[off:off+length]), "range [%d, %d) mismatch", off, off+length)
+
+			// reopen
+			rc, err := fh.Open()
+			tassert.CheckFatal(t, err)
+			b, err = ioutil.ReadAll(rc)
+			rc.Close()
+			tassert.CheckFatal(t, err)
+			tassert.Errorf(t, bytes.Equal(b, data), "plaintext mismatch after reopen")
+
+			// encrypting reader
+			rc = EncryptReader(bytes.NewReader(data), key)
+			fqn2 := fqn + ".2"
+			b, err = ioutil.ReadAll(rc)
+			rc.Close()
+			tassert.CheckFatal(t, err)
+			tassert.CheckFatal(t, ioutil.WriteFile(fqn2, b, 0o644))
+			fh2, err := NewFileHandle(fqn2, key, int64(size))
+			tassert.CheckFatal(t, err)
+			b, err = ioutil.ReadAll(fh2)
+			fh2.Close()
+			tassert.CheckFatal(t, err)
+			tassert.Errorf(t, bytes.Equal(b, data), "plaintext mismatch (encrypting reader)")
+		})
+	}
+}
+
+func TestTamper(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		fqn  = filepath.Join(dir, "obj")
+		key  = bytes.Repeat([]byte{1}, 16)
+		size = 2*chunkSize + 100
+		data = make([]byte, size)
+	)
+	rand.Read(data)
+	encrypt(t, fqn, key, data)
+
+	// wrong key
+	fh, err := NewFileHandle(fqn, bytes.Repeat([]byte{2}, 16), int64(size))
+	tassert.CheckFatal(t, err)
+	_, err = ioutil.ReadAll(fh)
+	fh.Close()
+	tassert.Errorf(t, err != nil, "expected error reading with the wrong key")
+
+	// wrong size (truncation)
+	_, err = NewFileHandle(fqn, key, int64(size-1))
+	tassert.Errorf(t, err != nil, "expected error opening with the wrong size")
+
+	// flip a bit in the second chunk
+	b, err := ioutil.ReadFile(fqn)
+	tassert.CheckFatal(t, err)
+	b[hdrLen+chunkSize+tagLen+10] ^= 1
+	tassert.CheckFatal(t, ioutil.WriteFile(fqn, b, 0o644))
+
+	fh, err = NewFileHandle(fqn, key, int64(size))
+	tassert.CheckFatal(t, err)
+	defer fh.Close()
+	buf := make([]byte, 10)
+	_, err = fh.ReadAt(buf, 0)
+	tassert.CheckError(t, err) // the first chunk is intact
+	_, err = fh.ReadAt(buf, chunkSize+5)
+	tassert.Errorf(t, err != nil, "expected error reading tampered chunk")
+}
+
+func TestKeyProviders(t *testing.T) {
+	key := bytes.Repeat([]byte{3}, 32)
+	b64 := base64.StdEncoding.EncodeToString(key)
+
+	// keyfile
+	keyfile := filepath.Join(t.TempDir(), "keys.json")
+	tassert.CheckFatal(t, ioutil.WriteFile(keyfile, []byte(`{"k1": "`+b64+`", "short": "AAAA"}`), 0o600))
+	c := &keyCache{}
+	conf := &cmn.KeyProviderConf{Type: cmn.KeyProviderFile, KeyFile: keyfile}
+	k, err := c.key("k1", conf)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bytes.Equal(k, key), "keyfile: key mismatch")
+	_, err = c.key("short", conf)
+	tassert.Errorf(t, err != nil, "expected error for invalid key length")
+	_, err = c.key("k2", conf)
+	tassert.Errorf(t, err != nil, "expected error for missing key")
+
+	// http
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(cmn.HeaderAuthorization) != cmn.MakeHeaderAuthnToken("secret") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/keys/k1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"key": "` + b64 + `"}`))
+	}))
+	defer srv.Close()
+	conf = &cmn.KeyProviderConf{Type: cmn.KeyProviderHTTP, URL: srv.URL + "/keys/", Token: "secret"}
+	k, err = c.key("k1", conf)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bytes.Equal(k, key), "http: key mismatch")
+	_, err = c.key("k2", conf)
+	tassert.Errorf(t, err != nil, "expected error for missing key")
+
+	// none
+	_, err = c.key("k1", &cmn.KeyProviderConf{})
+	tassert.Errorf(t, err == ErrNoProvider, "expected %v, got %v", ErrNoProvider, err)
+}
+
+// cached keys remain available while a key is being fetched
+func TestKeyCacheSlowProvider(t *testing.T) {
+	var (
+		b64     = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{5}, 16))
+		release = make(chan struct{})
+		c       = &keyCache{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-release
+		}
+		w.Write([]byte(`{"key": "` + b64 + `"}`))
+	}))
+	defer srv.Close()
+	defer close(release)
+	conf := &cmn.KeyProviderConf{Type: cmn.KeyProviderHTTP, URL: srv.URL}
+	_, err := c.key("fast", conf)
+	tassert.CheckFatal(t, err)
+
+	go c.key("slow", conf)
+	done := make(chan error, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond) // let the slow fetch start
+		_, err := c.key("fast", conf)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		tassert.CheckFatal(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("cached key is blocked by the key being fetched")
+	}
+}
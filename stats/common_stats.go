@@ -7,6 +7,7 @@ package stats
 
 import (
 	"encoding/json"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -40,6 +41,7 @@ const (
 	KindLatency    = "latency"
 	KindThroughput = "throughput"
 	KindSpecial    = "special"
+	KindGauge      = "gauge" // current value (e.g., queue length) updated with deltas
 )
 
 // number-of-goroutines watermarks expressed as multipliers over the number of available logical CPUs (GOMAXPROCS)
@@ -48,7 +50,7 @@ const (
 	numGorExtreme = 1000
 )
 
-var kinds = []string{KindCounter, KindLatency, KindThroughput, KindSpecial}
+var kinds = []string{KindCounter, KindLatency, KindThroughput, KindSpecial, KindGauge}
 
 // CoreStats stats
 const (
@@ -95,6 +97,7 @@ type (
 		CoreStats() *CoreStats
 		GetWhatStats() interface{}
 		RegisterAll()
+		WritePrometheus(w io.Writer, node *cluster.Snode, xacts []cluster.XactStats) error
 	}
 	NamedVal64 struct {
 		Name       string
@@ -178,6 +181,9 @@ type (
 		numSamples int64
 		cumulative int64
 		isCommon   bool // optional, common to the proxy and target
+
+		// total number of latency samples (never reset - see prometheus.go)
+		cumulativeSamples int64
 	}
 	copyValue struct {
 		Value int64 `json:"v,string"`
@@ -239,6 +245,7 @@ func (s *CoreStats) doAdd(name, nameSuffix string, val int64) {
 		}
 		v.Lock()
 		v.numSamples++
+		v.cumulativeSamples++
 		v.cumulative += val
 		v.Value += val
 		v.Unlock()
@@ -258,6 +265,12 @@ func (s *CoreStats) doAdd(name, nameSuffix string, val int64) {
 			v.Value += val
 			v.Unlock()
 		}
+	case KindGauge:
+		v.Lock()
+		v.Value += val
+		val = v.Value
+		v.Unlock()
+		s.statsdC.Send(name, 1, metric{Type: statsd.Gauge, Name: "value", Value: val})
 	default:
 		cmn.AssertMsg(false, v.kind)
 	}
@@ -330,8 +343,12 @@ func (s *CoreStats) copyCumulative(ctracker copyTracker) {
 	}
 }
 
-// init StatsD client
+// init StatsD client (unless stats are exported to Prometheus)
 func (s *CoreStats) initStatsD(node *cluster.Snode) {
+	if cmn.GCO.Get().Stats.Exporter == cmn.StatsExporterPrometheus {
+		s.statsdC = &statsd.Client{} // not opened: sends nothing
+		return
+	}
 	var (
 		suffix = strings.ReplaceAll(node.ID(), ":", "_")
 		port   = 8125 // StatsD default port, see https://github.com/etsy/stats
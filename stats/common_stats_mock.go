@@ -5,6 +5,12 @@
  */
 package stats
 
+import (
+	"io"
+
+	"github.com/NVIDIA/aistore/cluster"
+)
+
 type (
 	TrackerMock struct{}
 )
@@ -24,3 +30,6 @@ func (*TrackerMock) AddMany(namedVal64 ...NamedVal64)      {}
 func (*TrackerMock) RegisterAll()                          {}
 func (*TrackerMock) CoreStats() *CoreStats                 { return nil }
 func (*TrackerMock) GetWhatStats() interface{}             { return nil }
+func (*TrackerMock) WritePrometheus(io.Writer, *cluster.Snode, []cluster.XactStats) error {
+	return nil
+}
@@ -0,0 +1,213 @@
+// Package stats provides methods and functionality to register, track, log,
+// and StatsD-notify statistics that, for the most part, include "counter" and "latency" kinds.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package stats
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Prometheus exporter (config: `stats.exporter` = "prometheus")
+//
+// Metrics are served by both proxies and targets at `/metrics` in the
+// Prometheus text exposition format:
+// https://prometheus.io/docs/instrumenting/exposition_formats/
+//
+// Stats names are converted as follows (see also the naming convention in target_stats.go):
+//  -> "*.n"    - counter "*_total"
+//  -> "*.size" - counter "*_bytes_total"
+//  -> "*.bps"  - counter "*_bytes_total" (cumulative, use rate() to get throughput)
+//  -> "*.ns"   - summary "*_latency_seconds" (_sum and _count)
+// All metric names are prefixed with "ais_proxy_" or "ais_target_".
+
+const PromContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type promWriter struct {
+	w      *bufio.Writer
+	prefix string
+	node   string
+}
+
+func newPromWriter(w io.Writer, node *cluster.Snode) *promWriter {
+	return &promWriter{
+		w:      bufio.NewWriter(w),
+		prefix: "ais_" + node.Type() + "_",
+		node:   node.ID(),
+	}
+}
+
+// writes HELP and TYPE lines of the metric family; all samples of the
+// family must follow
+func (pw *promWriter) family(name, typ, help string) {
+	pw.w.WriteString("# HELP " + pw.prefix + name + " " + help + "\n")
+	pw.w.WriteString("# TYPE " + pw.prefix + name + " " + typ + "\n")
+}
+
+// labels are name/value pairs, the node label is always added
+func (pw *promWriter) sample(name string, value float64, labels ...string) {
+	pw.w.WriteString(pw.prefix + name + `{node="` + promEscape(pw.node) + `"`)
+	for i := 0; i < len(labels)-1; i += 2 {
+		pw.w.WriteString("," + labels[i] + `="` + promEscape(labels[i+1]) + `"`)
+	}
+	pw.w.WriteString("} " + strconv.FormatFloat(value, 'g', -1, 64) + "\n")
+}
+
+func (pw *promWriter) flush() error { return pw.w.Flush() }
+
+func promEscape(s string) string {
+	if !strings.ContainsAny(s, "\\\"\n") {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+func promName(s string) string { return strings.NewReplacer(".", "_", "-", "_").Replace(s) }
+
+func secs(ns int64) float64 { return float64(ns) / float64(time.Second) }
+
+// writes cumulative counters and latencies
+func (s *CoreStats) writeProm(pw *promWriter) {
+	names := make([]string, 0, len(s.Tracker))
+	for name := range s.Tracker {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v := s.Tracker[name]
+		v.RLock()
+		switch v.kind {
+		case KindCounter:
+			var pname string
+			switch {
+			case strings.HasSuffix(name, ".n"):
+				pname = promName(strings.TrimSuffix(name, ".n")) + "_total"
+			case strings.HasSuffix(name, ".size"):
+				pname = promName(strings.TrimSuffix(name, ".size")) + "_bytes_total"
+			default:
+				pname = promName(name) + "_total"
+			}
+			pw.family(pname, "counter", "ais counter "+name)
+			pw.sample(pname, float64(v.Value))
+		case KindLatency:
+			pname := promName(strings.Replace(name, ".ns", "", 1)) + "_latency_seconds"
+			pw.family(pname, "summary", "ais latency "+name)
+			pw.sample(pname+"_sum", secs(v.cumulative))
+			pw.sample(pname+"_count", float64(v.cumulativeSamples))
+		case KindThroughput:
+			pname := promName(strings.TrimSuffix(name, ".bps")) + "_bytes_total"
+			pw.family(pname, "counter", "ais throughput "+name+" (cumulative)")
+			pw.sample(pname, float64(v.cumulative))
+		case KindGauge:
+			if strings.HasSuffix(name, ".ns") {
+				pname := promName(strings.TrimSuffix(name, ".ns")) + "_seconds"
+				pw.family(pname, "gauge", "ais gauge "+name)
+				pw.sample(pname, secs(v.Value))
+			} else {
+				pname := promName(name)
+				pw.family(pname, "gauge", "ais gauge "+name)
+				pw.sample(pname, float64(v.Value))
+			}
+		default:
+			if name == Uptime {
+				pw.family("uptime_seconds", "gauge", "ais node uptime")
+				pw.sample("uptime_seconds", secs(v.Value))
+			} else {
+				pname := promName(name)
+				pw.family(pname, "gauge", "ais "+name)
+				pw.sample(pname, float64(v.Value))
+			}
+		}
+		v.RUnlock()
+	}
+}
+
+// WritePrometheus writes proxy's stats.
+func (r *Prunner) WritePrometheus(w io.Writer, node *cluster.Snode, _ []cluster.XactStats) error {
+	pw := newPromWriter(w, node)
+	r.Core.writeProm(pw)
+	return pw.flush()
+}
+
+// WritePrometheus writes target's stats, per-mountpath capacity and utilization,
+// per-disk throughput, and progress of the given xactions.
+func (r *Trunner) WritePrometheus(w io.Writer, node *cluster.Snode, xacts []cluster.XactStats) error {
+	pw := newPromWriter(w, node)
+	r.Core.writeProm(pw)
+
+	// mountpaths
+	mpaths := make([]string, 0, len(r.MPCap))
+	for mpath := range r.MPCap {
+		mpaths = append(mpaths, mpath)
+	}
+	sort.Strings(mpaths)
+	pw.family("mountpath_used_bytes", "gauge", "used capacity of the mountpath")
+	for _, mpath := range mpaths {
+		pw.sample("mountpath_used_bytes", float64(r.MPCap[mpath].Used), "mountpath", mpath)
+	}
+	pw.family("mountpath_avail_bytes", "gauge", "available capacity of the mountpath")
+	for _, mpath := range mpaths {
+		pw.sample("mountpath_avail_bytes", float64(r.MPCap[mpath].Avail), "mountpath", mpath)
+	}
+	pw.family("mountpath_util_percent", "gauge", "average utilization of the mountpath's disks")
+	for _, mpath := range mpaths {
+		pw.sample("mountpath_util_percent", float64(fs.GetMpathUtil(mpath)), "mountpath", mpath)
+	}
+
+	// disks
+	var (
+		diskStats = fs.GetSelectedDiskStats()
+		disks     = make([]string, 0, len(diskStats))
+	)
+	for disk := range diskStats {
+		disks = append(disks, disk)
+	}
+	sort.Strings(disks)
+	pw.family("disk_read_bytes_per_second", "gauge", "disk read throughput")
+	for _, disk := range disks {
+		pw.sample("disk_read_bytes_per_second", float64(diskStats[disk].RBps), "disk", disk)
+	}
+	pw.family("disk_write_bytes_per_second", "gauge", "disk write throughput")
+	for _, disk := range disks {
+		pw.sample("disk_write_bytes_per_second", float64(diskStats[disk].WBps), "disk", disk)
+	}
+	pw.family("disk_util_percent", "gauge", "disk utilization")
+	for _, disk := range disks {
+		pw.sample("disk_util_percent", float64(diskStats[disk].Util), "disk", disk)
+	}
+
+	// xactions
+	sort.Slice(xacts, func(i, j int) bool { return xacts[i].ID() < xacts[j].ID() })
+	xlabels := func(xact cluster.XactStats) []string {
+		bck := xact.Bck()
+		var bname string
+		if !bck.IsEmpty() {
+			bname = bck.String()
+		}
+		return []string{"kind", xact.Kind(), "id", xact.ID(), "bucket", bname}
+	}
+	pw.family("xaction_objects_total", "counter", "number of objects processed by the xaction")
+	for _, xact := range xacts {
+		pw.sample("xaction_objects_total", float64(xact.ObjCount()), xlabels(xact)...)
+	}
+	pw.family("xaction_bytes_total", "counter", "number of bytes processed by the xaction")
+	for _, xact := range xacts {
+		pw.sample("xaction_bytes_total", float64(xact.BytesCount()), xlabels(xact)...)
+	}
+	pw.family("xaction_start_time_seconds", "gauge", "xaction start time (Unix epoch)")
+	for _, xact := range xacts {
+		pw.sample("xaction_start_time_seconds", float64(xact.StartTime().Unix()), xlabels(xact)...)
+	}
+	return pw.flush()
+}
@@ -0,0 +1,49 @@
+// Package stats provides methods and functionality to register, track, log,
+// and StatsD-notify statistics that, for the most part, include "counter" and "latency" kinds.
+/*
+ * Copyright (c) 2018-2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package stats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/stats/statsd"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestPrometheusCoreStats(t *testing.T) {
+	var (
+		s    = &CoreStats{statsdC: &statsd.Client{}}
+		node = &cluster.Snode{DaemonID: "p[1]", DaemonType: cmn.Proxy}
+		buf  = &bytes.Buffer{}
+	)
+	s.init(24)
+	s.doAdd(GetCount, "", 3)
+	s.doAdd(GetLatency, "", int64(time.Second))
+	s.doAdd(GetLatency, "", int64(2*time.Second))
+	s.copyT(make(copyTracker), nil) // resets latencies but not the exported totals
+	s.UpdateUptime(time.Minute)
+
+	pw := newPromWriter(buf, node)
+	s.writeProm(pw)
+	tassert.CheckFatal(t, pw.flush())
+
+	out := buf.String()
+	for _, line := range []string{
+		"# TYPE ais_proxy_get_total counter",
+		`ais_proxy_get_total{node="p[1]"} 3`,
+		"# TYPE ais_proxy_get_latency_seconds summary",
+		`ais_proxy_get_latency_seconds_sum{node="p[1]"} 3`,
+		`ais_proxy_get_latency_seconds_count{node="p[1]"} 2`,
+		`ais_proxy_kalive_min_latency_seconds_count{node="p[1]"} 0`,
+		`ais_proxy_uptime_seconds{node="p[1]"} 60`,
+	} {
+		tassert.Errorf(t, strings.Contains(out, line+"\n"), "expected %q in:\n%s", line, out)
+	}
+}
@@ -41,8 +41,17 @@ const (
 	ErrCksumSize     = "err.cksum.size"
 	ErrMetadataCount = "err.md.n"
 	ErrIOCount       = "err.io.n"
+	ErrReplCount     = "err.repl.n"
 	// special
 	RestartCount = "restart.n"
+	// replication (to remote cluster)
+	ReplCount       = "repl.n"
+	ReplSize        = "repl.size"
+	ReplDeleteCount = "repl.del.n"
+
+	// KindGauge
+	ReplPending = "repl.pending" // number of objects waiting to be replicated
+	ReplLag     = "repl.lag.ns"  // age of the oldest of those
 
 	// KindLatency
 	PutLatency      = "put.ns"
@@ -146,6 +155,7 @@ func (r *Trunner) RegisterAll() {
 	r.Register(ErrCksumSize, KindCounter)
 	r.Register(ErrMetadataCount, KindCounter)
 	r.Register(ErrIOCount, KindCounter)
+	r.Register(ErrReplCount, KindCounter)
 
 	// rebalance
 	r.Register(RebTxCount, KindCounter)
@@ -156,6 +166,13 @@ func (r *Trunner) RegisterAll() {
 	// special
 	r.Register(RestartCount, KindCounter)
 
+	// replication
+	r.Register(ReplCount, KindCounter)
+	r.Register(ReplSize, KindCounter)
+	r.Register(ReplDeleteCount, KindCounter)
+	r.Register(ReplPending, KindGauge)
+	r.Register(ReplLag, KindGauge)
+
 	// download
 	r.Register(DownloadSize, KindCounter)
 	r.Register(DownloadLatency, KindLatency)
@@ -252,6 +252,7 @@ func PrepareObjects(t *testing.T, desc ObjectsDesc) *ObjectsOut {
 
 	_ = fs.CSM.RegisterContentType(fs.WorkfileType, &fs.WorkfileContentResolver{})
 	_ = fs.CSM.RegisterContentType(fs.ObjectType, &fs.ObjectContentResolver{})
+	_ = fs.CSM.RegisterContentType(fs.VersionType, &fs.VersionContentResolver{})
 	_ = fs.CSM.RegisterContentType(ec.SliceType, &ec.SliceSpec{})
 	_ = fs.CSM.RegisterContentType(ec.MetaType, &ec.MetaSpec{})
 
@@ -65,6 +65,8 @@ var (
 var XactsDtor = map[string]XactDescriptor{
 	// bucket-less (aka "global") xactions with scope = (target | cluster)
 	cmn.ActLRU:       {Type: XactTypeGlobal, Startable: true, Mountpath: true},
+	cmn.ActLifecycle: {Type: XactTypeGlobal, Startable: true, Mountpath: true},
+	cmn.ActScrub:     {Type: XactTypeGlobal, Startable: true, Mountpath: true},
 	cmn.ActElection:  {Type: XactTypeGlobal, Startable: false},
 	cmn.ActResilver:  {Type: XactTypeGlobal, Startable: true, Mountpath: true},
 	cmn.ActRebalance: {Type: XactTypeGlobal, Startable: true, Metasync: true, Owned: false, Mountpath: true},
@@ -78,8 +80,10 @@ var XactsDtor = map[string]XactDescriptor{
 	cmn.ActPutCopies:     {Type: XactTypeBck, Startable: false},
 	cmn.ActRenameLB:      {Type: XactTypeBck, Startable: false, Metasync: true, Owned: false, Mountpath: true},
 	cmn.ActCopyBucket:    {Type: XactTypeBck, Startable: false, Metasync: true, Owned: false, RefreshCap: true, Mountpath: true},
+	cmn.ActSyncBucket:    {Type: XactTypeBck, Startable: false, Metasync: true, Owned: false, RefreshCap: true, Mountpath: true},
 	cmn.ActETLBucket:     {Type: XactTypeBck, Startable: false, Metasync: true, Owned: false, RefreshCap: true, Mountpath: true},
 	cmn.ActECEncode:      {Type: XactTypeBck, Startable: true, Metasync: true, Owned: false, RefreshCap: true, Mountpath: true},
+	cmn.ActECScrub:       {Type: XactTypeBck, Startable: true, Mountpath: true},
 	cmn.ActEvictObjects:  {Type: XactTypeBck, Startable: false, Mountpath: true},
 	cmn.ActDelete:        {Type: XactTypeBck, Startable: false, Mountpath: true},
 	cmn.ActLoadLomCache:  {Type: XactTypeBck, Startable: false, Mountpath: true},
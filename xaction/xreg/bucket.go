@@ -121,6 +121,10 @@ func (r *registry) renewECEncode(t cluster.Target, bck *cluster.Bck, uuid, phase
 	})
 }
 
+func RenewECScrub(t cluster.Target, bck *cluster.Bck, uuid string) (cluster.Xact, error) {
+	return defaultReg.renewBucketXact(cmn.ActECScrub, bck, XactArgs{T: t, UUID: uuid})
+}
+
 // TODO: Restart the EC (#531) in case of mountpath event.
 func RenewMakeNCopies(t cluster.Target, tag string) { defaultReg.renewMakeNCopies(t, tag) }
 
@@ -101,6 +101,28 @@ func (r *registry) renewLRU(id string) cluster.Xact {
 	return res.entry.Get()
 }
 
+func RenewLifecycle(id string) cluster.Xact { return defaultReg.renewLifecycle(id) }
+
+func (r *registry) renewLifecycle(id string) cluster.Xact {
+	e := r.globalXacts[cmn.ActLifecycle].New(XactArgs{UUID: id})
+	res := r.renewGlobalXaction(e)
+	if !res.isNew { // previous lifecycle xaction is still running
+		return nil
+	}
+	return res.entry.Get()
+}
+
+func RenewScrub(id string) cluster.Xact { return defaultReg.renewScrub(id) }
+
+func (r *registry) renewScrub(id string) cluster.Xact {
+	e := r.globalXacts[cmn.ActScrub].New(XactArgs{UUID: id})
+	res := r.renewGlobalXaction(e)
+	if !res.isNew { // previous scrub is still running
+		return nil
+	}
+	return res.entry.Get()
+}
+
 func RenewDownloader(t cluster.Target, statsT stats.Tracker) (cluster.Xact, error) {
 	return defaultReg.renewDownloader(t, statsT)
 }
@@ -139,6 +139,7 @@ func (t *bckSummaryTask) Run() error {
 				summary = cmn.BucketSummary{
 					Bck:            bck.Bck,
 					TotalDisksSize: totalDisksSize,
+					Quota:          bck.Props.Quota,
 				}
 			)
 